@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// runBackfill backfills a table in the foreground instead of starting the service. An interrupt stops the backfill
+// after saving its progress, and running the command again resumes it
+func runBackfill(cfg *config.Config, syncEngine *syncpkg.SyncEngine, tableName string, restart bool, logger *zap.Logger) error {
+	var tableConfig *config.TableConfig
+	for i := range cfg.Tables {
+		if cfg.Tables[i].TargetTable == tableName {
+			tableConfig = &cfg.Tables[i]
+			break
+		}
+	}
+	if tableConfig == nil {
+		return fmt.Errorf("table not found: %s", tableName)
+	}
+
+	progress, err := syncEngine.StartBackfill(*tableConfig, restart)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			logger.Info("Stopping backfill after the current range")
+			cancel(syncpkg.ErrBackfillStopped)
+		case <-ctx.Done():
+		}
+	}()
+
+	err = syncEngine.Backfill(ctx, *tableConfig, progress)
+	if errors.Is(err, syncpkg.ErrBackfillStopped) {
+		logger.Info("Backfill stopped, run the command again to resume",
+			zap.String("table", tableName),
+			zap.Int("ranges_done", progress.RangesDone),
+			zap.Int("ranges_total", progress.RangesTotal),
+		)
+		return nil
+	}
+	return err
+}
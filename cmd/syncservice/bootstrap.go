@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// bootstrapOutcome is the result of one table's load during a bootstrap
+type bootstrapOutcome struct {
+	table  string
+	result *syncpkg.SyncResult
+	err    error
+}
+
+// runBootstrap performs a full load of every table in the foreground instead of starting the service, and fails
+// when any table did not load. Tables start once the tables they depend on have loaded, with at most
+// worker_pool.workers loads at a time; a table whose dependency failed is not loaded. An interrupt lets running
+// loads finish their cancellation and starts no others
+func runBootstrap(cfg *config.Config, syncEngine *syncpkg.SyncEngine, logger *zap.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	configured := make(map[string]bool, len(cfg.Tables))
+	for _, tc := range cfg.Tables {
+		configured[tc.TargetTable] = true
+	}
+
+	workers := cfg.WorkerPool.GetWorkers()
+	logger.Info("Starting bootstrap", zap.Int("tables", len(cfg.Tables)), zap.Int("concurrency", workers))
+	start := time.Now()
+
+	queued := append([]config.TableConfig(nil), cfg.Tables...)
+	finished := make(map[string]error, len(cfg.Tables))
+	done := make(chan bootstrapOutcome)
+	running := 0
+	var failed []string
+
+	for len(queued) > 0 || running > 0 {
+		// Skipping a table can unblock the decision on tables depending on it, so passes repeat until nothing changes
+		for progressed := true; progressed && ctx.Err() == nil; {
+			progressed = false
+			remaining := queued[:0]
+			for _, tc := range queued {
+				ready, blocked := dependenciesLoaded(tc, configured, finished)
+				switch {
+				case blocked != "":
+					finished[tc.TargetTable] = fmt.Errorf("dependency %s did not load", blocked)
+					failed = append(failed, tc.TargetTable)
+					progressed = true
+					logger.Warn("Skipping table, a dependency did not load",
+						zap.String("table", tc.TargetTable),
+						zap.String("dependency", blocked),
+					)
+				case ready && running < workers:
+					running++
+					progressed = true
+					go func(tc config.TableConfig) {
+						result, err := syncEngine.SyncTable(ctx, tc)
+						done <- bootstrapOutcome{table: tc.TargetTable, result: result, err: err}
+					}(tc)
+				default:
+					remaining = append(remaining, tc)
+				}
+			}
+			queued = remaining
+		}
+
+		if running == 0 {
+			if ctx.Err() != nil {
+				return fmt.Errorf("bootstrap interrupted with %d tables not loaded", len(queued))
+			}
+			names := make([]string, len(queued))
+			for i, tc := range queued {
+				names[i] = tc.TargetTable
+			}
+			return fmt.Errorf("dependency cycle detected between %s", strings.Join(names, ", "))
+		}
+
+		outcome := <-done
+		running--
+		finished[outcome.table] = outcome.err
+		if outcome.err != nil {
+			failed = append(failed, outcome.table)
+			logger.Error("Bootstrap load failed", zap.String("table", outcome.table), zap.Error(outcome.err))
+			continue
+		}
+		logger.Info("Bootstrap load completed",
+			zap.String("table", outcome.table),
+			zap.Int("rows_written", outcome.result.RowsWritten),
+			zap.Duration("duration", outcome.result.Duration),
+		)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d tables did not load: %s", len(failed), len(cfg.Tables), strings.Join(failed, ", "))
+	}
+	logger.Info("Bootstrap completed", zap.Int("tables", len(cfg.Tables)), zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// dependenciesLoaded reports whether every configured table a table depends on has loaded, or returns the first
+// dependency that failed. Dependencies that are not configured tables are ignored
+func dependenciesLoaded(tc config.TableConfig, configured map[string]bool, finished map[string]error) (ready bool, blocked string) {
+	ready = true
+	for _, dependency := range tc.DependsOn {
+		if !configured[dependency] {
+			continue
+		}
+		err, ok := finished[dependency]
+		switch {
+		case !ok:
+			ready = false
+		case err != nil:
+			return false, dependency
+		}
+	}
+	return ready, ""
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"os"
 	"os/signal"
@@ -11,14 +12,23 @@ import (
 	"go.uber.org/zap"
 
 	actorpkg "mssql-postgres-sync/internal/actor"
+	"mssql-postgres-sync/internal/alert"
 	"mssql-postgres-sync/internal/api"
 	"mssql-postgres-sync/internal/config"
 	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/events"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/logging"
+	"mssql-postgres-sync/internal/snapshot"
 	syncpkg "mssql-postgres-sync/internal/sync"
 )
 
 func main() {
-	configPath := flag.String("config", "config/sync-config.yaml", "path to configuration file")
+	configPath := flag.String("config", "config/sync-config.yaml", "path to configuration file, optional when SYNC_CONFIG_JSON or SYNC__ variables configure the service")
+	profile := flag.String("profile", os.Getenv("SYNC_PROFILE"), "environment overlay merged over the config (e.g. prod loads sync-config.prod.yaml)")
+	backfillTable := flag.String("backfill", "", "backfill a table range by range in the foreground and exit instead of starting the service")
+	backfillRestart := flag.Bool("backfill-restart", false, "with -backfill, discard saved progress and start over")
+	bootstrap := flag.Bool("bootstrap", false, "load every table once, in dependency order, and exit instead of starting the service")
 	flag.Parse()
 
 	logger, err := zap.NewProduction()
@@ -27,12 +37,28 @@ func main() {
 	}
 	defer logger.Sync()
 
-	cfg, err := config.LoadConfig(*configPath)
+	var overlays []string
+	if *profile != "" {
+		overlays = append(overlays, config.ProfilePath(*configPath, *profile))
+	}
+
+	cfg, err := config.LoadConfig(*configPath, overlays...)
 	if err != nil {
 		logger.Fatal("Failed to load configuration", zap.Error(err))
 	}
 
-	dbManager, err := database.NewDatabaseManager(cfg, logger)
+	logs, err := logging.NewManager(cfg.Logging)
+	if err != nil {
+		logger.Fatal("Invalid logging configuration", zap.Error(err))
+	}
+	logger = logs.Logger
+	defer logs.Sync()
+
+	if *profile != "" {
+		logger.Info("Loaded configuration profile", zap.String("profile", *profile))
+	}
+
+	dbManager, err := database.NewDatabaseManager(cfg, logs.For(logging.ModuleDatabase))
 	if err != nil {
 		logger.Fatal("Failed to initialize database connections", zap.Error(err))
 	}
@@ -42,16 +68,110 @@ func main() {
 		}
 	}()
 
-	syncEngine := syncpkg.NewSyncEngine(dbManager, cfg, logger)
+	if !dbManager.Ready() {
+		waitCtx, stopWait := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		if timeout := cfg.Startup.GetConnectTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(waitCtx, timeout)
+			defer cancel()
+		}
+		var err error
+		if *backfillTable != "" || *bootstrap {
+			err = dbManager.WaitReady(waitCtx)
+		} else {
+			err = api.WaitForDatabases(waitCtx, cfg, logs.For(logging.ModuleAPI), dbManager)
+		}
+		stopWait()
+		if errors.Is(err, context.Canceled) {
+			logger.Info("Shutdown signal received while waiting for databases")
+			return
+		}
+		if err != nil {
+			logger.Fatal("Databases did not become reachable", zap.Any("connections", dbManager.ConnectionStates()), zap.Error(err))
+		}
+	}
+
+	var historyStore *history.Store
+	if cfg.History.Enabled {
+		historyStore = history.NewStore(dbManager.Target.DB, cfg.History.Table, logs.For(logging.ModuleSync))
+		if err := historyStore.EnsureSchema(); err != nil {
+			logger.Fatal("Failed to migrate sync history tables", zap.Error(err))
+		}
+	}
+
+	publisher, err := events.NewPublisher(cfg.Events, logs.For(logging.ModuleSync))
+	if err != nil {
+		logger.Fatal("Invalid events configuration", zap.Error(err))
+	}
+	defer func() {
+		if err := publisher.Close(); err != nil {
+			logger.Error("Failed to close event publisher", zap.Error(err))
+		}
+	}()
+
+	syncEngine := syncpkg.NewSyncEngine(dbManager, cfg, logs.For(logging.ModuleSync), historyStore, publisher)
+	if err := syncEngine.ExpandTenants(); err != nil {
+		logger.Fatal("Failed to expand tenant tables", zap.Error(err))
+	}
+	if err := syncEngine.ValidateTargetPermissions(); err != nil {
+		logger.Fatal("Target schema validation failed", zap.Error(err))
+	}
+	syncEngine.WarnBatchedCommits()
+
+	if *backfillTable != "" {
+		if err := runBackfill(cfg, syncEngine, *backfillTable, *backfillRestart, logger); err != nil {
+			logger.Fatal("Backfill failed", zap.String("table", *backfillTable), zap.Error(err))
+		}
+		return
+	}
+
+	if *bootstrap {
+		if err := runBootstrap(cfg, syncEngine, logger); err != nil {
+			logger.Fatal("Bootstrap failed", zap.Error(err))
+		}
+		return
+	}
+
+	notifier := alert.NewNotifier(cfg.Alerts, logger)
 
 	actorSystem := actor.NewActorSystem()
 
 	coordinatorProps := actor.PropsFromProducer(func() actor.Actor {
-		return actorpkg.NewCoordinatorActor(syncEngine, cfg, logger, actorSystem)
-	})
-	coordinatorPID := actorSystem.Root.Spawn(coordinatorProps)
+		return actorpkg.NewCoordinatorActor(syncEngine, cfg, logs.For(logging.ModuleActor), actorSystem, notifier)
+	}, actorpkg.MailboxOptions("coordinator")...)
+
+	var coordinatorPID *actor.PID
+	if cfg.Cluster.Enabled {
+		// Remoting must start before the coordinator is spawned so its PID carries the node address
+		remoting, err := actorpkg.StartRemote(actorSystem, cfg.Cluster)
+		if err != nil {
+			logger.Fatal("Failed to start cluster remoting", zap.Error(err))
+		}
+		defer remoting.Shutdown(true)
+
+		coordinatorPID, err = actorSystem.Root.SpawnNamed(coordinatorProps, actorpkg.CoordinatorName)
+		if err != nil {
+			logger.Fatal("Failed to start coordinator", zap.Error(err))
+		}
+		logger.Info("Joined sync cluster",
+			zap.String("node_id", cfg.Cluster.NodeID),
+			zap.String("address", coordinatorPID.GetAddress()),
+			zap.Int("nodes", len(cfg.Cluster.Nodes)),
+		)
+	} else {
+		coordinatorPID = actorSystem.Root.Spawn(coordinatorProps)
+	}
 
-	apiServer := api.NewServer(cfg, logger, coordinatorPID, actorSystem, dbManager)
+	apiServer := api.NewServer(cfg, logs.For(logging.ModuleAPI), coordinatorPID, actorSystem, dbManager, syncEngine, historyStore, logs)
+	if err := apiServer.ValidateProjections(); err != nil {
+		logger.Fatal("Projection validation failed", zap.Error(err))
+	}
+
+	snapshotScheduler, err := snapshot.NewScheduler(cfg, apiServer.Handler, logger)
+	if err != nil {
+		logger.Fatal("Invalid snapshot configuration", zap.Error(err))
+	}
+	snapshotScheduler.Start()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -83,6 +203,8 @@ func main() {
 		logger.Info("API server exited")
 	}
 
+	snapshotScheduler.Stop()
+
 	stopFuture := actorSystem.Root.StopFuture(coordinatorPID)
 	if stopFuture != nil {
 		stopFuture.Wait()
@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import axios from 'axios';
 import './App.css';
+import { DEFAULT_UI_CONFIG, applyBranding, fetchUIConfig, isFeatureEnabled } from './uiConfig';
 
 const DEFAULT_CURRENCY = 'USD';
 const currencyFormatters = {};
@@ -49,6 +50,47 @@ const getRowValue = (row, column) => {
   return targetKey ? row[targetKey] : undefined;
 };
 
+// cellStyle returns the inline style of a cell annotated by a field rule
+const cellStyle = (annotation) => {
+  if (!annotation) {
+    return undefined;
+  }
+  return {
+    color: annotation.color,
+    background: annotation.badge ? undefined : annotation.background,
+    fontWeight: annotation.bold ? 700 : undefined,
+  };
+};
+
+// defaultSort returns the sort state of a projection's default_sort list; spec carries every column for the sort parameter
+const defaultSort = (projection) => {
+  const sorts = projection.default_sort || [];
+  return {
+    column: sorts[0]?.column || '',
+    direction: (sorts[0]?.direction || 'ASC').toUpperCase(),
+    spec: sorts.map((sort) => `${sort.column}:${(sort.direction || 'asc').toLowerCase()}`).join(','),
+  };
+};
+
+// visibleFields returns a projection's fields without the user's hidden columns, in their saved order
+const visibleFields = (projection, preferences) => {
+  const fields = projection.fields || [];
+  if (!preferences) {
+    return fields;
+  }
+  const hidden = new Set(preferences.hidden || []);
+  const order = preferences.order || [];
+  const position = (field) => {
+    const index = order.indexOf(field.column);
+    return index < 0 ? order.length : index;
+  };
+  return fields
+    .filter((field) => !hidden.has(field.column))
+    .map((field, index) => ({ field, index }))
+    .sort((a, b) => position(a.field) - position(b.field) || a.index - b.index)
+    .map(({ field }) => field);
+};
+
 const formatFieldValue = (value, type) => {
   if (value === null || value === undefined || value === '') {
     return '—';
@@ -116,27 +158,60 @@ function App() {
   const [error, setError] = useState(null);
   const [syncStatus, setSyncStatus] = useState({});
   const [lastRefresh, setLastRefresh] = useState(null);
+  const [projectionAvailability, setProjectionAvailability] = useState(null);
 
   const [projections, setProjections] = useState([]);
+  const [columnPreferences, setColumnPreferences] = useState({});
   const [projectionData, setProjectionData] = useState({});
   const [projectionFilters, setProjectionFilters] = useState({});
   const [projectionSorts, setProjectionSorts] = useState({});
   const [projectionLoading, setProjectionLoading] = useState({});
   const [projectionError, setProjectionError] = useState({});
   const [loadingProjections, setLoadingProjections] = useState(false);
+  const [filterOptions, setFilterOptions] = useState({});
+
+  const [uiConfig, setUIConfig] = useState(DEFAULT_UI_CONFIG);
+  const [activeMenuGroup, setActiveMenuGroup] = useState(null);
+  const [dashboards, setDashboards] = useState([]);
+  const [activeDashboard, setActiveDashboard] = useState(null);
 
   useEffect(() => {
     fetchStatus();
     fetchProjections();
+    fetchUIConfig()
+      .then((config) => {
+        setUIConfig(config);
+        applyBranding(config.branding);
+      })
+      .catch((err) => console.error('Error fetching UI config, using defaults:', err));
+    fetchDashboards();
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
+  // Poll table status and reload projection data at the intervals hinted by the backend
+  useEffect(() => {
+    const timers = [];
+    if (uiConfig.refresh.status > 0) {
+      timers.push(setInterval(fetchStatus, uiConfig.refresh.status * 1000));
+    }
+    if (uiConfig.refresh.projections > 0) {
+      timers.push(
+        setInterval(() => {
+          projections.forEach((projection) => fetchProjectionData(projection.id));
+        }, uiConfig.refresh.projections * 1000)
+      );
+    }
+    return () => timers.forEach(clearInterval);
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [uiConfig.refresh.status, uiConfig.refresh.projections, projections]);
+
   const fetchStatus = async () => {
     try {
       setLoading(true);
       setError(null);
       const response = await axios.get('/api/status');
       setTables(response.data.tables || []);
+      setProjectionAvailability(response.data.projections || null);
       setLastRefresh(new Date());
     } catch (err) {
       setError('Failed to fetch status: ' + (err.response?.data?.message || err.message));
@@ -153,21 +228,19 @@ function App() {
       const response = await axios.get('/api/projections');
       const configs = response.data.projections || [];
       setProjections(configs);
+      setColumnPreferences(response.data.preferences || {});
 
       const initialFilters = {};
       const initialSorts = {};
 
       configs.forEach((projection) => {
         initialFilters[projection.id] = {};
-        const defaultDirection = projection.default_sort?.direction?.toUpperCase() || 'ASC';
-        initialSorts[projection.id] = {
-          column: projection.default_sort?.column || '',
-          direction: defaultDirection,
-        };
+        initialSorts[projection.id] = defaultSort(projection);
       });
 
       setProjectionFilters(initialFilters);
       setProjectionSorts(initialSorts);
+      fetchFilterOptions(configs);
 
       await Promise.all(
         configs.map((projection) =>
@@ -186,6 +259,65 @@ function App() {
     }
   };
 
+  // fetchFilterOptions loads the values of select filters without configured options from the projection views
+  const fetchFilterOptions = (configs) => {
+    configs.forEach((projection) => {
+      (projection.filters || []).forEach(async (filter) => {
+        if ((filter.type || '').toLowerCase() !== 'select' || (filter.options || []).length > 0) {
+          return;
+        }
+        try {
+          const response = await axios.get(`/api/projections/${projection.id}/filters/${filter.id}/options`);
+          setFilterOptions((prev) => ({
+            ...prev,
+            [`${projection.id}/${filter.id}`]: response.data.options || [],
+          }));
+        } catch (err) {
+          console.error(`Error fetching options of filter ${filter.id}:`, err);
+        }
+      });
+    });
+  };
+
+  const fetchDashboards = async () => {
+    try {
+      const response = await axios.get('/api/dashboards');
+      setDashboards(response.data.dashboards || []);
+    } catch (err) {
+      console.error('Error fetching dashboards:', err);
+    }
+  };
+
+  // openDashboard shows a dashboard and loads its widgets with their default filters and sort
+  const openDashboard = (dashboard) => {
+    setActiveDashboard(dashboard.id);
+    dashboard.widgets.forEach((widget) => {
+      const projection = projections.find((item) => item.id === widget.projection);
+      if (!projection) {
+        return;
+      }
+
+      const filters = {};
+      Object.entries(widget.filters || {}).forEach(([filterId, value]) => {
+        const filterDef = (projection.filters || []).find((item) => item.id === filterId);
+        filters[filterId] = (filterDef?.type || '').toLowerCase() === 'select'
+          ? value.split(',').map((item) => item.trim()).filter(Boolean)
+          : value;
+      });
+
+      const options = { filters };
+      if (widget.sort) {
+        options.sort = { column: widget.sort.column, direction: (widget.sort.direction || 'ASC').toUpperCase() };
+      }
+      fetchProjectionData(projection.id, options);
+    });
+  };
+
+  const openMenuGroup = (groupId) => {
+    setActiveDashboard(null);
+    setActiveMenuGroup(groupId);
+  };
+
   const fetchProjectionData = async (projectionId, options = {}) => {
     const { filters: overrideFilters, sort: overrideSort, projectionOverride } = options;
     const projection = projectionOverride || projections.find((item) => item.id === projectionId);
@@ -195,10 +327,7 @@ function App() {
     }
 
     const activeFilters = overrideFilters !== undefined ? overrideFilters : projectionFilters[projectionId] || {};
-    const activeSort = overrideSort !== undefined ? overrideSort : projectionSorts[projectionId] || {
-      column: projection.default_sort?.column || '',
-      direction: (projection.default_sort?.direction || 'ASC').toUpperCase(),
-    };
+    const activeSort = overrideSort !== undefined ? overrideSort : projectionSorts[projectionId] || defaultSort(projection);
 
     setProjectionFilters((prev) => ({
       ...prev,
@@ -210,6 +339,7 @@ function App() {
       [projectionId]: {
         column: activeSort.column || '',
         direction: (activeSort.direction || 'ASC').toUpperCase(),
+        spec: activeSort.spec || '',
       },
     }));
 
@@ -219,7 +349,9 @@ function App() {
     try {
       const params = new URLSearchParams();
 
-      if (activeSort.column) {
+      if (activeSort.spec) {
+        params.append('sort', activeSort.spec);
+      } else if (activeSort.column) {
         params.append('sort', activeSort.column);
         params.append('direction', (activeSort.direction || 'ASC').toUpperCase());
       }
@@ -263,6 +395,7 @@ function App() {
       const metaSort = {
         column: responseData.meta?.sort_column || activeSort.column || '',
         direction: (responseData.meta?.sort_direction || activeSort.direction || 'ASC').toUpperCase(),
+        spec: responseData.meta?.sort || activeSort.spec || '',
       };
 
       setProjectionSorts((prev) => ({
@@ -428,6 +561,26 @@ function App() {
     }
 
     const activeSort = projectionSorts[projection.id] || {};
+    const descriptions = {};
+    (projectionData[projection.id]?.meta?.columns || []).forEach((meta) => {
+      if (meta.description) {
+        descriptions[meta.column.toLowerCase()] = meta.description;
+      }
+    });
+    // Annotations and actions are aligned with the response rows, which grouping splits up, so they are looked up by row
+    const annotationsByRow = new Map();
+    const actionsByRow = new Map();
+    const responseAnnotations = projectionData[projection.id]?.annotations || [];
+    const responseActions = projectionData[projection.id]?.actions || [];
+    (projectionData[projection.id]?.rows || []).forEach((row, index) => {
+      if (responseAnnotations[index]) {
+        annotationsByRow.set(row, responseAnnotations[index]);
+      }
+      if (responseActions[index]) {
+        actionsByRow.set(row, responseActions[index]);
+      }
+    });
+    const actions = projection.actions || [];
 
     return (
       <div className="projection-table-wrapper">
@@ -452,6 +605,7 @@ function App() {
                   <th
                     key={`${projection.id}-header-${field.column}`}
                     className={isSortable ? 'sortable' : ''}
+                    title={descriptions[(field.column || '').toLowerCase()]}
                     onClick={() => handleProjectionSort(projection, field)}
                   >
                     <span>{field.label || field.column}</span>
@@ -459,12 +613,13 @@ function App() {
                   </th>
                 );
               })}
+              {actions.length > 0 && <th className="projection-actions">Actions</th>}
             </tr>
           </thead>
           <tbody>
             {rows.length === 0 ? (
               <tr>
-                <td className="projection-empty" colSpan={fields.length}>
+                <td className="projection-empty" colSpan={fields.length + (actions.length > 0 ? 1 : 0)}>
                   No data available.
                 </td>
               </tr>
@@ -475,10 +630,15 @@ function App() {
                     const rawValue = getRowValue(row, field.column);
                     const displayValue = formatFieldValue(rawValue, field.type);
                     const fieldType = (field.type || '').toLowerCase();
+                    const annotation = annotationsByRow.get(row)?.[field.column];
 
                     return (
-                      <td key={`${projection.id}-row-${rowIndex}-${field.column}`}>
-                        {fieldType === 'badge' ? (
+                      <td key={`${projection.id}-row-${rowIndex}-${field.column}`} style={cellStyle(annotation)}>
+                        {annotation?.badge ? (
+                          <span className="projection-badge" style={{ background: annotation.background, color: annotation.color }}>
+                            {displayValue}
+                          </span>
+                        ) : fieldType === 'badge' ? (
                           <span className={`projection-badge badge-${String(displayValue).toLowerCase().replace(/\s+/g, '-')}`}>
                             {displayValue}
                           </span>
@@ -488,6 +648,23 @@ function App() {
                       </td>
                     );
                   })}
+                  {actions.length > 0 && (
+                    <td className="projection-actions">
+                      {actions
+                        .filter((action) => actionsByRow.get(row)?.[action.id])
+                        .map((action) => (
+                          <a
+                            key={`${projection.id}-row-${rowIndex}-action-${action.id}`}
+                            className="projection-action"
+                            href={actionsByRow.get(row)[action.id]}
+                            target={action.new_tab ? '_blank' : undefined}
+                            rel={action.new_tab ? 'noopener noreferrer' : undefined}
+                          >
+                            {action.label}
+                          </a>
+                        ))}
+                    </td>
+                  )}
                 </tr>
               ))
             )}
@@ -564,10 +741,10 @@ function App() {
     );
   };
 
-  const renderProjectionCard = (projection) => {
+  const renderProjectionCard = (projection, title) => {
     const rows = projectionData[projection.id]?.rows || [];
     const isLoading = projectionLoading[projection.id];
-    const fields = projection.fields || [];
+    const fields = visibleFields(projection, columnPreferences[projection.id]);
     const filters = projectionFilters[projection.id] || {};
 
     return (
@@ -580,7 +757,7 @@ function App() {
           }}
         >
           <div className="projection-card-title">
-            <h3>{projection.title || projection.target_view}</h3>
+            <h3>{title || projection.title || projection.target_view}</h3>
             {projection.description && <p>{projection.description}</p>}
           </div>
           <div className="projection-card-actions">
@@ -591,7 +768,7 @@ function App() {
             >
               {isLoading ? '⏳ Loading...' : '🔁 Reload Data'}
             </button>
-            {projection.sync_table && (
+            {projection.sync_table && isFeatureEnabled(uiConfig, 'table_sync') && (
               <button
                 className="btn btn-sm btn-primary"
                 onClick={() => triggerTableSync(projection.sync_table, projection.id)}
@@ -621,7 +798,7 @@ function App() {
                           handleProjectionFilterChange(projection, filter, options, true);
                         }}
                       >
-                        {(filter.options || []).map((option) => (
+                        {(filter.options?.length ? filter.options : filterOptions[`${projection.id}/${filter.id}`] || []).map((option) => (
                           <option key={option.value} value={option.value}>
                             {option.label || option.value}
                           </option>
@@ -681,6 +858,19 @@ function App() {
             <div className="projection-empty">No records found for the current filters.</div>
           )}
 
+          {projectionData[projection.id]?.stale && (
+            <div className="projection-stale">
+              Showing cached results from {new Date(projectionData[projection.id].cached_at).toLocaleString()}; the
+              database is currently unavailable.
+            </div>
+          )}
+
+          {projectionData[projection.id]?.meta?.truncated && (
+            <div className="projection-truncated">
+              Showing the first {projectionData[projection.id].meta.max_rows} rows. Narrow the filters to see the rest.
+            </div>
+          )}
+
           {isLoading && rows.length > 0 && (
             <div className="projection-loading-overlay">Refreshing…</div>
           )}
@@ -690,12 +880,27 @@ function App() {
     );
   };
 
+  // The backend lists every projection in some menu group; without a menu all projections are shown together
+  const menuGroups = uiConfig.menu;
+  const currentDashboard = dashboards.find((dashboard) => dashboard.id === activeDashboard);
+  const currentMenuGroup = currentDashboard
+    ? null
+    : menuGroups.find((group) => group.id === activeMenuGroup) || menuGroups[0];
+  const visibleProjections = currentMenuGroup
+    ? currentMenuGroup.projections
+        .map((entry) => projections.find((projection) => projection.id === entry.id))
+        .filter(Boolean)
+    : projections;
+
   return (
     <div className="App">
       <div className="container">
         <header className="header">
-          <h1>🔄 MSSQL → PostgreSQL Sync Service</h1>
-          <p className="subtitle">Real-time database synchronization dashboard</p>
+          {uiConfig.branding.logo_url && (
+            <img className="header-logo" src={uiConfig.branding.logo_url} alt="" />
+          )}
+          <h1>{uiConfig.branding.title}</h1>
+          {uiConfig.branding.subtitle && <p className="subtitle">{uiConfig.branding.subtitle}</p>}
         </header>
 
         {error && (
@@ -705,6 +910,19 @@ function App() {
           </div>
         )}
 
+        {projectionAvailability && !projectionAvailability.available && (
+          <div className="alert alert-warning">
+            <span className="alert-icon">🔌</span>
+            The target database is unavailable
+            {projectionAvailability.unavailable_since &&
+              ` since ${new Date(projectionAvailability.unavailable_since).toLocaleTimeString()}`}
+            .{' '}
+            {projectionAvailability.serving_cached
+              ? 'Projections show their last cached results where available.'
+              : 'Projection data cannot be loaded until it recovers.'}
+          </div>
+        )}
+
         <div className="actions-bar">
           <button
             onClick={fetchStatus}
@@ -713,106 +931,177 @@ function App() {
           >
             {loading ? '⏳ Loading...' : '🔃 Refresh Status'}
           </button>
-          <button
-            onClick={triggerAllSync}
-            className="btn btn-primary"
-            disabled={loading}
-          >
-            {loading ? '⏳ Syncing...' : '⚡ Sync All Tables'}
-          </button>
-          <button
-            onClick={fetchProjections}
-            className="btn btn-secondary"
-            disabled={loadingProjections}
-          >
-            {loadingProjections ? '⏳ Loading...' : '🧭 Reload Projections'}
-          </button>
+          {isFeatureEnabled(uiConfig, 'sync_all') && (
+            <button
+              onClick={triggerAllSync}
+              className="btn btn-primary"
+              disabled={loading}
+            >
+              {loading ? '⏳ Syncing...' : '⚡ Sync All Tables'}
+            </button>
+          )}
+          {isFeatureEnabled(uiConfig, 'projections') && (
+            <button
+              onClick={fetchProjections}
+              className="btn btn-secondary"
+              disabled={loadingProjections}
+            >
+              {loadingProjections ? '⏳ Loading...' : '🧭 Reload Projections'}
+            </button>
+          )}
           {lastRefresh && (
             <span className="last-refresh">Last updated: {lastRefresh.toLocaleTimeString()}</span>
           )}
         </div>
 
-        <div className="table-grid">
-          {tables.length === 0 ? (
-            <div className="empty-state">
-              <p>No tables configured for synchronization.</p>
-              <p className="empty-state-hint">Check your configuration file.</p>
-            </div>
-          ) : (
-            tables.map((table) => (
-              <div key={table.target_table} className="table-card">
-                <div className="table-card-header">
-                  <h3>{table.target_table}</h3>
-                  {syncStatus[table.target_table] && (
-                    <span className={`badge ${getStatusBadgeClass(table.target_table)}`}>
-                      {getStatusBadgeText(table.target_table)}
-                    </span>
-                  )}
-                </div>
-
-                <div className="table-card-body">
-                  <div className="table-info-row">
-                    <span className="label">Source:</span>
-                    <span className="value">{table.source_table}</span>
-                  </div>
-                  <div className="table-info-row">
-                    <span className="label">Target:</span>
-                    <span className="value">{table.target_table}</span>
-                  </div>
-                  <div className="table-info-row">
-                    <span className="label">Refresh Rate:</span>
-                    <span className="value">{table.refresh_rate}s</span>
+        {isFeatureEnabled(uiConfig, 'table_status') && (
+          <div className="table-grid">
+            {tables.length === 0 ? (
+              <div className="empty-state">
+                <p>No tables configured for synchronization.</p>
+                <p className="empty-state-hint">Check your configuration file.</p>
+              </div>
+            ) : (
+              tables.map((table) => (
+                <div key={table.target_table} className="table-card">
+                  <div className="table-card-header">
+                    <h3>{table.target_table}</h3>
+                    {syncStatus[table.target_table] && (
+                      <span className={`badge ${getStatusBadgeClass(table.target_table)}`}>
+                        {getStatusBadgeText(table.target_table)}
+                      </span>
+                    )}
                   </div>
 
-                  <div className="table-features">
-                    <span className={`feature-badge ${table.proto_actor_enabled ? 'enabled' : 'disabled'}`}>
-                      {table.proto_actor_enabled ? '✓' : '✗'} Auto Sync
-                    </span>
-                    <span className={`feature-badge ${table.web_api_enabled ? 'enabled' : 'disabled'}`}>
-                      {table.web_api_enabled ? '✓' : '✗'} API Trigger
-                    </span>
+                  <div className="table-card-body">
+                    <div className="table-info-row">
+                      <span className="label">Source:</span>
+                      <span className="value">{table.source_table}</span>
+                    </div>
+                    <div className="table-info-row">
+                      <span className="label">Target:</span>
+                      <span className="value">{table.target_table}</span>
+                    </div>
+                    <div className="table-info-row">
+                      <span className="label">Refresh Rate:</span>
+                      <span className="value">{table.refresh_rate}s</span>
+                    </div>
+                    <div className="table-info-row">
+                      <span className="label">Last Sync:</span>
+                      <span className="value">
+                        {table.last_sync ? new Date(table.last_sync).toLocaleString() : 'Never'}
+                      </span>
+                    </div>
+                    {table.last_error && (
+                      <div className="table-info-row">
+                        <span className="label">Last Error:</span>
+                        <span className="value error-text" title={table.last_error}>{table.last_error}</span>
+                      </div>
+                    )}
+
+                    <div className="table-features">
+                      <span className={`feature-badge ${table.proto_actor_enabled ? 'enabled' : 'disabled'}`}>
+                        {table.proto_actor_enabled ? '✓' : '✗'} Auto Sync
+                      </span>
+                      <span className={`feature-badge ${table.web_api_enabled ? 'enabled' : 'disabled'}`}>
+                        {table.web_api_enabled ? '✓' : '✗'} API Trigger
+                      </span>
+                    </div>
                   </div>
-                </div>
 
-                <div className="table-card-footer">
-                  {table.web_api_enabled ? (
-                    <button
-                      onClick={() => triggerTableSync(table.target_table)}
-                      className="btn btn-sm btn-primary"
-                      disabled={syncStatus[table.target_table] === 'syncing'}
-                    >
-                      {syncStatus[table.target_table] === 'syncing' ? '⏳ Syncing...' : '🔄 Sync Now'}
-                    </button>
-                  ) : (
-                    <span className="disabled-text">Manual sync disabled</span>
+                  {isFeatureEnabled(uiConfig, 'table_sync') && (
+                    <div className="table-card-footer">
+                      {table.web_api_enabled ? (
+                        <button
+                          onClick={() => triggerTableSync(table.target_table)}
+                          className="btn btn-sm btn-primary"
+                          disabled={syncStatus[table.target_table] === 'syncing'}
+                        >
+                          {syncStatus[table.target_table] === 'syncing' ? '⏳ Syncing...' : '🔄 Sync Now'}
+                        </button>
+                      ) : (
+                        <span className="disabled-text">Manual sync disabled</span>
+                      )}
+                    </div>
                   )}
                 </div>
-              </div>
-            ))
-          )}
-        </div>
-
-        <section className="projections-section">
-          <div className="projections-header">
-            <h2>Projection Views</h2>
-            {loadingProjections && <span className="projection-status">Loading projections…</span>}
+              ))
+            )}
           </div>
+        )}
 
-          {projections.length === 0 && !loadingProjections ? (
-            <div className="empty-state">
-              <p>No projection views configured.</p>
-              <p className="empty-state-hint">Add projection definitions to your YAML config.</p>
+        {isFeatureEnabled(uiConfig, 'projections') && (
+          <section className="projections-section">
+            <div className="projections-header">
+              <h2>Projection Views</h2>
+              {loadingProjections && <span className="projection-status">Loading projections…</span>}
             </div>
-          ) : (
-            <div className="projection-grid">
-              {projections.map((projection) => renderProjectionCard(projection))}
-            </div>
-          )}
-        </section>
 
-        <footer className="footer">
-          <p>Powered by Proto.Actor, Gin, and React.js</p>
-        </footer>
+            {projections.length === 0 && !loadingProjections ? (
+              <div className="empty-state">
+                <p>No projection views configured.</p>
+                <p className="empty-state-hint">Add projection definitions to your YAML config.</p>
+              </div>
+            ) : (
+              <>
+                {menuGroups.length + dashboards.length > 1 && (
+                  <nav className="projection-menu">
+                    {dashboards.map((dashboard) => (
+                      <button
+                        key={`dashboard-${dashboard.id}`}
+                        className={`btn btn-sm ${dashboard.id === currentDashboard?.id ? 'btn-primary' : 'btn-secondary'}`}
+                        onClick={() => openDashboard(dashboard)}
+                        title={dashboard.description}
+                      >
+                        <span className="projection-menu-icon">📊</span>
+                        {dashboard.title || dashboard.id}
+                      </button>
+                    ))}
+                    {menuGroups.map((group) => (
+                      <button
+                        key={group.id}
+                        className={`btn btn-sm ${group.id === currentMenuGroup?.id ? 'btn-primary' : 'btn-secondary'}`}
+                        onClick={() => openMenuGroup(group.id)}
+                      >
+                        {group.icon && <span className="projection-menu-icon">{group.icon}</span>}
+                        {group.label}
+                      </button>
+                    ))}
+                  </nav>
+                )}
+                {currentDashboard ? (
+                  <div
+                    className="dashboard-grid"
+                    style={{ gridTemplateColumns: `repeat(${currentDashboard.columns}, minmax(0, 1fr))` }}
+                  >
+                    {currentDashboard.widgets.map((widget, index) => {
+                      const projection = projections.find((item) => item.id === widget.projection);
+                      return projection ? (
+                        <div
+                          key={`${widget.projection}-${index}`}
+                          className="dashboard-widget"
+                          style={{ gridColumn: `span ${widget.width}` }}
+                        >
+                          {renderProjectionCard(projection, widget.title)}
+                        </div>
+                      ) : null;
+                    })}
+                  </div>
+                ) : (
+                  <div className="projection-grid">
+                    {visibleProjections.map((projection) => renderProjectionCard(projection))}
+                  </div>
+                )}
+              </>
+            )}
+          </section>
+        )}
+
+        {uiConfig.branding.footer && (
+          <footer className="footer">
+            <p>{uiConfig.branding.footer}</p>
+          </footer>
+        )}
       </div>
     </div>
   );
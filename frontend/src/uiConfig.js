@@ -0,0 +1,47 @@
+import axios from 'axios';
+
+// Used until /api/ui-config answers, and when it cannot be reached
+export const DEFAULT_UI_CONFIG = {
+  branding: {
+    title: 'MSSQL → PostgreSQL Sync Service',
+    subtitle: 'Real-time database synchronization dashboard',
+    footer: 'Powered by Proto.Actor, Gin, and React.js',
+  },
+  menu: [],
+  refresh: { status: 30, projections: 0 },
+  features: {
+    table_status: true,
+    table_sync: true,
+    sync_all: true,
+    projections: true,
+  },
+};
+
+// fetchUIConfig loads the branding, projection menu, refresh hints and feature flags served by the backend
+export const fetchUIConfig = async () => {
+  const response = await axios.get('/api/ui-config');
+  const config = response.data || {};
+  return {
+    branding: { ...DEFAULT_UI_CONFIG.branding, ...config.branding },
+    menu: config.menu || [],
+    refresh: { ...DEFAULT_UI_CONFIG.refresh, ...config.refresh },
+    features: { ...DEFAULT_UI_CONFIG.features, ...config.features },
+  };
+};
+
+// isFeatureEnabled treats flags the backend does not know about as enabled
+export const isFeatureEnabled = (config, name) => config.features?.[name] !== false;
+
+// applyBranding sets the document title and the theme colors used by the stylesheets
+export const applyBranding = (branding) => {
+  if (branding.title) {
+    document.title = branding.title;
+  }
+  const root = document.documentElement;
+  if (branding.primary_color) {
+    root.style.setProperty('--primary-color', branding.primary_color);
+  }
+  if (branding.accent_color) {
+    root.style.setProperty('--accent-color', branding.accent_color);
+  }
+};
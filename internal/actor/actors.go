@@ -0,0 +1,186 @@
+package actor
+
+import (
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+)
+
+// Sync actor states reported by GET /api/actors
+const (
+	ActorIdle      = "idle"
+	ActorSyncing   = "syncing"
+	ActorScheduled = "scheduled"
+	ActorStopped   = "stopped"
+)
+
+// actorStateMessage reports a sync actor state transition to the coordinator
+type actorStateMessage struct {
+	TableName string
+	State     string
+	NextRun   time.Time
+	JobID     string     // job of the sync that started, when State is syncing
+	Actor     *actor.PID // the reporting sync actor or pool worker
+}
+
+// GetActorsMessage requests the state of every sync actor from the coordinator
+type GetActorsMessage struct{}
+
+// ActorsResponse is the coordinator's reply to GetActorsMessage
+type ActorsResponse struct {
+	Actors      []ActorInfo   `json:"actors"`
+	Mailboxes   []MailboxInfo `json:"mailboxes"`    // every instrumented actor, deepest mailbox first
+	DeadLetters int64         `json:"dead_letters"` // messages sent to stopped or unknown actors since startup
+}
+
+// ActorInfo describes a sync actor as seen by the coordinator
+type ActorInfo struct {
+	Table      string       `json:"table"`
+	Family     string       `json:"family,omitempty"` // tenant template the table was expanded from
+	Tenant     string       `json:"tenant,omitempty"`
+	Actor      string       `json:"actor"`
+	State      string       `json:"state"`
+	Mailbox    int64        `json:"mailbox_depth"`
+	NextRun    *time.Time   `json:"next_run,omitempty"`
+	LastResult *ActorResult `json:"last_result,omitempty"`
+	Restarts   int          `json:"restarts"`
+	LastCrash  string       `json:"last_crash,omitempty"`
+}
+
+// ActorResult summarizes the last sync result of an actor
+type ActorResult struct {
+	Status      string    `json:"status"` // success, failure, skipped or unchanged
+	At          time.Time `json:"at"`
+	DurationMs  int64     `json:"duration_ms"`
+	RowsSynced  int       `json:"rows_synced"`
+	RowsRead    int       `json:"rows_read"`
+	RowsSkipped int       `json:"rows_skipped"`
+	BytesRead   int64     `json:"bytes_read"`
+	Estimated   int64     `json:"rows_estimated,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// reportState tells the coordinator about the actor's current state
+func (a *SyncActor) reportState(ctx actor.Context, state string) {
+	if ctx.Parent() == nil {
+		return
+	}
+	ctx.Send(ctx.Parent(), &actorStateMessage{
+		TableName: a.tableConfig.TargetTable,
+		State:     state,
+		NextRun:   a.nextRun,
+		JobID:     a.runningJob,
+		Actor:     ctx.Self(),
+	})
+}
+
+// restingState returns the state of the actor when it is not syncing
+func (a *SyncActor) restingState() string {
+	if a.nextRun.After(time.Now()) {
+		return ActorScheduled
+	}
+	return ActorIdle
+}
+
+// recordActorState updates the table state from a sync actor state report
+func (c *CoordinatorActor) recordActorState(msg *actorStateMessage) {
+	if c.replacedActor(msg) {
+		return
+	}
+	if msg.State == ActorSyncing {
+		c.startQueuedRun(msg.TableName, msg.JobID)
+	}
+	state, ok := c.tableStates[msg.TableName]
+	if !ok {
+		return
+	}
+	if msg.State == ActorSyncing && state.ActorState != ActorSyncing {
+		state.SyncingSince = time.Now()
+	}
+	// Any report shows the actor is processing messages
+	state.PingSentAt = time.Time{}
+	state.ActorState = msg.State
+	state.NextRun = msg.NextRun
+}
+
+// actorsSnapshot lists the sync actors in configuration order
+func (c *CoordinatorActor) actorsSnapshot() *ActorsResponse {
+	response := &ActorsResponse{Actors: make([]ActorInfo, 0, len(c.tableStates))}
+	for _, tc := range c.config.Tables {
+		actorID, ok := c.tableActor(tc.TargetTable)
+		state, hasState := c.tableStates[tc.TargetTable]
+		if !ok || !hasState {
+			continue
+		}
+
+		info := ActorInfo{
+			Table:     tc.TargetTable,
+			Family:    tc.Family,
+			Tenant:    tc.Tenant,
+			Actor:     actorID,
+			State:     state.ActorState,
+			Mailbox:   mailboxDepth(syncActorName(tc.TargetTable)),
+			Restarts:  state.Restarts,
+			LastCrash: state.LastCrash,
+		}
+		if info.State == "" {
+			info.State = ActorIdle
+		}
+		if state.ActorStopped {
+			info.State = ActorStopped
+		} else if info.State == ActorScheduled && !state.NextRun.IsZero() {
+			nextRun := state.NextRun
+			info.NextRun = &nextRun
+		}
+		if state.LastResult != nil {
+			lastResult := *state.LastResult
+			info.LastResult = &lastResult
+		}
+		response.Actors = append(response.Actors, info)
+	}
+	response.Mailboxes = MailboxSnapshot()
+	response.DeadLetters = DeadLetterCount()
+	return response
+}
+
+// tableActor returns the id of the actor syncing a table: its sync actor, or with the worker pool
+// the worker currently syncing it, "pool" while it waits
+func (c *CoordinatorActor) tableActor(tableName string) (string, bool) {
+	if c.pool == nil {
+		pid, ok := c.syncActors[tableName]
+		return pid.GetId(), ok
+	}
+	if _, ok := c.pool.tables[tableName]; !ok {
+		return "", false
+	}
+	if worker, busy := c.poolWorkerFor(tableName); busy {
+		return worker, true
+	}
+	return "pool", true
+}
+
+// actorResult converts a sync result message into the summary kept for GET /api/actors
+func actorResult(msg *SyncResultMessage) *ActorResult {
+	result := &ActorResult{
+		Status:      "success",
+		At:          time.Now(),
+		DurationMs:  msg.Duration.Milliseconds(),
+		RowsSynced:  msg.RowsSynced,
+		RowsRead:    msg.RowsRead,
+		RowsSkipped: msg.RowsSkipped,
+		BytesRead:   msg.BytesRead,
+		Estimated:   msg.Estimated,
+	}
+	switch {
+	case msg.Unchanged:
+		result.Status = "unchanged"
+	case msg.Skipped:
+		result.Status = "skipped"
+	case !msg.Success:
+		result.Status = "failure"
+	}
+	if msg.Error != nil {
+		result.Error = msg.Error.Error()
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// StartBackfillMessage starts or resumes the backfill of a table
+type StartBackfillMessage struct {
+	TableName string
+	Restart   bool // discard saved progress and start over from the configured dates
+}
+
+// StopBackfillMessage stops a running backfill, keeping its progress
+type StopBackfillMessage struct {
+	TableName string
+}
+
+// BackfillResponse answers a start or stop request with the backfill's progress
+type BackfillResponse struct {
+	Backfill *history.Backfill
+	Error    string
+}
+
+// backfillDoneMessage reports that a backfill goroutine returned
+type backfillDoneMessage struct {
+	TableName string
+	Err       error
+}
+
+// BackfillActor runs table backfills in the background, one goroutine per table, apart from the regular refresh
+type BackfillActor struct {
+	syncEngine  *syncpkg.SyncEngine
+	tables      []config.TableConfig
+	logger      *zap.Logger
+	actorSystem *actor.ActorSystem
+	running     map[string]context.CancelCauseFunc
+}
+
+// NewBackfillActor creates a new backfill actor
+func NewBackfillActor(syncEngine *syncpkg.SyncEngine, tables []config.TableConfig, logger *zap.Logger, actorSystem *actor.ActorSystem) actor.Actor {
+	return &BackfillActor{
+		syncEngine:  syncEngine,
+		tables:      tables,
+		logger:      logger,
+		actorSystem: actorSystem,
+		running:     make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// Receive handles incoming messages
+func (a *BackfillActor) Receive(ctx actor.Context) {
+	switch msg := ctx.Message().(type) {
+	case *actor.Started:
+		a.logger.Info("BackfillActor started")
+		a.resumeInterrupted(ctx)
+
+	case *StartBackfillMessage:
+		progress, err := a.start(ctx, msg.TableName, msg.Restart)
+		if err != nil {
+			ctx.Respond(&BackfillResponse{Error: err.Error()})
+			return
+		}
+		ctx.Respond(&BackfillResponse{Backfill: progress})
+
+	case *StopBackfillMessage:
+		cancel, ok := a.running[msg.TableName]
+		if !ok {
+			ctx.Respond(&BackfillResponse{Error: fmt.Sprintf("no backfill running for %s", msg.TableName)})
+			return
+		}
+		// The entry is removed once the backfill has saved its progress and returned
+		cancel(syncpkg.ErrBackfillStopped)
+		ctx.Respond(&BackfillResponse{})
+
+	case *backfillDoneMessage:
+		delete(a.running, msg.TableName)
+		if msg.Err != nil && !errors.Is(msg.Err, context.Canceled) && !errors.Is(msg.Err, syncpkg.ErrBackfillStopped) {
+			a.logger.Error("Backfill failed", zap.String("table", msg.TableName), zap.Error(msg.Err))
+		}
+
+	case *actor.Stopping, *actor.Restarting:
+		// Interrupted backfills stay running in the history database and resume on the next start
+		for _, cancel := range a.running {
+			cancel(context.Canceled)
+		}
+
+	case *actor.Stopped:
+		a.logger.Info("BackfillActor stopped")
+	}
+}
+
+// start loads the progress a table's backfill continues from and runs it in the background
+func (a *BackfillActor) start(ctx actor.Context, tableName string, restart bool) (*history.Backfill, error) {
+	tc, ok := a.tableConfig(tableName)
+	if !ok {
+		return nil, fmt.Errorf("table %s has no backfill configured on this node", tableName)
+	}
+	if _, running := a.running[tableName]; running {
+		return nil, fmt.Errorf("backfill of %s is already running", tableName)
+	}
+
+	progress, err := a.syncEngine.StartBackfill(tc, restart)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *progress
+
+	runCtx, cancel := context.WithCancelCause(context.Background())
+	a.running[tableName] = cancel
+	pid := ctx.Self()
+	go func() {
+		err := a.syncEngine.Backfill(runCtx, tc, progress)
+		a.actorSystem.Root.Send(pid, &backfillDoneMessage{TableName: tableName, Err: err})
+	}()
+	return &snapshot, nil
+}
+
+// resumeInterrupted restarts the backfills that were running when the service last stopped
+func (a *BackfillActor) resumeInterrupted(ctx actor.Context) {
+	for _, tc := range a.tables {
+		progress, err := a.syncEngine.History.GetBackfill(tc.TargetTable)
+		if err != nil {
+			a.logger.Warn("Failed to load backfill progress", zap.String("table", tc.TargetTable), zap.Error(err))
+			continue
+		}
+		if progress == nil || progress.Status != history.BackfillRunning {
+			continue
+		}
+		if _, err := a.start(ctx, tc.TargetTable, false); err != nil {
+			a.logger.Error("Failed to resume backfill", zap.String("table", tc.TargetTable), zap.Error(err))
+			continue
+		}
+		a.logger.Info("Resumed backfill", zap.String("table", tc.TargetTable), zap.Time("next", progress.Next))
+	}
+}
+
+func (a *BackfillActor) tableConfig(tableName string) (config.TableConfig, bool) {
+	for _, tc := range a.tables {
+		if tc.TargetTable == tableName {
+			return tc, true
+		}
+	}
+	return config.TableConfig{}, false
+}
+
+// backfillTables returns the tables with a backfill configured
+func backfillTables(tables []config.TableConfig) []config.TableConfig {
+	var backfilled []config.TableConfig
+	for _, tc := range tables {
+		if tc.Backfill != nil {
+			backfilled = append(backfilled, tc)
+		}
+	}
+	return backfilled
+}
+
+// startBackfillActor starts the backfill actor when any local table has a backfill configured
+func (c *CoordinatorActor) startBackfillActor(ctx actor.Context) {
+	tables := backfillTables(c.localTables())
+	if len(tables) == 0 || c.syncEngine.History == nil {
+		return
+	}
+
+	props := actor.PropsFromProducer(func() actor.Actor {
+		return NewBackfillActor(c.syncEngine, tables, c.logger, c.actorSystem)
+	}, MailboxOptions("backfill")...)
+	pid, err := ctx.SpawnNamed(props, "backfill")
+	if err != nil {
+		c.logger.Error("Failed to start backfill actor", zap.Error(err))
+		return
+	}
+	c.backfillPID = pid
+}
+
+// forwardBackfill passes a backfill request to the backfill actor, which responds to the original sender
+func (c *CoordinatorActor) forwardBackfill(ctx actor.Context) {
+	if c.backfillPID == nil {
+		ctx.Respond(&BackfillResponse{Error: "no table has a backfill configured on this node"})
+		return
+	}
+	ctx.Forward(c.backfillPID)
+}
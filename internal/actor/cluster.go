@@ -0,0 +1,246 @@
+package actor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"github.com/asynkron/protoactor-go/remote"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// CoordinatorName is the name the coordinator is spawned under in cluster mode, so other nodes can address it
+const CoordinatorName = "coordinator"
+
+// remoteSyncTimeout bounds how long a job waits for another node to sync a table. It covers a
+// scheduled sync already running on the owner followed by the requested one
+const remoteSyncTimeout = 30 * time.Minute
+
+// Type URLs of the messages exchanged between coordinators
+const (
+	remoteSyncRequestType = "mssql-postgres-sync/remoteSyncRequest"
+	remoteSyncResultType  = "mssql-postgres-sync/remoteSyncResult"
+)
+
+// remoteSyncRequest asks the coordinator owning a table to sync it for a job running on another node
+type remoteSyncRequest struct {
+	TableName string `json:"table_name"`
+	JobID     string `json:"job_id"`
+}
+
+// remoteSyncResult answers a remoteSyncRequest once the owner's sync actor has finished
+type remoteSyncResult struct {
+	TableName  string `json:"table_name"`
+	JobID      string `json:"job_id"`
+	Success    bool   `json:"success"`
+	Skipped    bool   `json:"skipped"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	RowsSynced int    `json:"rows_synced"`
+	RowsRead   int    `json:"rows_read"`
+	BytesRead  int64  `json:"bytes_read"`
+	Estimated  int64  `json:"rows_estimated,omitempty"`
+}
+
+// StartRemote starts actor remoting on this node's configured address
+func StartRemote(actorSystem *actor.ActorSystem, cfg config.ClusterConfig) (*remote.Remote, error) {
+	node, ok := cfg.Node(cfg.NodeID)
+	if !ok {
+		return nil, fmt.Errorf("cluster node %s is not configured", cfg.NodeID)
+	}
+	port, err := node.Port()
+	if err != nil {
+		return nil, err
+	}
+
+	remoting := remote.NewRemote(actorSystem, remote.Configure(cfg.GetBindHost(), port, remote.WithAdvertisedHost(node.Address)))
+	remoting.Start()
+	return remoting, nil
+}
+
+// encodeClusterMessage wraps a message for another node. Remoting only carries protobuf
+// messages, so the JSON payload travels inside an Any tagged with the message type
+func encodeClusterMessage(typeURL string, msg interface{}) (*anypb.Any, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &anypb.Any{TypeUrl: typeURL, Value: payload}, nil
+}
+
+// isLocalTable reports whether the table's sync actor runs on this node
+func (c *CoordinatorActor) isLocalTable(tc config.TableConfig) bool {
+	return c.config.Cluster.IsLocal(c.config.Cluster.TableOwner(tc))
+}
+
+// localTables returns the tables whose sync actors run on this node
+func (c *CoordinatorActor) localTables() []config.TableConfig {
+	if !c.config.Cluster.Enabled {
+		return c.config.Tables
+	}
+	tables := make([]config.TableConfig, 0, len(c.config.Tables))
+	for _, tc := range c.config.Tables {
+		if c.isLocalTable(tc) {
+			tables = append(tables, tc)
+		}
+	}
+	return tables
+}
+
+// dispatchRemote asks the coordinator of the node owning a table to sync it and completes the
+// job table when that node replies, fails to answer within remoteSyncTimeout or cannot be reached
+func (c *CoordinatorActor) dispatchRemote(ctx actor.Context, jobID string, tc config.TableConfig) error {
+	owner := c.config.Cluster.TableOwner(tc)
+	node, _ := c.config.Cluster.Node(owner)
+
+	request, err := encodeClusterMessage(remoteSyncRequestType, &remoteSyncRequest{
+		TableName: tc.TargetTable,
+		JobID:     jobID,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("Forwarding table sync to owning node",
+		zap.String("job_id", jobID),
+		zap.String("table", tc.TargetTable),
+		zap.String("node", owner),
+	)
+
+	tableName := tc.TargetTable
+	future := ctx.RequestFuture(actor.NewPID(node.Address, CoordinatorName), request, remoteSyncTimeout)
+	ctx.ReenterAfter(future, func(res interface{}, err error) {
+		result := &SyncResultMessage{TableName: tableName, JobID: jobID}
+		if err == nil {
+			err = decodeRemoteResult(res, result)
+		}
+		if err != nil {
+			result.Error = fmt.Errorf("node %s: %w", owner, err)
+		}
+		c.completeJobTable(ctx, result)
+	})
+	return nil
+}
+
+// decodeRemoteResult fills a sync result from another node's reply
+func decodeRemoteResult(res interface{}, result *SyncResultMessage) error {
+	msg, ok := res.(*anypb.Any)
+	if !ok || msg.GetTypeUrl() != remoteSyncResultType {
+		return fmt.Errorf("unexpected reply %T", res)
+	}
+
+	var reply remoteSyncResult
+	if err := json.Unmarshal(msg.GetValue(), &reply); err != nil {
+		return err
+	}
+	result.Success = reply.Success
+	result.Skipped = reply.Skipped
+	result.Duration = time.Duration(reply.DurationMs) * time.Millisecond
+	result.RowsSynced = reply.RowsSynced
+	result.RowsRead = reply.RowsRead
+	result.BytesRead = reply.BytesRead
+	result.Estimated = reply.Estimated
+	if reply.Error != "" {
+		result.Error = errors.New(reply.Error)
+	}
+	return nil
+}
+
+// handleClusterMessage handles a message from another node's coordinator
+func (c *CoordinatorActor) handleClusterMessage(ctx actor.Context, msg *anypb.Any) {
+	if msg.GetTypeUrl() != remoteSyncRequestType {
+		c.logger.Warn("Unknown cluster message", zap.String("type", msg.GetTypeUrl()))
+		return
+	}
+
+	var request remoteSyncRequest
+	if err := json.Unmarshal(msg.GetValue(), &request); err != nil {
+		c.logger.Warn("Invalid cluster sync request", zap.Error(err))
+		return
+	}
+	if ctx.Sender() == nil {
+		return
+	}
+
+	tc, ok := c.tableConfig(request.TableName)
+	if !ok || !c.hasSyncActor(request.TableName) {
+		c.replyRemote(ctx.Sender(), &SyncResultMessage{
+			TableName: request.TableName,
+			JobID:     request.JobID,
+			Error:     fmt.Errorf("sync actor not found on node %s", c.config.Cluster.NodeID),
+		})
+		return
+	}
+
+	c.logger.Info("Syncing table for remote job",
+		zap.String("job_id", request.JobID),
+		zap.String("table", request.TableName),
+		zap.String("requester", ctx.Sender().GetAddress()),
+	)
+	c.remoteSyncs[remoteSyncKey(request.JobID, request.TableName)] = ctx.Sender()
+	switch c.resolveOverlap(tc, request.JobID) {
+	case OverlapSkipped:
+		c.completeRemoteSync(&SyncResultMessage{
+			TableName: request.TableName,
+			JobID:     request.JobID,
+			Skipped:   true,
+			Error:     errAlreadySyncing,
+		})
+	case OverlapCoalesced:
+		// Answered with the result of the pending run it joined
+	default:
+		c.sendTableSync(ctx, tc, request.JobID)
+	}
+}
+
+// completeRemoteSync replies to the node that requested a sync, reporting whether the result belonged to one
+func (c *CoordinatorActor) completeRemoteSync(msg *SyncResultMessage) bool {
+	key := remoteSyncKey(msg.JobID, msg.TableName)
+	requester, ok := c.remoteSyncs[key]
+	if !ok {
+		return false
+	}
+	delete(c.remoteSyncs, key)
+	c.replyRemote(requester, msg)
+	return true
+}
+
+// replyRemote sends a sync result to a requesting node
+func (c *CoordinatorActor) replyRemote(requester *actor.PID, msg *SyncResultMessage) {
+	reply := &remoteSyncResult{
+		TableName:  msg.TableName,
+		JobID:      msg.JobID,
+		Success:    msg.Success,
+		Skipped:    msg.Skipped,
+		DurationMs: msg.Duration.Milliseconds(),
+		RowsSynced: msg.RowsSynced,
+		RowsRead:   msg.RowsRead,
+		BytesRead:  msg.BytesRead,
+		Estimated:  msg.Estimated,
+	}
+	if msg.Error != nil {
+		reply.Error = msg.Error.Error()
+	}
+
+	encoded, err := encodeClusterMessage(remoteSyncResultType, reply)
+	if err != nil {
+		c.logger.Error("Failed to encode remote sync result", zap.String("table", msg.TableName), zap.Error(err))
+		return
+	}
+	c.actorSystem.Root.Send(requester, encoded)
+}
+
+func remoteSyncKey(jobID, tableName string) string {
+	return jobID + "/" + tableName
+}
+
+// isRemoteTable reports whether a configured table is synced by another node
+func (c *CoordinatorActor) isRemoteTable(tableName string) bool {
+	tc, ok := c.tableConfig(tableName)
+	return ok && !c.isLocalTable(tc)
+}
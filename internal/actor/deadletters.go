@@ -0,0 +1,127 @@
+package actor
+
+import (
+	"errors"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// errSyncActorStopped fails job tables whose sync request reached a stopped actor
+var errSyncActorStopped = errors.New("sync actor stopped")
+
+// deadLetterMessage forwards a dead letter from the event stream to the coordinator
+type deadLetterMessage struct {
+	PID     *actor.PID
+	Message interface{}
+	Sender  *actor.PID
+}
+
+// watchDeadLetters counts every dead letter and forwards them to the coordinator, which logs them
+// with table context and handles undelivered sync requests
+func (c *CoordinatorActor) watchDeadLetters(ctx actor.Context) {
+	self := ctx.Self()
+	c.deadLetterSub = c.actorSystem.EventStream.Subscribe(func(evt interface{}) {
+		deadLetter, ok := evt.(*actor.DeadLetterEvent)
+		if !ok {
+			return
+		}
+
+		deadLetters.Add(1)
+		metrics.ActorDeadLettersTotal.WithLabelValues(messageType(deadLetter.Message)).Inc()
+
+		// Letters to the coordinator itself cannot be forwarded to it
+		if deadLetter.PID.Equal(self) {
+			return
+		}
+		c.actorSystem.Root.Send(self, &deadLetterMessage{
+			PID:     deadLetter.PID,
+			Message: deadLetter.Message,
+			Sender:  deadLetter.Sender,
+		})
+	})
+}
+
+// stopWatchingDeadLetters removes the dead letter subscription
+func (c *CoordinatorActor) stopWatchingDeadLetters() {
+	if c.deadLetterSub != nil {
+		c.actorSystem.EventStream.Unsubscribe(c.deadLetterSub)
+		c.deadLetterSub = nil
+	}
+}
+
+// handleDeadLetter logs a dead letter and, for sync requests sent to a stopped sync actor, either
+// restarts the actor and redelivers the request or fails the job table so waiting callers are released
+func (c *CoordinatorActor) handleDeadLetter(ctx actor.Context, msg *deadLetterMessage) {
+	tableName := c.tableForPID(msg.PID)
+	fields := []zap.Field{
+		zap.String("actor", msg.PID.GetId()),
+		zap.String("table", tableName),
+		zap.String("message", messageType(msg.Message)),
+	}
+	if msg.Sender != nil {
+		fields = append(fields, zap.String("sender", msg.Sender.GetId()))
+	}
+
+	syncMsg, ok := msg.Message.(*SyncTableMessage)
+	if !ok {
+		c.logger.Warn("Dead letter", fields...)
+		return
+	}
+	fields = append(fields, zap.String("job_id", syncMsg.JobID))
+
+	if !c.config.Supervision.RerouteDeadLetters {
+		c.logger.Warn("Sync request was not delivered", fields...)
+		c.failUndelivered(ctx, syncMsg)
+		return
+	}
+
+	pid, err := c.respawnSyncActor(ctx, syncMsg.TableConfig.TargetTable)
+	if err != nil {
+		c.logger.Error("Failed to restart sync actor for undelivered sync request", append(fields, zap.Error(err))...)
+		c.failUndelivered(ctx, syncMsg)
+		return
+	}
+
+	c.logger.Warn("Rerouting undelivered sync request to restarted sync actor", fields...)
+	ctx.Send(pid, syncMsg)
+}
+
+// respawnSyncActor replaces a table's stopped sync actor, resetting its stopped state
+func (c *CoordinatorActor) respawnSyncActor(ctx actor.Context, tableName string) (*actor.PID, error) {
+	state, ok := c.tableStates[tableName]
+	if ok && !state.ActorStopped {
+		// Already replaced by an earlier dead letter
+		return c.syncActors[tableName], nil
+	}
+
+	tc, found := c.tableConfig(tableName)
+	if !found {
+		return nil, errSyncActorStopped
+	}
+
+	pid, err := c.spawnSyncActor(ctx, tc)
+	if err != nil {
+		return nil, err
+	}
+	c.syncActors[tableName] = pid
+	if ok {
+		state.ActorStopped = false
+		state.ActorState = ActorIdle
+	}
+	return pid, nil
+}
+
+// failUndelivered completes the job table of an undelivered sync request as failed
+func (c *CoordinatorActor) failUndelivered(ctx actor.Context, msg *SyncTableMessage) {
+	if msg.JobID == "" {
+		return
+	}
+	c.completeJobTable(ctx, &SyncResultMessage{
+		TableName: msg.TableConfig.TargetTable,
+		JobID:     msg.JobID,
+		Error:     errSyncActorStopped,
+	})
+}
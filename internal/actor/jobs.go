@@ -0,0 +1,377 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Job execution modes
+const (
+	JobModeParallel   = "parallel"
+	JobModeSequential = "sequential"
+	JobModeDependency = "dependency"
+)
+
+// Job and job table status values
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobSkipped   = "skipped"
+)
+
+// maxRetainedJobs bounds how many finished jobs the coordinator remembers
+const maxRetainedJobs = 200
+
+// GetJobMessage requests a snapshot of a job from the coordinator
+type GetJobMessage struct {
+	JobID string
+}
+
+// WaitJobMessage asks the coordinator to reply once a job has finished
+type WaitJobMessage struct {
+	JobID string
+}
+
+// JobResponse is the coordinator's reply to GetJobMessage, WaitJobMessage and trigger messages
+type JobResponse struct {
+	Job   SyncJob
+	Found bool
+}
+
+// SyncJob tracks the progress of a group of table syncs and the pipelines run after them
+type SyncJob struct {
+	ID         string        `json:"id"`
+	Mode       string        `json:"mode"`
+	Status     string        `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	Tables     []JobTable    `json:"tables"`
+	Pipelines  []JobPipeline `json:"pipelines,omitempty"`
+}
+
+// JobTable tracks a single table within a job
+type JobTable struct {
+	TableName  string     `json:"table_name"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+	RowsSynced int        `json:"rows_synced"`
+	RowsRead   int        `json:"rows_read"`
+	BytesRead  int64      `json:"bytes_read"`
+	Estimated  int64      `json:"rows_estimated,omitempty"` // source rows estimated by the preflight check
+	Overlap    string     `json:"overlap,omitempty"`        // queued, coalesced, skipped or restarted when requested while the table was syncing
+}
+
+func newSyncJob(id, mode string, tableNames []string) *SyncJob {
+	job := &SyncJob{
+		ID:        id,
+		Mode:      normalizeJobMode(mode),
+		Status:    JobQueued,
+		CreatedAt: time.Now(),
+		Tables:    make([]JobTable, 0, len(tableNames)),
+	}
+	for _, name := range tableNames {
+		job.Tables = append(job.Tables, JobTable{TableName: name, Status: JobQueued})
+	}
+	return job
+}
+
+func normalizeJobMode(mode string) string {
+	switch strings.ToLower(mode) {
+	case JobModeSequential:
+		return JobModeSequential
+	case JobModeDependency:
+		return JobModeDependency
+	default:
+		return JobModeParallel
+	}
+}
+
+// startJob registers a job and dispatches its first tables
+func (c *CoordinatorActor) startJob(ctx actor.Context, job *SyncJob) {
+	c.jobs[job.ID] = job
+	c.jobOrder = append(c.jobOrder, job.ID)
+	c.pruneJobs()
+
+	job.Pipelines = c.jobPipelines(job)
+	job.Status = JobRunning
+	c.dispatchJob(ctx, job)
+}
+
+// completeJobTable records a table result against its job and dispatches follow-up tables
+func (c *CoordinatorActor) completeJobTable(ctx actor.Context, msg *SyncResultMessage) {
+	c.completeCoalesced(ctx, msg)
+	if c.completeRemoteSync(msg) {
+		return
+	}
+
+	job, ok := c.jobs[msg.JobID]
+	if !ok {
+		return
+	}
+
+	for i := range job.Tables {
+		entry := &job.Tables[i]
+		if entry.TableName != msg.TableName || entry.Status != JobRunning {
+			continue
+		}
+
+		now := time.Now()
+		entry.FinishedAt = &now
+		entry.DurationMs = msg.Duration.Milliseconds()
+		entry.RowsSynced = msg.RowsSynced
+		entry.RowsRead = msg.RowsRead
+		entry.BytesRead = msg.BytesRead
+		entry.Estimated = msg.Estimated
+		switch {
+		case msg.Skipped:
+			entry.Status = JobSkipped
+		case msg.Success:
+			entry.Status = JobSucceeded
+		default:
+			entry.Status = JobFailed
+		}
+		if msg.Error != nil {
+			entry.Error = msg.Error.Error()
+		}
+		break
+	}
+
+	c.dispatchJob(ctx, job)
+}
+
+// dispatchJob starts the queued tables of a job that are allowed to run under its mode
+func (c *CoordinatorActor) dispatchJob(ctx actor.Context, job *SyncJob) {
+	if job.Status != JobRunning {
+		return
+	}
+
+	switch job.Mode {
+	case JobModeSequential:
+		if !job.hasStatus(JobRunning) {
+			for i := range job.Tables {
+				if job.Tables[i].Status == JobQueued {
+					c.dispatchJobTable(ctx, job, &job.Tables[i])
+					if job.Tables[i].Status == JobRunning {
+						break
+					}
+				}
+			}
+		}
+	case JobModeDependency:
+		c.dispatchDependencies(ctx, job)
+	default:
+		for i := range job.Tables {
+			if job.Tables[i].Status == JobQueued {
+				c.dispatchJobTable(ctx, job, &job.Tables[i])
+			}
+		}
+	}
+
+	c.dispatchPipelines(ctx, job)
+
+	if !job.hasStatus(JobQueued) && !job.hasStatus(JobRunning) && !job.pipelinesPending() {
+		c.finishJob(ctx, job)
+	}
+}
+
+// dispatchDependencies starts tables whose depends_on tables within the job have succeeded
+func (c *CoordinatorActor) dispatchDependencies(ctx actor.Context, job *SyncJob) {
+	for progressed := true; progressed; {
+		progressed = false
+		for i := range job.Tables {
+			entry := &job.Tables[i]
+			if entry.Status != JobQueued {
+				continue
+			}
+
+			tc, _ := c.tableConfig(entry.TableName)
+			ready := true
+			for _, dependency := range tc.DependsOn {
+				depStatus, inJob := job.tableStatus(dependency)
+				if !inJob {
+					continue
+				}
+				switch depStatus {
+				case JobSucceeded:
+				case JobFailed, JobSkipped:
+					entry.Status = JobSkipped
+					entry.Error = fmt.Sprintf("dependency %s did not succeed", dependency)
+					ready = false
+					progressed = true
+				default:
+					ready = false
+				}
+				if entry.Status == JobSkipped {
+					break
+				}
+			}
+
+			if ready {
+				c.dispatchJobTable(ctx, job, entry)
+				progressed = progressed || entry.Status != JobRunning
+			}
+		}
+	}
+
+	// Anything still queued with nothing running can never start: a dependency cycle
+	if job.hasStatus(JobQueued) && !job.hasStatus(JobRunning) {
+		for i := range job.Tables {
+			if job.Tables[i].Status == JobQueued {
+				job.Tables[i].Status = JobFailed
+				job.Tables[i].Error = "dependency cycle detected"
+			}
+		}
+	}
+}
+
+// dispatchJobTable sends a table of a job to its sync actor, or to the node owning it in cluster mode
+func (c *CoordinatorActor) dispatchJobTable(ctx actor.Context, job *SyncJob, entry *JobTable) {
+	now := time.Now()
+
+	tc, ok := c.tableConfig(entry.TableName)
+	remote := ok && !c.isLocalTable(tc)
+	if !ok || (!remote && !c.hasSyncActor(entry.TableName)) {
+		entry.Status = JobFailed
+		entry.Error = "sync actor not found"
+		entry.FinishedAt = &now
+		return
+	}
+
+	if window, inBlackout := tc.ActiveBlackout(c.config.Defaults, now); inBlackout {
+		c.logger.Info("Skipping table in blackout window",
+			zap.String("job_id", job.ID),
+			zap.String("table", entry.TableName),
+			zap.String("blackout", window.String()),
+		)
+		entry.Status = JobSkipped
+		entry.Error = fmt.Sprintf("blackout window %s", window.String())
+		entry.FinishedAt = &now
+		return
+	}
+
+	entry.Status = JobRunning
+	entry.StartedAt = &now
+	if remote {
+		if err := c.dispatchRemote(ctx, job.ID, tc); err != nil {
+			entry.Status = JobFailed
+			entry.Error = err.Error()
+			entry.FinishedAt = &now
+		}
+		return
+	}
+
+	entry.Overlap = c.resolveOverlap(tc, job.ID)
+	switch entry.Overlap {
+	case OverlapSkipped:
+		entry.Status = JobSkipped
+		entry.Error = errAlreadySyncing.Error()
+		entry.FinishedAt = &now
+	case OverlapCoalesced:
+		// Completed with the result of the pending run it joined
+	default:
+		c.sendTableSync(ctx, tc, job.ID)
+	}
+}
+
+func (c *CoordinatorActor) finishJob(ctx actor.Context, job *SyncJob) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = JobSucceeded
+	if job.hasStatus(JobFailed) {
+		job.Status = JobFailed
+	}
+	for _, entry := range job.Pipelines {
+		if entry.Status == JobFailed {
+			job.Status = JobFailed
+		}
+	}
+
+	c.logger.Info("Sync job finished",
+		zap.String("job_id", job.ID),
+		zap.String("mode", job.Mode),
+		zap.String("status", job.Status),
+		zap.Duration("duration", now.Sub(job.CreatedAt)),
+	)
+
+	if waiters := c.jobWaiters[job.ID]; len(waiters) > 0 {
+		snapshot := c.jobSnapshot(job.ID)
+		for _, waiter := range waiters {
+			ctx.Send(waiter, snapshot)
+		}
+		delete(c.jobWaiters, job.ID)
+	}
+}
+
+// waitForJob replies immediately for finished or unknown jobs, otherwise once the job finishes
+func (c *CoordinatorActor) waitForJob(ctx actor.Context, jobID string) {
+	job, ok := c.jobs[jobID]
+	if !ok || job.FinishedAt != nil || ctx.Sender() == nil {
+		ctx.Respond(c.jobSnapshot(jobID))
+		return
+	}
+	c.jobWaiters[jobID] = append(c.jobWaiters[jobID], ctx.Sender())
+}
+
+// jobSnapshot copies a job for a request-reply response
+func (c *CoordinatorActor) jobSnapshot(jobID string) *JobResponse {
+	job, ok := c.jobs[jobID]
+	if !ok {
+		return &JobResponse{}
+	}
+	snapshot := *job
+	snapshot.Tables = append([]JobTable(nil), job.Tables...)
+	snapshot.Pipelines = append([]JobPipeline(nil), job.Pipelines...)
+	return &JobResponse{Job: snapshot, Found: true}
+}
+
+// pruneJobs drops the oldest finished jobs once the retention limit is exceeded
+func (c *CoordinatorActor) pruneJobs() {
+	for len(c.jobOrder) > maxRetainedJobs {
+		oldest := c.jobOrder[0]
+		if job, ok := c.jobs[oldest]; ok && job.FinishedAt == nil {
+			break
+		}
+		delete(c.jobs, oldest)
+		delete(c.jobWaiters, oldest)
+		c.jobOrder = c.jobOrder[1:]
+	}
+}
+
+// tableConfig returns the configuration of a target table
+func (c *CoordinatorActor) tableConfig(tableName string) (config.TableConfig, bool) {
+	for _, tc := range c.config.Tables {
+		if tc.TargetTable == tableName {
+			return tc, true
+		}
+	}
+	return config.TableConfig{}, false
+}
+
+func (job *SyncJob) hasStatus(status string) bool {
+	for _, entry := range job.Tables {
+		if entry.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (job *SyncJob) tableStatus(tableName string) (string, bool) {
+	for _, entry := range job.Tables {
+		if entry.TableName == tableName {
+			return entry.Status, true
+		}
+	}
+	return "", false
+}
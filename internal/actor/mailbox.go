@@ -0,0 +1,141 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// MailboxInfo describes the mailbox of an actor
+type MailboxInfo struct {
+	Actor            string  `json:"actor"`
+	Depth            int64   `json:"depth"` // queued messages, including the one being processed
+	Processed        int64   `json:"processed"`
+	AvgProcessingMs  float64 `json:"avg_processing_ms"`
+	LastProcessingMs float64 `json:"last_processing_ms"`
+}
+
+// mailboxStats tracks the queue depth and processing time of one actor's mailbox
+type mailboxStats struct {
+	name      string
+	posted    atomic.Int64
+	received  atomic.Int64
+	processed atomic.Int64
+	totalNs   atomic.Int64
+	lastNs    atomic.Int64
+}
+
+var (
+	mailboxesMu sync.RWMutex
+	mailboxes   = make(map[string]*mailboxStats)
+
+	deadLetters atomic.Int64
+)
+
+// MailboxStarted implements actor.MailboxMiddleware
+func (s *mailboxStats) MailboxStarted() {}
+
+// MessagePosted implements actor.MailboxMiddleware
+func (s *mailboxStats) MessagePosted(message interface{}) {
+	metrics.ActorMailboxDepth.WithLabelValues(s.name).Set(float64(s.posted.Add(1) - s.received.Load()))
+}
+
+// MessageReceived implements actor.MailboxMiddleware, called once a message has been processed
+func (s *mailboxStats) MessageReceived(message interface{}) {
+	metrics.ActorMailboxDepth.WithLabelValues(s.name).Set(float64(s.posted.Load() - s.received.Add(1)))
+}
+
+// MailboxEmpty implements actor.MailboxMiddleware
+func (s *mailboxStats) MailboxEmpty() {}
+
+// timeMessages records how long the actor takes to process each user message
+func (s *mailboxStats) timeMessages(next actor.ReceiverFunc) actor.ReceiverFunc {
+	return func(c actor.ReceiverContext, envelope *actor.MessageEnvelope) {
+		start := time.Now()
+		next(c, envelope)
+		elapsed := time.Since(start)
+
+		s.processed.Add(1)
+		s.totalNs.Add(int64(elapsed))
+		s.lastNs.Store(int64(elapsed))
+		metrics.ActorMessageDurationSeconds.WithLabelValues(s.name, messageType(envelope.Message)).Observe(elapsed.Seconds())
+	}
+}
+
+func (s *mailboxStats) info() MailboxInfo {
+	info := MailboxInfo{
+		Actor:            s.name,
+		Depth:            s.posted.Load() - s.received.Load(),
+		Processed:        s.processed.Load(),
+		LastProcessingMs: float64(s.lastNs.Load()) / float64(time.Millisecond),
+	}
+	if info.Processed > 0 {
+		info.AvgProcessingMs = float64(s.totalNs.Load()) / float64(info.Processed) / float64(time.Millisecond)
+	}
+	return info
+}
+
+// MailboxOptions returns props options that instrument the mailbox of the named actor
+func MailboxOptions(name string) []actor.PropsOption {
+	mailboxesMu.Lock()
+	stats, ok := mailboxes[name]
+	if !ok {
+		stats = &mailboxStats{name: name}
+		mailboxes[name] = stats
+	}
+	mailboxesMu.Unlock()
+
+	return []actor.PropsOption{
+		actor.WithMailbox(actor.Unbounded(stats)),
+		actor.WithReceiverMiddleware(stats.timeMessages),
+	}
+}
+
+// MailboxSnapshot returns the mailbox of every instrumented actor, deepest first
+func MailboxSnapshot() []MailboxInfo {
+	mailboxesMu.RLock()
+	infos := make([]MailboxInfo, 0, len(mailboxes))
+	for _, stats := range mailboxes {
+		infos = append(infos, stats.info())
+	}
+	mailboxesMu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Depth != infos[j].Depth {
+			return infos[i].Depth > infos[j].Depth
+		}
+		return infos[i].Actor < infos[j].Actor
+	})
+	return infos
+}
+
+// mailboxDepth returns the queue depth of the named actor
+func mailboxDepth(name string) int64 {
+	mailboxesMu.RLock()
+	defer mailboxesMu.RUnlock()
+	if stats, ok := mailboxes[name]; ok {
+		return stats.posted.Load() - stats.received.Load()
+	}
+	return 0
+}
+
+// DeadLetterCount returns the number of dead letters seen since startup
+func DeadLetterCount() int64 {
+	return deadLetters.Load()
+}
+
+// messageType returns the unqualified type name of a message
+func messageType(message interface{}) string {
+	name := fmt.Sprintf("%T", message)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
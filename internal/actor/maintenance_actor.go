@@ -0,0 +1,188 @@
+package actor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// maintenanceTimeout bounds a single maintenance operation; VACUUM FULL on large tables can take a while
+const maintenanceTimeout = time.Hour
+
+// RunMaintenanceMessage asks the maintenance actor to run an operation on a target table
+type RunMaintenanceMessage struct {
+	TableName string
+	Operation string // analyze, vacuum or vacuum_full
+	scheduled bool
+}
+
+// MaintenanceActor runs ANALYZE and VACUUM on target tables, one operation at a time
+type MaintenanceActor struct {
+	syncEngine  *syncpkg.SyncEngine
+	tables      []config.TableConfig
+	logger      *zap.Logger
+	actorSystem *actor.ActorSystem
+	timerMu     sync.Mutex
+	timers      map[string]*time.Timer
+}
+
+// NewMaintenanceActor creates a new maintenance actor
+func NewMaintenanceActor(syncEngine *syncpkg.SyncEngine, tables []config.TableConfig, logger *zap.Logger, actorSystem *actor.ActorSystem) actor.Actor {
+	return &MaintenanceActor{
+		syncEngine:  syncEngine,
+		tables:      tables,
+		logger:      logger,
+		actorSystem: actorSystem,
+		timers:      make(map[string]*time.Timer),
+	}
+}
+
+// Receive handles incoming messages
+func (a *MaintenanceActor) Receive(ctx actor.Context) {
+	switch msg := ctx.Message().(type) {
+	case *actor.Started:
+		a.logger.Info("MaintenanceActor started")
+		for _, tc := range a.tables {
+			if operation := vacuumOperation(tc.Maintenance); operation != "" {
+				a.scheduleVacuum(ctx, tc.TargetTable, operation, tc.Maintenance.GetVacuumInterval())
+			}
+		}
+
+	case *RunMaintenanceMessage:
+		a.run(msg)
+		if msg.scheduled {
+			if tc, ok := a.tableConfig(msg.TableName); ok {
+				a.scheduleVacuum(ctx, msg.TableName, msg.Operation, tc.Maintenance.GetVacuumInterval())
+			}
+		}
+
+	case *actor.Stopping, *actor.Restarting:
+		a.stopTimers()
+
+	case *actor.Stopped:
+		a.logger.Info("MaintenanceActor stopped")
+	}
+}
+
+// run executes a maintenance operation unless the database circuit breaker is open
+func (a *MaintenanceActor) run(msg *RunMaintenanceMessage) {
+	if !a.syncEngine.DB.Available() {
+		a.logger.Debug("Skipping maintenance, database circuit breaker is open",
+			zap.String("table", msg.TableName),
+			zap.String("operation", msg.Operation),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), maintenanceTimeout)
+	defer cancel()
+	if err := a.syncEngine.RunMaintenance(ctx, msg.TableName, msg.Operation); err != nil {
+		a.logger.Error("Target maintenance failed",
+			zap.String("table", msg.TableName),
+			zap.String("operation", msg.Operation),
+			zap.Error(err),
+		)
+	}
+}
+
+// scheduleVacuum schedules the next vacuum of a table
+func (a *MaintenanceActor) scheduleVacuum(ctx actor.Context, tableName, operation string, interval time.Duration) {
+	pid := ctx.Self()
+
+	a.timerMu.Lock()
+	defer a.timerMu.Unlock()
+	if timer, ok := a.timers[tableName]; ok {
+		timer.Stop()
+	}
+	a.timers[tableName] = time.AfterFunc(interval, func() {
+		a.actorSystem.Root.Send(pid, &RunMaintenanceMessage{
+			TableName: tableName,
+			Operation: operation,
+			scheduled: true,
+		})
+	})
+}
+
+func (a *MaintenanceActor) stopTimers() {
+	a.timerMu.Lock()
+	defer a.timerMu.Unlock()
+	for tableName, timer := range a.timers {
+		timer.Stop()
+		delete(a.timers, tableName)
+	}
+}
+
+func (a *MaintenanceActor) tableConfig(tableName string) (config.TableConfig, bool) {
+	for _, tc := range a.tables {
+		if tc.TargetTable == tableName && tc.Maintenance != nil {
+			return tc, true
+		}
+	}
+	return config.TableConfig{}, false
+}
+
+// vacuumOperation returns the scheduled vacuum operation of a maintenance policy, empty when none
+func vacuumOperation(maintenance *config.Maintenance) string {
+	if maintenance == nil {
+		return ""
+	}
+	switch strings.ToLower(maintenance.Vacuum) {
+	case "standard":
+		return history.KindVacuum
+	case "full":
+		return history.KindVacuumFull
+	default:
+		return ""
+	}
+}
+
+// needsMaintenanceActor reports whether any table has a maintenance policy
+func needsMaintenanceActor(tables []config.TableConfig) bool {
+	for _, tc := range tables {
+		if tc.Maintenance != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// startMaintenanceActor starts the maintenance actor when any table has a maintenance policy
+func (c *CoordinatorActor) startMaintenanceActor(ctx actor.Context) {
+	tables := c.localTables()
+	if !needsMaintenanceActor(tables) {
+		return
+	}
+
+	props := actor.PropsFromProducer(func() actor.Actor {
+		return NewMaintenanceActor(c.syncEngine, tables, c.logger, c.actorSystem)
+	}, MailboxOptions("maintenance")...)
+	pid, err := ctx.SpawnNamed(props, "maintenance")
+	if err != nil {
+		c.logger.Error("Failed to start maintenance actor", zap.Error(err))
+		return
+	}
+	c.maintenancePID = pid
+}
+
+// requestAnalyze asks the maintenance actor to ANALYZE a table after a successful load
+func (c *CoordinatorActor) requestAnalyze(ctx actor.Context, msg *SyncResultMessage) {
+	if c.maintenancePID == nil || !msg.Success || msg.Unchanged || msg.Skipped {
+		return
+	}
+	tc, ok := c.tableConfig(msg.TableName)
+	if !ok || tc.Maintenance == nil || !tc.Maintenance.AnalyzeAfterLoad {
+		return
+	}
+	ctx.Send(c.maintenancePID, &RunMaintenanceMessage{
+		TableName: msg.TableName,
+		Operation: history.KindAnalyze,
+	})
+}
@@ -0,0 +1,195 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Overlap decisions recorded on job tables requested while the table was syncing
+const (
+	OverlapQueued    = "queued"    // runs once the current sync has finished
+	OverlapCoalesced = "coalesced" // joined a pending run and shares its result
+	OverlapSkipped   = "skipped"   // dropped because the table was syncing
+	OverlapRestarted = "restarted" // cancelled the current sync and runs in its place
+)
+
+var (
+	// errAlreadySyncing skips syncs requested while the table is syncing under the skip overlap policy
+	errAlreadySyncing = errors.New("table is already syncing")
+	// errSyncRestarted cancels a sync replaced by a newer request under the restart overlap policy
+	errSyncRestarted = errors.New("sync restarted by a newer request")
+)
+
+// runningSync is the registration of a running sync
+type runningSync struct {
+	cancel context.CancelCauseFunc
+}
+
+// Running syncs by target table, so a sync can be cancelled while its actor is busy running it
+var (
+	runningMu    sync.Mutex
+	runningSyncs = make(map[string]*runningSync)
+)
+
+// registerRunningSync records the sync running for a table and returns the function that removes it. A sync
+// abandoned by the watchdog that finishes late leaves the registration of its replacement in place
+func registerRunningSync(tableName string, cancel context.CancelCauseFunc) func() {
+	entry := &runningSync{cancel: cancel}
+	runningMu.Lock()
+	runningSyncs[tableName] = entry
+	runningMu.Unlock()
+
+	return func() {
+		runningMu.Lock()
+		if runningSyncs[tableName] == entry {
+			delete(runningSyncs, tableName)
+		}
+		runningMu.Unlock()
+	}
+}
+
+// syncRunning reports whether a table is syncing on this node
+func syncRunning(tableName string) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	_, ok := runningSyncs[tableName]
+	return ok
+}
+
+// cancelRunningSync cancels the running sync of a table with cause, reporting whether one was running
+func cancelRunningSync(tableName string, cause error) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	entry, ok := runningSyncs[tableName]
+	if ok {
+		entry.cancel(cause)
+	}
+	return ok
+}
+
+// resolveOverlap applies the table's overlap policy to a requested sync. Under the queue policy the
+// first request becomes the table's pending run until it starts, and later requests join it; the
+// returned decision is empty when the request does not overlap anything
+func (c *CoordinatorActor) resolveOverlap(tc config.TableConfig, jobID string) string {
+	tableName := tc.TargetTable
+	policy := tc.GetOverlap(c.config.Defaults)
+
+	decision := ""
+	if pending, ok := c.queuedRuns[tableName]; ok && policy == config.OverlapQueue {
+		key := remoteSyncKey(pending, tableName)
+		c.coalesced[key] = append(c.coalesced[key], jobID)
+		decision = OverlapCoalesced
+	} else if !c.tableSyncing(tableName) {
+		if policy == config.OverlapQueue {
+			c.queuedRuns[tableName] = jobID
+		}
+		return ""
+	} else {
+		switch policy {
+		case config.OverlapSkip:
+			decision = OverlapSkipped
+		case config.OverlapRestart:
+			cancelRunningSync(tableName, errSyncRestarted)
+			decision = OverlapRestarted
+		default:
+			c.queuedRuns[tableName] = jobID
+			decision = OverlapQueued
+		}
+	}
+
+	c.logger.Info("Sync requested while table is syncing",
+		zap.String("table", tableName),
+		zap.String("job_id", jobID),
+		zap.String("policy", policy),
+		zap.String("decision", decision),
+	)
+	return decision
+}
+
+// startQueuedRun clears a table's pending run once it has started, so later requests queue a new one
+func (c *CoordinatorActor) startQueuedRun(tableName, jobID string) {
+	if jobID != "" && c.queuedRuns[tableName] == jobID {
+		delete(c.queuedRuns, tableName)
+	}
+}
+
+// completeCoalesced completes the requests that joined a finished run with a copy of its result
+func (c *CoordinatorActor) completeCoalesced(ctx actor.Context, msg *SyncResultMessage) {
+	c.startQueuedRun(msg.TableName, msg.JobID)
+
+	key := remoteSyncKey(msg.JobID, msg.TableName)
+	joined := c.coalesced[key]
+	delete(c.coalesced, key)
+	for _, jobID := range joined {
+		result := *msg
+		result.JobID = jobID
+		c.completeJobTable(ctx, &result)
+	}
+}
+
+// tableSyncing reports whether a table is syncing on this node, including pool syncs handed to a worker but not yet started
+func (c *CoordinatorActor) tableSyncing(tableName string) bool {
+	if c.pool != nil {
+		if pt, ok := c.pool.tables[tableName]; ok && pt.running {
+			return true
+		}
+	}
+	return syncRunning(tableName)
+}
+
+// overlapTick applies the overlap policy to a scheduled tick firing while a requested sync is running,
+// reporting whether it overlapped. Skipped ticks only schedule the next one
+func (a *SyncActor) overlapTick(tableName, policy string) bool {
+	if !syncRunning(tableName) {
+		return false
+	}
+
+	decision := OverlapQueued
+	switch policy {
+	case config.OverlapSkip:
+		decision = OverlapSkipped
+	case config.OverlapRestart:
+		cancelRunningSync(tableName, errSyncRestarted)
+		decision = OverlapRestarted
+	}
+	a.logger.Info("Scheduled sync fired while table is syncing",
+		zap.String("table", tableName),
+		zap.String("policy", policy),
+		zap.String("decision", decision),
+	)
+	return true
+}
+
+// overlapPoolTick applies the overlap policy to a scheduled pool sync falling due while its table is
+// syncing, reporting whether it still runs. A skipped tick is replaced when the running sync finishes
+func (c *CoordinatorActor) overlapPoolTick(item *poolItem) bool {
+	pt := c.pool.tables[item.table]
+	if item.jobID != "" || !pt.running {
+		return true
+	}
+
+	policy := pt.config.GetOverlap(c.config.Defaults)
+	decision := OverlapQueued
+	switch policy {
+	case config.OverlapSkip:
+		decision = OverlapSkipped
+		if pt.scheduled == item {
+			pt.scheduled = nil
+		}
+	case config.OverlapRestart:
+		cancelRunningSync(item.table, errSyncRestarted)
+		decision = OverlapRestarted
+	}
+	c.logger.Info("Scheduled sync fell due while table is syncing",
+		zap.String("table", item.table),
+		zap.String("policy", policy),
+		zap.String("decision", decision),
+	)
+	return decision != OverlapSkipped
+}
@@ -0,0 +1,218 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/metrics"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// JobPipeline tracks a post-sync pipeline within a job
+type JobPipeline struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+}
+
+// runPipelineMessage asks the pipeline actor to run a pipeline for a job
+type runPipelineMessage struct {
+	JobID    string
+	Pipeline config.Pipeline
+}
+
+// pipelineDoneMessage reports the outcome of a pipeline run to the coordinator
+type pipelineDoneMessage struct {
+	JobID    string
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// PipelineActor runs post-sync pipelines one at a time, so transformations such as dbt runs never overlap
+type PipelineActor struct {
+	syncEngine *syncpkg.SyncEngine
+	logger     *zap.Logger
+}
+
+// NewPipelineActor creates a new pipeline actor
+func NewPipelineActor(syncEngine *syncpkg.SyncEngine, logger *zap.Logger) actor.Actor {
+	return &PipelineActor{
+		syncEngine: syncEngine,
+		logger:     logger,
+	}
+}
+
+// Receive handles incoming messages
+func (a *PipelineActor) Receive(ctx actor.Context) {
+	switch msg := ctx.Message().(type) {
+	case *actor.Started:
+		a.logger.Info("PipelineActor started")
+
+	case *runPipelineMessage:
+		a.logger.Info("Running pipeline",
+			zap.String("pipeline", msg.Pipeline.Name),
+			zap.String("job_id", msg.JobID),
+		)
+		start := time.Now()
+		err := a.syncEngine.RunPipeline(context.Background(), msg.Pipeline, msg.JobID)
+
+		status := "success"
+		if err != nil {
+			status = "failure"
+			a.logger.Error("Pipeline failed",
+				zap.String("pipeline", msg.Pipeline.Name),
+				zap.String("job_id", msg.JobID),
+				zap.Error(err),
+			)
+		} else {
+			a.logger.Info("Pipeline completed",
+				zap.String("pipeline", msg.Pipeline.Name),
+				zap.String("job_id", msg.JobID),
+				zap.Duration("duration", time.Since(start)),
+			)
+		}
+		metrics.PipelineRunsTotal.WithLabelValues(msg.Pipeline.Name, status).Inc()
+
+		ctx.Send(ctx.Parent(), &pipelineDoneMessage{
+			JobID:    msg.JobID,
+			Name:     msg.Pipeline.Name,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+
+	case *actor.Stopped:
+		a.logger.Info("PipelineActor stopped")
+	}
+}
+
+// startPipelineActor starts the pipeline actor when any pipeline is configured
+func (c *CoordinatorActor) startPipelineActor(ctx actor.Context) {
+	if len(c.config.Pipelines) == 0 {
+		return
+	}
+
+	props := actor.PropsFromProducer(func() actor.Actor {
+		return NewPipelineActor(c.syncEngine, c.logger)
+	}, MailboxOptions("pipelines")...)
+	pid, err := ctx.SpawnNamed(props, "pipelines")
+	if err != nil {
+		c.logger.Error("Failed to start pipeline actor", zap.Error(err))
+		return
+	}
+	c.pipelinePID = pid
+}
+
+// jobPipelines returns the pipelines built on any of a job's tables, queued until those tables finish
+func (c *CoordinatorActor) jobPipelines(job *SyncJob) []JobPipeline {
+	var pipelines []JobPipeline
+	for _, p := range c.config.Pipelines {
+		for _, tableName := range p.After {
+			if _, inJob := job.tableStatus(tableName); inJob {
+				pipelines = append(pipelines, JobPipeline{Name: p.Name, Status: JobQueued})
+				break
+			}
+		}
+	}
+	return pipelines
+}
+
+// dispatchPipelines starts the queued pipelines of a job whose tables in the job have all succeeded, and skips those
+// with a table that failed or was skipped
+func (c *CoordinatorActor) dispatchPipelines(ctx actor.Context, job *SyncJob) {
+	for i := range job.Pipelines {
+		entry := &job.Pipelines[i]
+		if entry.Status != JobQueued {
+			continue
+		}
+		pipeline, ok := c.pipelineConfig(entry.Name)
+		if !ok {
+			continue
+		}
+
+		ready := true
+		for _, tableName := range pipeline.After {
+			status, inJob := job.tableStatus(tableName)
+			if !inJob {
+				continue
+			}
+			switch status {
+			case JobSucceeded:
+			case JobFailed, JobSkipped:
+				entry.Status = JobSkipped
+				entry.Error = fmt.Sprintf("table %s did not succeed", tableName)
+			default:
+				ready = false
+			}
+			if entry.Status == JobSkipped {
+				break
+			}
+		}
+		if entry.Status == JobSkipped || !ready {
+			continue
+		}
+
+		now := time.Now()
+		entry.StartedAt = &now
+		if c.pipelinePID == nil {
+			entry.Status = JobFailed
+			entry.Error = "pipeline actor not running"
+			entry.FinishedAt = &now
+			continue
+		}
+		entry.Status = JobRunning
+		ctx.Send(c.pipelinePID, &runPipelineMessage{JobID: job.ID, Pipeline: pipeline})
+	}
+}
+
+// completeJobPipeline records a pipeline outcome against its job and finishes the job when nothing else is pending
+func (c *CoordinatorActor) completeJobPipeline(ctx actor.Context, msg *pipelineDoneMessage) {
+	job, ok := c.jobs[msg.JobID]
+	if !ok {
+		return
+	}
+	for i := range job.Pipelines {
+		entry := &job.Pipelines[i]
+		if entry.Name != msg.Name || entry.Status != JobRunning {
+			continue
+		}
+		now := time.Now()
+		entry.FinishedAt = &now
+		entry.DurationMs = msg.Duration.Milliseconds()
+		entry.Status = JobSucceeded
+		if msg.Err != nil {
+			entry.Status = JobFailed
+			entry.Error = msg.Err.Error()
+		}
+		break
+	}
+	c.dispatchJob(ctx, job)
+}
+
+// pipelineConfig returns the configuration of a pipeline
+func (c *CoordinatorActor) pipelineConfig(name string) (config.Pipeline, bool) {
+	for _, p := range c.config.Pipelines {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return config.Pipeline{}, false
+}
+
+// pipelinesPending reports whether any pipeline of the job is queued or running
+func (job *SyncJob) pipelinesPending() bool {
+	for _, entry := range job.Pipelines {
+		if entry.Status == JobQueued || entry.Status == JobRunning {
+			return true
+		}
+	}
+	return false
+}
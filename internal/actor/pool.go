@@ -0,0 +1,487 @@
+package actor
+
+import (
+	"container/heap"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// poolSyncMessage asks a pool worker to sync a table
+type poolSyncMessage struct {
+	TableConfig     config.TableConfig
+	JobID           string
+	Scheduled       bool
+	ChangeToken     string
+	RefreshInterval time.Duration
+	Preempt         *atomic.Bool
+}
+
+// poolWorkerReady tells the coordinator a worker has started and can take work
+type poolWorkerReady struct {
+	Worker *actor.PID
+}
+
+// poolSyncDone tells the coordinator a worker has finished a sync, carrying the table's updated change detection state
+type poolSyncDone struct {
+	Worker          *actor.PID
+	TableName       string
+	ChangeToken     string
+	RefreshInterval time.Duration
+}
+
+// poolWorkerFailed tells the coordinator a worker crashed, releasing the table it was syncing
+type poolWorkerFailed struct {
+	Worker *actor.PID
+	Reason interface{}
+}
+
+// poolTickMessage wakes the coordinator when the next scheduled sync is due
+type poolTickMessage struct{}
+
+// PoolWorkerActor syncs whichever table the coordinator hands it, one at a time
+type PoolWorkerActor struct {
+	syncEngine  *syncpkg.SyncEngine
+	defaults    config.DefaultConfig
+	logger      *zap.Logger
+	actorSystem *actor.ActorSystem
+}
+
+// NewPoolWorkerActor creates a new pool worker
+func NewPoolWorkerActor(syncEngine *syncpkg.SyncEngine, defaults config.DefaultConfig, logger *zap.Logger, actorSystem *actor.ActorSystem) actor.Actor {
+	return &PoolWorkerActor{
+		syncEngine:  syncEngine,
+		defaults:    defaults,
+		logger:      logger,
+		actorSystem: actorSystem,
+	}
+}
+
+// Receive handles incoming messages
+func (w *PoolWorkerActor) Receive(ctx actor.Context) {
+	switch msg := ctx.Message().(type) {
+	case *actor.Started:
+		ctx.Send(ctx.Parent(), &poolWorkerReady{Worker: ctx.Self()})
+
+	case *poolSyncMessage:
+		// The table's sync runs exactly as in its own sync actor, reporting state and results to the coordinator
+		runner := &SyncActor{
+			syncEngine:      w.syncEngine,
+			tableConfig:     msg.TableConfig,
+			defaults:        w.defaults,
+			logger:          w.logger,
+			actorSystem:     w.actorSystem,
+			changeToken:     msg.ChangeToken,
+			refreshInterval: msg.RefreshInterval,
+			preempt:         msg.Preempt,
+		}
+		if msg.Scheduled {
+			runner.runScheduledTick(ctx)
+		} else {
+			runner.runRequestedSync(ctx, msg.JobID)
+		}
+		ctx.Send(ctx.Parent(), &poolSyncDone{
+			Worker:          ctx.Self(),
+			TableName:       msg.TableConfig.TargetTable,
+			ChangeToken:     runner.changeToken,
+			RefreshInterval: runner.refreshInterval,
+		})
+	}
+}
+
+// workerPool is the coordinator's queue of pending syncs and the workers consuming it
+type workerPool struct {
+	tables  map[string]*poolTable
+	pending syncQueue // syncs that are not due yet, by due time
+	ready   syncQueue // due syncs waiting for a worker, by rank
+	idle    []*actor.PID
+	busy    map[string]*poolItem // item being synced by each worker, by worker id
+	seq     uint64
+	timer   *time.Timer
+}
+
+// poolTable is the scheduling state of a table synced by the worker pool
+type poolTable struct {
+	config          config.TableConfig
+	running         bool
+	scheduled       *poolItem // the table's queued scheduled sync, nil when none is queued
+	changeToken     string
+	refreshInterval time.Duration
+}
+
+// poolItem is a queued sync. Requested syncs have a zero due time, so they run before scheduled ones
+type poolItem struct {
+	table     string
+	jobID     string
+	due       time.Time
+	rank      int
+	seq       uint64
+	preempt   *atomic.Bool // set to ask the worker syncing the item to yield between chunks
+	preempted bool         // the worker yielded, so the item is queued again when it reports back
+}
+
+// Pool ranks: requested syncs and high priority tables jump the queue, low priority loads can be preempted
+const (
+	rankHigh = iota
+	rankNormal
+	rankLow
+)
+
+// itemRank returns the rank of a sync: requested syncs rank with high priority tables
+func itemRank(tc config.TableConfig, jobID string) int {
+	if jobID != "" {
+		return rankHigh
+	}
+	switch tc.GetPriority() {
+	case config.PriorityHigh:
+		return rankHigh
+	case config.PriorityLow:
+		return rankLow
+	default:
+		return rankNormal
+	}
+}
+
+// syncQueue is a heap of syncs ordered by due time then arrival, or first by rank when byRank is set
+type syncQueue struct {
+	items  []*poolItem
+	byRank bool
+}
+
+func (q *syncQueue) Len() int { return len(q.items) }
+
+func (q *syncQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.byRank && a.rank != b.rank {
+		return a.rank < b.rank
+	}
+	if !a.due.Equal(b.due) {
+		return a.due.Before(b.due)
+	}
+	return a.seq < b.seq
+}
+
+func (q *syncQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *syncQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(*poolItem))
+}
+
+func (q *syncQueue) Pop() interface{} {
+	old := q.items
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	q.items = old[:len(old)-1]
+	return item
+}
+
+// startWorkerPool queues the initial syncs of the local tables and spawns the pool workers
+func (c *CoordinatorActor) startWorkerPool(ctx actor.Context) {
+	c.pool = &workerPool{
+		tables: make(map[string]*poolTable),
+		ready:  syncQueue{byRank: true},
+		busy:   make(map[string]*poolItem),
+	}
+
+	now := time.Now()
+	for _, tableConfig := range c.localTables() {
+		c.pool.tables[tableConfig.TargetTable] = &poolTable{config: tableConfig}
+		c.tableStates[tableConfig.TargetTable] = &TableState{
+			TableName:    tableConfig.TargetTable,
+			MaxStaleness: tableConfig.GetMaxStaleness(c.config.Defaults),
+		}
+
+		if !tableConfig.GetProtoActorTrigger(c.config.Defaults) {
+			continue
+		}
+		switch tableConfig.GetInitialSync(c.config.Defaults) {
+		case config.InitialSyncDeferred:
+			c.schedulePoolSync(tableConfig.TargetTable, now.Add(refreshDelay(tableConfig, c.config.Defaults, 0)))
+		case config.InitialSyncDisabled:
+			// Scheduling starts after the first manual trigger
+		default:
+			c.schedulePoolSync(tableConfig.TargetTable, now)
+		}
+	}
+
+	workers := c.config.WorkerPool.GetWorkers()
+	for i := 1; i <= workers; i++ {
+		actorName := fmt.Sprintf("worker-%d", i)
+		if _, err := ctx.SpawnNamed(c.poolWorkerProps(actorName), actorName); err != nil {
+			c.logger.Error("Failed to start pool worker",
+				zap.String("actor", actorName),
+				zap.Error(err),
+			)
+		}
+	}
+
+	c.logger.Info("Started sync worker pool",
+		zap.Int("workers", workers),
+		zap.Int("tables", len(c.pool.tables)),
+	)
+}
+
+// poolWorkerProps returns the props of a pool worker whose mailbox is instrumented under name
+func (c *CoordinatorActor) poolWorkerProps(name string) *actor.Props {
+	return actor.PropsFromProducer(func() actor.Actor {
+		return NewPoolWorkerActor(c.syncEngine, c.config.Defaults, c.logger, c.actorSystem)
+	}, MailboxOptions(name)...)
+}
+
+// schedulePoolSync queues a table's next scheduled sync unless one is already queued
+func (c *CoordinatorActor) schedulePoolSync(tableName string, due time.Time) {
+	pt := c.pool.tables[tableName]
+	if pt.scheduled != nil {
+		return
+	}
+	pt.scheduled = c.pool.push(pt.config, "", due)
+	if !pt.running {
+		c.reportPoolState(pt)
+	}
+}
+
+// reportPoolState sets the actor state reported for a table that is not syncing
+func (c *CoordinatorActor) reportPoolState(pt *poolTable) {
+	state, ok := c.tableStates[pt.config.TargetTable]
+	if !ok {
+		return
+	}
+	state.ActorState = ActorIdle
+	state.NextRun = time.Time{}
+	if pt.scheduled != nil {
+		state.ActorState = ActorScheduled
+		state.NextRun = pt.scheduled.due
+	}
+}
+
+// queuePoolSync queues a manual or job sync of a table, reporting whether the pool syncs the table
+func (c *CoordinatorActor) queuePoolSync(ctx actor.Context, tableName, jobID string) bool {
+	pt, ok := c.pool.tables[tableName]
+	if !ok {
+		return false
+	}
+	c.pool.push(pt.config, jobID, time.Time{})
+	c.dispatchPool(ctx)
+	return true
+}
+
+func (p *workerPool) push(tc config.TableConfig, jobID string, due time.Time) *poolItem {
+	p.seq++
+	item := &poolItem{
+		table: tc.TargetTable,
+		jobID: jobID,
+		due:   due,
+		rank:  itemRank(tc, jobID),
+		seq:   p.seq,
+	}
+	heap.Push(&p.pending, item)
+	return item
+}
+
+// dispatchPool moves due syncs to the ready queue and hands them to idle workers by rank. A table never
+// syncs on two workers at once; its queued syncs wait until the running one has finished
+func (c *CoordinatorActor) dispatchPool(ctx actor.Context) {
+	p := c.pool
+	now := time.Now()
+
+	for p.pending.Len() > 0 && !p.pending.items[0].due.After(now) {
+		item := heap.Pop(&p.pending).(*poolItem)
+		if c.overlapPoolTick(item) {
+			heap.Push(&p.ready, item)
+		}
+	}
+
+	var waiting []*poolItem
+	for len(p.idle) > 0 && p.ready.Len() > 0 {
+		item := heap.Pop(&p.ready).(*poolItem)
+		pt := p.tables[item.table]
+		if pt.running {
+			waiting = append(waiting, item)
+			continue
+		}
+
+		worker := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.busy[worker.GetId()] = item
+		pt.running = true
+		if pt.scheduled == item {
+			pt.scheduled = nil
+		}
+		item.preempt = new(atomic.Bool)
+		item.preempted = false
+
+		ctx.Send(worker, &poolSyncMessage{
+			TableConfig:     pt.config,
+			JobID:           item.jobID,
+			Scheduled:       item.jobID == "",
+			ChangeToken:     pt.changeToken,
+			RefreshInterval: pt.refreshInterval,
+			Preempt:         item.preempt,
+		})
+	}
+	for _, item := range waiting {
+		heap.Push(&p.ready, item)
+	}
+
+	c.preemptLowPriority()
+	c.armPoolTimer(ctx, now)
+}
+
+// preemptLowPriority asks workers running low priority loads to yield while high ranked syncs wait for a worker
+func (c *CoordinatorActor) preemptLowPriority() {
+	p := c.pool
+	if len(p.idle) > 0 {
+		return
+	}
+
+	waiting := 0
+	for _, item := range p.ready.items {
+		if item.rank == rankHigh && !p.tables[item.table].running {
+			waiting++
+		}
+	}
+	for _, item := range p.busy {
+		if item.rank == rankLow && item.preempt.Load() {
+			waiting--
+		}
+	}
+
+	for _, item := range p.busy {
+		if waiting <= 0 {
+			return
+		}
+		if item.rank != rankLow || item.preempt.Load() {
+			continue
+		}
+		c.logger.Info("Preempting low priority load", zap.String("table", item.table))
+		item.preempt.Store(true)
+		waiting--
+	}
+}
+
+// armPoolTimer wakes the coordinator when the earliest pending sync becomes due
+func (c *CoordinatorActor) armPoolTimer(ctx actor.Context, now time.Time) {
+	p := c.pool
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.pending.Len() == 0 {
+		return
+	}
+
+	self := ctx.Self()
+	p.timer = time.AfterFunc(p.pending.items[0].due.Sub(now), func() {
+		c.actorSystem.Root.Send(self, &poolTickMessage{})
+	})
+}
+
+// markPreempted flags the item of a load that yielded to a higher priority sync, so it is queued again
+func (c *CoordinatorActor) markPreempted(tableName string) {
+	for _, item := range c.pool.busy {
+		if item.table == tableName {
+			item.preempted = true
+		}
+	}
+}
+
+// completePoolSync frees the worker, keeps the table's change detection state and schedules its next sync
+func (c *CoordinatorActor) completePoolSync(ctx actor.Context, msg *poolSyncDone) {
+	p := c.pool
+	item, busy := p.busy[msg.Worker.GetId()]
+	if !busy {
+		// A worker replaced by the watchdog finishing after all; its table was already released
+		return
+	}
+	delete(p.busy, msg.Worker.GetId())
+	p.idle = append(p.idle, msg.Worker)
+
+	if pt, ok := p.tables[msg.TableName]; ok {
+		pt.running = false
+		pt.changeToken = msg.ChangeToken
+		pt.refreshInterval = msg.RefreshInterval
+		if item != nil && item.preempted {
+			// The yielded load keeps its place among syncs of its rank and runs when a worker is free
+			heap.Push(&p.ready, item)
+			if item.jobID == "" && pt.scheduled == nil {
+				pt.scheduled = item
+			}
+		}
+		c.reschedulePoolTable(pt)
+		c.reportPoolState(pt)
+	}
+
+	c.dispatchPool(ctx)
+}
+
+// reschedulePoolTable queues the next scheduled sync of a table after a sync has finished
+func (c *CoordinatorActor) reschedulePoolTable(pt *poolTable) {
+	if !pt.config.GetProtoActorTrigger(c.config.Defaults) {
+		return
+	}
+	delay := refreshDelay(pt.config, c.config.Defaults, pt.refreshInterval)
+	c.schedulePoolSync(pt.config.TargetTable, time.Now().Add(delay))
+}
+
+// failPoolWorker releases the table of a crashed worker, failing its job table. The worker
+// rejoins the idle workers when supervision has restarted it
+func (c *CoordinatorActor) failPoolWorker(ctx actor.Context, msg *poolWorkerFailed) {
+	p := c.pool
+	item, ok := p.busy[msg.Worker.GetId()]
+	if !ok {
+		return
+	}
+	delete(p.busy, msg.Worker.GetId())
+
+	if pt, found := p.tables[item.table]; found {
+		pt.running = false
+		c.reschedulePoolTable(pt)
+		c.reportPoolState(pt)
+	}
+	if item.jobID != "" {
+		c.completeJobTable(ctx, &SyncResultMessage{
+			TableName: item.table,
+			JobID:     item.jobID,
+			Error:     fmt.Errorf("pool worker crashed: %v", msg.Reason),
+		})
+	}
+
+	c.dispatchPool(ctx)
+}
+
+// addPoolWorker adds a started or restarted worker to the idle workers
+func (c *CoordinatorActor) addPoolWorker(ctx actor.Context, worker *actor.PID) {
+	for _, idle := range c.pool.idle {
+		if idle.Equal(worker) {
+			return
+		}
+	}
+	c.pool.idle = append(c.pool.idle, worker)
+	c.dispatchPool(ctx)
+}
+
+// stopWorkerPool stops the pool timer
+func (c *CoordinatorActor) stopWorkerPool() {
+	if c.pool != nil && c.pool.timer != nil {
+		c.pool.timer.Stop()
+		c.pool.timer = nil
+	}
+}
+
+// poolWorkerFor returns the worker syncing a table, if any
+func (c *CoordinatorActor) poolWorkerFor(tableName string) (string, bool) {
+	for worker, item := range c.pool.busy {
+		if item.table == tableName {
+			return worker, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,196 @@
+package actor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/alert"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+const defaultStalenessCheckInterval = 30 * time.Second
+
+// CheckStalenessMessage asks the coordinator to evaluate table staleness SLOs
+type CheckStalenessMessage struct{}
+
+// GetTableStatesMessage requests a snapshot of table states from the coordinator
+type GetTableStatesMessage struct{}
+
+// TableStatesResponse is the coordinator's reply to GetTableStatesMessage
+type TableStatesResponse struct {
+	States map[string]TableState
+}
+
+// TableState tracks the sync state of a table as seen by the coordinator
+type TableState struct {
+	TableName    string
+	MaxStaleness time.Duration
+	LastSuccess  time.Time
+	LastAttempt  time.Time
+	LastError    string
+	Stale        bool
+	StaleSince   time.Time
+	Restarts     int
+	LastCrash    string
+	LastCrashAt  time.Time
+	ActorStopped bool
+	ActorState   string
+	NextRun      time.Time
+	LastResult   *ActorResult
+	SyncingSince time.Time // start of the running sync, while ActorState is syncing
+	PingSentAt   time.Time // watchdog ping not answered yet, zero when none is pending
+}
+
+// recordResult updates the table state and metrics from a sync result
+func (c *CoordinatorActor) recordResult(msg *SyncResultMessage) {
+	status := "success"
+	switch {
+	case msg.Unchanged:
+		status = "unchanged"
+	case !msg.Success:
+		status = "failure"
+	}
+	metrics.SyncRunsTotal.WithLabelValues(msg.TableName, status).Inc()
+	if !msg.Unchanged {
+		metrics.SyncDurationSeconds.WithLabelValues(msg.TableName).Observe(msg.Duration.Seconds())
+		metrics.SyncRowsTotal.WithLabelValues(msg.TableName, "read").Add(float64(msg.RowsRead))
+		metrics.SyncRowsTotal.WithLabelValues(msg.TableName, "written").Add(float64(msg.RowsWritten))
+		metrics.SyncRowsTotal.WithLabelValues(msg.TableName, "skipped").Add(float64(msg.RowsSkipped))
+		metrics.SyncBytesTotal.WithLabelValues(msg.TableName).Add(float64(msg.BytesRead))
+	}
+
+	state, ok := c.tableStates[msg.TableName]
+	if !ok {
+		return
+	}
+	state.LastResult = actorResult(msg)
+	if !msg.Skipped {
+		state.LastAttempt = time.Now()
+		state.LastError = ""
+		if msg.Error != nil {
+			state.LastError = msg.Error.Error()
+		}
+	}
+	if msg.Success {
+		state.LastSuccess = time.Now()
+		c.evaluateStaleness(state, time.Now())
+	}
+}
+
+// restoreLastRuns seeds the table states with the last attempted and successful runs from the sync history,
+// so last sync times and staleness survive restarts
+func (c *CoordinatorActor) restoreLastRuns() {
+	if c.syncEngine == nil || c.syncEngine.History == nil {
+		return
+	}
+
+	runs, err := c.syncEngine.History.LastRuns()
+	if err != nil {
+		c.logger.Warn("Failed to restore last sync times from history", zap.Error(err))
+		return
+	}
+	for _, run := range runs {
+		state, ok := c.tableStates[run.TableName]
+		if !ok {
+			continue
+		}
+		state.LastAttempt = run.LastAttempt
+		state.LastError = run.LastError
+		if run.LastSuccess != nil {
+			state.LastSuccess = *run.LastSuccess
+		}
+	}
+}
+
+// checkStaleness evaluates the staleness SLO of every table
+func (c *CoordinatorActor) checkStaleness() {
+	now := time.Now()
+	for _, state := range c.tableStates {
+		c.evaluateStaleness(state, now)
+	}
+}
+
+// evaluateStaleness flips the stale flag of a table and raises alerts on transitions
+func (c *CoordinatorActor) evaluateStaleness(state *TableState, now time.Time) {
+	reference := state.LastSuccess
+	if reference.IsZero() {
+		reference = c.startedAt
+	}
+	age := now.Sub(reference)
+	metrics.TableSecondsSinceSuccess.WithLabelValues(state.TableName).Set(age.Seconds())
+
+	if state.MaxStaleness <= 0 {
+		return
+	}
+
+	stale := age > state.MaxStaleness
+	if stale == state.Stale {
+		return
+	}
+
+	state.Stale = stale
+	if stale {
+		state.StaleSince = now
+		metrics.TableStale.WithLabelValues(state.TableName).Set(1)
+		c.notifier.Notify(alert.Event{
+			Type:    alert.EventTableStale,
+			Table:   state.TableName,
+			Message: fmt.Sprintf("Table %s has not synced successfully for %s (max_staleness %s)", state.TableName, age.Round(time.Second), state.MaxStaleness),
+			Details: map[string]interface{}{
+				"max_staleness_seconds": state.MaxStaleness.Seconds(),
+				"age_seconds":           age.Seconds(),
+			},
+		})
+		return
+	}
+
+	state.StaleSince = time.Time{}
+	metrics.TableStale.WithLabelValues(state.TableName).Set(0)
+	c.notifier.Notify(alert.Event{
+		Type:    alert.EventTableRecovered,
+		Table:   state.TableName,
+		Message: fmt.Sprintf("Table %s is within its staleness SLO again", state.TableName),
+	})
+}
+
+// snapshotTableStates copies the table states for a request-reply response
+func (c *CoordinatorActor) snapshotTableStates() *TableStatesResponse {
+	states := make(map[string]TableState, len(c.tableStates))
+	for name, state := range c.tableStates {
+		states[name] = *state
+	}
+	return &TableStatesResponse{States: states}
+}
+
+// scheduleStalenessCheck schedules the next staleness evaluation
+func (c *CoordinatorActor) scheduleStalenessCheck(ctx actor.Context) {
+	interval := time.Duration(c.config.Alerts.StalenessCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultStalenessCheckInterval
+	}
+
+	pid := ctx.Self()
+
+	c.stalenessMu.Lock()
+	if c.stalenessTimer != nil {
+		c.stalenessTimer.Stop()
+	}
+	c.stalenessTimer = time.AfterFunc(interval, func() {
+		if c.actorSystem != nil {
+			c.actorSystem.Root.Send(pid, &CheckStalenessMessage{})
+		}
+	})
+	c.stalenessMu.Unlock()
+}
+
+func (c *CoordinatorActor) stopStalenessCheck() {
+	c.stalenessMu.Lock()
+	if c.stalenessTimer != nil {
+		c.stalenessTimer.Stop()
+		c.stalenessTimer = nil
+	}
+	c.stalenessMu.Unlock()
+}
@@ -0,0 +1,117 @@
+package actor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/alert"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+const (
+	defaultMaxRestarts       = 5
+	defaultRestartWindow     = 5 * time.Minute
+	defaultInitialBackoff    = time.Second
+	defaultMaxRestartBackoff = time.Minute
+)
+
+// HandleFailure implements actor.SupervisorStrategy for the coordinator's sync actors.
+// Failing actors are restarted with exponential backoff; once they exceed the allowed
+// restarts within the window they are stopped and an alert is raised.
+func (c *CoordinatorActor) HandleFailure(actorSystem *actor.ActorSystem, supervisor actor.Supervisor, child *actor.PID, rs *actor.RestartStatistics, reason interface{}, message interface{}) {
+	maxRestarts, window, initialBackoff, maxBackoff := c.supervisionSettings()
+	tableName := c.tableForPID(child)
+
+	rs.Fail()
+	failures := rs.NumberOfFailures(window)
+
+	if c.pool != nil {
+		c.actorSystem.Root.Send(c.self, &poolWorkerFailed{Worker: child, Reason: reason})
+	}
+
+	metrics.ActorRestartsTotal.WithLabelValues(tableName).Inc()
+	if state, ok := c.tableStates[tableName]; ok {
+		state.Restarts++
+		state.LastCrash = fmt.Sprint(reason)
+		state.LastCrashAt = time.Now()
+		state.ActorState = ActorIdle
+		state.NextRun = time.Time{}
+	}
+
+	if failures > maxRestarts {
+		rs.Reset()
+		c.logger.Error("Sync actor exceeded restart limit, stopping",
+			zap.String("table", tableName),
+			zap.Int("failures", failures),
+			zap.Duration("window", window),
+			zap.Any("reason", reason),
+		)
+		if state, ok := c.tableStates[tableName]; ok {
+			state.ActorStopped = true
+		}
+		supervisor.StopChildren(child)
+		c.notifier.Notify(alert.Event{
+			Type:    alert.EventActorStopped,
+			Table:   tableName,
+			Message: fmt.Sprintf("Sync actor for %s crashed %d times within %s and was stopped", tableName, failures, window),
+			Details: map[string]interface{}{
+				"reason": fmt.Sprint(reason),
+			},
+		})
+		return
+	}
+
+	backoff := initialBackoff << uint(failures-1)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	c.logger.Warn("Sync actor crashed, restarting",
+		zap.String("table", tableName),
+		zap.Int("failures", failures),
+		zap.Duration("backoff", backoff),
+		zap.Any("reason", reason),
+		zap.Any("message", message),
+	)
+
+	time.AfterFunc(backoff, func() {
+		supervisor.RestartChildren(child)
+	})
+}
+
+// supervisionSettings returns the configured restart policy with defaults applied
+func (c *CoordinatorActor) supervisionSettings() (int, time.Duration, time.Duration, time.Duration) {
+	cfg := c.config.Supervision
+
+	maxRestarts := cfg.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+	window := time.Duration(cfg.Window) * time.Second
+	if window <= 0 {
+		window = defaultRestartWindow
+	}
+	initialBackoff := time.Duration(cfg.InitialBackoff) * time.Second
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+	maxBackoff := time.Duration(cfg.MaxBackoff) * time.Second
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxRestartBackoff
+	}
+
+	return maxRestarts, window, initialBackoff, maxBackoff
+}
+
+// tableForPID returns the table handled by a sync actor
+func (c *CoordinatorActor) tableForPID(pid *actor.PID) string {
+	for tableName, childPID := range c.syncActors {
+		if childPID.Equal(pid) {
+			return tableName
+		}
+	}
+	return pid.GetId()
+}
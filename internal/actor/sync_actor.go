@@ -2,30 +2,49 @@ package actor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/asynkron/protoactor-go/actor"
+	"github.com/asynkron/protoactor-go/eventstream"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/anypb"
 
+	"mssql-postgres-sync/internal/alert"
 	"mssql-postgres-sync/internal/config"
 	syncpkg "mssql-postgres-sync/internal/sync"
+	"mssql-postgres-sync/internal/trigger"
 )
 
 // Messages
 type SyncTableMessage struct {
 	TableConfig config.TableConfig
+	JobID       string
 }
 
-type ScheduleSyncMessage struct{}
+type ScheduleSyncMessage struct {
+	Overlapped bool // the tick fired while the table was syncing
+}
 
 type SyncResultMessage struct {
-	TableName string
-	Success   bool
-	Error     error
-	Duration  time.Duration
+	TableName   string
+	JobID       string
+	Success     bool
+	Skipped     bool
+	Unchanged   bool // change detection found no source changes, so the sync was not needed
+	Error       error
+	Duration    time.Duration
+	RowsSynced  int
+	RowsRead    int
+	RowsWritten int
+	RowsSkipped int
+	BytesRead   int64
+	Estimated   int64 // source rows estimated before the sync, 0 when not estimated
+	Preempted   bool  // the load yielded to a higher priority sync in the worker pool and will run again
 }
 
 // SyncActor handles table synchronization with scheduling
@@ -38,6 +57,20 @@ type SyncActor struct {
 	cancelFunc   context.CancelFunc
 	timerMu      sync.Mutex
 	nextSchedule *time.Timer
+	scheduled    bool
+
+	// Change detection state: the token of the last successful sync and the backed-off polling interval
+	changeToken     string
+	refreshInterval time.Duration
+
+	// nextRun is when the scheduled timer fires, zero when nothing is scheduled
+	nextRun time.Time
+
+	// preempt asks a pool worker's load to yield between chunks, nil for sync actors
+	preempt *atomic.Bool
+
+	// runningJob is the job of the sync in progress, empty for scheduled syncs
+	runningJob string
 }
 
 // NewSyncActor creates a new sync actor
@@ -53,7 +86,7 @@ func NewSyncActor(syncEngine *syncpkg.SyncEngine, tableConfig config.TableConfig
 
 // Receive handles incoming messages
 func (a *SyncActor) Receive(ctx actor.Context) {
-	switch ctx.Message().(type) {
+	switch msg := ctx.Message().(type) {
 	case *actor.Started:
 		a.logger.Info("SyncActor started",
 			zap.String("source_table", a.tableConfig.SourceTable),
@@ -62,11 +95,24 @@ func (a *SyncActor) Receive(ctx actor.Context) {
 
 		// Start scheduled sync if enabled
 		if a.tableConfig.GetProtoActorTrigger(a.defaults) {
-			ctx.Send(ctx.Self(), &ScheduleSyncMessage{})
+			switch a.tableConfig.GetInitialSync(a.defaults) {
+			case config.InitialSyncDeferred:
+				// Wait for the first scheduled tick
+				a.scheduleNextSync(ctx)
+			case config.InitialSyncDisabled:
+				// Scheduling starts after the first manual trigger
+				a.logger.Info("Initial sync disabled, waiting for manual trigger",
+					zap.String("table", a.tableConfig.TargetTable),
+				)
+			default:
+				ctx.Send(ctx.Self(), &ScheduleSyncMessage{})
+			}
 		}
 
 	case *ScheduleSyncMessage:
-		a.performSync(ctx)
+		if !msg.Overlapped || a.tableConfig.GetOverlap(a.defaults) != config.OverlapSkip {
+			a.runScheduledTick(ctx)
+		}
 		// Schedule next sync
 		if a.tableConfig.GetProtoActorTrigger(a.defaults) {
 			a.scheduleNextSync(ctx)
@@ -74,7 +120,21 @@ func (a *SyncActor) Receive(ctx actor.Context) {
 
 	case *SyncTableMessage:
 		// Manual trigger
-		a.performSync(ctx)
+		if !a.runRequestedSync(ctx, msg.JobID) {
+			return
+		}
+		if a.tableConfig.GetProtoActorTrigger(a.defaults) && !a.scheduled {
+			a.scheduleNextSync(ctx)
+		}
+
+	case *pingMessage:
+		ctx.Send(ctx.Parent(), &pongMessage{TableName: a.tableConfig.TargetTable})
+
+	case *actor.Restarting:
+		a.logger.Warn("SyncActor restarting",
+			zap.String("source_table", a.tableConfig.SourceTable),
+		)
+		a.stopSchedule()
 
 	case *actor.Stopping:
 		a.logger.Info("SyncActor stopping",
@@ -93,8 +153,105 @@ func (a *SyncActor) Receive(ctx actor.Context) {
 	}
 }
 
-// performSync executes the synchronization
-func (a *SyncActor) performSync(ctx actor.Context) {
+// runScheduledTick runs a scheduled sync unless the table is in a blackout window or the database circuit breaker is open
+func (a *SyncActor) runScheduledTick(ctx actor.Context) {
+	if window, inBlackout := a.tableConfig.ActiveBlackout(a.defaults, time.Now()); inBlackout {
+		a.logger.Info("Skipping scheduled sync during blackout window",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.String("blackout", window.String()),
+		)
+	} else if a.syncEngine.DB.Available() {
+		a.runScheduledSync(ctx)
+	} else {
+		a.logger.Debug("Skipping scheduled sync, database circuit breaker is open",
+			zap.String("table", a.tableConfig.TargetTable),
+		)
+	}
+}
+
+// runRequestedSync runs a manual or job sync, rejecting it as skipped during a blackout window, and reports whether it ran
+func (a *SyncActor) runRequestedSync(ctx actor.Context, jobID string) bool {
+	if window, inBlackout := a.tableConfig.ActiveBlackout(a.defaults, time.Now()); inBlackout {
+		a.logger.Warn("Rejected manual sync during blackout window",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.String("blackout", window.String()),
+		)
+		if ctx.Parent() != nil {
+			ctx.Send(ctx.Parent(), &SyncResultMessage{
+				TableName: a.tableConfig.TargetTable,
+				JobID:     jobID,
+				Skipped:   true,
+				Error:     fmt.Errorf("blackout window %s", window.String()),
+			})
+		}
+		return false
+	}
+	a.performSync(ctx, jobID)
+	return true
+}
+
+// runScheduledSync runs a scheduled sync, skipping it and backing off when change detection finds no changes
+func (a *SyncActor) runScheduledSync(ctx actor.Context) {
+	if a.tableConfig.ChangeDetection == nil {
+		a.performSync(ctx, "")
+		return
+	}
+
+	detectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	token, err := a.syncEngine.ChangeToken(detectCtx, a.tableConfig)
+	cancel()
+	if err != nil {
+		a.logger.Warn("Change detection failed, running full sync",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.Error(err),
+		)
+		a.changeToken = ""
+		a.refreshInterval = 0
+		a.performSync(ctx, "")
+		return
+	}
+
+	if token == a.changeToken {
+		a.backOffRefresh()
+		a.logger.Debug("Source unchanged, skipping sync",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.Duration("next_check", a.refreshInterval),
+		)
+		if ctx.Parent() != nil {
+			ctx.Send(ctx.Parent(), &SyncResultMessage{
+				TableName: a.tableConfig.TargetTable,
+				Success:   true,
+				Unchanged: true,
+			})
+		}
+		return
+	}
+
+	// The token is read before syncing so changes made during the sync are picked up next time
+	a.refreshInterval = 0
+	a.changeToken = ""
+	if a.performSync(ctx, "") {
+		a.changeToken = token
+	}
+}
+
+// backOffRefresh doubles the polling interval up to the table's max_refresh_rate
+func (a *SyncActor) backOffRefresh() {
+	base := time.Duration(a.tableConfig.GetRefreshRate(a.defaults)) * time.Second
+	limit := time.Duration(a.tableConfig.GetMaxRefreshRate(a.defaults)) * time.Second
+
+	next := a.refreshInterval * 2
+	if next < base*2 {
+		next = base * 2
+	}
+	if next > limit {
+		next = limit
+	}
+	a.refreshInterval = next
+}
+
+// performSync executes the synchronization, tagging the result with the job that requested it, and reports whether it succeeded
+func (a *SyncActor) performSync(ctx actor.Context, jobID string) bool {
 	startTime := time.Now()
 
 	a.logger.Info("Performing sync",
@@ -102,26 +259,63 @@ func (a *SyncActor) performSync(ctx actor.Context) {
 		zap.String("target_table", a.tableConfig.TargetTable),
 	)
 
-	// Create context with timeout
-	syncCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	a.runningJob = jobID
+	a.reportState(ctx, ActorSyncing)
+	defer func() {
+		a.runningJob = ""
+		a.reportState(ctx, a.restingState())
+	}()
+
+	// Create context with timeout, cancelled early when a newer request restarts the sync
+	restartCtx, restart := context.WithCancelCause(context.Background())
+	syncCtx, cancel := context.WithTimeout(restartCtx, 10*time.Minute)
+	if a.preempt != nil {
+		syncCtx = syncpkg.WithPreemption(syncCtx, a.preempt)
+	}
 	a.cancelFunc = cancel
+	unregister := registerRunningSync(a.tableConfig.TargetTable, restart)
 	defer func() {
+		unregister()
 		cancel()
+		restart(nil)
 		a.cancelFunc = nil
 	}()
 
 	// Perform sync
-	err := a.syncEngine.SyncTable(syncCtx, a.tableConfig)
+	syncResult, err := a.syncEngine.SyncTable(syncCtx, a.tableConfig)
 	duration := time.Since(startTime)
+	restarted := err != nil && errors.Is(context.Cause(syncCtx), errSyncRestarted)
+	if restarted {
+		err = errSyncRestarted
+	}
 
 	result := &SyncResultMessage{
-		TableName: a.tableConfig.TargetTable,
-		Success:   err == nil,
-		Error:     err,
-		Duration:  duration,
+		TableName:   a.tableConfig.TargetTable,
+		JobID:       jobID,
+		Success:     err == nil,
+		Error:       err,
+		Duration:    duration,
+		RowsSynced:  syncResult.RowsSynced,
+		RowsRead:    syncResult.RowsRead,
+		RowsWritten: syncResult.RowsWritten,
+		RowsSkipped: syncResult.RowsSkipped,
+		BytesRead:   syncResult.BytesRead,
+		Estimated:   syncResult.RowsEstimated,
+		Skipped:     restarted,
+		Preempted:   errors.Is(err, syncpkg.ErrPreempted),
 	}
 
-	if err != nil {
+	if restarted {
+		a.logger.Info("Sync cancelled by a newer request",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.Duration("duration", duration),
+		)
+	} else if result.Preempted {
+		a.logger.Info("Sync preempted by a higher priority sync",
+			zap.String("table", a.tableConfig.TargetTable),
+			zap.Duration("duration", duration),
+		)
+	} else if err != nil {
 		a.logger.Error("Sync failed",
 			zap.String("table", a.tableConfig.TargetTable),
 			zap.Error(err),
@@ -131,6 +325,9 @@ func (a *SyncActor) performSync(ctx actor.Context) {
 		a.logger.Info("Sync completed successfully",
 			zap.String("table", a.tableConfig.TargetTable),
 			zap.Duration("duration", duration),
+			zap.Int("rows_written", syncResult.RowsWritten),
+			zap.Int64("bytes_read", syncResult.BytesRead),
+			zap.Float64("rows_per_second", syncResult.RowsPerSecond()),
 		)
 	}
 
@@ -138,14 +335,12 @@ func (a *SyncActor) performSync(ctx actor.Context) {
 	if ctx.Parent() != nil {
 		ctx.Send(ctx.Parent(), result)
 	}
+	return err == nil
 }
 
 // scheduleNextSync schedules the next sync operation
 func (a *SyncActor) scheduleNextSync(ctx actor.Context) {
-	refreshRate := time.Duration(a.tableConfig.GetRefreshRate(a.defaults)) * time.Second
-	if refreshRate <= 0 {
-		refreshRate = time.Second
-	}
+	refreshRate := refreshDelay(a.tableConfig, a.defaults, a.refreshInterval)
 
 	a.logger.Info("Scheduling next sync",
 		zap.String("table", a.tableConfig.TargetTable),
@@ -153,6 +348,11 @@ func (a *SyncActor) scheduleNextSync(ctx actor.Context) {
 	)
 
 	pid := ctx.Self()
+	tableName := a.tableConfig.TargetTable
+	policy := a.tableConfig.GetOverlap(a.defaults)
+	a.scheduled = true
+	a.nextRun = time.Now().Add(refreshRate)
+	a.reportState(ctx, ActorScheduled)
 
 	a.timerMu.Lock()
 	if a.nextSchedule != nil {
@@ -160,13 +360,27 @@ func (a *SyncActor) scheduleNextSync(ctx actor.Context) {
 	}
 	a.nextSchedule = time.AfterFunc(refreshRate, func() {
 		if a.actorSystem != nil {
-			a.actorSystem.Root.Send(pid, &ScheduleSyncMessage{})
+			a.actorSystem.Root.Send(pid, &ScheduleSyncMessage{Overlapped: a.overlapTick(tableName, policy)})
 		}
 	})
 	a.timerMu.Unlock()
 }
 
+// refreshDelay returns the time until a table's next scheduled sync: its refresh rate, or the
+// change detection interval when that has backed off further
+func refreshDelay(tc config.TableConfig, defaults config.DefaultConfig, refreshInterval time.Duration) time.Duration {
+	delay := time.Duration(tc.GetRefreshRate(defaults)) * time.Second
+	if refreshInterval > delay {
+		delay = refreshInterval
+	}
+	if delay <= 0 {
+		delay = time.Second
+	}
+	return delay
+}
+
 func (a *SyncActor) stopSchedule() {
+	a.nextRun = time.Time{}
 	a.timerMu.Lock()
 	if a.nextSchedule != nil {
 		a.nextSchedule.Stop()
@@ -177,21 +391,48 @@ func (a *SyncActor) stopSchedule() {
 
 // CoordinatorActor coordinates all sync actors
 type CoordinatorActor struct {
-	syncEngine  *syncpkg.SyncEngine
-	config      *config.Config
-	logger      *zap.Logger
-	syncActors  map[string]*actor.PID
-	actorSystem *actor.ActorSystem
+	syncEngine      *syncpkg.SyncEngine
+	config          *config.Config
+	logger          *zap.Logger
+	notifier        *alert.Notifier
+	syncActors      map[string]*actor.PID
+	tableStates     map[string]*TableState
+	jobs            map[string]*SyncJob
+	jobOrder        []string
+	jobWaiters      map[string][]*actor.PID
+	remoteSyncs     map[string]*actor.PID // nodes waiting on a table synced here for their job, by job and table
+	queuedRuns      map[string]string     // job of each table's requested run that has not started yet, under the queue overlap policy
+	coalesced       map[string][]string   // jobs sharing the result of a queued run, by its job and table
+	pool            *workerPool           // replaces the sync actors when worker_pool is enabled
+	self            *actor.PID
+	pendingTriggers map[string]*pendingTrigger
+	maintenancePID  *actor.PID
+	pipelinePID     *actor.PID
+	backfillPID     *actor.PID
+	actorSystem     *actor.ActorSystem
+	startedAt       time.Time
+	stalenessMu     sync.Mutex
+	stalenessTimer  *time.Timer
+	watchdogTimer   *time.Timer
+	deadLetterSub   *eventstream.Subscription
 }
 
 // NewCoordinatorActor creates a new coordinator actor
-func NewCoordinatorActor(syncEngine *syncpkg.SyncEngine, cfg *config.Config, logger *zap.Logger, actorSystem *actor.ActorSystem) actor.Actor {
+func NewCoordinatorActor(syncEngine *syncpkg.SyncEngine, cfg *config.Config, logger *zap.Logger, actorSystem *actor.ActorSystem, notifier *alert.Notifier) actor.Actor {
 	return &CoordinatorActor{
-		syncEngine:  syncEngine,
-		config:      cfg,
-		logger:      logger,
-		syncActors:  make(map[string]*actor.PID),
-		actorSystem: actorSystem,
+		syncEngine:      syncEngine,
+		config:          cfg,
+		logger:          logger,
+		notifier:        notifier,
+		syncActors:      make(map[string]*actor.PID),
+		tableStates:     make(map[string]*TableState),
+		jobs:            make(map[string]*SyncJob),
+		jobWaiters:      make(map[string][]*actor.PID),
+		remoteSyncs:     make(map[string]*actor.PID),
+		queuedRuns:      make(map[string]string),
+		coalesced:       make(map[string][]string),
+		pendingTriggers: make(map[string]*pendingTrigger),
+		actorSystem:     actorSystem,
 	}
 }
 
@@ -200,11 +441,78 @@ func (c *CoordinatorActor) Receive(ctx actor.Context) {
 	switch msg := ctx.Message().(type) {
 	case *actor.Started:
 		c.logger.Info("CoordinatorActor started")
-		c.startSyncActors(ctx)
+		c.startedAt = time.Now()
+		c.self = ctx.Self()
+		c.watchDeadLetters(ctx)
+		if c.config.WorkerPool.Enabled {
+			c.startWorkerPool(ctx)
+		} else {
+			c.startSyncActors(ctx)
+		}
+		c.restoreLastRuns()
+		c.startMaintenanceActor(ctx)
+		c.startBackfillActor(ctx)
+		c.startPipelineActor(ctx)
+		c.startTriggerListeners(ctx)
+		c.scheduleStalenessCheck(ctx)
+		c.scheduleWatchdog(ctx)
+
+	case *CheckStalenessMessage:
+		c.checkStaleness()
+		c.scheduleStalenessCheck(ctx)
+
+	case *GetTableStatesMessage:
+		ctx.Respond(c.snapshotTableStates())
+
+	case *GetActorsMessage:
+		ctx.Respond(c.actorsSnapshot())
+
+	case *actorStateMessage:
+		c.recordActorState(msg)
+
+	case *checkWatchdogMessage:
+		c.checkWatchdog(ctx)
+		c.scheduleWatchdog(ctx)
+
+	case *pongMessage:
+		if state, ok := c.tableStates[msg.TableName]; ok {
+			state.PingSentAt = time.Time{}
+		}
+
+	case *deadLetterMessage:
+		c.handleDeadLetter(ctx, msg)
+
+	case *anypb.Any:
+		c.handleClusterMessage(ctx, msg)
+
+	case *poolWorkerReady:
+		c.addPoolWorker(ctx, msg.Worker)
+
+	case *poolSyncDone:
+		c.completePoolSync(ctx, msg)
+
+	case *poolWorkerFailed:
+		c.failPoolWorker(ctx, msg)
+
+	case *poolTickMessage:
+		c.dispatchPool(ctx)
 
 	case *SyncResultMessage:
+		if msg.Preempted {
+			// The worker pool queues the load again, so it is neither a failure nor a job result
+			c.markPreempted(msg.TableName)
+			return
+		}
+		c.recordResult(msg)
+		c.requestAnalyze(ctx, msg)
+		if msg.JobID != "" {
+			c.completeJobTable(ctx, msg)
+		}
+
 		// Log sync results
-		if msg.Success {
+		if msg.Unchanged {
+			c.logger.Debug("Table sync result: UNCHANGED", zap.String("table", msg.TableName))
+		} else if msg.Success {
 			c.logger.Info("Table sync result: SUCCESS",
 				zap.String("table", msg.TableName),
 				zap.Duration("duration", msg.Duration),
@@ -218,29 +526,65 @@ func (c *CoordinatorActor) Receive(ctx actor.Context) {
 		}
 
 	case *TriggerSyncMessage:
-		// Manual trigger for specific table
-		if pid, ok := c.syncActors[msg.TableName]; ok {
-			ctx.Send(pid, &SyncTableMessage{TableConfig: msg.TableConfig})
-			c.logger.Info("Triggered manual sync", zap.String("table", msg.TableName))
-		} else {
+		// Manual trigger for specific table, tracked as a single-table job
+		if !c.hasSyncActor(msg.TableName) && !c.isRemoteTable(msg.TableName) {
 			c.logger.Warn("Sync actor not found", zap.String("table", msg.TableName))
 		}
+		job := newSyncJob(syncpkg.NewBatchID(), JobModeParallel, []string{msg.TableName})
+		c.logger.Info("Triggered manual sync",
+			zap.String("table", msg.TableName),
+			zap.String("job_id", job.ID),
+		)
+		c.startJob(ctx, job)
+		ctx.Respond(c.jobSnapshot(job.ID))
 
 	case *TriggerAllSyncMessage:
-		// Trigger all tables
-		c.logger.Info("Triggering sync for all tables")
-		for tableName, pid := range c.syncActors {
-			// Find config for this table
-			for _, tc := range c.config.Tables {
-				if tc.TargetTable == tableName {
-					ctx.Send(pid, &SyncTableMessage{TableConfig: tc})
-					break
-				}
+		// Trigger all tables as a tracked batch
+		mode := msg.Mode
+		if mode == "" {
+			mode = c.config.Defaults.SyncAllMode
+		}
+		tableNames := make([]string, 0, len(c.config.Tables))
+		for _, tc := range c.config.Tables {
+			if msg.Family != "" && tc.Family != msg.Family {
+				continue
 			}
+			tableNames = append(tableNames, tc.TargetTable)
 		}
+		job := newSyncJob(syncpkg.NewBatchID(), mode, tableNames)
+		c.logger.Info("Triggering sync for all tables",
+			zap.String("job_id", job.ID),
+			zap.String("mode", job.Mode),
+			zap.String("family", msg.Family),
+		)
+		c.startJob(ctx, job)
+		ctx.Respond(c.jobSnapshot(job.ID))
+
+	case *TriggerTablesMessage:
+		c.handleTriggerTables(ctx, msg)
+
+	case *fireTriggerMessage:
+		c.fireTrigger(ctx, msg.Source)
+
+	case *pipelineDoneMessage:
+		c.completeJobPipeline(ctx, msg)
+
+	case *GetJobMessage:
+		ctx.Respond(c.jobSnapshot(msg.JobID))
+
+	case *WaitJobMessage:
+		c.waitForJob(ctx, msg.JobID)
+
+	case *StartBackfillMessage, *StopBackfillMessage:
+		c.forwardBackfill(ctx)
 
 	case *actor.Stopping:
 		c.logger.Info("CoordinatorActor stopping")
+		c.stopStalenessCheck()
+		c.stopWatchdog()
+		c.stopPendingTriggers()
+		c.stopWatchingDeadLetters()
+		c.stopWorkerPool()
 
 	case *actor.Stopped:
 		c.logger.Info("CoordinatorActor stopped")
@@ -249,32 +593,100 @@ func (c *CoordinatorActor) Receive(ctx actor.Context) {
 
 // startSyncActors starts all sync actors based on configuration
 func (c *CoordinatorActor) startSyncActors(ctx actor.Context) {
-	for _, tableConfig := range c.config.Tables {
-		actorName := fmt.Sprintf("sync-%s", sanitizeActorName(tableConfig.TargetTable))
-
-		props := actor.PropsFromProducer(func() actor.Actor {
-			return NewSyncActor(c.syncEngine, tableConfig, c.config.Defaults, c.logger, c.actorSystem)
-		})
-
-		pid, err := ctx.SpawnNamed(props, actorName)
+	for _, tableConfig := range c.localTables() {
+		pid, err := c.spawnSyncActor(ctx, tableConfig)
 		if err != nil {
 			c.logger.Error("Failed to start sync actor",
-				zap.String("actor", actorName),
+				zap.String("actor", syncActorName(tableConfig.TargetTable)),
 				zap.Error(err),
 			)
 			continue
 		}
 
 		c.syncActors[tableConfig.TargetTable] = pid
+		c.tableStates[tableConfig.TargetTable] = &TableState{
+			TableName:    tableConfig.TargetTable,
+			MaxStaleness: tableConfig.GetMaxStaleness(c.config.Defaults),
+		}
 
 		c.logger.Info("Started sync actor",
-			zap.String("actor", actorName),
+			zap.String("actor", pid.GetId()),
 			zap.String("source_table", tableConfig.SourceTable),
 			zap.String("target_table", tableConfig.TargetTable),
+			zap.String("tenant", tableConfig.Tenant),
 		)
 	}
 }
 
+// spawnSyncActor spawns the sync actor of a table as a child of the coordinator
+func (c *CoordinatorActor) spawnSyncActor(ctx actor.Context, tableConfig config.TableConfig) (*actor.PID, error) {
+	return ctx.SpawnNamed(c.syncActorProps(tableConfig), syncActorName(tableConfig.TargetTable))
+}
+
+// syncActorProps returns the props of a table's sync actor
+func (c *CoordinatorActor) syncActorProps(tableConfig config.TableConfig) *actor.Props {
+	return actor.PropsFromProducer(func() actor.Actor {
+		return NewSyncActor(c.syncEngine, tableConfig, c.config.Defaults, c.logger, c.actorSystem)
+	}, MailboxOptions(syncActorName(tableConfig.TargetTable))...)
+}
+
+// startTriggerListeners starts a listener actor for every configured message queue or sentinel trigger
+func (c *CoordinatorActor) startTriggerListeners(ctx actor.Context) {
+	for _, triggerConfig := range c.config.Triggers {
+		// In cluster mode each trigger listens on one node only, so a trigger starts one job
+		if !c.config.Cluster.IsLocal(c.config.Cluster.Owner("trigger/" + triggerConfig.Name)) {
+			continue
+		}
+
+		listener, err := trigger.NewListener(triggerConfig, c.syncEngine.DB.Source)
+		if err != nil {
+			c.logger.Error("Invalid trigger configuration", zap.Error(err))
+			continue
+		}
+
+		actorName := fmt.Sprintf("trigger-%s", sanitizeActorName(triggerConfig.Name))
+		triggerConfig := triggerConfig
+		props := actor.PropsFromProducer(func() actor.Actor {
+			return NewTriggerListenerActor(triggerConfig, listener, c.logger, c.actorSystem)
+		}, MailboxOptions(actorName)...)
+
+		if _, err := ctx.SpawnNamed(props, actorName); err != nil {
+			c.logger.Error("Failed to start trigger listener",
+				zap.String("actor", actorName),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// hasSyncActor reports whether a table is synced on this node, by its sync actor or the worker pool
+func (c *CoordinatorActor) hasSyncActor(tableName string) bool {
+	if c.pool != nil {
+		_, ok := c.pool.tables[tableName]
+		return ok
+	}
+	_, ok := c.syncActors[tableName]
+	return ok
+}
+
+// sendTableSync hands a requested sync to the table's sync actor, or queues it for the worker pool
+func (c *CoordinatorActor) sendTableSync(ctx actor.Context, tc config.TableConfig, jobID string) bool {
+	if c.pool != nil {
+		return c.queuePoolSync(ctx, tc.TargetTable, jobID)
+	}
+	pid, ok := c.syncActors[tc.TargetTable]
+	if !ok {
+		return false
+	}
+	ctx.Send(pid, &SyncTableMessage{TableConfig: tc, JobID: jobID})
+	return true
+}
+
+// syncActorName returns the child name of a table's sync actor
+func syncActorName(tableName string) string {
+	return fmt.Sprintf("sync-%s", sanitizeActorName(tableName))
+}
+
 func sanitizeActorName(name string) string {
 	sanitized := strings.ReplaceAll(name, " ", "-")
 	sanitized = strings.ReplaceAll(sanitized, ".", "-")
@@ -283,11 +695,15 @@ func sanitizeActorName(name string) string {
 	return sanitized
 }
 
-// TriggerSyncMessage triggers sync for a specific table
+// TriggerSyncMessage triggers sync for a specific table. Both trigger messages are
+// answered with a JobResponse describing the job that tracks the requested syncs.
 type TriggerSyncMessage struct {
 	TableName   string
 	TableConfig config.TableConfig
 }
 
-// TriggerAllSyncMessage triggers sync for all tables
-type TriggerAllSyncMessage struct{}
+// TriggerAllSyncMessage triggers sync for all tables as a batch
+type TriggerAllSyncMessage struct {
+	Mode   string // parallel, sequential or dependency; empty uses the configured default
+	Family string // limits the batch to the tenant tables of one family
+}
@@ -0,0 +1,102 @@
+package actor
+
+import (
+	"context"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/trigger"
+)
+
+const (
+	listenerInitialBackoff = time.Second
+	listenerMaxBackoff     = time.Minute
+)
+
+// TriggerListenerActor runs a trigger listener and asks the coordinator to sync its tables every time it fires
+type TriggerListenerActor struct {
+	config      config.TriggerConfig
+	listener    trigger.Listener
+	logger      *zap.Logger
+	actorSystem *actor.ActorSystem
+	cancel      context.CancelFunc
+}
+
+// NewTriggerListenerActor creates a new trigger listener actor
+func NewTriggerListenerActor(cfg config.TriggerConfig, listener trigger.Listener, logger *zap.Logger, actorSystem *actor.ActorSystem) actor.Actor {
+	return &TriggerListenerActor{
+		config:      cfg,
+		listener:    listener,
+		logger:      logger,
+		actorSystem: actorSystem,
+	}
+}
+
+// Receive handles incoming messages
+func (a *TriggerListenerActor) Receive(ctx actor.Context) {
+	switch ctx.Message().(type) {
+	case *actor.Started:
+		runCtx, cancel := context.WithCancel(context.Background())
+		a.cancel = cancel
+		go a.run(runCtx, ctx.Parent())
+
+		a.logger.Info("TriggerListenerActor started",
+			zap.String("trigger", a.config.Name),
+			zap.String("type", a.config.Type),
+		)
+
+	case *actor.Stopping, *actor.Restarting:
+		if a.cancel != nil {
+			a.cancel()
+		}
+
+	case *actor.Stopped:
+		a.logger.Info("TriggerListenerActor stopped", zap.String("trigger", a.config.Name))
+	}
+}
+
+// run keeps the listener connected, reconnecting with exponential backoff after failures
+func (a *TriggerListenerActor) run(ctx context.Context, coordinator *actor.PID) {
+	fire := func() {
+		a.logger.Debug("Trigger message received", zap.String("trigger", a.config.Name))
+		a.actorSystem.Root.Send(coordinator, &TriggerTablesMessage{
+			Source:   "trigger:" + a.config.Name,
+			Tables:   a.config.Tables,
+			Mode:     a.config.Mode,
+			Debounce: time.Duration(a.config.Debounce) * time.Second,
+		})
+	}
+
+	backoff := listenerInitialBackoff
+	for {
+		started := time.Now()
+		err := a.listener.Listen(ctx, fire)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A listener that stayed connected for a while starts over with a short backoff
+		if time.Since(started) > listenerMaxBackoff {
+			backoff = listenerInitialBackoff
+		}
+		a.logger.Warn("Trigger listener disconnected, reconnecting",
+			zap.String("trigger", a.config.Name),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > listenerMaxBackoff {
+			backoff = listenerMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package actor
+
+import (
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// TriggerTablesMessage triggers a job for a set of tables on behalf of an external trigger source.
+// With a debounce, triggers from the same source within the window coalesce into a single job.
+type TriggerTablesMessage struct {
+	Source   string
+	Tables   []string
+	Mode     string
+	Debounce time.Duration
+}
+
+// DebouncedResponse is the coordinator's reply when a trigger was coalesced into a pending job
+type DebouncedResponse struct {
+	Source  string
+	FiresAt time.Time
+}
+
+// fireTriggerMessage starts the pending job for a debounced trigger source
+type fireTriggerMessage struct {
+	Source string
+}
+
+// pendingTrigger is a debounced trigger waiting for its window to elapse
+type pendingTrigger struct {
+	message *TriggerTablesMessage
+	firesAt time.Time
+	timer   *time.Timer
+}
+
+// handleTriggerTables starts a job immediately or coalesces the trigger into a pending one
+func (c *CoordinatorActor) handleTriggerTables(ctx actor.Context, msg *TriggerTablesMessage) {
+	if msg.Debounce <= 0 {
+		job := c.startTriggerJob(ctx, msg)
+		ctx.Respond(c.jobSnapshot(job.ID))
+		return
+	}
+
+	if pending, ok := c.pendingTriggers[msg.Source]; ok {
+		pending.message.Tables = mergeTables(pending.message.Tables, msg.Tables)
+		ctx.Respond(&DebouncedResponse{Source: msg.Source, FiresAt: pending.firesAt})
+		return
+	}
+
+	self := ctx.Self()
+	source := msg.Source
+	pending := &pendingTrigger{
+		message: msg,
+		firesAt: time.Now().Add(msg.Debounce),
+		timer: time.AfterFunc(msg.Debounce, func() {
+			c.actorSystem.Root.Send(self, &fireTriggerMessage{Source: source})
+		}),
+	}
+	c.pendingTriggers[msg.Source] = pending
+
+	c.logger.Info("Trigger debounced",
+		zap.String("source", msg.Source),
+		zap.Time("fires_at", pending.firesAt),
+	)
+	ctx.Respond(&DebouncedResponse{Source: msg.Source, FiresAt: pending.firesAt})
+}
+
+// fireTrigger starts the job for a debounced trigger source
+func (c *CoordinatorActor) fireTrigger(ctx actor.Context, source string) {
+	pending, ok := c.pendingTriggers[source]
+	if !ok {
+		return
+	}
+	delete(c.pendingTriggers, source)
+	c.startTriggerJob(ctx, pending.message)
+}
+
+func (c *CoordinatorActor) startTriggerJob(ctx actor.Context, msg *TriggerTablesMessage) *SyncJob {
+	mode := msg.Mode
+	if mode == "" {
+		mode = c.config.Defaults.SyncAllMode
+	}
+	job := newSyncJob(syncpkg.NewBatchID(), mode, msg.Tables)
+	c.logger.Info("Triggered sync",
+		zap.String("source", msg.Source),
+		zap.Strings("tables", msg.Tables),
+		zap.String("job_id", job.ID),
+	)
+	c.startJob(ctx, job)
+	return job
+}
+
+// stopPendingTriggers cancels debounced triggers that have not fired yet
+func (c *CoordinatorActor) stopPendingTriggers() {
+	for source, pending := range c.pendingTriggers {
+		pending.timer.Stop()
+		delete(c.pendingTriggers, source)
+	}
+}
+
+func mergeTables(existing, added []string) []string {
+	seen := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		seen[name] = true
+	}
+	for _, name := range added {
+		if !seen[name] {
+			existing = append(existing, name)
+			seen[name] = true
+		}
+	}
+	return existing
+}
@@ -0,0 +1,195 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/alert"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// Reasons for the watchdog to replace a sync actor
+const (
+	watchdogStuckSync = "stuck_sync" // a sync ran longer than stuck_after
+	watchdogNoPong    = "no_pong"    // an idle actor did not answer a ping within ping_timeout
+)
+
+// errSyncStuck cancels a sync and fails the job tables of an actor replaced by the watchdog
+var errSyncStuck = errors.New("sync actor stuck, replaced by the watchdog")
+
+// checkWatchdogMessage asks the coordinator to look for stuck sync actors
+type checkWatchdogMessage struct{}
+
+// pingMessage asks a sync actor to show it still processes messages
+type pingMessage struct{}
+
+// pongMessage is a sync actor's answer to pingMessage
+type pongMessage struct {
+	TableName string
+}
+
+// checkWatchdog replaces the sync actors whose sync has run longer than stuck_after and those that did not answer
+// the last ping, and pings the other idle actors. Syncing actors are not pinged, as they only read their mailbox
+// once the sync has finished; with the worker pool only sync durations are checked
+func (c *CoordinatorActor) checkWatchdog(ctx actor.Context) {
+	cfg := c.config.Supervision.Watchdog
+	now := time.Now()
+	for tableName, state := range c.tableStates {
+		if state.ActorStopped {
+			continue
+		}
+		switch {
+		case state.ActorState == ActorSyncing:
+			if !state.SyncingSince.IsZero() && now.Sub(state.SyncingSince) > cfg.GetStuckAfter() {
+				c.replaceStuckActor(ctx, state, watchdogStuckSync, now.Sub(state.SyncingSince))
+			}
+		case c.pool != nil:
+		case !state.PingSentAt.IsZero():
+			if now.Sub(state.PingSentAt) > cfg.GetPingTimeout() {
+				c.replaceStuckActor(ctx, state, watchdogNoPong, now.Sub(state.PingSentAt))
+			}
+		default:
+			if pid, ok := c.syncActors[tableName]; ok {
+				state.PingSentAt = now
+				ctx.Send(pid, &pingMessage{})
+			}
+		}
+	}
+}
+
+// replaceStuckActor cancels the table's running sync and replaces its sync actor or pool worker. The old actor is
+// stopped, which takes effect only if its blocked message ever returns, so the replacement gets a new name
+func (c *CoordinatorActor) replaceStuckActor(ctx actor.Context, state *TableState, reason string, elapsed time.Duration) {
+	tableName := state.TableName
+	cancelRunningSync(tableName, errSyncStuck)
+
+	state.Restarts++
+	state.LastCrash = errSyncStuck.Error()
+	state.LastCrashAt = time.Now()
+	state.ActorState = ActorIdle
+	state.SyncingSince = time.Time{}
+	state.PingSentAt = time.Time{}
+
+	var err error
+	if c.pool != nil {
+		err = c.replacePoolWorker(ctx, tableName)
+	} else {
+		err = c.replaceSyncActor(ctx, tableName)
+	}
+
+	message := fmt.Sprintf("Sync actor for %s was syncing for %s and was replaced", tableName, elapsed.Round(time.Second))
+	if reason == watchdogNoPong {
+		message = fmt.Sprintf("Sync actor for %s did not answer a ping for %s and was replaced", tableName, elapsed.Round(time.Second))
+	}
+	fields := []zap.Field{
+		zap.String("table", tableName),
+		zap.String("reason", reason),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		c.logger.Error("Watchdog failed to replace stuck sync actor", append(fields, zap.Error(err))...)
+		message = fmt.Sprintf("Sync actor for %s is stuck (%s) and could not be replaced: %v", tableName, reason, err)
+	} else {
+		c.logger.Error("Watchdog replaced stuck sync actor", fields...)
+	}
+
+	metrics.ActorWatchdogRestartsTotal.WithLabelValues(tableName, reason).Inc()
+
+	c.notifier.Notify(alert.Event{
+		Type:    alert.EventActorStuck,
+		Table:   tableName,
+		Message: message,
+		Details: map[string]interface{}{
+			"reason":          reason,
+			"elapsed_seconds": elapsed.Seconds(),
+		},
+	})
+}
+
+// replaceSyncActor spawns a new sync actor for a table in place of its stuck one, failing the job tables that were
+// sent to the stuck actor
+func (c *CoordinatorActor) replaceSyncActor(ctx actor.Context, tableName string) error {
+	tc, ok := c.tableConfig(tableName)
+	if !ok {
+		return errSyncActorStopped
+	}
+	if old, found := c.syncActors[tableName]; found {
+		ctx.Stop(old)
+	}
+
+	actorName := syncActorName(tableName) + c.actorSystem.ProcessRegistry.NextId()
+	pid, err := ctx.SpawnNamed(c.syncActorProps(tc), actorName)
+	if err != nil {
+		return err
+	}
+	c.syncActors[tableName] = pid
+
+	for jobID, job := range c.jobs {
+		if status, inJob := job.tableStatus(tableName); inJob && status == JobRunning {
+			c.completeJobTable(ctx, &SyncResultMessage{TableName: tableName, JobID: jobID, Error: errSyncStuck})
+		}
+	}
+	return nil
+}
+
+// replacePoolWorker releases the table of the stuck worker syncing it and adds a new worker to the pool in its place
+func (c *CoordinatorActor) replacePoolWorker(ctx actor.Context, tableName string) error {
+	workerID, busy := c.poolWorkerFor(tableName)
+	if !busy {
+		return nil
+	}
+	for _, child := range ctx.Children() {
+		if child.GetId() != workerID {
+			continue
+		}
+		c.failPoolWorker(ctx, &poolWorkerFailed{Worker: child, Reason: errSyncStuck})
+		ctx.Stop(child)
+		break
+	}
+
+	actorName := "worker" + c.actorSystem.ProcessRegistry.NextId()
+	_, err := ctx.SpawnNamed(c.poolWorkerProps(actorName), actorName)
+	return err
+}
+
+// replacedActor reports whether a state report comes from a sync actor or pool worker the watchdog has replaced
+func (c *CoordinatorActor) replacedActor(msg *actorStateMessage) bool {
+	if msg.Actor == nil {
+		return false
+	}
+	if c.pool != nil {
+		workerID, busy := c.poolWorkerFor(msg.TableName)
+		return !busy || workerID != msg.Actor.GetId()
+	}
+	pid, ok := c.syncActors[msg.TableName]
+	return ok && !pid.Equal(msg.Actor)
+}
+
+// scheduleWatchdog schedules the next watchdog check when the watchdog is enabled
+func (c *CoordinatorActor) scheduleWatchdog(ctx actor.Context) {
+	if !c.config.Supervision.Watchdog.Enabled {
+		return
+	}
+
+	pid := ctx.Self()
+	if c.watchdogTimer != nil {
+		c.watchdogTimer.Stop()
+	}
+	c.watchdogTimer = time.AfterFunc(c.config.Supervision.Watchdog.GetInterval(), func() {
+		if c.actorSystem != nil {
+			c.actorSystem.Root.Send(pid, &checkWatchdogMessage{})
+		}
+	})
+}
+
+// stopWatchdog stops the watchdog timer
+func (c *CoordinatorActor) stopWatchdog() {
+	if c.watchdogTimer != nil {
+		c.watchdogTimer.Stop()
+		c.watchdogTimer = nil
+	}
+}
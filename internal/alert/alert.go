@@ -0,0 +1,91 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Event types sent to alert webhooks
+const (
+	EventTableStale     = "table_stale"
+	EventTableRecovered = "table_recovered"
+	EventActorStopped   = "actor_stopped"
+	EventActorStuck     = "actor_stuck"
+)
+
+// Event represents an alert delivered to the configured webhooks
+type Event struct {
+	Type    string                 `json:"type"`
+	Table   string                 `json:"table,omitempty"`
+	Message string                 `json:"message"`
+	Time    time.Time              `json:"time"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Notifier delivers alert events to webhooks
+type Notifier struct {
+	Webhooks []string
+	Client   *http.Client
+	Logger   *zap.Logger
+}
+
+// NewNotifier creates a new alert notifier
+func NewNotifier(cfg config.AlertConfig, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		Webhooks: cfg.Webhooks,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Logger:   logger,
+	}
+}
+
+// Notify logs the event and posts it asynchronously to every webhook
+func (n *Notifier) Notify(event Event) {
+	if n == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	n.Logger.Warn("Alert raised",
+		zap.String("type", event.Type),
+		zap.String("table", event.Table),
+		zap.String("message", event.Message),
+	)
+
+	if len(n.Webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		n.Logger.Error("Failed to encode alert", zap.Error(err))
+		return
+	}
+
+	for _, url := range n.Webhooks {
+		go n.post(url, payload)
+	}
+}
+
+func (n *Notifier) post(url string, payload []byte) {
+	resp, err := n.Client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		n.Logger.Error("Failed to deliver alert webhook", zap.String("url", url), zap.Error(err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		n.Logger.Error("Alert webhook returned error status",
+			zap.String("url", url),
+			zap.Int("status", resp.StatusCode),
+		)
+	}
+}
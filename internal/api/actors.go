@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	actorpkg "mssql-postgres-sync/internal/actor"
+)
+
+// GetActors returns the state, next run, last result and restart count of every sync actor
+func (h *APIHandler) GetActors(c *gin.Context) {
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, &actorpkg.GetActorsMessage{}, 2*time.Second).Result()
+	if err != nil {
+		h.Logger.Error("Failed to fetch actor states from coordinator", zap.Error(err))
+		// Mailboxes are read directly, so a coordinator backed up behind its queue still shows up
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":        "Coordinator did not respond",
+			"mailboxes":    actorpkg.MailboxSnapshot(),
+			"dead_letters": actorpkg.DeadLetterCount(),
+		})
+		return
+	}
+
+	response, ok := result.(*actorpkg.ActorsResponse)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Unexpected coordinator response",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	actorpkg "mssql-postgres-sync/internal/actor"
+	"mssql-postgres-sync/internal/history"
+)
+
+// ListBackfills returns the saved progress of every table backfill
+func (h *APIHandler) ListBackfills(c *gin.Context) {
+	if h.History == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync history is not enabled",
+		})
+		return
+	}
+
+	backfills, err := h.History.Backfills()
+	if err != nil {
+		h.Logger.Error("Failed to load backfill progress", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load backfill progress",
+		})
+		return
+	}
+	if backfills == nil {
+		backfills = []history.Backfill{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"backfills": backfills,
+	})
+}
+
+// GetBackfill returns the saved progress of a table's backfill
+func (h *APIHandler) GetBackfill(c *gin.Context) {
+	tableName := c.Param("table")
+	if !h.checkBackfillTable(c, tableName) {
+		return
+	}
+
+	progress, err := h.History.GetBackfill(tableName)
+	if err != nil {
+		h.Logger.Error("Failed to load backfill progress", zap.String("table", tableName), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load backfill progress",
+		})
+		return
+	}
+	if progress == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Table has not been backfilled: " + tableName,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// StartBackfill starts a table's backfill, or resumes it from its saved progress. restart=true starts over
+func (h *APIHandler) StartBackfill(c *gin.Context) {
+	tableName := c.Param("table")
+	if !h.checkBackfillTable(c, tableName) {
+		return
+	}
+
+	restart := false
+	if raw := c.Query("restart"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid restart",
+			})
+			return
+		}
+		restart = parsed
+	}
+
+	response, ok := h.requestBackfill(c, &actorpkg.StartBackfillMessage{TableName: tableName, Restart: restart})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("Started backfill",
+		zap.String("table", tableName),
+		zap.Bool("restart", restart),
+	)
+	c.JSON(http.StatusAccepted, response.Backfill)
+}
+
+// StopBackfill stops a running backfill; it keeps its progress and resumes when started again
+func (h *APIHandler) StopBackfill(c *gin.Context) {
+	tableName := c.Param("table")
+	if !h.checkBackfillTable(c, tableName) {
+		return
+	}
+
+	if _, ok := h.requestBackfill(c, &actorpkg.StopBackfillMessage{TableName: tableName}); !ok {
+		return
+	}
+
+	h.Logger.Info("Stopping backfill", zap.String("table", tableName))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Backfill stopping after the current range: " + tableName,
+	})
+}
+
+// checkBackfillTable writes an error response unless history is enabled and the table has a backfill configured
+func (h *APIHandler) checkBackfillTable(c *gin.Context, tableName string) bool {
+	if h.History == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync history is not enabled",
+		})
+		return false
+	}
+
+	for _, tc := range h.Config.Tables {
+		if tc.TargetTable != tableName {
+			continue
+		}
+		if tc.Backfill == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Table has no backfill configured: " + tableName,
+			})
+			return false
+		}
+		return true
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "Table not found: " + tableName,
+	})
+	return false
+}
+
+// requestBackfill sends a backfill request to the coordinator, writing an error response when it fails
+func (h *APIHandler) requestBackfill(c *gin.Context, message interface{}) (*actorpkg.BackfillResponse, bool) {
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, message, 5*time.Second).Result()
+	if err != nil {
+		h.Logger.Error("Failed to send backfill request to coordinator", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Coordinator did not respond",
+		})
+		return nil, false
+	}
+
+	response, ok := result.(*actorpkg.BackfillResponse)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Unexpected coordinator response",
+		})
+		return nil, false
+	}
+	if response.Error != "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": response.Error,
+		})
+		return nil, false
+	}
+	return response, true
+}
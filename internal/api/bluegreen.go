@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// RollbackTable points a blue/green table's view back at its previous generation
+func (h *APIHandler) RollbackTable(c *gin.Context) {
+	if h.SyncEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync engine is not available",
+		})
+		return
+	}
+
+	tableName := c.Param("name")
+	var tc *config.TableConfig
+	for i := range h.Config.Tables {
+		if h.Config.Tables[i].TargetTable == tableName {
+			tc = &h.Config.Tables[i]
+			break
+		}
+	}
+	if tc == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Table not found: " + tableName,
+		})
+		return
+	}
+	if !tc.BlueGreen {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Table is not a blue_green table: " + tableName,
+		})
+		return
+	}
+
+	generation, err := h.SyncEngine.RollbackTable(c.Request.Context(), *tc)
+	if err != nil {
+		if errors.Is(err, syncpkg.ErrNoPreviousGeneration) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		h.Logger.Error("Failed to roll back table",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	h.Logger.Info("Rolled back blue/green table",
+		zap.String("table", tableName),
+		zap.String("generation", generation),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"table":      tableName,
+		"generation": generation,
+	})
+}
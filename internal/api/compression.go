@@ -0,0 +1,111 @@
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipMiddleware compresses responses for clients that accept gzip encoding
+func gzipMiddleware(level int) gin.HandlerFunc {
+	if level < gzip.BestSpeed || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+
+	pool := &sync.Pool{
+		New: func() interface{} {
+			writer, _ := gzip.NewWriterLevel(nil, level)
+			return writer
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, pool: pool}
+		c.Writer = writer
+		c.Header("Vary", "Accept-Encoding")
+		defer writer.close()
+
+		c.Next()
+	}
+}
+
+func acceptsGzip(req *http.Request) bool {
+	if req.Method == http.MethodHead {
+		return false
+	}
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		name := strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0])
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter lazily starts compression on the first body write so empty responses stay empty
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	pool        *sync.Pool
+	writer      *gzip.Writer
+	passthrough bool
+}
+
+func (w *gzipResponseWriter) start() {
+	if w.writer != nil || w.passthrough {
+		return
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		w.passthrough = true
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.writer = w.pool.Get().(*gzip.Writer)
+	w.writer.Reset(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	w.start()
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.writer.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if w.writer != nil {
+		w.writer.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the underlying writer, so http.ResponseController can reach the connection
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.writer == nil {
+		return
+	}
+	w.writer.Close()
+	w.pool.Put(w.writer)
+	w.writer = nil
+}
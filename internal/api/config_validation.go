@@ -0,0 +1,174 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	syncpkg "mssql-postgres-sync/internal/sync"
+)
+
+// ConfigIssue is a problem found validating a configuration
+type ConfigIssue struct {
+	Projection string `json:"projection,omitempty"` // projection the problem was found in, for target view checks
+	Message    string `json:"message"`
+}
+
+// ConfigValidationResponse is the result of validating the loaded or a candidate configuration. Errors would stop
+// the service from starting; warnings would not
+type ConfigValidationResponse struct {
+	Config   string        `json:"config"` // loaded or candidate
+	Valid    bool          `json:"valid"`
+	Errors   []ConfigIssue `json:"errors"`
+	Warnings []ConfigIssue `json:"warnings"`
+}
+
+// ValidateLoadedConfig runs the configuration checks of startup again on the configuration the service runs with,
+// including the projection columns of the target views as they are now
+func (h *APIHandler) ValidateLoadedConfig(c *gin.Context) {
+	key, ok := h.configAdmin(c)
+	if !ok {
+		return
+	}
+	data := h.Config.Raw()
+	if data == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Loaded configuration is not available"})
+		return
+	}
+	h.respondConfigValidation(c, key, "loaded", data)
+}
+
+// ValidateCandidateConfig runs the configuration checks of startup on an uploaded YAML configuration, to preview
+// a configuration change before it is deployed. Overlays and environment overrides are not applied
+func (h *APIHandler) ValidateCandidateConfig(c *gin.Context) {
+	key, ok := h.configAdmin(c)
+	if !ok {
+		return
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Configuration exceeds the request body limit"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read configuration: " + err.Error()})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request body must be a YAML configuration"})
+		return
+	}
+	h.respondConfigValidation(c, key, "candidate", data)
+}
+
+// configAdmin checks that a configuration request is made with an admin API key, responding with an error and
+// returning false otherwise
+func (h *APIHandler) configAdmin(c *gin.Context) (*config.APIKeyConfig, bool) {
+	key, ok := h.apiKey(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown API key"})
+		return nil, false
+	}
+	if key == nil || !key.Admin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Config validation requires an admin API key"})
+		return nil, false
+	}
+	return key, true
+}
+
+// respondConfigValidation validates a configuration document and writes the result
+func (h *APIHandler) respondConfigValidation(c *gin.Context, key *config.APIKeyConfig, source string, data []byte) {
+	result := h.validateConfig(data)
+	result.Config = source
+
+	h.Logger.Info("Validated configuration",
+		zap.String("config", source),
+		zap.String("api_key", key.Name),
+		zap.Bool("valid", result.Valid),
+		zap.Int("errors", len(result.Errors)),
+		zap.Int("warnings", len(result.Warnings)),
+	)
+	c.JSON(http.StatusOK, result)
+}
+
+// validateConfig runs the checks of startup on a configuration document: parsing and validating it, expanding its
+// tenant tables, checking its target schema permissions and its projections against the target views of the running
+// service. Unknown settings and tables committing in batches are warnings. Projection problems are errors in strict
+// projection_validation mode, where they would stop the service from starting, and warnings otherwise
+func (h *APIHandler) validateConfig(data []byte) ConfigValidationResponse {
+	result := ConfigValidationResponse{
+		Errors:   []ConfigIssue{},
+		Warnings: []ConfigIssue{},
+	}
+	for _, unknown := range config.UnknownFields(data) {
+		result.Warnings = append(result.Warnings, ConfigIssue{Message: unknown})
+	}
+
+	cfg, err := config.ParseConfig(data)
+	if err != nil {
+		for _, problem := range configProblems(err) {
+			result.Errors = append(result.Errors, ConfigIssue{Message: problem.Error()})
+		}
+		return result
+	}
+
+	if h.SyncEngine == nil {
+		result.Warnings = append(result.Warnings, ConfigIssue{
+			Message: "sync engine is not available, tenant tables and target schema permissions were not checked",
+		})
+	} else if err := cfg.ExpandTenants(h.SyncEngine.TenantIDs); err != nil {
+		result.Errors = append(result.Errors, ConfigIssue{Message: err.Error()})
+	} else {
+		for _, problem := range h.SyncEngine.TargetPermissionProblems(cfg) {
+			result.Errors = append(result.Errors, ConfigIssue{Message: problem.Error()})
+		}
+	}
+
+	for _, tc := range syncpkg.BatchedCommitTables(cfg) {
+		result.Warnings = append(result.Warnings, ConfigIssue{
+			Message: fmt.Sprintf("table %s: loads commit in batches of %d rows, so the table is not consistent while a load runs or after one fails",
+				tc.TargetTable, tc.GetCommitEvery(cfg.Defaults)),
+		})
+	}
+
+	mode := projectionValidationMode(&cfg.API)
+	if mode != ProjectionValidationOff && len(cfg.Projections) > 0 {
+		if h.DBManager == nil || h.DBManager.Target == nil {
+			result.Warnings = append(result.Warnings, ConfigIssue{
+				Message: "target database is not available, projection columns were not checked",
+			})
+		} else {
+			for i := range cfg.Projections {
+				projection := &cfg.Projections[i]
+				if projection.IsSourcePassthrough() {
+					continue
+				}
+				for _, err := range h.validateProjection(projection) {
+					issue := ConfigIssue{Projection: projection.ID, Message: err.Error()}
+					if mode == ProjectionValidationStrict {
+						result.Errors = append(result.Errors, issue)
+					} else {
+						result.Warnings = append(result.Warnings, issue)
+					}
+				}
+			}
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// configProblems returns the problems joined in a ParseConfig error
+func configProblems(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// ListDashboards returns the configured dashboards in display order, with widget titles and widths resolved
+func (h *APIHandler) ListDashboards(c *gin.Context) {
+	lang := h.requestLanguage(c)
+	dashboards := make([]config.DashboardConfig, 0, len(h.Config.Dashboards))
+	for i := range h.Config.Dashboards {
+		dashboards = append(dashboards, h.resolveDashboard(&h.Config.Dashboards[i], lang))
+	}
+	sort.SliceStable(dashboards, func(i, j int) bool {
+		return dashboards[i].Order < dashboards[j].Order
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"dashboards": dashboards,
+	})
+}
+
+// GetDashboard returns a single dashboard with widget titles and widths resolved
+func (h *APIHandler) GetDashboard(c *gin.Context) {
+	dashboardID := c.Param("id")
+	dashboard, ok := h.Config.GetDashboardByID(dashboardID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Dashboard not found: %s", dashboardID),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.resolveDashboard(dashboard, h.requestLanguage(c)))
+}
+
+// resolveDashboard copies a dashboard with its column count, widget widths and widget titles defaulted, titles
+// taken from projections in the given language
+func (h *APIHandler) resolveDashboard(dashboard *config.DashboardConfig, lang string) config.DashboardConfig {
+	resolved := *dashboard
+	resolved.Columns = dashboard.GetColumns()
+	resolved.Widgets = make([]config.DashboardWidget, len(dashboard.Widgets))
+	for i, widget := range dashboard.Widgets {
+		widget.Width = widget.GetWidth(resolved.Columns)
+		if widget.Title == "" {
+			if projection, ok := h.Config.GetProjectionByID(widget.Projection); ok {
+				widget.Title = projection.Titles.Get(lang, projection.Title)
+			}
+		}
+		resolved.Widgets[i] = widget
+	}
+	return resolved
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// maxDiffLimit caps the keys and mismatched rows listed by GET /api/diff/:table
+const maxDiffLimit = 1000
+
+// GetTableDiff compares a table's source and target rows by its declared keys
+func (h *APIHandler) GetTableDiff(c *gin.Context) {
+	if h.SyncEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync engine is not available",
+		})
+		return
+	}
+
+	tableName := c.Param("table")
+	var tc *config.TableConfig
+	for i := range h.Config.Tables {
+		if h.Config.Tables[i].TargetTable == tableName {
+			tc = &h.Config.Tables[i]
+			break
+		}
+	}
+	if tc == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Table not found: " + tableName,
+		})
+		return
+	}
+	if len(tc.Keys) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Table has no keys declared: " + tableName,
+		})
+		return
+	}
+
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid limit",
+			})
+			return
+		}
+		limit = parsed
+	}
+	if limit > maxDiffLimit {
+		limit = maxDiffLimit
+	}
+
+	report, err := h.SyncEngine.DiffTable(c.Request.Context(), *tc, limit)
+	if err != nil {
+		h.Logger.Error("Failed to diff table",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
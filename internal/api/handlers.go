@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,6 +16,10 @@ import (
 	actorpkg "mssql-postgres-sync/internal/actor"
 	"mssql-postgres-sync/internal/config"
 	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/logging"
+	"mssql-postgres-sync/internal/sqlident"
+	syncpkg "mssql-postgres-sync/internal/sync"
 )
 
 // APIHandler handles HTTP requests
@@ -23,16 +29,41 @@ type APIHandler struct {
 	CoordinatorPID *actor.PID
 	ActorSystem    *actor.ActorSystem
 	DBManager      *database.DatabaseManager
+	SyncEngine     *syncpkg.SyncEngine
+	History        *history.Store
+	Logging        *logging.Manager
+	Usage          *usageTracker
+	Results        *resultCache // nil unless api.result_cache is enabled
+	Passthrough    *resultCache // responses of source_passthrough projections, nil unless one caches them
+
+	languages *languageMatcher // nil unless titles or labels are translated
+
+	target targetStatus
 }
 
 // NewAPIHandler creates a new API handler
-func NewAPIHandler(cfg *config.Config, logger *zap.Logger, coordinatorPID *actor.PID, actorSystem *actor.ActorSystem, dbManager *database.DatabaseManager) *APIHandler {
+func NewAPIHandler(cfg *config.Config, logger *zap.Logger, coordinatorPID *actor.PID, actorSystem *actor.ActorSystem, dbManager *database.DatabaseManager, syncEngine *syncpkg.SyncEngine, historyStore *history.Store, logs *logging.Manager) *APIHandler {
+	var results *resultCache
+	if cfg.API.ResultCache.Enabled {
+		results = newResultCache(cfg.API.ResultCache.GetMaxEntries())
+	}
+	var passthrough *resultCache
+	if hasPassthroughCache(cfg.Projections) {
+		passthrough = newResultCache(defaultPassthroughCacheEntries)
+	}
 	return &APIHandler{
 		Config:         cfg,
 		Logger:         logger,
 		CoordinatorPID: coordinatorPID,
 		ActorSystem:    actorSystem,
 		DBManager:      dbManager,
+		SyncEngine:     syncEngine,
+		History:        historyStore,
+		Logging:        logs,
+		Usage:          newUsageTracker(),
+		Results:        results,
+		Passthrough:    passthrough,
+		languages:      newLanguageMatcher(cfg),
 	}
 }
 
@@ -40,28 +71,64 @@ func NewAPIHandler(cfg *config.Config, logger *zap.Logger, coordinatorPID *actor
 type SyncRequest struct {
 	TableName string `json:"table_name,omitempty"`
 	SyncAll   bool   `json:"sync_all,omitempty"`
+	Family    string `json:"family,omitempty"` // sync all tenant tables of a family
+	Mode      string `json:"mode,omitempty"`   // parallel, sequential or dependency for sync_all
+	Wait      bool   `json:"wait,omitempty"`
+	Timeout   int    `json:"timeout,omitempty"` // seconds to wait when wait is set
 }
 
 // SyncResponse represents a sync response
 type SyncResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	JobID   string            `json:"job_id,omitempty"`
+	BatchID string            `json:"batch_id,omitempty"`
+	Job     *actorpkg.SyncJob `json:"job,omitempty"`
 }
 
+// defaultSyncWaitTimeout bounds how long a wait=true trigger blocks
+const defaultSyncWaitTimeout = 25 * time.Second
+
+// syncWaitMargin is the time left after a wait for writing the response before the write deadline
+const syncWaitMargin = 5 * time.Second
+
 // TableStatus represents table sync status
 type TableStatus struct {
-	SourceTable       string    `json:"source_table"`
-	TargetTable       string    `json:"target_table"`
-	RefreshRate       int       `json:"refresh_rate"`
-	ProtoActorEnabled bool      `json:"proto_actor_enabled"`
-	WebAPIEnabled     bool      `json:"web_api_enabled"`
-	LastSync          time.Time `json:"last_sync,omitempty"`
+	SourceTable       string     `json:"source_table"`
+	TargetTable       string     `json:"target_table"`
+	RefreshRate       int        `json:"refresh_rate"`
+	ProtoActorEnabled bool       `json:"proto_actor_enabled"`
+	WebAPIEnabled     bool       `json:"web_api_enabled"`
+	LastSync          *time.Time `json:"last_sync,omitempty"`    // last successful sync
+	LastAttempt       *time.Time `json:"last_attempt,omitempty"` // last sync attempt, successful or not
+	LastError         string     `json:"last_error,omitempty"`   // error of the last attempt if it failed
+	MaxStaleness      string     `json:"max_staleness,omitempty"`
+	Stale             bool       `json:"stale"`
+	StaleSince        *time.Time `json:"stale_since,omitempty"`
+	ActorRestarts     int        `json:"actor_restarts"`
+	ActorStopped      bool       `json:"actor_stopped"`
+	LastCrash         string     `json:"last_crash,omitempty"`
+}
+
+// ProjectionColumnMeta describes the type of a column returned by a projection query
+type ProjectionColumnMeta struct {
+	Column       string              `json:"column"`
+	Label        string              `json:"label,omitempty"`
+	Type         string              `json:"type"`
+	DatabaseType string              `json:"database_type,omitempty"`
+	Display      string              `json:"display,omitempty"`
+	Align        string              `json:"align"`
+	Format       *config.FieldFormat `json:"format,omitempty"`
+	Locale       string              `json:"locale,omitempty"`
+	Description  string              `json:"description,omitempty"` // column comment, shown as a tooltip
 }
 
 // StatusResponse represents the status response
 type StatusResponse struct {
-	Status string        `json:"status"`
-	Tables []TableStatus `json:"tables"`
+	Status      string                            `json:"status"`
+	Tables      []TableStatus                     `json:"tables"`
+	Connections map[string]database.BreakerStatus `json:"connections,omitempty"`
+	Projections *ProjectionAvailability           `json:"projections,omitempty"`
 }
 
 // TriggerSync triggers a sync operation
@@ -75,18 +142,81 @@ func (h *APIHandler) TriggerSync(c *gin.Context) {
 		return
 	}
 
+	if wait, err := strconv.ParseBool(c.DefaultQuery("wait", "false")); err == nil && wait {
+		req.Wait = true
+	}
+	if timeout, err := strconv.Atoi(c.Query("timeout")); err == nil && timeout > 0 {
+		req.Timeout = timeout
+	}
+
 	h.Logger.Info("Received sync trigger request",
 		zap.String("table_name", req.TableName),
 		zap.Bool("sync_all", req.SyncAll),
+		zap.String("family", req.Family),
+		zap.Bool("wait", req.Wait),
 	)
 
+	if h.DBManager != nil && !h.DBManager.Available() {
+		c.JSON(http.StatusServiceUnavailable, SyncResponse{
+			Success: false,
+			Message: "Sync paused: database circuit breaker is open",
+		})
+		return
+	}
+
+	if req.Family != "" {
+		found := false
+		for _, tc := range h.Config.Tables {
+			if tc.Family == req.Family {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, SyncResponse{
+				Success: false,
+				Message: "Tenant family not found: " + req.Family,
+			})
+			return
+		}
+		req.SyncAll = true
+	}
+
 	if req.SyncAll {
-		// Trigger all tables
-		h.ActorSystem.Root.Send(h.CoordinatorPID, &actorpkg.TriggerAllSyncMessage{})
+		switch strings.ToLower(req.Mode) {
+		case "", actorpkg.JobModeParallel, actorpkg.JobModeSequential, actorpkg.JobModeDependency:
+		default:
+			c.JSON(http.StatusBadRequest, SyncResponse{
+				Success: false,
+				Message: "Invalid mode: " + req.Mode,
+			})
+			return
+		}
+
+		// Trigger all tables as a tracked batch
+		job, err := h.requestJob(&actorpkg.TriggerAllSyncMessage{Mode: req.Mode, Family: req.Family})
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, SyncResponse{
+				Success: false,
+				Message: "Failed to trigger sync: " + err.Error(),
+			})
+			return
+		}
 
+		if req.Wait {
+			h.respondWhenFinished(c, job, req.Timeout)
+			return
+		}
+
+		message := "Sync triggered for all tables"
+		if req.Family != "" {
+			message = "Sync triggered for tenant family: " + req.Family
+		}
 		c.JSON(http.StatusOK, SyncResponse{
 			Success: true,
-			Message: "Sync triggered for all tables",
+			Message: message,
+			JobID:   job.ID,
+			BatchID: job.ID,
 		})
 		return
 	}
@@ -125,186 +255,443 @@ func (h *APIHandler) TriggerSync(c *gin.Context) {
 		return
 	}
 
+	if window, inBlackout := tableConfig.ActiveBlackout(h.Config.Defaults, time.Now()); inBlackout {
+		c.JSON(http.StatusConflict, SyncResponse{
+			Success: false,
+			Message: fmt.Sprintf("Sync rejected: table %s is in a blackout window %s", req.TableName, window.String()),
+		})
+		return
+	}
+
 	// Trigger sync
-	h.ActorSystem.Root.Send(h.CoordinatorPID, &actorpkg.TriggerSyncMessage{
+	job, err := h.requestJob(&actorpkg.TriggerSyncMessage{
 		TableName:   req.TableName,
 		TableConfig: *tableConfig,
 	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, SyncResponse{
+			Success: false,
+			Message: "Failed to trigger sync: " + err.Error(),
+		})
+		return
+	}
+
+	if req.Wait {
+		h.respondWhenFinished(c, job, req.Timeout)
+		return
+	}
 
 	c.JSON(http.StatusOK, SyncResponse{
 		Success: true,
 		Message: "Sync triggered for table: " + req.TableName,
+		JobID:   job.ID,
+	})
+}
+
+// syncWaitTimeout returns how long a wait=true trigger blocks: the requested timeout, capped at api.export_timeout.
+// The response's write deadline is moved past it, as the server write timeout would otherwise drop the connection
+// before the result is written; when it cannot be moved the wait ends in time to answer within the write timeout
+func (h *APIHandler) syncWaitTimeout(c *gin.Context, timeoutSeconds int) time.Duration {
+	timeout := defaultSyncWaitTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	if limit := h.Config.API.GetExportTimeout(); timeout > limit {
+		timeout = limit
+	}
+
+	err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + syncWaitMargin))
+	if err == nil {
+		return timeout
+	}
+	h.Logger.Debug("Cannot extend the write deadline of a waiting sync request", zap.Error(err))
+	if limit := h.Config.API.GetWriteTimeout() - syncWaitMargin; limit > 0 && timeout > limit {
+		timeout = limit
+	}
+	return timeout
+}
+
+// respondWhenFinished blocks until the job finishes (or the timeout elapses) and returns its result
+func (h *APIHandler) respondWhenFinished(c *gin.Context, job *actorpkg.SyncJob, timeoutSeconds int) {
+	timeout := h.syncWaitTimeout(c, timeoutSeconds)
+
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, &actorpkg.WaitJobMessage{JobID: job.ID}, timeout).Result()
+	if err != nil {
+		if snapshot, snapErr := h.requestJob(&actorpkg.GetJobMessage{JobID: job.ID}); snapErr == nil {
+			job = snapshot
+		}
+		c.JSON(http.StatusAccepted, SyncResponse{
+			Success: false,
+			Message: fmt.Sprintf("Sync still running after %s", timeout),
+			JobID:   job.ID,
+			Job:     job,
+		})
+		return
+	}
+
+	response, ok := result.(*actorpkg.JobResponse)
+	if !ok || !response.Found {
+		c.JSON(http.StatusInternalServerError, SyncResponse{
+			Success: false,
+			Message: "Unexpected coordinator response",
+			JobID:   job.ID,
+		})
+		return
+	}
+
+	finished := response.Job
+	if finished.Status != actorpkg.JobSucceeded {
+		c.JSON(http.StatusInternalServerError, SyncResponse{
+			Success: false,
+			Message: "Sync " + finished.Status,
+			JobID:   finished.ID,
+			Job:     &finished,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncResponse{
+		Success: true,
+		Message: "Sync completed",
+		JobID:   finished.ID,
+		Job:     &finished,
 	})
 }
 
+// requestJob sends a trigger message to the coordinator and waits for the job it created
+func (h *APIHandler) requestJob(message interface{}) (*actorpkg.SyncJob, error) {
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, message, 5*time.Second).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	response, ok := result.(*actorpkg.JobResponse)
+	if !ok || !response.Found {
+		return nil, fmt.Errorf("unexpected coordinator response %T", result)
+	}
+	return &response.Job, nil
+}
+
 // GetStatus returns the current status
 func (h *APIHandler) GetStatus(c *gin.Context) {
 	var tables []TableStatus
 
+	states := h.fetchTableStates()
 	for _, tc := range h.Config.Tables {
-		tables = append(tables, TableStatus{
+		status := TableStatus{
 			SourceTable:       tc.SourceTable,
 			TargetTable:       tc.TargetTable,
 			RefreshRate:       tc.GetRefreshRate(h.Config.Defaults),
 			ProtoActorEnabled: tc.GetProtoActorTrigger(h.Config.Defaults),
 			WebAPIEnabled:     tc.GetWebAPITrigger(h.Config.Defaults),
-		})
+		}
+		if maxStaleness := tc.GetMaxStaleness(h.Config.Defaults); maxStaleness > 0 {
+			status.MaxStaleness = maxStaleness.String()
+		}
+		if state, ok := states[tc.TargetTable]; ok {
+			status.Stale = state.Stale
+			if state.Stale {
+				staleSince := state.StaleSince
+				status.StaleSince = &staleSince
+			}
+			if !state.LastSuccess.IsZero() {
+				lastSync := state.LastSuccess
+				status.LastSync = &lastSync
+			}
+			if !state.LastAttempt.IsZero() {
+				lastAttempt := state.LastAttempt
+				status.LastAttempt = &lastAttempt
+			}
+			status.LastError = state.LastError
+			status.ActorRestarts = state.Restarts
+			status.ActorStopped = state.ActorStopped
+			status.LastCrash = state.LastCrash
+		}
+		tables = append(tables, status)
 	}
 
-	c.JSON(http.StatusOK, StatusResponse{
+	response := StatusResponse{
 		Status: "running",
 		Tables: tables,
-	})
-}
+	}
+	if h.DBManager != nil {
+		response.Connections = h.DBManager.BreakerStatuses()
+		for _, breaker := range response.Connections {
+			if breaker.State != database.BreakerClosed {
+				response.Status = "degraded"
+			}
+		}
+		if h.DBManager.Target != nil {
+			availability := h.projectionAvailability(c.Request.Context())
+			response.Projections = &availability
+			if !availability.Available {
+				response.Status = "degraded"
+			}
+		}
+	}
 
-// ListProjections returns all configured projections for the UI
-func (h *APIHandler) ListProjections(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"projections": h.Config.Projections,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
-// GetProjectionData returns data for a specific projection view with optional filters and sorting
-func (h *APIHandler) GetProjectionData(c *gin.Context) {
-	if h.DBManager == nil || h.DBManager.Target == nil {
-		h.Logger.Error("Target database not configured for projections")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Target database connection is not available",
+// GetJob returns the per-table progress of a sync job or batch
+func (h *APIHandler) GetJob(c *gin.Context) {
+	jobID := c.Param("id")
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, &actorpkg.GetJobMessage{JobID: jobID}, 2*time.Second).Result()
+	if err != nil {
+		h.Logger.Error("Failed to fetch job from coordinator", zap.String("job_id", jobID), zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Coordinator did not respond",
 		})
 		return
 	}
 
-	projectionID := c.Param("id")
-	projection, ok := h.Config.GetProjectionByID(projectionID)
-	if !ok {
+	response, ok := result.(*actorpkg.JobResponse)
+	if !ok || !response.Found {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+			"error": "Job not found: " + jobID,
 		})
 		return
 	}
 
-	selectClause, sortableColumns := buildSelectClause(projection)
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString("SELECT ")
-	queryBuilder.WriteString(selectClause)
-	queryBuilder.WriteString(" FROM ")
-	queryBuilder.WriteString(quoteQualifiedIdentifier(projection.TargetView))
+	c.JSON(http.StatusOK, response.Job)
+}
 
-	filtersMap := c.QueryMap("filters")
-	var (
-		whereClauses   []string
-		queryArgs      []interface{}
-		appliedFilters = make(map[string]interface{})
-		parameterIndex = 1
-	)
+// GetTableStats returns run statistics and daily trend data for a table from the sync history
+func (h *APIHandler) GetTableStats(c *gin.Context) {
+	if h.History == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync history is not enabled",
+		})
+		return
+	}
 
-	for _, filterCfg := range projection.Filters {
-		raw, exists := filtersMap[filterCfg.ID]
-		if !exists {
-			continue
+	tableName := c.Param("name")
+	found := false
+	for _, tc := range h.Config.Tables {
+		if tc.TargetTable == tableName {
+			found = true
+			break
 		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Table not found: " + tableName,
+		})
+		return
+	}
 
-		raw = strings.TrimSpace(raw)
-		if raw == "" {
-			continue
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid limit",
+			})
+			return
 		}
+		limit = parsed
+	}
 
-		columnIdentifier := quoteIdentifier(filterCfg.Column)
-		switch strings.ToLower(filterCfg.Type) {
-		case "select":
-			values := splitAndClean(raw)
-			if len(values) == 0 {
-				continue
-			}
-			placeholders := make([]string, 0, len(values))
-			for _, value := range values {
-				queryArgs = append(queryArgs, value)
-				placeholders = append(placeholders, fmt.Sprintf("$%d", parameterIndex))
-				parameterIndex++
-			}
-			whereClauses = append(whereClauses, fmt.Sprintf("%s IN (%s)", columnIdentifier, strings.Join(placeholders, ", ")))
-			appliedFilters[filterCfg.ID] = values
-		case "number":
-			value, err := strconv.ParseFloat(raw, 64)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": fmt.Sprintf("Invalid numeric filter for %s", filterCfg.ID),
-				})
-				return
-			}
-			queryArgs = append(queryArgs, value)
-			whereClauses = append(whereClauses, fmt.Sprintf("%s >= $%d", columnIdentifier, parameterIndex))
-			parameterIndex++
-			appliedFilters[filterCfg.ID] = value
-		default:
-			queryArgs = append(queryArgs, raw)
-			whereClauses = append(whereClauses, fmt.Sprintf("%s = $%d", columnIdentifier, parameterIndex))
-			parameterIndex++
-			appliedFilters[filterCfg.ID] = raw
-		}
+	records, err := h.History.Recent(tableName, limit)
+	if err != nil {
+		h.Logger.Error("Failed to load sync history",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load sync history",
+		})
+		return
 	}
 
-	if len(whereClauses) > 0 {
-		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+	maintenance, err := h.History.RecentMaintenance(tableName, limit)
+	if err != nil {
+		h.Logger.Warn("Failed to load maintenance history",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
 	}
 
-	sortColumn := strings.TrimSpace(c.Query("sort"))
-	sortDirection := strings.ToUpper(strings.TrimSpace(c.DefaultQuery("direction", "")))
-	if sortDirection != "ASC" && sortDirection != "DESC" {
-		sortDirection = ""
+	targets, err := h.History.RecentFanout(tableName, limit)
+	if err != nil {
+		h.Logger.Warn("Failed to load fan-out target history",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
 	}
 
-	if sortColumn == "" && projection.DefaultSort != nil {
-		sortColumn = projection.DefaultSort.Column
-		sortDirection = strings.ToUpper(projection.DefaultSort.Direction)
+	c.JSON(http.StatusOK, gin.H{
+		"stats":       history.ComputeStats(tableName, records),
+		"runs":        records,
+		"maintenance": maintenance,
+		"targets":     targets,
+	})
+}
+
+// fetchTableStates asks the coordinator for the current table states
+func (h *APIHandler) fetchTableStates() map[string]actorpkg.TableState {
+	if h.ActorSystem == nil || h.CoordinatorPID == nil {
+		return nil
 	}
 
-	if projection.DefaultSort != nil {
-		sortableColumns[strings.ToLower(projection.DefaultSort.Column)] = true
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, &actorpkg.GetTableStatesMessage{}, 2*time.Second).Result()
+	if err != nil {
+		h.Logger.Warn("Failed to fetch table states from coordinator", zap.Error(err))
+		return nil
 	}
 
-	if sortColumn != "" {
-		if !sortableColumns[strings.ToLower(sortColumn)] {
-			sortColumn = ""
-		} else {
-			if sortDirection != "DESC" {
-				sortDirection = "ASC"
-			}
-			queryBuilder.WriteString(" ORDER BY ")
-			queryBuilder.WriteString(quoteIdentifier(sortColumn))
-			queryBuilder.WriteRune(' ')
-			queryBuilder.WriteString(sortDirection)
+	response, ok := result.(*actorpkg.TableStatesResponse)
+	if !ok {
+		return nil
+	}
+	return response.States
+}
+
+// ListProjections returns all configured projections for the UI, with titles and labels in the request's language
+// and the requesting user's column preferences
+func (h *APIHandler) ListProjections(c *gin.Context) {
+	projections := h.Config.Projections
+	if lang := h.requestLanguage(c); lang != "" {
+		projections = make([]config.ProjectionConfig, len(h.Config.Projections))
+		for i := range h.Config.Projections {
+			projections[i] = h.Config.Projections[i].Localize(lang)
+		}
+	}
+	response := gin.H{
+		"projections": projections,
+	}
+	if preferences := h.userPreferences(c); preferences != nil {
+		response["preferences"] = preferences
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProjectionData returns data for a specific projection view with optional filters and sorting
+func (h *APIHandler) GetProjectionData(c *gin.Context) {
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return
+	}
+	projection = h.localizeProjection(c, projection)
+	if projection.IsSourcePassthrough() {
+		h.getPassthroughData(c, projection)
+		return
+	}
+
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		h.Logger.Error("Target database not configured for projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Target database connection is not available",
+		})
+		return
+	}
+
+	params := projectionParamsFromRequest(c)
+	maxRows := projection.GetMaxRows(&h.Config.API)
+	if maxRows > 0 {
+		// One row more than the cap is read to tell whether the result was truncated
+		params.Limit = maxRows + 1
+	}
+	var snapshotAt *time.Time
+	if raw := c.Query("as_of"); raw != "" {
+		at, ok := h.resolveAsOf(c, projection, raw, &params)
+		if !ok {
+			return
 		}
+		snapshotAt = &at
+	}
+	projectionQuery, err := buildProjectionQuery(params, projection)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
 	}
 
-	query := queryBuilder.String()
+	query := projectionQuery.SQL
+	queryArgs := projectionQuery.Args
+	appliedFilters := projectionQuery.AppliedFilters
+	sortColumn := projectionQuery.SortColumn
+	sortDirection := projectionQuery.SortDirection
+
 	h.Logger.Debug("Executing projection query",
 		zap.String("projection_id", projection.ID),
 		zap.String("query", query),
 		zap.Any("args", queryArgs),
 	)
 
-	rows, err := h.DBManager.Target.Queryx(query, queryArgs...)
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "projection:"+projection.ID), projection)
+	defer cancel()
+	if !h.DBManager.TargetBreaker.Allow() {
+		if !h.serveCachedProjection(c, projection) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Target database is unavailable",
+			})
+		}
+		return
+	}
+	debug, ok := h.projectionExplain(queryCtx, c, projection, projectionQuery)
+	if !ok {
+		return
+	}
+	rows, err := h.DBManager.Target.QueryxPreparedContext(queryCtx, query, queryArgs...)
+	if err != nil {
+		if database.IsConnectionError(err) && h.serveCachedProjection(c, projection) {
+			h.targetUnavailable(err)
+			return
+		}
+		h.projectionQueryFailed(queryCtx, c, projection, "Failed to query projection data", err)
+		return
+	}
+	h.targetAvailable()
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		h.Logger.Error("Failed to query projection data",
+		h.Logger.Error("Failed to read projection column types",
 			zap.String("projection_id", projection.ID),
 			zap.Error(err),
 		)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to query projection data",
+			"error": "Failed to read projection columns",
 		})
 		return
 	}
-	defer rows.Close()
+
+	switch format := projectionFormat(c); format {
+	case formatArrow, formatParquet:
+		h.writeColumnarProjection(c, projection.ID, format, rows, columnTypes, maxRows)
+		return
+	case formatNDJSON:
+		h.streamProjectionNDJSON(c, projection, rows, maxRows)
+		return
+	case formatCSV:
+		h.writeProjectionCSV(c, projection, rows, columnTypes, maxRows)
+		return
+	}
+
+	columnsMeta := buildColumnsMeta(projection, columnTypes, h.columnDescriptions(queryCtx, projection.TargetView, projection.SyncTable))
+
+	fieldsByColumn := projectionFieldsByColumn(projection)
+	totals := newProjectionTotals(projection)
+	annotate := projection.HasRules()
 
 	var (
 		resultRows  []map[string]interface{}
-		totalSums   = make(map[string]float64)
-		totalCounts = make(map[string]int)
+		annotations []map[string]CellAnnotation
+		rowActions  []map[string]string
+		truncated   bool
 	)
 
 	for rows.Next() {
+		if maxRows > 0 && len(resultRows) == maxRows {
+			truncated = true
+			break
+		}
 		rowData := make(map[string]interface{})
 		if err := rows.MapScan(rowData); err != nil {
 			h.Logger.Error("Failed to scan projection row",
@@ -317,64 +704,25 @@ func (h *APIHandler) GetProjectionData(c *gin.Context) {
 			return
 		}
 
-		normalizedRow := make(map[string]interface{})
-		lowerRow := make(map[string]interface{})
-		lowerRaw := make(map[string]interface{})
-		for col, val := range rowData {
-			normalized := normalizeDBValue(val)
-			normalizedRow[col] = normalized
-			lowerKey := strings.ToLower(col)
-			lowerRow[lowerKey] = normalized
-			lowerRaw[lowerKey] = val
+		row := normalizeProjectionRow(rowData, fieldsByColumn)
+		totals.add(row)
+		resultRows = append(resultRows, row.values)
+		if annotate {
+			annotations = append(annotations, annotateRow(projection, row))
 		}
-
-		resultRows = append(resultRows, normalizedRow)
-
-		for _, totalCfg := range projection.Totals {
-			columnKey := strings.ToLower(totalCfg.Column)
-			switch strings.ToLower(totalCfg.Format) {
-			case "count":
-				if lowerRow[columnKey] != nil {
-					totalCounts[columnKey]++
-				}
-			default:
-				if value, ok := lowerRaw[columnKey]; ok {
-					if floatVal, converted := valueToFloat64(value); converted {
-						totalSums[columnKey] += floatVal
-						continue
-					}
-				}
-				if value, ok := lowerRow[columnKey]; ok {
-					if floatVal, converted := valueToFloat64(value); converted {
-						totalSums[columnKey] += floatVal
-					}
-				}
-			}
+		if len(projection.Actions) > 0 {
+			rowActions = append(rowActions, renderRowActions(projection, row))
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		h.Logger.Error("Error iterating projection rows",
-			zap.String("projection_id", projection.ID),
-			zap.Error(err),
-		)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error reading projection rows",
-		})
+		h.projectionQueryFailed(queryCtx, c, projection, "Error reading projection rows", err)
 		return
 	}
 
-	totalsResponse := make(map[string]interface{})
-	for _, totalCfg := range projection.Totals {
-		columnKey := strings.ToLower(totalCfg.Column)
-		switch strings.ToLower(totalCfg.Format) {
-		case "count":
-			totalsResponse[totalCfg.Column] = totalCounts[columnKey]
-		default:
-			totalsResponse[totalCfg.Column] = totalSums[columnKey]
-		}
-	}
+	totalsResponse := totals.response(params.Select)
 
+	addUsageRows(c, len(resultRows))
 	response := gin.H{
 		"projection_id": projection.ID,
 		"rows":          resultRows,
@@ -383,9 +731,30 @@ func (h *APIHandler) GetProjectionData(c *gin.Context) {
 		"meta": gin.H{
 			"sort_column":    sortColumn,
 			"sort_direction": sortDirection,
+			"sort":           formatSort(projectionQuery.Sort),
 			"row_count":      len(resultRows),
+			"truncated":      truncated,
+			"max_rows":       maxRows,
+			"columns":        columnsMeta,
 		},
 	}
+	if annotate {
+		response["annotations"] = annotations
+	}
+	if len(projection.Actions) > 0 {
+		response["actions"] = rowActions
+	}
+	if snapshotAt != nil {
+		meta := response["meta"].(gin.H)
+		meta["as_of"] = c.Query("as_of")
+		meta["snapshot_at"] = snapshotAt.Format(time.RFC3339Nano)
+	}
+	if h.Results != nil && debug == nil {
+		h.Results.store(resultCacheKey(c, projection), response)
+	}
+	if debug != nil {
+		response["debug"] = debug
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -407,7 +776,13 @@ func buildSelectClause(projection *config.ProjectionConfig) (string, map[string]
 
 	columns := make([]string, 0, len(projection.Fields))
 	for _, field := range projection.Fields {
-		columns = append(columns, quoteIdentifier(field.Column))
+		target := projection.TargetColumn(field.Column)
+		column := quoteIdentifier(target)
+		if target != field.Column {
+			// Renamed columns are returned under their configured names
+			column += " AS " + sqlident.PostgresColumn(field.Column)
+		}
+		columns = append(columns, column)
 		sortable := field.Sortable == nil || (field.Sortable != nil && *field.Sortable)
 		if sortable {
 			sortableColumns[strings.ToLower(field.Column)] = true
@@ -417,6 +792,94 @@ func buildSelectClause(projection *config.ProjectionConfig) (string, map[string]
 	return strings.Join(columns, ", "), sortableColumns
 }
 
+// buildColumnsMeta combines the database column types with the projection field hints
+func buildColumnsMeta(projection *config.ProjectionConfig, columnTypes []*sql.ColumnType, descriptions map[string]string) []ProjectionColumnMeta {
+	fieldsByColumn := projectionFieldsByColumn(projection)
+
+	columns := make([]ProjectionColumnMeta, 0, len(columnTypes))
+	for _, columnType := range columnTypes {
+		meta := ProjectionColumnMeta{
+			Column:       columnType.Name(),
+			DatabaseType: strings.ToLower(columnType.DatabaseTypeName()),
+			Type:         classifyDatabaseType(columnType.DatabaseTypeName()),
+			Description:  descriptions[strings.ToLower(projection.TargetColumn(columnType.Name()))],
+		}
+		if field, ok := fieldsByColumn[strings.ToLower(columnType.Name())]; ok {
+			meta.Label = field.Label
+			meta.Display = strings.ToLower(field.Type)
+			if field.Format != nil {
+				meta.Format = field.Format
+				meta.Locale = fieldLocale(projection, field)
+			}
+		}
+
+		switch meta.Type {
+		case "number":
+			meta.Align = "right"
+		case "bool":
+			meta.Align = "center"
+		default:
+			meta.Align = "left"
+		}
+
+		columns = append(columns, meta)
+	}
+	return columns
+}
+
+// columnDescriptions returns the column comments of the given relations keyed by lower-case column name.
+// Earlier relations take precedence, so comments on the projection view override those copied onto the synced table
+func (h *APIHandler) columnDescriptions(ctx context.Context, relations ...string) map[string]string {
+	descriptions := make(map[string]string)
+	for _, relation := range relations {
+		if relation == "" {
+			continue
+		}
+
+		rows, err := h.DBManager.Target.QueryxContext(ctx, `
+			SELECT a.attname, col_description(a.attrelid, a.attnum)
+			FROM pg_attribute a
+			WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+				AND col_description(a.attrelid, a.attnum) IS NOT NULL`, quoteQualifiedIdentifier(relation))
+		if err != nil {
+			h.Logger.Warn("Failed to read column descriptions", zap.String("relation", relation), zap.Error(err))
+			continue
+		}
+
+		for rows.Next() {
+			var column, description string
+			if err := rows.Scan(&column, &description); err != nil {
+				break
+			}
+			if _, ok := descriptions[strings.ToLower(column)]; !ok {
+				descriptions[strings.ToLower(column)] = description
+			}
+		}
+		rows.Close()
+	}
+	return descriptions
+}
+
+// classifyDatabaseType maps a PostgreSQL type name, or a SQL Server one for passthrough projections, to a generic
+// UI type
+func classifyDatabaseType(databaseType string) string {
+	switch strings.ToUpper(databaseType) {
+	case "INT2", "INT4", "INT8", "NUMERIC", "FLOAT4", "FLOAT8", "MONEY",
+		"TINYINT", "SMALLINT", "INT", "BIGINT", "DECIMAL", "FLOAT", "REAL", "SMALLMONEY":
+		return "number"
+	case "BOOL", "BIT":
+		return "bool"
+	case "DATE":
+		return "date"
+	case "TIMESTAMP", "TIMESTAMPTZ", "DATETIME", "DATETIME2", "SMALLDATETIME", "DATETIMEOFFSET":
+		return "datetime"
+	case "TIME", "TIMETZ":
+		return "time"
+	default:
+		return "string"
+	}
+}
+
 func quoteIdentifier(identifier string) string {
 	identifier = strings.TrimSpace(identifier)
 	if identifier == "" {
@@ -426,12 +889,7 @@ func quoteIdentifier(identifier string) string {
 		return "*"
 	}
 
-	parts := strings.Split(identifier, ".")
-	for i, part := range parts {
-		cleaned := strings.ReplaceAll(part, "\"", "\"\"")
-		parts[i] = fmt.Sprintf("\"%s\"", cleaned)
-	}
-	return strings.Join(parts, ".")
+	return sqlident.Postgres(identifier)
 }
 
 func quoteQualifiedIdentifier(identifier string) string {
@@ -466,6 +924,116 @@ func normalizeDBValue(value interface{}) interface{} {
 	}
 }
 
+// applyFieldPolicy applies the configured NULL and empty string display policies to a value
+func applyFieldPolicy(field config.ProjectionFieldConfig, value interface{}) interface{} {
+	if str, ok := value.(string); ok && str == "" {
+		switch strings.ToLower(field.EmptyString) {
+		case "null":
+			value = nil
+		case "default":
+			if field.Default != nil {
+				return *field.Default
+			}
+		}
+	}
+
+	if value == nil && strings.EqualFold(field.NullPolicy, "default") && field.Default != nil {
+		return *field.Default
+	}
+	return value
+}
+
+// projectionFieldsByColumn returns the projection's fields keyed by lower-case column name
+func projectionFieldsByColumn(projection *config.ProjectionConfig) map[string]config.ProjectionFieldConfig {
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+	return fieldsByColumn
+}
+
+// projectionRow is a scanned projection row with its values normalized for the JSON response
+type projectionRow struct {
+	values map[string]interface{} // normalized values by column
+	lower  map[string]interface{} // normalized values by lower-case column
+	raw    map[string]interface{} // scanned values by lower-case column
+}
+
+// normalizeProjectionRow normalizes the scanned values of a row and applies the field display policies
+func normalizeProjectionRow(rowData map[string]interface{}, fieldsByColumn map[string]config.ProjectionFieldConfig) projectionRow {
+	row := projectionRow{
+		values: make(map[string]interface{}, len(rowData)),
+		lower:  make(map[string]interface{}, len(rowData)),
+		raw:    make(map[string]interface{}, len(rowData)),
+	}
+	for col, val := range rowData {
+		normalized := normalizeDBValue(val)
+		lowerKey := strings.ToLower(col)
+		if field, ok := fieldsByColumn[lowerKey]; ok {
+			normalized = applyFieldPolicy(field, normalized)
+		}
+		row.values[col] = normalized
+		row.lower[lowerKey] = normalized
+		row.raw[lowerKey] = val
+	}
+	return row
+}
+
+// projectionTotals accumulates the configured totals of a projection over the rows of a response
+type projectionTotals struct {
+	projection *config.ProjectionConfig
+	sums       map[string]float64
+	counts     map[string]int
+}
+
+func newProjectionTotals(projection *config.ProjectionConfig) *projectionTotals {
+	return &projectionTotals{projection: projection, sums: make(map[string]float64), counts: make(map[string]int)}
+}
+
+// add adds a row to the totals
+func (t *projectionTotals) add(row projectionRow) {
+	for _, totalCfg := range t.projection.Totals {
+		columnKey := strings.ToLower(totalCfg.Column)
+		switch strings.ToLower(totalCfg.Format) {
+		case "count":
+			if row.lower[columnKey] != nil {
+				t.counts[columnKey]++
+			}
+		default:
+			if value, ok := row.raw[columnKey]; ok {
+				if floatVal, converted := valueToFloat64(value); converted {
+					t.sums[columnKey] += floatVal
+					continue
+				}
+			}
+			if value, ok := row.lower[columnKey]; ok {
+				if floatVal, converted := valueToFloat64(value); converted {
+					t.sums[columnKey] += floatVal
+				}
+			}
+		}
+	}
+}
+
+// response returns the totals by configured column, leaving out those of columns not in selected when it is set
+func (t *projectionTotals) response(selected []string) map[string]interface{} {
+	totals := make(map[string]interface{})
+	for _, totalCfg := range t.projection.Totals {
+		if len(selected) > 0 && !hasColumn(selected, totalCfg.Column) {
+			// Totals of columns left out by columns= are left out too
+			continue
+		}
+		columnKey := strings.ToLower(totalCfg.Column)
+		switch strings.ToLower(totalCfg.Format) {
+		case "count":
+			totals[totalCfg.Column] = t.counts[columnKey]
+		default:
+			totals[totalCfg.Column] = t.sums[columnKey]
+		}
+	}
+	return totals
+}
+
 func valueToFloat64(value interface{}) (float64, bool) {
 	switch v := value.(type) {
 	case nil:
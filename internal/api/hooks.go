@@ -0,0 +1,140 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	actorpkg "mssql-postgres-sync/internal/actor"
+	"mssql-postgres-sync/internal/config"
+)
+
+// HookResponse represents the response of an inbound webhook call
+type HookResponse struct {
+	Success   bool       `json:"success"`
+	Message   string     `json:"message"`
+	JobID     string     `json:"job_id,omitempty"`
+	Debounced bool       `json:"debounced,omitempty"`
+	FiresAt   *time.Time `json:"fires_at,omitempty"`
+}
+
+// TriggerHook handles an authenticated inbound webhook and triggers the tables mapped to it
+func (h *APIHandler) TriggerHook(c *gin.Context) {
+	name := c.Param("name")
+	hook, ok := h.Config.GetHook(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, HookResponse{
+			Success: false,
+			Message: "Hook not found: " + name,
+		})
+		return
+	}
+
+	if !hookAuthorized(c, hook) {
+		h.Logger.Warn("Rejected unauthorized hook call",
+			zap.String("hook", name),
+			zap.String("ip", c.ClientIP()),
+		)
+		c.JSON(http.StatusUnauthorized, HookResponse{
+			Success: false,
+			Message: "Invalid hook token",
+		})
+		return
+	}
+
+	if h.DBManager != nil && !h.DBManager.Available() {
+		c.JSON(http.StatusServiceUnavailable, HookResponse{
+			Success: false,
+			Message: "Sync paused: database circuit breaker is open",
+		})
+		return
+	}
+
+	tables, err := h.hookTables(hook)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, HookResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.ActorSystem.Root.RequestFuture(h.CoordinatorPID, &actorpkg.TriggerTablesMessage{
+		Source:   "hook:" + hook.Name,
+		Tables:   tables,
+		Mode:     hook.Mode,
+		Debounce: time.Duration(hook.Debounce) * time.Second,
+	}, 5*time.Second).Result()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, HookResponse{
+			Success: false,
+			Message: "Failed to trigger sync: " + err.Error(),
+		})
+		return
+	}
+
+	switch response := result.(type) {
+	case *actorpkg.DebouncedResponse:
+		firesAt := response.FiresAt
+		c.JSON(http.StatusAccepted, HookResponse{
+			Success:   true,
+			Message:   fmt.Sprintf("Sync for hook %s scheduled", hook.Name),
+			Debounced: true,
+			FiresAt:   &firesAt,
+		})
+	case *actorpkg.JobResponse:
+		c.JSON(http.StatusAccepted, HookResponse{
+			Success: true,
+			Message: fmt.Sprintf("Sync triggered for hook %s", hook.Name),
+			JobID:   response.Job.ID,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, HookResponse{
+			Success: false,
+			Message: fmt.Sprintf("Unexpected coordinator response %T", result),
+		})
+	}
+}
+
+// hookAuthorized checks the hook token from the Authorization bearer or X-Hook-Token header
+func hookAuthorized(c *gin.Context, hook *config.HookConfig) bool {
+	if hook.Token == "" {
+		return false
+	}
+
+	token := c.GetHeader("X-Hook-Token")
+	if auth := c.GetHeader("Authorization"); token == "" && strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(hook.Token)) == 1
+}
+
+// hookTables resolves the hook's tables, skipping tables with the WebAPI trigger disabled
+func (h *APIHandler) hookTables(hook *config.HookConfig) ([]string, error) {
+	var tables []string
+	for _, name := range hook.Tables {
+		var tableConfig *config.TableConfig
+		for i := range h.Config.Tables {
+			if h.Config.Tables[i].TargetTable == name {
+				tableConfig = &h.Config.Tables[i]
+				break
+			}
+		}
+		if tableConfig == nil {
+			return nil, fmt.Errorf("hook %s references unknown table %s", hook.Name, name)
+		}
+		if !tableConfig.GetWebAPITrigger(h.Config.Defaults) {
+			continue
+		}
+		tables = append(tables, name)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("hook %s has no tables with the WebAPI trigger enabled", hook.Name)
+	}
+	return tables, nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// languageKey is the gin context key of the language a request's titles and labels are returned in
+const languageKey = "language"
+
+// languageMatcher picks the configured language closest to a request's, ui.language first
+type languageMatcher struct {
+	languages []string
+	matcher   language.Matcher
+}
+
+// newLanguageMatcher returns a matcher over the configured languages, nil when nothing is translated
+func newLanguageMatcher(cfg *config.Config) *languageMatcher {
+	languages := cfg.Languages()
+	if len(languages) < 2 {
+		return nil
+	}
+	tags := make([]language.Tag, len(languages))
+	for i, lang := range languages {
+		tags[i] = language.Make(lang)
+	}
+	return &languageMatcher{languages: languages, matcher: language.NewMatcher(tags)}
+}
+
+// requestLanguage returns the translation language of a request, chosen from ?lang= or else the Accept-Language
+// header, and sets Content-Language. It is empty for ui.language and when nothing is translated, which leaves
+// titles and labels as configured
+func (h *APIHandler) requestLanguage(c *gin.Context) string {
+	if h.languages == nil {
+		return ""
+	}
+	if lang, ok := c.Get(languageKey); ok {
+		return lang.(string)
+	}
+
+	requested := c.Query("lang")
+	if requested == "" {
+		requested = c.GetHeader("Accept-Language")
+	}
+	lang := h.languages.languages[0]
+	if tags, _, err := language.ParseAcceptLanguage(requested); err == nil && len(tags) > 0 {
+		if _, index, confidence := h.languages.matcher.Match(tags...); confidence != language.No {
+			lang = h.languages.languages[index]
+		}
+	}
+	c.Header("Content-Language", lang)
+	c.Header("Vary", "Accept-Language")
+
+	if lang == h.languages.languages[0] {
+		lang = ""
+	}
+	c.Set(languageKey, lang)
+	return lang
+}
+
+// localizeProjection returns the projection with its title and labels in the request's language
+func (h *APIHandler) localizeProjection(c *gin.Context, projection *config.ProjectionConfig) *config.ProjectionConfig {
+	lang := h.requestLanguage(c)
+	if lang == "" {
+		return projection
+	}
+	localized := projection.Localize(lang)
+	return &localized
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LogLevelRequest represents a runtime log level change
+type LogLevelRequest struct {
+	Module string `json:"module,omitempty"` // api, sync, actor, database; empty changes every module
+	Level  string `json:"level"`
+}
+
+// LogLevelsResponse represents the current level of every logging module
+type LogLevelsResponse struct {
+	Levels map[string]string `json:"levels"`
+}
+
+// GetLogLevels returns the current log level of every module
+func (h *APIHandler) GetLogLevels(c *gin.Context) {
+	if h.Logging == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Runtime logging configuration is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, LogLevelsResponse{Levels: h.Logging.Levels()})
+}
+
+// SetLogLevel changes the log level of a module without a restart
+func (h *APIHandler) SetLogLevel(c *gin.Context) {
+	if h.Logging == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Runtime logging configuration is not available"})
+		return
+	}
+
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if err := h.Logging.SetLevel(req.Module, req.Level); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.Logger.Info("Log level changed",
+		zap.String("module", req.Module),
+		zap.String("level", req.Level),
+	)
+	c.JSON(http.StatusOK, LogLevelsResponse{Levels: h.Logging.Levels()})
+}
@@ -0,0 +1,775 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// odataNamespace is the schema namespace of the projection entity types
+const odataNamespace = "Projections"
+
+// odataError writes an error in the OData JSON error format
+func odataError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": gin.H{"code": strconv.Itoa(status), "message": message}})
+}
+
+// odataBaseURL returns the absolute URL of the OData service root of a request
+func odataBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + "/odata"
+}
+
+// ODataServiceDocument lists the projections as OData entity sets
+func (h *APIHandler) ODataServiceDocument(c *gin.Context) {
+	sets := make([]gin.H, 0, len(h.Config.Projections))
+	for _, projection := range h.Config.Projections {
+		if projection.IsSourcePassthrough() {
+			continue
+		}
+		sets = append(sets, gin.H{"name": projection.ID, "kind": "EntitySet", "url": projection.ID})
+	}
+	c.Header("OData-Version", "4.0")
+	c.JSON(http.StatusOK, gin.H{
+		"@odata.context": odataBaseURL(c) + "/$metadata",
+		"value":          sets,
+	})
+}
+
+// CSDL documents of the OData metadata endpoint
+type (
+	edmx struct {
+		XMLName      xml.Name         `xml:"edmx:Edmx"`
+		Version      string           `xml:"Version,attr"`
+		Namespace    string           `xml:"xmlns:edmx,attr"`
+		DataServices edmxDataServices `xml:"edmx:DataServices"`
+	}
+	edmxDataServices struct {
+		Schema edmSchema `xml:"Schema"`
+	}
+	edmSchema struct {
+		Xmlns       string             `xml:"xmlns,attr"`
+		Namespace   string             `xml:"Namespace,attr"`
+		EntityTypes []edmEntityType    `xml:"EntityType"`
+		Container   edmEntityContainer `xml:"EntityContainer"`
+	}
+	edmEntityType struct {
+		Name       string        `xml:"Name,attr"`
+		Key        *edmKey       `xml:"Key,omitempty"`
+		Properties []edmProperty `xml:"Property"`
+	}
+	edmKey struct {
+		PropertyRefs []edmPropertyRef `xml:"PropertyRef"`
+	}
+	edmPropertyRef struct {
+		Name string `xml:"Name,attr"`
+	}
+	edmProperty struct {
+		Name     string `xml:"Name,attr"`
+		Type     string `xml:"Type,attr"`
+		Nullable bool   `xml:"Nullable,attr"`
+	}
+	edmEntityContainer struct {
+		Name       string         `xml:"Name,attr"`
+		EntitySets []edmEntitySet `xml:"EntitySet"`
+	}
+	edmEntitySet struct {
+		Name       string `xml:"Name,attr"`
+		EntityType string `xml:"EntityType,attr"`
+	}
+)
+
+// ODataMetadata returns the CSDL document describing each projection as an entity type, with the property types
+// read from its target view
+func (h *APIHandler) ODataMetadata(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		odataError(c, http.StatusInternalServerError, "Target database connection is not available")
+		return
+	}
+
+	schema := edmSchema{
+		Xmlns:     "http://docs.oasis-open.org/odata/ns/edm",
+		Namespace: odataNamespace,
+		Container: edmEntityContainer{Name: "Container"},
+	}
+	for i := range h.Config.Projections {
+		projection := &h.Config.Projections[i]
+		if projection.IsSourcePassthrough() {
+			continue
+		}
+		entityType, err := h.odataEntityType(projection)
+		if err != nil {
+			h.Logger.Warn("Failed to describe projection for OData",
+				zap.String("projection_id", projection.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		schema.EntityTypes = append(schema.EntityTypes, entityType)
+		schema.Container.EntitySets = append(schema.Container.EntitySets, edmEntitySet{
+			Name:       projection.ID,
+			EntityType: odataNamespace + "." + entityType.Name,
+		})
+	}
+
+	document, err := xml.MarshalIndent(edmx{
+		Version:      "4.0",
+		Namespace:    "http://docs.oasis-open.org/odata/ns/edmx",
+		DataServices: edmxDataServices{Schema: schema},
+	}, "", "  ")
+	if err != nil {
+		odataError(c, http.StatusInternalServerError, "Failed to build metadata")
+		return
+	}
+	c.Header("OData-Version", "4.0")
+	c.Data(http.StatusOK, "application/xml", append([]byte(xml.Header), document...))
+}
+
+// odataEntityType describes a projection's fields, or all columns of its view when it has none. The key is the
+// projection's key when the projection returns all its columns, else the first property
+func (h *APIHandler) odataEntityType(projection *config.ProjectionConfig) (edmEntityType, error) {
+	schemaName, view := sqlident.SplitQualified(projection.TargetView, "public")
+	var columns []struct {
+		Name     string `db:"column_name"`
+		DataType string `db:"data_type"`
+		Nullable string `db:"is_nullable"`
+	}
+	if err := h.DBManager.Target.Select(&columns, `
+		SELECT column_name, data_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		ORDER BY ordinal_position
+	`, schemaName, view); err != nil {
+		return edmEntityType{}, err
+	}
+	if len(columns) == 0 {
+		return edmEntityType{}, fmt.Errorf("target view %s does not exist or has no columns", projection.TargetView)
+	}
+
+	entityType := edmEntityType{Name: odataTypeName(projection.ID)}
+	property := func(name, column string) {
+		for _, col := range columns {
+			if col.Name == column {
+				entityType.Properties = append(entityType.Properties, edmProperty{
+					Name:     name,
+					Type:     edmType(col.DataType),
+					Nullable: col.Nullable == "YES",
+				})
+				return
+			}
+		}
+	}
+	if len(projection.Fields) == 0 {
+		for _, col := range columns {
+			property(col.Name, col.Name)
+		}
+	} else {
+		for _, field := range projection.Fields {
+			property(field.Column, projection.TargetColumn(field.Column))
+		}
+	}
+	if len(entityType.Properties) == 0 {
+		return edmEntityType{}, fmt.Errorf("no projection field exists in target view %s", projection.TargetView)
+	}
+
+	key := &edmKey{}
+	keyColumns := h.Config.ProjectionKey(projection)
+	for _, column := range keyColumns {
+		for _, prop := range entityType.Properties {
+			if strings.EqualFold(prop.Name, column) {
+				key.PropertyRefs = append(key.PropertyRefs, edmPropertyRef{Name: prop.Name})
+			}
+		}
+	}
+	if len(key.PropertyRefs) != len(keyColumns) {
+		key.PropertyRefs = nil
+	}
+	if len(key.PropertyRefs) == 0 {
+		key.PropertyRefs = []edmPropertyRef{{Name: entityType.Properties[0].Name}}
+	}
+	entityType.Key = key
+	return entityType, nil
+}
+
+// odataTypeName turns a projection id into an entity type name, e.g. orders-performance -> OrdersPerformance
+func odataTypeName(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// edmType maps a PostgreSQL information_schema data type to an EDM primitive type
+func edmType(dataType string) string {
+	switch strings.ToLower(dataType) {
+	case "smallint":
+		return "Edm.Int16"
+	case "integer":
+		return "Edm.Int32"
+	case "bigint":
+		return "Edm.Int64"
+	case "numeric", "money":
+		return "Edm.Decimal"
+	case "real":
+		return "Edm.Single"
+	case "double precision":
+		return "Edm.Double"
+	case "boolean":
+		return "Edm.Boolean"
+	case "date":
+		return "Edm.Date"
+	case "timestamp without time zone", "timestamp with time zone":
+		return "Edm.DateTimeOffset"
+	case "time without time zone", "time with time zone":
+		return "Edm.TimeOfDay"
+	case "uuid":
+		return "Edm.Guid"
+	case "bytea":
+		return "Edm.Binary"
+	default:
+		return "Edm.String"
+	}
+}
+
+// GetODataEntitySet returns the rows of a projection for an OData query. $filter, $orderby, $select, $top and $skip
+// are translated into the projection query builder, so they are restricted to the projection's fields
+func (h *APIHandler) GetODataEntitySet(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		odataError(c, http.StatusInternalServerError, "Target database connection is not available")
+		return
+	}
+
+	projection, ok := h.Config.GetProjectionByID(c.Param("id"))
+	if !ok || projection.IsSourcePassthrough() {
+		odataError(c, http.StatusNotFound, fmt.Sprintf("Entity set not found: %s", c.Param("id")))
+		return
+	}
+
+	params, err := odataParams(c, projection, h.Config.API.OData.GetMaxPageSize())
+	if err != nil {
+		odataError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	query, err := buildProjectionQuery(params, projection)
+	if err != nil {
+		odataError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "odata:"+projection.ID), projection)
+	defer cancel()
+	rows, err := h.DBManager.Target.QueryxPreparedContext(queryCtx, query.SQL, query.Args...)
+	if err != nil {
+		h.Logger.Error("Failed to query OData entity set",
+			zap.String("projection_id", projection.ID),
+			zap.Error(err),
+		)
+		if errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+			odataError(c, http.StatusGatewayTimeout, "Projection query exceeded its statement timeout")
+			return
+		}
+		odataError(c, http.StatusInternalServerError, "Failed to query projection data")
+		return
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		odataError(c, http.StatusInternalServerError, "Failed to read projection columns")
+		return
+	}
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+
+	// One row more than the page is read to know whether a next link is needed
+	pageSize := params.Limit - 1
+	values := make([]map[string]interface{}, 0)
+	more := false
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			odataError(c, http.StatusInternalServerError, "Failed to parse projection row")
+			return
+		}
+		if len(values) == pageSize {
+			more = true
+			break
+		}
+		for _, columnType := range columnTypes {
+			name := columnType.Name()
+			value := odataValue(columnType, row[name])
+			if field, ok := fieldsByColumn[strings.ToLower(name)]; ok {
+				value = applyFieldPolicy(field, value)
+			}
+			row[name] = value
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		odataError(c, http.StatusInternalServerError, "Error reading projection rows")
+		return
+	}
+	addUsageRows(c, len(values))
+
+	response := gin.H{
+		"@odata.context": odataBaseURL(c) + "/$metadata#" + projection.ID,
+		"value":          values,
+	}
+	if strings.EqualFold(c.Query("$count"), "true") {
+		var count int64
+		if err := h.DBManager.Target.QueryRowxPreparedContext(queryCtx, query.CountSQL, query.Args...).Scan(&count); err != nil {
+			odataError(c, http.StatusInternalServerError, "Failed to count projection rows")
+			return
+		}
+		response["@odata.count"] = count
+	}
+	// The next link continues the client's $top, so none is given once the page holds all the rows it asked for
+	top, err := strconv.Atoi(c.Query("$top"))
+	if more && (err != nil || top > pageSize) {
+		next := c.Request.URL.Query()
+		next.Set("$skip", strconv.Itoa(params.Offset+pageSize))
+		if err == nil {
+			next.Set("$top", strconv.Itoa(top-pageSize))
+		}
+		response["@odata.nextLink"] = odataBaseURL(c) + "/" + projection.ID + "?" + next.Encode()
+	}
+
+	c.Header("OData-Version", "4.0")
+	c.JSON(http.StatusOK, response)
+}
+
+// odataParams translates the OData system query options of a request into projection query parameters. The limit
+// is one more than the page size, to detect further pages
+func odataParams(c *gin.Context, projection *config.ProjectionConfig, maxPageSize int) (projectionParams, error) {
+	params := projectionParams{Limit: maxPageSize + 1}
+
+	if raw := c.Query("$top"); raw != "" {
+		top, err := strconv.Atoi(raw)
+		if err != nil || top < 0 {
+			return params, fmt.Errorf("$top must be a non-negative integer")
+		}
+		if top < maxPageSize {
+			params.Limit = top + 1
+		}
+	}
+	if raw := c.Query("$skip"); raw != "" {
+		skip, err := strconv.Atoi(raw)
+		if err != nil || skip < 0 {
+			return params, fmt.Errorf("$skip must be a non-negative integer")
+		}
+		params.Offset = skip
+	}
+
+	if raw := strings.TrimSpace(c.Query("$select")); raw != "" && raw != "*" {
+		for _, name := range strings.Split(raw, ",") {
+			column, err := odataColumn(projection, strings.TrimSpace(name))
+			if err != nil {
+				return params, err
+			}
+			params.Select = append(params.Select, column)
+		}
+	}
+
+	if raw := strings.TrimSpace(c.Query("$orderby")); raw != "" {
+		for _, item := range strings.Split(raw, ",") {
+			parts := strings.Fields(item)
+			if len(parts) == 0 || len(parts) > 2 {
+				return params, fmt.Errorf("invalid $orderby item %q", strings.TrimSpace(item))
+			}
+			column, err := odataColumn(projection, parts[0])
+			if err != nil {
+				return params, err
+			}
+			direction := "ASC"
+			if len(parts) == 2 {
+				switch strings.ToLower(parts[1]) {
+				case "asc":
+				case "desc":
+					direction = "DESC"
+				default:
+					return params, fmt.Errorf("invalid $orderby direction %q", parts[1])
+				}
+			}
+			params.OrderBy = append(params.OrderBy, projectionSort{Column: column, Direction: direction})
+		}
+	}
+
+	if raw := strings.TrimSpace(c.Query("$filter")); raw != "" {
+		condition, err := parseODataFilter(raw, projection)
+		if err != nil {
+			return params, fmt.Errorf("invalid $filter: %w", err)
+		}
+		params.Conditions = append(params.Conditions, condition)
+	}
+	return params, nil
+}
+
+// odataColumn resolves a property name to the projection field it names
+func odataColumn(projection *config.ProjectionConfig, name string) (string, error) {
+	for _, field := range projection.Fields {
+		if strings.EqualFold(field.Column, name) {
+			return field.Column, nil
+		}
+	}
+	return "", fmt.Errorf("unknown property %q", name)
+}
+
+// odataValue converts a scanned value to its OData JSON representation
+func odataValue(columnType *sql.ColumnType, value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		if strings.EqualFold(columnType.DatabaseTypeName(), "DATE") {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		switch strings.ToUpper(columnType.DatabaseTypeName()) {
+		case "NUMERIC":
+			return json.Number(v)
+		case "BYTEA":
+			return base64.StdEncoding.EncodeToString(v)
+		}
+		return string(v)
+	default:
+		return normalizeDBValue(value)
+	}
+}
+
+// odataToken is a lexical token of a $filter expression
+type odataToken struct {
+	kind  string // ident, string, number, literal (true, false, null and date/time values) or punct
+	text  string
+	value interface{}
+}
+
+// tokenizeODataFilter splits a $filter expression into tokens
+func tokenizeODataFilter(input string) ([]odataToken, error) {
+	var tokens []odataToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, odataToken{kind: "punct", text: string(r)})
+			i++
+		case r == '\'':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						b.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, odataToken{kind: "string", text: b.String(), value: b.String()})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToLower(word) {
+			case "true", "false":
+				tokens = append(tokens, odataToken{kind: "literal", text: word, value: strings.EqualFold(word, "true")})
+			case "null":
+				tokens = append(tokens, odataToken{kind: "literal", text: word})
+			default:
+				tokens = append(tokens, odataToken{kind: "ident", text: word})
+			}
+		case unicode.IsDigit(r) || r == '-' || r == '+':
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ')' && runes[i] != ',' && runes[i] != '(' {
+				i++
+			}
+			token, err := odataLiteral(string(runes[start:i]))
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+// odataLiteral parses a number, date or date/time literal. Dates and times are bound as text for PostgreSQL to cast
+func odataLiteral(text string) (odataToken, error) {
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return odataToken{kind: "number", text: text, value: n}, nil
+	}
+	if f, err := strconv.ParseFloat(strings.TrimRight(text, "mMdDfF"), 64); err == nil {
+		return odataToken{kind: "number", text: text, value: f}, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339Nano} {
+		if _, err := time.Parse(layout, text); err == nil {
+			return odataToken{kind: "literal", text: text, value: text}, nil
+		}
+	}
+	return odataToken{}, fmt.Errorf("invalid literal %q", text)
+}
+
+// odataComparisons maps OData comparison operators to SQL
+var odataComparisons = map[string]string{"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+
+// odataFilterParser is a recursive descent parser of $filter expressions. It supports comparisons of a property
+// with a literal, contains, startswith and endswith, and combinations with and, or, not and parentheses
+type odataFilterParser struct {
+	tokens     []odataToken
+	pos        int
+	projection *config.ProjectionConfig
+}
+
+// parseODataFilter parses a $filter expression into a projection condition
+func parseODataFilter(input string, projection *config.ProjectionConfig) (projectionCondition, error) {
+	tokens, err := tokenizeODataFilter(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &odataFilterParser{tokens: tokens, projection: projection}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return condition, nil
+}
+
+func (p *odataFilterParser) peek() *odataToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *odataFilterParser) next() (odataToken, error) {
+	if p.pos >= len(p.tokens) {
+		return odataToken{}, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+// keyword reports whether the next token is the given keyword, consuming it if so
+func (p *odataFilterParser) keyword(word string) bool {
+	if t := p.peek(); t != nil && t.kind == "ident" && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *odataFilterParser) expect(punct string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != "punct" || t.text != punct {
+		return fmt.Errorf("expected %q, found %q", punct, t.text)
+	}
+	return nil
+}
+
+func (p *odataFilterParser) parseOr() (projectionCondition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(arg func(interface{}) string) string { return "(" + l(arg) + " OR " + r(arg) + ")" }
+	}
+	return left, nil
+}
+
+func (p *odataFilterParser) parseAnd() (projectionCondition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(arg func(interface{}) string) string { return "(" + l(arg) + " AND " + r(arg) + ")" }
+	}
+	return left, nil
+}
+
+func (p *odataFilterParser) parseNot() (projectionCondition, error) {
+	if p.keyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(arg func(interface{}) string) string { return "NOT " + inner(arg) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *odataFilterParser) parsePrimary() (projectionCondition, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == "punct" && t.text == "(" {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return func(arg func(interface{}) string) string { return "(" + inner(arg) + ")" }, nil
+	}
+	if t.kind != "ident" {
+		return nil, fmt.Errorf("expected a property or function, found %q", t.text)
+	}
+
+	switch fn := strings.ToLower(t.text); fn {
+	case "contains", "startswith", "endswith":
+		if next := p.peek(); next != nil && next.kind == "punct" && next.text == "(" {
+			return p.parseStringFunction(fn)
+		}
+	}
+
+	column, err := p.column(t.text)
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	sqlOp, ok := odataComparisons[strings.ToLower(op.text)]
+	if op.kind != "ident" || !ok {
+		return nil, fmt.Errorf("expected a comparison operator after %s, found %q", t.text, op.text)
+	}
+	literal, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch literal.kind {
+	case "string", "number", "literal":
+	default:
+		return nil, fmt.Errorf("expected a literal after %s %s, found %q", t.text, op.text, literal.text)
+	}
+
+	if literal.kind == "literal" && literal.value == nil {
+		switch sqlOp {
+		case "=":
+			return func(func(interface{}) string) string { return column + " IS NULL" }, nil
+		case "<>":
+			return func(func(interface{}) string) string { return column + " IS NOT NULL" }, nil
+		default:
+			return nil, fmt.Errorf("null can only be compared with eq or ne")
+		}
+	}
+	value := literal.value
+	return func(arg func(interface{}) string) string { return column + " " + sqlOp + " " + arg(value) }, nil
+}
+
+// parseStringFunction parses contains, startswith or endswith of a property and a string into a LIKE condition
+func (p *odataFilterParser) parseStringFunction(fn string) (projectionCondition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != "ident" {
+		return nil, fmt.Errorf("%s expects a property, found %q", fn, t.text)
+	}
+	column, err := p.column(t.text)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	literal, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if literal.kind != "string" {
+		return nil, fmt.Errorf("%s expects a string, found %q", fn, literal.text)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(literal.text)
+	switch fn {
+	case "contains":
+		pattern = "%" + pattern + "%"
+	case "startswith":
+		pattern += "%"
+	case "endswith":
+		pattern = "%" + pattern
+	}
+	return func(arg func(interface{}) string) string { return column + "::text LIKE " + arg(pattern) }, nil
+}
+
+// column resolves a property to the quoted view column of the projection field it names
+func (p *odataFilterParser) column(name string) (string, error) {
+	column, err := odataColumn(p.projection, name)
+	if err != nil {
+		return "", err
+	}
+	return quoteIdentifier(p.projection.TargetColumn(column)), nil
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+)
+
+// PreferencesRequest is the body of PUT /api/projections/:id/preferences
+type PreferencesRequest struct {
+	Hidden []string `json:"hidden"` // field columns not shown
+	Order  []string `json:"order"`  // field columns in display order, the others following in projection order
+}
+
+// requestIdentity returns the user a request is made by: the api.preferences.identity_header value when it is
+// configured, or else the name of the request's API key. It is empty for anonymous requests
+func (h *APIHandler) requestIdentity(c *gin.Context) string {
+	if header := h.Config.API.Preferences.IdentityHeader; header != "" {
+		return strings.TrimSpace(c.GetHeader(header))
+	}
+	if key := requestAPIKey(c); key != nil {
+		return key.Name
+	}
+	return ""
+}
+
+// preferencesProjection resolves the identity and projection of a preferences request, responding with an error
+// and returning false when the history store is disabled, the request is anonymous or the projection has no fields
+func (h *APIHandler) preferencesProjection(c *gin.Context) (string, *config.ProjectionConfig, bool) {
+	if h.History == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync history is not enabled",
+		})
+		return "", nil, false
+	}
+
+	identity := h.requestIdentity(c)
+	if identity == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Preferences require an identified user",
+		})
+		return "", nil, false
+	}
+
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return "", nil, false
+	}
+	if len(projection.Fields) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Projection %s has no fields to hide or order", projection.ID),
+		})
+		return "", nil, false
+	}
+	return identity, projection, true
+}
+
+// GetPreferences returns the requesting user's column preferences for a projection, empty when none were saved
+func (h *APIHandler) GetPreferences(c *gin.Context) {
+	identity, projection, ok := h.preferencesProjection(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.History.GetPreferences(identity, projection.ID)
+	if err != nil {
+		h.Logger.Error("Failed to load column preferences", zap.String("projection_id", projection.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load column preferences",
+		})
+		return
+	}
+	if preferences == nil {
+		preferences = &history.ColumnPreferences{ProjectionID: projection.ID, Hidden: []string{}, Order: []string{}}
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// SavePreferences stores the requesting user's hidden and ordered columns for a projection. Columns must be fields
+// of the projection and are saved with their configured spelling
+func (h *APIHandler) SavePreferences(c *gin.Context) {
+	identity, projection, ok := h.preferencesProjection(c)
+	if !ok {
+		return
+	}
+
+	var req PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	hidden, err := preferenceColumns(projection, req.Hidden)
+	if err == nil && len(hidden) == len(projection.Fields) {
+		err = fmt.Errorf("at least one column must stay visible")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("hidden: %v", err),
+		})
+		return
+	}
+	order, err := preferenceColumns(projection, req.Order)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("order: %v", err),
+		})
+		return
+	}
+
+	preferences := &history.ColumnPreferences{
+		Identity:     identity,
+		ProjectionID: projection.ID,
+		Hidden:       hidden,
+		Order:        order,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if err := h.History.SavePreferences(preferences); err != nil {
+		h.Logger.Error("Failed to save column preferences", zap.String("projection_id", projection.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to save column preferences",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// DeletePreferences resets the requesting user's column preferences for a projection
+func (h *APIHandler) DeletePreferences(c *gin.Context) {
+	identity, projection, ok := h.preferencesProjection(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.History.DeletePreferences(identity, projection.ID)
+	if err != nil {
+		h.Logger.Error("Failed to delete column preferences", zap.String("projection_id", projection.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete column preferences",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"projection_id": projection.ID,
+		"deleted":       deleted,
+	})
+}
+
+// preferenceColumns resolves columns of a preferences request to the projection's field columns, refusing
+// unknown and repeated ones
+func preferenceColumns(projection *config.ProjectionConfig, columns []string) ([]string, error) {
+	resolved := make([]string, 0, len(columns))
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		field, ok := projectionField(projection, column)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a field of projection %s", column, projection.ID)
+		}
+		if seen[field.Column] {
+			return nil, fmt.Errorf("%s is listed twice", field.Column)
+		}
+		seen[field.Column] = true
+		resolved = append(resolved, field.Column)
+	}
+	return resolved, nil
+}
+
+// userPreferences returns the requesting user's column preferences by projection id, nil for anonymous requests or
+// without the history store. A failure to load them is logged and leaves them out
+func (h *APIHandler) userPreferences(c *gin.Context) map[string]history.ColumnPreferences {
+	if h.History == nil {
+		return nil
+	}
+	identity := h.requestIdentity(c)
+	if identity == "" {
+		return nil
+	}
+
+	saved, err := h.History.Preferences(identity)
+	if err != nil {
+		h.Logger.Warn("Failed to load column preferences", zap.Error(err))
+		return nil
+	}
+	preferences := make(map[string]history.ColumnPreferences, len(saved))
+	for _, p := range saved {
+		preferences[p.ProjectionID] = p
+	}
+	return preferences
+}
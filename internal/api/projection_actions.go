@@ -0,0 +1,31 @@
+package api
+
+import (
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// renderRowActions renders the URLs of a projection's actions for a row by action id, nil when none could be
+// rendered. Actions whose placeholders take a NULL value, or a column left out by columns=, are left out
+func renderRowActions(projection *config.ProjectionConfig, row projectionRow) map[string]string {
+	var actions map[string]string
+	for i := range projection.Actions {
+		action := &projection.Actions[i]
+		link, ok := action.Render(func(column string) (string, bool) {
+			value := row.lower[strings.ToLower(column)]
+			if value == nil {
+				return "", false
+			}
+			return plainValue(value), true
+		})
+		if !ok {
+			continue
+		}
+		if actions == nil {
+			actions = make(map[string]string, len(projection.Actions))
+		}
+		actions[action.ID] = link
+	}
+	return actions
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/decimal128"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/compress"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Columnar projection formats
+const (
+	formatArrow   = "arrow"
+	formatParquet = "parquet"
+
+	arrowStreamMediaType = "application/vnd.apache.arrow.stream"
+	parquetMediaType     = "application/vnd.apache.parquet"
+
+	columnarBatchSize = 4096
+)
+
+// columnarWriter writes arrow records to a response body
+type columnarWriter interface {
+	Write(rec arrow.Record) error
+	Close() error
+}
+
+// buildArrowSchema maps projection column types onto an arrow schema
+func buildArrowSchema(columnTypes []*sql.ColumnType) *arrow.Schema {
+	fields := make([]arrow.Field, 0, len(columnTypes))
+	for _, columnType := range columnTypes {
+		fields = append(fields, arrow.Field{
+			Name:     columnType.Name(),
+			Type:     arrowTypeFor(columnType),
+			Nullable: true,
+		})
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// arrowTypeFor returns the arrow type of a column. Numerics are decimals of the column's precision and scale, or
+// strings when unconstrained or wider than decimal128, and money is a string, so neither loses digits
+func arrowTypeFor(columnType *sql.ColumnType) arrow.DataType {
+	switch strings.ToUpper(columnType.DatabaseTypeName()) {
+	case "INT2", "INT4", "INT8":
+		return arrow.PrimitiveTypes.Int64
+	case "NUMERIC":
+		if precision, scale, ok := columnType.DecimalSize(); ok && precision > 0 && precision <= decimal128.MaxPrecision {
+			return &arrow.Decimal128Type{Precision: int32(precision), Scale: int32(scale)}
+		}
+		return arrow.BinaryTypes.String
+	case "FLOAT4", "FLOAT8":
+		return arrow.PrimitiveTypes.Float64
+	case "BOOL":
+		return arrow.FixedWidthTypes.Boolean
+	case "DATE", "TIMESTAMP", "TIMESTAMPTZ":
+		return arrow.FixedWidthTypes.Timestamp_us
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// newColumnarWriter creates an arrow IPC stream or parquet writer for the schema
+func newColumnarWriter(format string, w io.Writer, schema *arrow.Schema, mem memory.Allocator) (columnarWriter, error) {
+	switch format {
+	case formatParquet:
+		props := parquet.NewWriterProperties(
+			parquet.WithCompression(compress.Codecs.Snappy),
+			parquet.WithAllocator(mem),
+		)
+		return pqarrow.NewFileWriter(schema, w, props, pqarrow.NewArrowWriterProperties(pqarrow.WithAllocator(mem)))
+	case formatArrow:
+		return ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem)), nil
+	default:
+		return nil, fmt.Errorf("unsupported columnar format: %s", format)
+	}
+}
+
+// writeColumnarRows streams query rows to the writer in record batches, at most limit of them unless limit is 0,
+// returning the row count
+func writeColumnarRows(rows *sqlx.Rows, schema *arrow.Schema, writer columnarWriter, mem memory.Allocator, limit int) (int, error) {
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	flush := func() error {
+		rec := builder.NewRecord()
+		defer rec.Release()
+		if rec.NumRows() == 0 {
+			return nil
+		}
+		return writer.Write(rec)
+	}
+
+	count := 0
+	pending := 0
+	for (limit <= 0 || count < limit) && rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return count, err
+		}
+		for i, value := range values {
+			appendArrowValue(builder.Field(i), value)
+		}
+		count++
+		pending++
+
+		if pending >= columnarBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+			pending = 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	return count, flush()
+}
+
+// appendArrowValue appends a database value to a column builder, storing NULL when it cannot be converted
+func appendArrowValue(builder array.Builder, value interface{}) {
+	if value == nil {
+		builder.AppendNull()
+		return
+	}
+
+	switch b := builder.(type) {
+	case *array.Int64Builder:
+		switch v := value.(type) {
+		case int64:
+			b.Append(v)
+			return
+		case int32:
+			b.Append(int64(v))
+			return
+		case int:
+			b.Append(int64(v))
+			return
+		case []byte:
+			if parsed, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				b.Append(parsed)
+				return
+			}
+		case string:
+			if parsed, err := strconv.ParseInt(v, 10, 64); err == nil {
+				b.Append(parsed)
+				return
+			}
+		}
+	case *array.Decimal128Builder:
+		scale := b.Type().(*arrow.Decimal128Type).Scale
+		if n, ok := parseDecimal128(fmt.Sprint(normalizeDBValue(value)), scale); ok {
+			b.Append(n)
+			return
+		}
+	case *array.Float64Builder:
+		if f, ok := valueToFloat64(value); ok {
+			b.Append(f)
+			return
+		}
+	case *array.BooleanBuilder:
+		switch v := value.(type) {
+		case bool:
+			b.Append(v)
+			return
+		case []byte:
+			if parsed, err := strconv.ParseBool(string(v)); err == nil {
+				b.Append(parsed)
+				return
+			}
+		}
+	case *array.TimestampBuilder:
+		if ts, ok := value.(time.Time); ok {
+			b.Append(arrow.Timestamp(ts.UnixMicro()))
+			return
+		}
+	case *array.StringBuilder:
+		b.Append(fmt.Sprint(normalizeDBValue(value)))
+		return
+	}
+
+	builder.AppendNull()
+}
+
+// parseDecimal128 parses a decimal string such as -12.50 into a decimal128 of the given scale without going through
+// a float. Digits beyond the scale fail the parse rather than being rounded
+func parseDecimal128(value string, scale int32) (decimal128.Num, bool) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if int32(len(fraction)) > scale {
+		if strings.Trim(fraction[scale:], "0") != "" {
+			return decimal128.Num{}, false
+		}
+		fraction = fraction[:scale]
+	}
+	digits := whole + fraction + strings.Repeat("0", int(scale)-len(fraction))
+
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok || n.BitLen() > 127 {
+		return decimal128.Num{}, false
+	}
+	return decimal128.FromBigInt(n), true
+}
+
+// projectionFormat resolves the requested export format from the format query parameter or Accept header
+func projectionFormat(c *gin.Context) string {
+	if format := strings.ToLower(strings.TrimSpace(c.Query("format"))); format != "" {
+		return format
+	}
+
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, arrowStreamMediaType):
+		return formatArrow
+	case strings.Contains(accept, parquetMediaType):
+		return formatParquet
+	default:
+		return "json"
+	}
+}
+
+// writeColumnarProjection streams projection rows as an arrow IPC stream or parquet file
+func (h *APIHandler) writeColumnarProjection(c *gin.Context, projectionID, format string, rows *sqlx.Rows, columnTypes []*sql.ColumnType, maxRows int) {
+	contentType := arrowStreamMediaType
+	if format == formatParquet {
+		contentType = parquetMediaType
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", projectionID+".parquet"))
+	}
+	c.Header("Content-Type", contentType)
+	announceTruncation(c, maxRows)
+	c.Status(http.StatusOK)
+
+	count, err := exportColumnar(c.Writer, format, rows, columnTypes, maxRows)
+	addUsageRows(c, count)
+	if err != nil {
+		h.Logger.Error("Failed to write projection data",
+			zap.String("projection_id", projectionID),
+			zap.String("format", format),
+			zap.Error(err),
+		)
+		return
+	}
+	reportTruncation(c, rows, count, maxRows)
+
+	h.Logger.Debug("Projection data exported",
+		zap.String("projection_id", projectionID),
+		zap.String("format", format),
+		zap.Int("rows", count),
+	)
+}
+
+// exportColumnar writes the rows to w as an arrow IPC stream or parquet file, at most limit of them unless limit is
+// 0, returning the row count
+func exportColumnar(w io.Writer, format string, rows *sqlx.Rows, columnTypes []*sql.ColumnType, limit int) (int, error) {
+	mem := memory.NewGoAllocator()
+	schema := buildArrowSchema(columnTypes)
+
+	writer, err := newColumnarWriter(format, w, schema, mem)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := writeColumnarRows(rows, schema, writer, mem, limit)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// resultCache keeps the last JSON response of projection data requests by projection and query string, so they can
+// still be answered while the target database is unreachable. The least recently used response is dropped beyond
+// the capacity
+type resultCache struct {
+	capacity int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+}
+
+// cachedResult is a projection data response and when it was produced
+type cachedResult struct {
+	key      string
+	response gin.H
+	storedAt time.Time
+}
+
+func newResultCache(capacity int) *resultCache {
+	return &resultCache{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// store keeps the response of a request, replacing the one cached for the same request
+func (rc *resultCache) store(key string, response gin.H) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if element, ok := rc.entries[key]; ok {
+		rc.order.MoveToFront(element)
+		element.Value = &cachedResult{key: key, response: response, storedAt: time.Now()}
+		return
+	}
+	rc.entries[key] = rc.order.PushFront(&cachedResult{key: key, response: response, storedAt: time.Now()})
+	for rc.order.Len() > rc.capacity {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cachedResult).key)
+	}
+}
+
+// load returns the cached response of a request when it is not older than maxAge, 0 meaning no limit
+func (rc *resultCache) load(key string, maxAge time.Duration) (*cachedResult, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	result := element.Value.(*cachedResult)
+	if maxAge > 0 && time.Since(result.storedAt) > maxAge {
+		return nil, false
+	}
+	rc.order.MoveToFront(element)
+	return result, true
+}
+
+// resultCacheKey identifies a projection data request by projection and its query parameters in canonical order
+func resultCacheKey(c *gin.Context, projection *config.ProjectionConfig) string {
+	query := c.Request.URL.Query()
+	query.Del("explain")
+	// Responses hold labels, so the language they were translated to is part of the key
+	query.Del("lang")
+	if lang := c.GetString(languageKey); lang != "" {
+		query.Set("lang", lang)
+	}
+	return projection.ID + "?" + query.Encode()
+}
+
+// ProjectionAvailability reports whether projection queries reach the target database
+type ProjectionAvailability struct {
+	Available        bool       `json:"available"`
+	UnavailableSince *time.Time `json:"unavailable_since,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	ServingCached    bool       `json:"serving_cached"` // data requests are answered from the result cache meanwhile
+}
+
+// targetStatus tracks whether projection queries last reached the target database
+type targetStatus struct {
+	mu        sync.Mutex
+	down      bool
+	since     time.Time
+	lastError string
+}
+
+// targetUnavailable records a projection query that could not reach the target database
+func (h *APIHandler) targetUnavailable(err error) {
+	h.DBManager.TargetBreaker.RecordFailure(err)
+
+	h.target.mu.Lock()
+	defer h.target.mu.Unlock()
+	if !h.target.down {
+		h.target.down = true
+		h.target.since = time.Now()
+		h.Logger.Error("Target database unreachable, projections degraded", zap.Error(err))
+	}
+	h.target.lastError = err.Error()
+}
+
+// targetAvailable records a projection query that reached the target database
+func (h *APIHandler) targetAvailable() {
+	h.target.mu.Lock()
+	defer h.target.mu.Unlock()
+	if h.target.down {
+		h.Logger.Info("Target database reachable again, projections recovered",
+			zap.Duration("outage", time.Since(h.target.since)),
+		)
+	}
+	h.target.down = false
+	h.target.lastError = ""
+}
+
+// projectionAvailability reports the availability of projection queries. While they are failing, the target is
+// pinged so the status recovers without waiting for the next projection request
+func (h *APIHandler) projectionAvailability(ctx context.Context) ProjectionAvailability {
+	h.target.mu.Lock()
+	down := h.target.down
+	h.target.mu.Unlock()
+
+	if down {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		if err := h.DBManager.Target.PingContext(pingCtx); err == nil {
+			h.targetAvailable()
+		}
+	}
+
+	h.target.mu.Lock()
+	defer h.target.mu.Unlock()
+	availability := ProjectionAvailability{
+		Available:     !h.target.down,
+		ServingCached: h.target.down && h.Results != nil,
+	}
+	if h.target.down {
+		since := h.target.since
+		availability.UnavailableSince = &since
+		availability.LastError = h.target.lastError
+	}
+	return availability
+}
+
+// serveCachedProjection answers a projection data request with its last cached response, flagged stale, when the
+// target database is unreachable. It reports whether a cached response was written
+func (h *APIHandler) serveCachedProjection(c *gin.Context, projection *config.ProjectionConfig) bool {
+	if h.Results == nil {
+		return false
+	}
+	cached, ok := h.Results.load(resultCacheKey(c, projection), h.Config.API.ResultCache.GetMaxAge())
+	if !ok {
+		return false
+	}
+
+	response := make(gin.H, len(cached.response)+2)
+	for key, value := range cached.response {
+		response[key] = value
+	}
+	response["stale"] = true
+	response["cached_at"] = cached.storedAt.UTC()
+
+	h.Logger.Warn("Serving cached projection data while the target database is unreachable",
+		zap.String("projection_id", projection.ID),
+		zap.Time("cached_at", cached.storedAt),
+	)
+	c.Header("Warning", `110 - "Response is Stale"`)
+	c.JSON(http.StatusOK, response)
+	return true
+}
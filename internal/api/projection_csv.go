@@ -0,0 +1,115 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+const (
+	formatCSV = "csv"
+
+	csvMediaType = "text/csv"
+)
+
+// writeProjectionCSV streams projection rows as CSV, using field labels as headers and applying field formats
+func (h *APIHandler) writeProjectionCSV(c *gin.Context, projection *config.ProjectionConfig, rows *sqlx.Rows, columnTypes []*sql.ColumnType, maxRows int) {
+	c.Header("Content-Type", csvMediaType+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", projection.ID+".csv"))
+	announceTruncation(c, maxRows)
+	c.Status(http.StatusOK)
+
+	count, err := exportCSV(c.Writer, c.Writer.Flush, projection, rows, columnTypes, maxRows)
+	addUsageRows(c, count)
+	if err != nil {
+		h.Logger.Warn("Projection CSV export aborted",
+			zap.String("projection_id", projection.ID),
+			zap.Int("rows", count),
+			zap.Error(err),
+		)
+		return
+	}
+	reportTruncation(c, rows, count, maxRows)
+
+	h.Logger.Debug("Projection data exported",
+		zap.String("projection_id", projection.ID),
+		zap.String("format", formatCSV),
+		zap.Int("rows", count),
+	)
+}
+
+// exportCSV writes the rows to w as CSV, at most limit of them unless limit is 0, calling flush periodically, and
+// returns the row count
+func exportCSV(w io.Writer, flush func(), projection *config.ProjectionConfig, rows *sqlx.Rows, columnTypes []*sql.ColumnType, limit int) (int, error) {
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+
+	header := make([]string, len(columnTypes))
+	fields := make([]*config.ProjectionFieldConfig, len(columnTypes))
+	for i, columnType := range columnTypes {
+		header[i] = columnType.Name()
+		if field, ok := fieldsByColumn[strings.ToLower(columnType.Name())]; ok {
+			fields[i] = &field
+			if field.Label != "" {
+				header[i] = field.Label
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	record := make([]string, len(columnTypes))
+	for (limit <= 0 || count < limit) && rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return count, err
+		}
+
+		for i, value := range values {
+			normalized := normalizeDBValue(value)
+			field := fields[i]
+			if field == nil {
+				record[i] = formatFieldValue(nil, "", normalized)
+				continue
+			}
+			normalized = applyFieldPolicy(*field, normalized)
+			record[i] = formatFieldValue(field.Format, fieldLocale(projection, *field), normalized)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return count, err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			writer.Flush()
+			if flush != nil {
+				flush()
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	if flush != nil {
+		flush()
+	}
+	return count, writer.Error()
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// explainMode reads the explain parameter: "" when not requested, "plan" for explain=true and "analyze" for
+// explain=analyze, which runs the query once more to report actual timings and buffers
+func explainMode(c *gin.Context) string {
+	switch strings.ToLower(c.Query("explain")) {
+	case "true", "1", "plan":
+		return "plan"
+	case "analyze":
+		return "analyze"
+	}
+	return ""
+}
+
+// projectionExplain returns the generated SQL and the PostgreSQL plan of a projection query when the request asked
+// for them with explain, nil otherwise. Only admin API keys may explain queries; on failure the response is written
+// and false is returned
+func (h *APIHandler) projectionExplain(ctx context.Context, c *gin.Context, projection *config.ProjectionConfig, query *projectionQuery) (gin.H, bool) {
+	mode := explainMode(c)
+	if mode == "" {
+		return nil, true
+	}
+
+	key := requestAPIKey(c)
+	if key == nil || !key.Admin {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "explain requires an admin API key",
+		})
+		return nil, false
+	}
+	switch projectionFormat(c) {
+	case formatArrow, formatParquet, formatNDJSON, formatCSV:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "explain is only available for JSON responses",
+		})
+		return nil, false
+	}
+
+	options := "FORMAT JSON"
+	if mode == "analyze" {
+		options = "ANALYZE, BUFFERS, FORMAT JSON"
+	}
+	h.Logger.Info("Explaining projection query",
+		zap.String("projection_id", projection.ID),
+		zap.String("api_key", key.Name),
+		zap.String("mode", mode),
+		zap.String("query", query.SQL),
+		zap.Any("args", query.Args),
+	)
+
+	var plan []byte
+	if err := h.DBManager.Target.QueryRowContext(ctx, "EXPLAIN ("+options+") "+query.SQL, query.Args...).Scan(&plan); err != nil {
+		h.projectionQueryFailed(ctx, c, projection, "Failed to explain projection query", err)
+		return nil, false
+	}
+
+	return gin.H{
+		"sql":  query.SQL,
+		"args": query.Args,
+		"plan": json.RawMessage(plan),
+	}, true
+}
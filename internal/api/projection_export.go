@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"mssql-postgres-sync/internal/database"
+)
+
+// ExportProjection writes a projection's full data set (default sort, no filters) to w in the
+// given format (csv or parquet) and returns the number of rows written. It is used by scheduled snapshots.
+func (h *APIHandler) ExportProjection(ctx context.Context, w io.Writer, projectionID, format string) (int, error) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		return 0, fmt.Errorf("target database connection is not available")
+	}
+
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		return 0, fmt.Errorf("projection not found: %s", projectionID)
+	}
+	if projection.IsSourcePassthrough() {
+		return 0, fmt.Errorf("projection %s queries the source and cannot be exported", projectionID)
+	}
+
+	query, err := buildProjectionQuery(projectionParams{}, projection)
+	if err != nil {
+		return 0, err
+	}
+
+	queryCtx := database.WithQueryLabel(ctx, "projection:"+projection.ID)
+	rows, err := h.DBManager.Target.QueryxContext(queryCtx, query.SQL, query.Args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query projection data: %w", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read projection columns: %w", err)
+	}
+
+	switch format {
+	case formatCSV:
+		return exportCSV(w, nil, projection, rows, columnTypes, 0)
+	case formatParquet, formatArrow:
+		return exportColumnar(w, format, rows, columnTypes, 0)
+	default:
+		return 0, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Formatting styles for projection fields
+const (
+	formatStyleCurrency = "currency"
+	formatStylePercent  = "percent"
+	formatStyleDecimal  = "decimal"
+	formatStyleDate     = "date"
+	formatStyleDateTime = "datetime"
+)
+
+// fieldLocale returns the locale for a field, falling back to the projection locale
+func fieldLocale(projection *config.ProjectionConfig, field config.ProjectionFieldConfig) string {
+	if field.Format != nil && field.Format.Locale != "" {
+		return field.Format.Locale
+	}
+	return projection.Locale
+}
+
+// formatFieldValue renders a normalized value using the field's format, or as plain text when none is configured
+func formatFieldValue(format *config.FieldFormat, locale string, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if format == nil {
+		return plainValue(value)
+	}
+
+	switch strings.ToLower(format.Style) {
+	case formatStyleCurrency, formatStylePercent, formatStyleDecimal:
+		number, ok := valueToFloat64(value)
+		if !ok {
+			return plainValue(value)
+		}
+		return formatNumber(format, locale, number)
+	case formatStyleDate, formatStyleDateTime:
+		ts, ok := valueToTime(value)
+		if !ok {
+			return plainValue(value)
+		}
+		layout := "2006-01-02"
+		if strings.EqualFold(format.Style, formatStyleDateTime) {
+			layout = "2006-01-02 15:04:05"
+		}
+		if format.DateFormat != "" {
+			layout = goDateLayout(format.DateFormat)
+		}
+		return ts.Format(layout)
+	default:
+		return plainValue(value)
+	}
+}
+
+func formatNumber(format *config.FieldFormat, locale string, number float64) string {
+	decimals := 2
+	if format.Decimals != nil && *format.Decimals >= 0 {
+		decimals = *format.Decimals
+	}
+
+	tag := language.English
+	if locale != "" {
+		if parsed, err := language.Parse(locale); err == nil {
+			tag = parsed
+		}
+	}
+	printer := message.NewPrinter(tag)
+
+	pattern := fmt.Sprintf("%%.%df", decimals)
+
+	switch strings.ToLower(format.Style) {
+	case formatStylePercent:
+		return printer.Sprintf(pattern, number*100) + "%"
+	case formatStyleCurrency:
+		formatted := printer.Sprintf(pattern, number)
+		if format.Currency != "" {
+			return strings.ToUpper(format.Currency) + " " + formatted
+		}
+		return formatted
+	default:
+		return printer.Sprintf(pattern, number)
+	}
+}
+
+func valueToTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+			if ts, err := time.Parse(layout, v); err == nil {
+				return ts, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// goDateLayout converts a yyyy-MM-dd HH:mm:ss style pattern into a Go time layout
+func goDateLayout(pattern string) string {
+	replacer := strings.NewReplacer(
+		"yyyy", "2006",
+		"yy", "06",
+		"MMMM", "January",
+		"MMM", "Jan",
+		"MM", "01",
+		"dd", "02",
+		"HH", "15",
+		"hh", "03",
+		"mm", "04",
+		"ss", "05",
+	)
+	return replacer.Replace(pattern)
+}
+
+func plainValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+)
+
+// truncatedHeader is the trailer set on streamed projection data cut off at the projection's max_rows
+const truncatedHeader = "X-Result-Truncated"
+
+// announceTruncation declares the truncation trailer of streamed projection data capped at maxRows
+func announceTruncation(c *gin.Context, maxRows int) {
+	if maxRows > 0 {
+		c.Header("Trailer", truncatedHeader)
+	}
+}
+
+// reportTruncation sets the truncation trailer when a stream stopped at maxRows with rows left
+func reportTruncation(c *gin.Context, rows *sqlx.Rows, count, maxRows int) {
+	if maxRows > 0 && count == maxRows && rows.Next() {
+		c.Writer.Header().Set(truncatedHeader, "true")
+	}
+}
+
+// withStatementTimeout bounds a projection query by the projection's statement timeout. When the context expires
+// the driver cancels the query on the server, so a pathological filter does not keep running after the request
+func (h *APIHandler) withStatementTimeout(ctx context.Context, projection *config.ProjectionConfig) (context.Context, context.CancelFunc) {
+	if timeout := projection.GetStatementTimeout(&h.Config.API); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// projectionQueryFailed logs a failed projection query and responds with 504 when it ran into the statement
+// timeout, with 503 when the target database is unreachable, or with 500 and message otherwise
+func (h *APIHandler) projectionQueryFailed(ctx context.Context, c *gin.Context, projection *config.ProjectionConfig, message string, err error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		h.Logger.Warn("Projection query exceeded its statement timeout",
+			zap.String("projection_id", projection.ID),
+			zap.Duration("timeout", projection.GetStatementTimeout(&h.Config.API)),
+		)
+		c.JSON(http.StatusGatewayTimeout, gin.H{
+			"error": "Projection query exceeded its statement timeout; narrow the filters",
+		})
+		return
+	}
+	if database.IsConnectionError(err) {
+		h.targetUnavailable(err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Target database is unavailable",
+		})
+		return
+	}
+	h.Logger.Error(message,
+		zap.String("projection_id", projection.ID),
+		zap.Error(err),
+	)
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": message,
+	})
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const (
+	formatNDJSON = "ndjson"
+
+	ndjsonMediaType = "application/x-ndjson"
+
+	// streamFlushEvery is the number of rows written between flushes for streamed formats
+	streamFlushEvery = 500
+)
+
+// streamProjectionNDJSON writes projection rows as newline-delimited JSON while they are scanned, at most maxRows
+// of them unless maxRows is 0
+func (h *APIHandler) streamProjectionNDJSON(c *gin.Context, projection *config.ProjectionConfig, rows *sqlx.Rows, maxRows int) {
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+
+	c.Header("Content-Type", ndjsonMediaType)
+	announceTruncation(c, maxRows)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	count := 0
+	defer func() { addUsageRows(c, count) }()
+	for (maxRows <= 0 || count < maxRows) && rows.Next() {
+		rowData := make(map[string]interface{})
+		if err := rows.MapScan(rowData); err != nil {
+			h.Logger.Error("Failed to scan projection row",
+				zap.String("projection_id", projection.ID),
+				zap.Error(err),
+			)
+			return
+		}
+
+		for col, val := range rowData {
+			normalized := normalizeDBValue(val)
+			if field, ok := fieldsByColumn[strings.ToLower(col)]; ok {
+				normalized = applyFieldPolicy(field, normalized)
+			}
+			rowData[col] = normalized
+		}
+
+		if err := encoder.Encode(rowData); err != nil {
+			h.Logger.Warn("Projection stream aborted",
+				zap.String("projection_id", projection.ID),
+				zap.Int("rows", count),
+				zap.Error(err),
+			)
+			return
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		h.Logger.Error("Failed to read projection rows",
+			zap.String("projection_id", projection.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	reportTruncation(c, rows, count, maxRows)
+	c.Writer.Flush()
+
+	h.Logger.Debug("Projection data streamed",
+		zap.String("projection_id", projection.ID),
+		zap.Int("rows", count),
+	)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/database"
+)
+
+const (
+	defaultFilterOptions = 1000
+	maxFilterOptions     = 10000
+)
+
+// FilterOption is a distinct value of a filter's column
+type FilterOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Count *int64 `json:"count,omitempty"` // rows with the value, when requested with counts=true
+}
+
+// GetFilterOptions returns the distinct non-NULL values of a filter's column, so select filters can list the
+// values present in the view instead of options hard-coded in the configuration. Configured options keep their labels
+func (h *APIHandler) GetFilterOptions(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		h.Logger.Error("Target database not configured for projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Target database connection is not available",
+		})
+		return
+	}
+
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return
+	}
+	projection = h.localizeProjection(c, projection)
+
+	filterID := c.Param("filterId")
+	filterIndex := -1
+	for i, filter := range projection.Filters {
+		if filter.ID == filterID {
+			filterIndex = i
+			break
+		}
+	}
+	if filterIndex < 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Filter not found: %s", filterID),
+		})
+		return
+	}
+	filter := projection.Filters[filterIndex]
+
+	limit := defaultFilterOptions
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxFilterOptions {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("limit must be between 1 and %d", maxFilterOptions),
+			})
+			return
+		}
+		limit = parsed
+	}
+	withCounts := strings.EqualFold(c.Query("counts"), "true")
+
+	column := quoteIdentifier(projection.TargetColumn(filter.Column))
+	var args []interface{}
+	if path := jsonPathArray(filter.Path); path != "" {
+		args = append(args, path)
+		column = fmt.Sprintf("(%s #>> $1::text[])", column)
+	}
+	// One value more than the limit is read to report truncation
+	args = append(args, limit+1)
+	relation := quoteQualifiedIdentifier(projection.TargetView)
+	query := fmt.Sprintf("SELECT DISTINCT %s::text AS value FROM %s WHERE %s IS NOT NULL ORDER BY 1 LIMIT $%d",
+		column, relation, column, len(args))
+	if withCounts {
+		query = fmt.Sprintf("SELECT %s::text AS value, COUNT(*) AS count FROM %s WHERE %s IS NOT NULL GROUP BY 1 ORDER BY 1 LIMIT $%d",
+			column, relation, column, len(args))
+	}
+
+	labels := make(map[string]string, len(filter.Options))
+	for _, option := range filter.Options {
+		labels[option.Value] = option.Label
+	}
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "projection:"+projection.ID), projection)
+	defer cancel()
+	rows, err := h.DBManager.Target.QueryxPreparedContext(queryCtx, query, args...)
+	if err != nil {
+		h.projectionQueryFailed(queryCtx, c, projection, "Failed to query filter options", err)
+		return
+	}
+	defer rows.Close()
+
+	options := make([]FilterOption, 0)
+	truncated := false
+	for rows.Next() {
+		if len(options) == limit {
+			truncated = true
+			break
+		}
+		var option FilterOption
+		if withCounts {
+			var count int64
+			err = rows.Scan(&option.Value, &count)
+			option.Count = &count
+		} else {
+			err = rows.Scan(&option.Value)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to parse filter option",
+			})
+			return
+		}
+		option.Label = option.Value
+		if label, ok := labels[option.Value]; ok && label != "" {
+			option.Label = label
+		}
+		options = append(options, option)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error reading filter options",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projection_id": projection.ID,
+		"filter_id":     filter.ID,
+		"options":       options,
+		"meta": gin.H{
+			"option_count": len(options),
+			"truncated":    truncated,
+		},
+	})
+}
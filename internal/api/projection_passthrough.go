@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+)
+
+// defaultPassthroughCacheEntries bounds the responses cached for source_passthrough projections
+const defaultPassthroughCacheEntries = 1000
+
+// passthroughArgs validates the request's values of a passthrough projection's parameters and returns them as named
+// query arguments, with the canonical query string of the values identifying the request in the cache
+func passthroughArgs(c *gin.Context, passthrough *config.ProjectionPassthrough) ([]interface{}, string, error) {
+	args := make([]interface{}, 0, len(passthrough.Parameters))
+	values := url.Values{}
+	for i := range passthrough.Parameters {
+		param := &passthrough.Parameters[i]
+		raw, present := c.GetQuery(param.Name)
+		if !present || raw == "" {
+			switch {
+			case param.Default != nil:
+				raw = *param.Default
+			case param.Required:
+				return nil, "", fmt.Errorf("parameter %s is required", param.Name)
+			default:
+				args = append(args, sql.Named(param.Name, nil))
+				continue
+			}
+		}
+
+		value, err := param.Parse(raw)
+		if err != nil {
+			return nil, "", fmt.Errorf("parameter %s %w", param.Name, err)
+		}
+		args = append(args, sql.Named(param.Name, value))
+		values.Set(param.Name, raw)
+	}
+	return args, values.Encode(), nil
+}
+
+// getPassthroughData answers a data request of a source_passthrough projection by running its query on the source.
+// JSON responses are cached per parameter values for the projection's cache_ttl, as every request reaches MSSQL
+func (h *APIHandler) getPassthroughData(c *gin.Context, projection *config.ProjectionConfig) {
+	if h.DBManager == nil || h.DBManager.Source == nil {
+		h.Logger.Error("Source database not configured for passthrough projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Source database connection is not available",
+		})
+		return
+	}
+
+	passthrough := projection.Passthrough
+	args, cacheQuery, err := passthroughArgs(c, passthrough)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	format := projectionFormat(c)
+	cacheKey := projection.ID + "?" + cacheQuery
+	if lang := c.GetString(languageKey); lang != "" {
+		cacheKey += "#" + lang
+	}
+	ttl := passthrough.GetCacheTTL()
+	if format == "json" && ttl > 0 && h.Passthrough != nil {
+		if cached, ok := h.Passthrough.load(cacheKey, ttl); ok {
+			response := make(gin.H, len(cached.response)+1)
+			for key, value := range cached.response {
+				response[key] = value
+			}
+			response["cached_at"] = cached.storedAt.UTC()
+			addUsageRows(c, len(cached.response["rows"].([]map[string]interface{})))
+			c.JSON(http.StatusOK, response)
+			return
+		}
+	}
+
+	if !h.DBManager.SourceBreaker.Allow() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Source database is unavailable",
+		})
+		return
+	}
+
+	// The query is not wrapped to cap its rows, as T-SQL refuses CTEs and ORDER BY in derived tables; reading stops
+	// one row past max_rows instead, which tells whether the result was truncated
+	maxRows := projection.GetMaxRows(&h.Config.API)
+	query := passthrough.Statement()
+
+	h.Logger.Debug("Executing passthrough projection query",
+		zap.String("projection_id", projection.ID),
+		zap.String("query", query),
+		zap.Any("args", args),
+	)
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "passthrough:"+projection.ID), projection)
+	defer cancel()
+	rows, err := h.DBManager.Source.QueryxContext(queryCtx, query, args...)
+	if err != nil {
+		h.passthroughQueryFailed(queryCtx, c, projection, "Failed to query passthrough projection", err)
+		return
+	}
+	defer rows.Close()
+	h.DBManager.SourceBreaker.RecordSuccess()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		h.Logger.Error("Failed to read projection column types",
+			zap.String("projection_id", projection.ID),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to read projection columns",
+		})
+		return
+	}
+
+	switch format {
+	case formatArrow, formatParquet:
+		h.writeColumnarProjection(c, projection.ID, format, rows, columnTypes, maxRows)
+		return
+	case formatNDJSON:
+		h.streamProjectionNDJSON(c, projection, rows, maxRows)
+		return
+	case formatCSV:
+		h.writeProjectionCSV(c, projection, rows, columnTypes, maxRows)
+		return
+	}
+
+	fieldsByColumn := projectionFieldsByColumn(projection)
+	totals := newProjectionTotals(projection)
+	annotate := projection.HasRules()
+	var (
+		resultRows  []map[string]interface{}
+		annotations []map[string]CellAnnotation
+		rowActions  []map[string]string
+		truncated   bool
+	)
+	for rows.Next() {
+		if maxRows > 0 && len(resultRows) == maxRows {
+			truncated = true
+			break
+		}
+		rowData := make(map[string]interface{})
+		if err := rows.MapScan(rowData); err != nil {
+			h.Logger.Error("Failed to scan projection row",
+				zap.String("projection_id", projection.ID),
+				zap.Error(err),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to parse projection row",
+			})
+			return
+		}
+
+		row := normalizeProjectionRow(rowData, fieldsByColumn)
+		totals.add(row)
+		resultRows = append(resultRows, row.values)
+		if annotate {
+			annotations = append(annotations, annotateRow(projection, row))
+		}
+		if len(projection.Actions) > 0 {
+			rowActions = append(rowActions, renderRowActions(projection, row))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		h.passthroughQueryFailed(queryCtx, c, projection, "Error reading projection rows", err)
+		return
+	}
+
+	addUsageRows(c, len(resultRows))
+	response := gin.H{
+		"projection_id": projection.ID,
+		"rows":          resultRows,
+		"totals":        totals.response(nil),
+		"filters":       map[string]interface{}{},
+		"meta": gin.H{
+			"sort_column":    "",
+			"sort_direction": "",
+			"sort":           "",
+			"row_count":      len(resultRows),
+			"truncated":      truncated,
+			"max_rows":       maxRows,
+			"columns":        buildColumnsMeta(projection, columnTypes, nil),
+		},
+	}
+	if annotate {
+		response["annotations"] = annotations
+	}
+	if len(projection.Actions) > 0 {
+		response["actions"] = rowActions
+	}
+	if ttl > 0 && h.Passthrough != nil {
+		h.Passthrough.store(cacheKey, response)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// passthroughQueryFailed logs a failed passthrough query and responds with 504 when it ran into the statement
+// timeout, with 503 when the source database is unreachable, or with 500 and message otherwise
+func (h *APIHandler) passthroughQueryFailed(ctx context.Context, c *gin.Context, projection *config.ProjectionConfig, message string, err error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		h.Logger.Warn("Passthrough query exceeded its statement timeout",
+			zap.String("projection_id", projection.ID),
+			zap.Duration("timeout", projection.GetStatementTimeout(&h.Config.API)),
+		)
+		c.JSON(http.StatusGatewayTimeout, gin.H{
+			"error": "Projection query exceeded its statement timeout",
+		})
+		return
+	}
+	if database.IsConnectionError(err) {
+		h.DBManager.SourceBreaker.RecordFailure(err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Source database is unavailable",
+		})
+		return
+	}
+
+	h.Logger.Error(message,
+		zap.String("projection_id", projection.ID),
+		zap.Error(err),
+	)
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": message,
+	})
+}
+
+// hasPassthroughCache reports whether any projection caches passthrough responses
+func hasPassthroughCache(projections []config.ProjectionConfig) bool {
+	for i := range projections {
+		if projections[i].IsSourcePassthrough() && projections[i].Passthrough.GetCacheTTL() > 0 {
+			return true
+		}
+	}
+	return false
+}
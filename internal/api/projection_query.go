@@ -0,0 +1,374 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// projectionQuery is the SQL built for a projection request
+type projectionQuery struct {
+	SQL            string
+	CountSQL       string // counts the rows matching the same conditions, ignoring sort and paging
+	Args           []interface{}
+	AppliedFilters map[string]interface{}
+	SortColumn     string
+	SortDirection  string
+	Sort           []projectionSort // full ORDER BY; SortColumn and SortDirection are its first column
+}
+
+// projectionParams are the filter and sort parameters of a projection request
+type projectionParams struct {
+	Filters         map[string]string
+	Sort            string                // a column, or a list of columns with directions (col1:asc,col2:desc)
+	Direction       string                // direction of a single sort column
+	Nulls           string                // FIRST or LAST, overriding the fields' sort_nulls
+	CaseInsensitive bool                  // sort text fields ignoring case, besides fields with sort_case_insensitive
+	Select          []string              // configured field columns returned instead of all fields
+	Conditions      []projectionCondition // further conditions, combined with the filters
+	OrderBy         []projectionSort      // validated sort list, used instead of sort and direction
+	Limit           int                   // 0 for no limit
+	Offset          int
+	Relation        string // relation read instead of the target view, e.g. a load snapshot
+}
+
+// projectionCondition renders a WHERE condition, binding each value through arg, which returns its placeholder
+type projectionCondition func(arg func(value interface{}) string) string
+
+// projectionSort is a column of an ORDER BY
+type projectionSort struct {
+	Column          string
+	Direction       string // ASC or DESC
+	Nulls           string // FIRST, LAST or empty for PostgreSQL's default (NULLs sort as the largest value)
+	CaseInsensitive bool
+}
+
+// projectionParamsFromRequest reads filters[...], sort, direction, nulls, case_insensitive and columns from the
+// query string
+func projectionParamsFromRequest(c *gin.Context) projectionParams {
+	return projectionParams{
+		Filters:         c.QueryMap("filters"),
+		Sort:            c.Query("sort"),
+		Direction:       c.Query("direction"),
+		Nulls:           c.Query("nulls"),
+		CaseInsensitive: strings.EqualFold(c.Query("case_insensitive"), "true"),
+		Select:          splitAndClean(c.Query("columns")),
+	}
+}
+
+// buildProjectionQuery builds the projection SELECT from the filter and sort parameters
+func buildProjectionQuery(params projectionParams, projection *config.ProjectionConfig) (*projectionQuery, error) {
+	selectClause, sortableColumns := buildSelectClause(projection)
+	if len(params.Select) > 0 {
+		for _, column := range params.Select {
+			if !hasField(projection, column) {
+				return nil, fmt.Errorf("Unknown column %s", column)
+			}
+		}
+		selected := *projection
+		selected.Fields = selectedFields(projection, params.Select)
+		selectClause, _ = buildSelectClause(&selected)
+	}
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString("SELECT ")
+	queryBuilder.WriteString(selectClause)
+	relation := quoteQualifiedIdentifier(projection.TargetView)
+	if params.Relation != "" {
+		relation = params.Relation
+	}
+	queryBuilder.WriteString(" FROM ")
+	queryBuilder.WriteString(relation)
+
+	filtersMap := params.Filters
+	var (
+		whereClauses   []string
+		queryArgs      []interface{}
+		appliedFilters = make(map[string]interface{})
+		parameterIndex = 1
+	)
+
+	for _, filterCfg := range projection.Filters {
+		raw, exists := filtersMap[filterCfg.ID]
+		if !exists {
+			continue
+		}
+
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		columnIdentifier := quoteIdentifier(projection.TargetColumn(filterCfg.Column))
+		if path := jsonPathArray(filterCfg.Path); path != "" {
+			queryArgs = append(queryArgs, path)
+			columnIdentifier = fmt.Sprintf("(%s #>> $%d::text[])", columnIdentifier, parameterIndex)
+			parameterIndex++
+			if strings.EqualFold(filterCfg.Type, "number") {
+				columnIdentifier += "::numeric"
+			}
+		}
+		placeholder := "$%d"
+		if filterCfg.CaseInsensitive && !strings.EqualFold(filterCfg.Type, "number") {
+			columnIdentifier = fmt.Sprintf("lower(%s)", columnIdentifier)
+			placeholder = "lower($%d::text)"
+		}
+		switch strings.ToLower(filterCfg.Type) {
+		case "select":
+			values := splitAndClean(raw)
+			if len(values) == 0 {
+				continue
+			}
+			placeholders := make([]string, 0, len(values))
+			for _, value := range values {
+				queryArgs = append(queryArgs, value)
+				placeholders = append(placeholders, fmt.Sprintf(placeholder, parameterIndex))
+				parameterIndex++
+			}
+			whereClauses = append(whereClauses, fmt.Sprintf("%s IN (%s)", columnIdentifier, strings.Join(placeholders, ", ")))
+			appliedFilters[filterCfg.ID] = values
+		case "number":
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid numeric filter for %s", filterCfg.ID)
+			}
+			queryArgs = append(queryArgs, value)
+			whereClauses = append(whereClauses, fmt.Sprintf("%s >= $%d", columnIdentifier, parameterIndex))
+			parameterIndex++
+			appliedFilters[filterCfg.ID] = value
+		default:
+			queryArgs = append(queryArgs, raw)
+			whereClauses = append(whereClauses, fmt.Sprintf("%s = "+placeholder, columnIdentifier, parameterIndex))
+			parameterIndex++
+			appliedFilters[filterCfg.ID] = raw
+		}
+	}
+
+	arg := func(value interface{}) string {
+		queryArgs = append(queryArgs, value)
+		parameterIndex++
+		return fmt.Sprintf("$%d", parameterIndex-1)
+	}
+	for _, condition := range params.Conditions {
+		whereClauses = append(whereClauses, "("+condition(arg)+")")
+	}
+
+	whereClause := ""
+	if len(whereClauses) > 0 {
+		whereClause = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	queryBuilder.WriteString(whereClause)
+	countSQL := "SELECT COUNT(*) FROM " + relation + whereClause
+
+	for _, sort := range projection.DefaultSort {
+		sortableColumns[strings.ToLower(sort.Column)] = true
+	}
+
+	orderBy := params.OrderBy
+	if len(orderBy) == 0 {
+		var err error
+		if orderBy, err = parseSortParam(params.Sort, params.Direction, sortableColumns); err != nil {
+			return nil, err
+		}
+	}
+	if len(orderBy) == 0 {
+		for _, sort := range projection.DefaultSort {
+			orderBy = append(orderBy, projectionSort{Column: sort.Column, Direction: sortDirection(sort.Direction)})
+		}
+	}
+
+	nulls := strings.ToUpper(strings.TrimSpace(params.Nulls))
+	if nulls != "" && nulls != "FIRST" && nulls != "LAST" {
+		return nil, fmt.Errorf("Invalid nulls %s, expected first or last", params.Nulls)
+	}
+
+	sortColumn, sortDirection := "", ""
+	if len(orderBy) > 0 {
+		columns := make([]string, 0, len(orderBy))
+		for i, sort := range orderBy {
+			if !sortableColumns[strings.ToLower(sort.Column)] {
+				return nil, fmt.Errorf("Column %s is not sortable", sort.Column)
+			}
+			sort.Column = configuredColumn(projection, sort.Column)
+			if field, ok := projectionField(projection, sort.Column); ok {
+				if sort.Nulls == "" {
+					sort.Nulls = strings.ToUpper(field.SortNulls)
+				}
+				sort.CaseInsensitive = sort.CaseInsensitive || field.SortCaseInsensitive ||
+					(params.CaseInsensitive && textField(field))
+			}
+			if nulls != "" {
+				sort.Nulls = nulls
+			}
+			orderBy[i] = sort
+			columns = append(columns, orderByColumn(projection, sort))
+		}
+		queryBuilder.WriteString(" ORDER BY ")
+		queryBuilder.WriteString(strings.Join(columns, ", "))
+		sortColumn, sortDirection = orderBy[0].Column, orderBy[0].Direction
+	}
+
+	if params.Limit > 0 {
+		queryBuilder.WriteString(fmt.Sprintf(" LIMIT %d", params.Limit))
+	}
+	if params.Offset > 0 {
+		queryBuilder.WriteString(fmt.Sprintf(" OFFSET %d", params.Offset))
+	}
+
+	return &projectionQuery{
+		SQL:            queryBuilder.String(),
+		CountSQL:       countSQL,
+		Args:           queryArgs,
+		AppliedFilters: appliedFilters,
+		SortColumn:     sortColumn,
+		SortDirection:  sortDirection,
+		Sort:           orderBy,
+	}, nil
+}
+
+// orderByColumn renders a sort column of an ORDER BY
+func orderByColumn(projection *config.ProjectionConfig, sort projectionSort) string {
+	column := quoteIdentifier(projection.TargetColumn(sort.Column))
+	if sort.CaseInsensitive {
+		column = "lower(" + column + "::text)"
+	}
+	column += " " + sort.Direction
+	if sort.Nulls != "" {
+		column += " NULLS " + sort.Nulls
+	}
+	return column
+}
+
+// textField reports whether a field shows text, which a case-insensitive request sorts ignoring case
+func textField(field config.ProjectionFieldConfig) bool {
+	switch strings.ToLower(field.Type) {
+	case "text", "badge":
+		return true
+	}
+	return false
+}
+
+// parseSortParam parses the sort parameter: a list of columns with optional directions (col1:asc,col2:desc), or a
+// single column sorted in the direction parameter. An unsortable single column is ignored, as the UI sends whatever
+// header was clicked
+func parseSortParam(raw, direction string, sortableColumns map[string]bool) ([]projectionSort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if !strings.ContainsAny(raw, ":,") {
+		if !sortableColumns[strings.ToLower(raw)] {
+			return nil, nil
+		}
+		return []projectionSort{{Column: raw, Direction: sortDirection(direction)}}, nil
+	}
+
+	var sorts []projectionSort
+	for _, item := range strings.Split(raw, ",") {
+		column, dir, _ := strings.Cut(strings.TrimSpace(item), ":")
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, fmt.Errorf("Invalid sort %q", raw)
+		}
+		dir = strings.ToUpper(strings.TrimSpace(dir))
+		if dir != "" && dir != "ASC" && dir != "DESC" {
+			return nil, fmt.Errorf("Invalid sort direction %s for %s", dir, column)
+		}
+		sorts = append(sorts, projectionSort{Column: column, Direction: sortDirection(dir)})
+	}
+	return sorts, nil
+}
+
+// sortDirection normalizes a sort direction to ASC or DESC
+func sortDirection(direction string) string {
+	if strings.EqualFold(strings.TrimSpace(direction), "DESC") {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// formatSort renders a sort list in the syntax of the sort parameter
+func formatSort(sorts []projectionSort) string {
+	items := make([]string, len(sorts))
+	for i, sort := range sorts {
+		items[i] = sort.Column + ":" + strings.ToLower(sort.Direction)
+	}
+	return strings.Join(items, ",")
+}
+
+// selectedFields returns the projection fields named in columns, in projection order
+func selectedFields(projection *config.ProjectionConfig, columns []string) []config.ProjectionFieldConfig {
+	fields := make([]config.ProjectionFieldConfig, 0, len(columns))
+	for _, field := range projection.Fields {
+		for _, column := range columns {
+			if strings.EqualFold(field.Column, column) {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// hasField reports whether a column is one of the projection's fields
+func hasField(projection *config.ProjectionConfig, column string) bool {
+	_, ok := projectionField(projection, column)
+	return ok
+}
+
+// projectionField returns the projection field of a column
+func projectionField(projection *config.ProjectionConfig, column string) (config.ProjectionFieldConfig, bool) {
+	for _, field := range projection.Fields {
+		if strings.EqualFold(field.Column, column) {
+			return field, true
+		}
+	}
+	return config.ProjectionFieldConfig{}, false
+}
+
+// hasColumn reports whether columns contains column, ignoring case
+func hasColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredColumn returns a column name as spelled in the projection's fields or default sort, which requests
+// may name in any case
+func configuredColumn(projection *config.ProjectionConfig, column string) string {
+	for _, field := range projection.Fields {
+		if strings.EqualFold(field.Column, column) {
+			return field.Column
+		}
+	}
+	for _, sort := range projection.DefaultSort {
+		if strings.EqualFold(sort.Column, column) {
+			return sort.Column
+		}
+	}
+	return column
+}
+
+// jsonPathArray converts a dotted path (customer.address.city) into a Postgres text[] literal
+func jsonPathArray(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		segment = strings.ReplaceAll(segment, `\`, `\\`)
+		segment = strings.ReplaceAll(segment, `"`, `\"`)
+		segments = append(segments, `"`+segment+`"`)
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(segments, ",") + "}"
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+)
+
+// GetProjectionRow returns the projection row with the given key and the rows of its related child projections,
+// for drill-down views. Composite keys are comma separated, in key column order. include lists the relations to
+// return (default: all)
+func (h *APIHandler) GetProjectionRow(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		h.Logger.Error("Target database not configured for projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Target database connection is not available",
+		})
+		return
+	}
+
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return
+	}
+
+	keyColumns := h.Config.ProjectionKey(projection)
+	if len(keyColumns) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Projection %s has no key", projection.ID),
+		})
+		return
+	}
+	keyValues := strings.Split(c.Param("key"), ",")
+	if len(keyValues) != len(keyColumns) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Key must have %d comma-separated values (%s)", len(keyColumns), strings.Join(keyColumns, ", ")),
+		})
+		return
+	}
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "projection:"+projection.ID), projection)
+	defer cancel()
+	params := projectionParams{Limit: 1}
+	for i, column := range keyColumns {
+		params.Conditions = append(params.Conditions, equalsCondition(projection, column, strings.TrimSpace(keyValues[i])))
+	}
+	rows, raw, err := h.queryProjectionRows(queryCtx, projection, params)
+	if err != nil {
+		h.projectionQueryFailed(queryCtx, c, projection, "Failed to query projection row", err)
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Row not found: %s", c.Param("key")),
+		})
+		return
+	}
+
+	_, filtered := c.GetQuery("include")
+	include := splitAndClean(c.Query("include"))
+	related := make(map[string]interface{}, len(projection.Relations))
+	for _, relation := range projection.Relations {
+		if filtered && !hasColumn(include, relation.Name) {
+			continue
+		}
+		result, err := h.relatedRows(queryCtx, relation, raw[0])
+		if err != nil {
+			h.projectionQueryFailed(queryCtx, c, projection, fmt.Sprintf("Failed to query related rows of %s", relation.Name), err)
+			return
+		}
+		related[relation.Name] = result
+	}
+
+	addUsageRows(c, 1)
+	c.JSON(http.StatusOK, gin.H{
+		"projection_id": projection.ID,
+		"key":           keyColumns,
+		"row":           rows[0],
+		"related":       related,
+	})
+}
+
+// relatedRows returns the child rows of a relation for a parent row
+func (h *APIHandler) relatedRows(ctx context.Context, relation config.ProjectionRelationConfig, parent map[string]interface{}) (gin.H, error) {
+	child, ok := h.Config.GetProjectionByID(relation.Projection)
+	if !ok {
+		return nil, fmt.Errorf("projection %s not found", relation.Projection)
+	}
+
+	limit := relation.GetLimit()
+	params := projectionParams{Sort: relation.Sort, Limit: limit + 1}
+	childColumns := make([]string, 0, len(relation.On))
+	for childColumn := range relation.On {
+		childColumns = append(childColumns, childColumn)
+	}
+	sort.Strings(childColumns)
+	for _, childColumn := range childColumns {
+		parentColumn := relation.On[childColumn]
+		value, ok := rowValue(parent, parentColumn)
+		if !ok {
+			return nil, fmt.Errorf("column %s is not in the row", parentColumn)
+		}
+		if value == nil {
+			// NULL references no child rows
+			return gin.H{"projection_id": child.ID, "rows": []map[string]interface{}{}, "meta": gin.H{"row_count": 0, "truncated": false}}, nil
+		}
+		params.Conditions = append(params.Conditions, equalsCondition(child, childColumn, value))
+	}
+
+	rows, _, err := h.queryProjectionRows(ctx, child, params)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(rows) > limit
+	if truncated {
+		rows = rows[:limit]
+	}
+	return gin.H{
+		"projection_id": child.ID,
+		"rows":          rows,
+		"meta":          gin.H{"row_count": len(rows), "truncated": truncated},
+	}, nil
+}
+
+// queryProjectionRows runs a projection query and returns its rows with field policies applied, along with the
+// rows' normalized values before the policies
+func (h *APIHandler) queryProjectionRows(ctx context.Context, projection *config.ProjectionConfig, params projectionParams) ([]map[string]interface{}, []map[string]interface{}, error) {
+	query, err := buildProjectionQuery(params, projection)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := h.DBManager.Target.QueryxPreparedContext(ctx, query.SQL, query.Args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	return readProjectionRows(rows, projection)
+}
+
+// readProjectionRows reads projection rows, returning them with field policies applied and as normalized values
+func readProjectionRows(rows *sqlx.Rows, projection *config.ProjectionConfig) ([]map[string]interface{}, []map[string]interface{}, error) {
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+
+	result := make([]map[string]interface{}, 0)
+	var raw []map[string]interface{}
+	for rows.Next() {
+		rowData := make(map[string]interface{})
+		if err := rows.MapScan(rowData); err != nil {
+			return nil, nil, err
+		}
+		normalized := make(map[string]interface{}, len(rowData))
+		for col, val := range rowData {
+			value := normalizeDBValue(val)
+			normalized[col] = value
+			if field, ok := fieldsByColumn[strings.ToLower(col)]; ok {
+				value = applyFieldPolicy(field, value)
+			}
+			rowData[col] = value
+		}
+		result = append(result, rowData)
+		raw = append(raw, normalized)
+	}
+	return result, raw, rows.Err()
+}
+
+// equalsCondition matches a projection column to a value
+func equalsCondition(projection *config.ProjectionConfig, column string, value interface{}) projectionCondition {
+	identifier := quoteIdentifier(projection.TargetColumn(configuredColumn(projection, column)))
+	return func(arg func(interface{}) string) string {
+		return identifier + " = " + arg(value)
+	}
+}
+
+// rowValue returns a column's value from a row keyed by column name, ignoring case
+func rowValue(row map[string]interface{}, column string) (interface{}, bool) {
+	if value, ok := row[column]; ok {
+		return value, true
+	}
+	for name, value := range row {
+		if strings.EqualFold(name, column) {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// CellAnnotation is the formatting a field rule applies to a cell of a data response
+type CellAnnotation struct {
+	Color      string `json:"color,omitempty"`
+	Background string `json:"background,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Badge      bool   `json:"badge,omitempty"`
+}
+
+// annotateRow evaluates the field rules of a projection on a row and returns the annotations of its cells by field
+// column, nil when no rule matched. Fields left out of the row by columns= are not annotated
+func annotateRow(projection *config.ProjectionConfig, row projectionRow) map[string]CellAnnotation {
+	var annotations map[string]CellAnnotation
+	for i := range projection.Fields {
+		field := &projection.Fields[i]
+		if len(field.Rules) == 0 {
+			continue
+		}
+		if _, ok := row.lower[strings.ToLower(field.Column)]; !ok {
+			continue
+		}
+		for _, rule := range field.Rules {
+			if !ruleMatches(rule, row.lower[strings.ToLower(rule.GetColumn(field))]) {
+				continue
+			}
+			if annotations == nil {
+				annotations = make(map[string]CellAnnotation)
+			}
+			annotations[field.Column] = CellAnnotation{
+				Color:      rule.Color,
+				Background: rule.Background,
+				Bold:       rule.Bold,
+				Badge:      rule.Badge,
+			}
+			break
+		}
+	}
+	return annotations
+}
+
+// ruleMatches reports whether a normalized value satisfies a field rule. NULL only matches null, as in SQL
+func ruleMatches(rule config.FieldRule, value interface{}) bool {
+	switch rule.Operator {
+	case config.RuleNull:
+		return value == nil
+	case config.RuleNotNull:
+		return value != nil
+	}
+	if value == nil {
+		return false
+	}
+
+	switch rule.Operator {
+	case config.RuleContains:
+		return strings.Contains(strings.ToLower(plainValue(value)), strings.ToLower(rule.Value))
+	case config.RuleIn:
+		for _, operand := range rule.Values {
+			if compareRuleValue(value, operand) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp := compareRuleValue(value, rule.Value)
+	switch rule.Operator {
+	case config.RuleEq:
+		return cmp == 0
+	case config.RuleNe:
+		return cmp != 0
+	case config.RuleLt:
+		return cmp < 0
+	case config.RuleLe:
+		return cmp <= 0
+	case config.RuleGt:
+		return cmp > 0
+	case config.RuleGe:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// compareRuleValue compares a value with a rule operand, as numbers when both are numeric and as text otherwise, so
+// ISO dates and timestamps compare chronologically
+func compareRuleValue(value interface{}, operand string) int {
+	if number, ok := valueToFloat64(value); ok {
+		if other, err := strconv.ParseFloat(strings.TrimSpace(operand), 64); err == nil {
+			switch {
+			case number < other:
+				return -1
+			case number > other:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(plainValue(value), operand)
+}
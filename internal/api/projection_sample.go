@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+)
+
+const (
+	defaultSampleSize = 100
+	maxSampleSize     = 1000
+
+	// tableSampleMinRows is the estimated row count above which tables are sampled with TABLESAMPLE
+	tableSampleMinRows = 10000
+)
+
+// GetProjectionSample returns a random sample of projection rows with field masks applied
+func (h *APIHandler) GetProjectionSample(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		h.Logger.Error("Target database not configured for projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Target database connection is not available",
+		})
+		return
+	}
+
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return
+	}
+	if projection.IsSourcePassthrough() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Projection %s queries the source and has no view to sample", projection.ID),
+		})
+		return
+	}
+
+	size := defaultSampleSize
+	if raw := c.Query("n"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxSampleSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("n must be between 1 and %d", maxSampleSize),
+			})
+			return
+		}
+		size = parsed
+	}
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "projection:"+projection.ID), projection)
+	defer cancel()
+	query, method := h.buildSampleQuery(queryCtx, projection, size)
+
+	rows, err := h.DBManager.Target.QueryxContext(queryCtx, query, size)
+	if err != nil {
+		h.projectionQueryFailed(queryCtx, c, projection, "Failed to sample projection data", err)
+		return
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to read projection columns",
+		})
+		return
+	}
+
+	fieldsByColumn := make(map[string]config.ProjectionFieldConfig, len(projection.Fields))
+	for _, field := range projection.Fields {
+		fieldsByColumn[strings.ToLower(field.Column)] = field
+	}
+
+	resultRows := make([]map[string]interface{}, 0, size)
+	for rows.Next() {
+		rowData := make(map[string]interface{})
+		if err := rows.MapScan(rowData); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to parse projection row",
+			})
+			return
+		}
+
+		for col, val := range rowData {
+			value := normalizeDBValue(val)
+			if field, ok := fieldsByColumn[strings.ToLower(col)]; ok {
+				value = maskValue(field.Mask, applyFieldPolicy(field, value))
+			}
+			rowData[col] = value
+		}
+		resultRows = append(resultRows, rowData)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error reading projection rows",
+		})
+		return
+	}
+
+	addUsageRows(c, len(resultRows))
+	c.JSON(http.StatusOK, gin.H{
+		"projection_id": projection.ID,
+		"rows":          resultRows,
+		"meta": gin.H{
+			"row_count": len(resultRows),
+			"method":    method,
+			"columns":   buildColumnsMeta(projection, columnTypes, nil),
+		},
+	})
+}
+
+// buildSampleQuery picks the sampling method: large tables use TABLESAMPLE SYSTEM with enough oversampling
+// to fill the sample, while views and small tables are shuffled with ORDER BY random()
+func (h *APIHandler) buildSampleQuery(ctx context.Context, projection *config.ProjectionConfig, size int) (string, string) {
+	selectClause, _ := buildSelectClause(projection)
+	relation := quoteQualifiedIdentifier(projection.TargetView)
+
+	var relkind string
+	var reltuples float64
+	err := h.DBManager.Target.QueryRowContext(ctx,
+		"SELECT relkind, reltuples FROM pg_class WHERE oid = to_regclass($1)", relation).Scan(&relkind, &reltuples)
+	if err == nil && strings.ContainsAny(relkind, "rpm") && reltuples >= tableSampleMinRows {
+		percent := 100 * float64(size) * 4 / reltuples
+		if percent < 100 {
+			return fmt.Sprintf("SELECT %s FROM %s TABLESAMPLE SYSTEM (%s) ORDER BY random() LIMIT $1",
+				selectClause, relation, strconv.FormatFloat(percent, 'f', 4, 64)), "tablesample"
+		}
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s ORDER BY random() LIMIT $1", selectClause, relation), "random"
+}
+
+// maskValue anonymizes a sampled value according to the field's mask
+func maskValue(mask string, value interface{}) interface{} {
+	if value == nil || mask == "" {
+		return value
+	}
+
+	text := fmt.Sprint(value)
+	switch strings.ToLower(mask) {
+	case "null":
+		return nil
+	case "hash":
+		// Deterministic, so equal values still match across rows and samples
+		sum := sha256.Sum256([]byte(text))
+		return hex.EncodeToString(sum[:6])
+	case "partial":
+		runes := []rune(text)
+		keep := 4
+		if len(runes) <= keep {
+			return strings.Repeat("*", len(runes))
+		}
+		return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+	case "email":
+		at := strings.LastIndex(text, "@")
+		if at <= 0 {
+			return "***"
+		}
+		return string([]rune(text)[:1]) + "***" + text[at:]
+	default:
+		return "***"
+	}
+}
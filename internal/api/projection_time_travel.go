@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// errNoSnapshot is returned when a projection has no snapshot at or before the requested as_of time
+var errNoSnapshot = errors.New("no snapshot at or before as_of")
+
+// parseAsOf parses the as_of parameter of a projection request: an RFC 3339 time, or a date standing for the end of
+// that day in UTC
+func parseAsOf(raw string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, raw); err == nil {
+		return at, nil
+	}
+	if day, err := time.Parse("2006-01-02", raw); err == nil {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid as_of %q: expected an RFC 3339 time or a YYYY-MM-DD date", raw)
+}
+
+// snapshotRelation returns a relation reading the projection's snapshot of the latest load at or before asOf, aliased
+// as the target view so filters and sorts apply unchanged, and the time of that snapshot
+func (h *APIHandler) snapshotRelation(ctx context.Context, projection *config.ProjectionConfig, asOf time.Time) (string, time.Time, error) {
+	snapshots := sqlident.Postgres(projection.SnapshotTable())
+	at := sqlident.PostgresColumn(config.SnapshotAtColumn)
+
+	var exists bool
+	if err := h.DBManager.Target.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", snapshots).Scan(&exists); err != nil {
+		return "", time.Time{}, err
+	}
+	if !exists {
+		return "", time.Time{}, errNoSnapshot
+	}
+
+	var snapshotAt sql.NullTime
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s WHERE %s <= $1", at, snapshots, at)
+	if err := h.DBManager.Target.QueryRowContext(ctx, query, asOf).Scan(&snapshotAt); err != nil {
+		return "", time.Time{}, err
+	}
+	if !snapshotAt.Valid {
+		return "", time.Time{}, errNoSnapshot
+	}
+
+	_, view := sqlident.SplitQualified(projection.TargetView, "public")
+	relation := fmt.Sprintf("(SELECT * FROM %s WHERE %s = '%s'::timestamptz) AS %s",
+		snapshots, at, snapshotAt.Time.UTC().Format(time.RFC3339Nano), sqlident.PostgresColumn(view))
+	return relation, snapshotAt.Time, nil
+}
+
+// resolveAsOf points a projection request at the snapshot selected by its as_of parameter and returns the snapshot
+// time. It responds with 400 for an invalid as_of or a projection without time travel, and with 404 when no snapshot
+// is old enough
+func (h *APIHandler) resolveAsOf(c *gin.Context, projection *config.ProjectionConfig, raw string, params *projectionParams) (time.Time, bool) {
+	asOf, err := parseAsOf(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return time.Time{}, false
+	}
+	if h.Config.GetTimeTravel(projection) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Projection %s keeps no snapshots; enable time_travel on table %s", projection.ID, projection.SyncTable),
+		})
+		return time.Time{}, false
+	}
+
+	relation, snapshotAt, err := h.snapshotRelation(c.Request.Context(), projection, asOf)
+	if errors.Is(err, errNoSnapshot) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection %s has no snapshot at or before %s", projection.ID, asOf.Format(time.RFC3339)),
+		})
+		return time.Time{}, false
+	}
+	if err != nil {
+		h.Logger.Error("Failed to look up projection snapshot",
+			zap.String("projection_id", projection.ID),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up projection snapshot",
+		})
+		return time.Time{}, false
+	}
+	params.Relation = relation
+	return snapshotAt, true
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// timeseriesBuckets maps bucket sizes to their PostgreSQL interval
+var timeseriesBuckets = map[string]string{
+	"hour":    "1 hour",
+	"day":     "1 day",
+	"week":    "1 week",
+	"month":   "1 month",
+	"quarter": "3 months",
+	"year":    "1 year",
+}
+
+// TimeseriesPoint is the aggregate of one time bucket
+type TimeseriesPoint struct {
+	Bucket string   `json:"bucket"`
+	Value  *float64 `json:"value"` // NULL for avg, min and max of empty buckets
+}
+
+// GetProjectionTimeseries aggregates a value over the projection's time series column in buckets of an hour, day,
+// week, month, quarter or year. Buckets without rows are filled in, so charts get an evenly spaced series. The
+// projection's filters apply, and from/to bound the series
+func (h *APIHandler) GetProjectionTimeseries(c *gin.Context) {
+	if h.DBManager == nil || h.DBManager.Target == nil {
+		h.Logger.Error("Target database not configured for projections")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Target database connection is not available",
+		})
+		return
+	}
+
+	projectionID := c.Param("id")
+	projection, ok := h.Config.GetProjectionByID(projectionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Projection not found: %s", projectionID),
+		})
+		return
+	}
+	if projection.Timeseries == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Projection %s has no timeseries column", projection.ID),
+		})
+		return
+	}
+
+	bucket := strings.ToLower(c.DefaultQuery("bucket", "day"))
+	interval, ok := timeseriesBuckets[bucket]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "bucket must be hour, day, week, month, quarter or year",
+		})
+		return
+	}
+
+	agg := strings.ToLower(c.DefaultQuery("agg", "sum"))
+	value := c.Query("value")
+	switch agg {
+	case "sum", "avg", "min", "max":
+		if value == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("agg %s requires a value column", agg),
+			})
+			return
+		}
+	case "count":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "agg must be sum, avg, min, max or count",
+		})
+		return
+	}
+
+	timeColumn := configuredColumn(projection, projection.Timeseries.Column)
+	params := projectionParams{Filters: c.QueryMap("filters")}
+	if len(projection.Fields) > 0 {
+		params.Select = []string{timeColumn}
+		if value != "" {
+			params.Select = append(params.Select, value)
+		}
+	}
+	// from and to include the whole buckets they fall in
+	identifier := quoteIdentifier(projection.TargetColumn(timeColumn))
+	for _, bound := range []struct{ param, condition string }{
+		{"from", "%s >= date_trunc('%s', %s::timestamp)"},
+		{"to", "%s < date_trunc('%s', %s::timestamp) + interval '" + interval + "'"},
+	} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		if _, err := parseTimeParam(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be a date (2006-01-02) or an RFC 3339 timestamp", bound.param),
+			})
+			return
+		}
+		condition := bound.condition
+		params.Conditions = append(params.Conditions, func(arg func(interface{}) string) string {
+			return fmt.Sprintf(condition, identifier, bucket, arg(raw))
+		})
+	}
+
+	data, err := buildProjectionQuery(params, projection)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	timeExpr := "d." + sqlident.PostgresColumn(resultColumn(projection, timeColumn)) + "::timestamp"
+	valueExpr := timeExpr
+	if value != "" {
+		valueExpr = "d." + sqlident.PostgresColumn(resultColumn(projection, configuredColumn(projection, value)))
+	}
+	aggExpr := fmt.Sprintf("%s(%s)", strings.ToUpper(agg), valueExpr)
+	if agg == "sum" {
+		aggExpr = fmt.Sprintf("COALESCE(SUM(%s), 0)", valueExpr)
+	}
+
+	queryCtx, cancel := h.withStatementTimeout(database.WithQueryLabel(c.Request.Context(), "projection:"+projection.ID), projection)
+	defer cancel()
+
+	// The series spans the truncated from/to, or the data's first and last buckets when they are not given
+	var from, to sql.NullTime
+	args := append(append([]interface{}{}, data.Args...), nullableParam(c.Query("from")), nullableParam(c.Query("to")))
+	boundsSQL := fmt.Sprintf(`WITH d AS (%s)
+		SELECT date_trunc('%s', COALESCE($%d::timestamp, min(%s))), date_trunc('%s', COALESCE($%d::timestamp, max(%s))) FROM d`,
+		data.SQL, bucket, len(args)-1, timeExpr, bucket, len(args), timeExpr)
+	if err := h.DBManager.Target.QueryRowxPreparedContext(queryCtx, boundsSQL, args...).Scan(&from, &to); err != nil {
+		h.projectionQueryFailed(queryCtx, c, projection, "Failed to query time series", err)
+		return
+	}
+
+	points := make([]TimeseriesPoint, 0)
+	if from.Valid && to.Valid && !to.Time.Before(from.Time) {
+		maxBuckets := projection.Timeseries.GetMaxBuckets()
+		if n := bucketCount(bucket, from.Time, to.Time); n > maxBuckets {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("The series spans %d %s buckets, more than %d; narrow it with from and to or use a larger bucket", n, bucket, maxBuckets),
+			})
+			return
+		}
+
+		args = append(append([]interface{}{}, data.Args...), from.Time, to.Time, interval)
+		seriesSQL := fmt.Sprintf(`WITH d AS (%s),
+			b AS (SELECT generate_series($%d::timestamp, $%d::timestamp, $%d::interval) AS bucket)
+			SELECT b.bucket, %s FROM b LEFT JOIN d ON date_trunc('%s', %s) = b.bucket
+			GROUP BY b.bucket ORDER BY b.bucket`,
+			data.SQL, len(args)-2, len(args)-1, len(args), aggExpr, bucket, timeExpr)
+		rows, err := h.DBManager.Target.QueryxPreparedContext(queryCtx, seriesSQL, args...)
+		if err != nil {
+			h.projectionQueryFailed(queryCtx, c, projection, "Failed to query time series", err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var start time.Time
+			var aggregate sql.NullFloat64
+			if err := rows.Scan(&start, &aggregate); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to parse time series bucket",
+				})
+				return
+			}
+			point := TimeseriesPoint{Bucket: formatBucket(bucket, start)}
+			if aggregate.Valid {
+				point.Value = &aggregate.Float64
+			}
+			points = append(points, point)
+		}
+		if err := rows.Err(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error reading time series",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projection_id": projection.ID,
+		"column":        timeColumn,
+		"bucket":        bucket,
+		"agg":           agg,
+		"value":         value,
+		"points":        points,
+		"filters":       data.AppliedFilters,
+		"meta": gin.H{
+			"point_count": len(points),
+		},
+	})
+}
+
+// resultColumn returns the name a column has in projection query results: fields are returned under their
+// configured names, while projections without fields return the view's columns
+func resultColumn(projection *config.ProjectionConfig, column string) string {
+	if len(projection.Fields) == 0 {
+		return projection.TargetColumn(column)
+	}
+	return column
+}
+
+// parseTimeParam parses a date or RFC 3339 timestamp request parameter
+func parseTimeParam(raw string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// nullableParam binds an empty request parameter as NULL
+func nullableParam(raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+	return raw
+}
+
+// bucketCount returns the number of buckets from the bucket starting at from to the one starting at to
+func bucketCount(bucket string, from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	switch bucket {
+	case "hour":
+		return int(to.Sub(from)/time.Hour) + 1
+	case "day":
+		return int(to.Sub(from)/(24*time.Hour)) + 1
+	case "week":
+		return int(to.Sub(from)/(7*24*time.Hour)) + 1
+	case "month":
+		return months + 1
+	case "quarter":
+		return months/3 + 1
+	default:
+		return to.Year() - from.Year() + 1
+	}
+}
+
+// formatBucket formats the start of a bucket: a timestamp for hours, else a date
+func formatBucket(bucket string, start time.Time) string {
+	if bucket == "hour" {
+		return start.Format("2006-01-02T15:04:05")
+	}
+	return start.Format("2006-01-02")
+}
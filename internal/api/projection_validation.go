@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// Projection validation modes
+const (
+	ProjectionValidationWarn   = "warn"
+	ProjectionValidationStrict = "strict"
+	ProjectionValidationOff    = "off"
+)
+
+// ValidateProjections checks every projection's columns against its target view.
+// Problems are logged; in strict mode they are also returned so startup can fail.
+func (s *Server) ValidateProjections() error {
+	mode := projectionValidationMode(&s.Config.API)
+	if mode == ProjectionValidationOff || len(s.Config.Projections) == 0 {
+		return nil
+	}
+	if s.Handler.DBManager == nil || s.Handler.DBManager.Target == nil {
+		return nil
+	}
+
+	var problems []error
+	for i := range s.Config.Projections {
+		projection := &s.Config.Projections[i]
+		if projection.IsSourcePassthrough() {
+			continue
+		}
+		for _, err := range s.Handler.validateProjection(projection) {
+			s.Logger.Warn("Projection configuration problem",
+				zap.String("projection_id", projection.ID),
+				zap.String("target_view", projection.TargetView),
+				zap.Error(err),
+			)
+			problems = append(problems, fmt.Errorf("projection %s: %w", projection.ID, err))
+		}
+	}
+
+	if mode == ProjectionValidationStrict {
+		return errors.Join(problems...)
+	}
+	return nil
+}
+
+// projectionValidationMode returns the api.projection_validation mode, warn by default
+func projectionValidationMode(api *config.APIConfig) string {
+	mode := strings.ToLower(strings.TrimSpace(api.ProjectionValidation))
+	if mode == "" {
+		return ProjectionValidationWarn
+	}
+	return mode
+}
+
+// validateProjection returns an error for every configured column missing from the target view
+func (h *APIHandler) validateProjection(projection *config.ProjectionConfig) []error {
+	schema, view := sqlident.SplitQualified(projection.TargetView, "public")
+
+	var columns []string
+	if err := h.DBManager.Target.Select(&columns, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+	`, schema, view); err != nil {
+		return []error{fmt.Errorf("failed to inspect target view: %w", err)}
+	}
+	if len(columns) == 0 {
+		return []error{fmt.Errorf("target view %s does not exist or has no columns", projection.TargetView)}
+	}
+
+	known := make(map[string]bool, len(columns))
+	byLower := make(map[string]string, len(columns))
+	for _, column := range columns {
+		known[column] = true
+		byLower[strings.ToLower(column)] = column
+	}
+
+	var problems []error
+	check := func(kind, column string) {
+		column = projection.TargetColumn(column)
+		if column == "" || known[column] {
+			return
+		}
+		if match, ok := byLower[strings.ToLower(column)]; ok {
+			problems = append(problems, fmt.Errorf("%s column %q does not exist (did you mean %q?)", kind, column, match))
+			return
+		}
+		problems = append(problems, fmt.Errorf("%s column %q does not exist", kind, column))
+	}
+
+	for _, field := range projection.Fields {
+		check("field", field.Column)
+	}
+	for _, filter := range projection.Filters {
+		check("filter", filter.Column)
+	}
+	for _, column := range projection.GroupBy {
+		check("group_by", column)
+	}
+	for _, total := range projection.Totals {
+		check("total", total.Column)
+	}
+	for _, sort := range projection.DefaultSort {
+		check("default_sort", sort.Column)
+	}
+	for _, column := range projection.Key {
+		check("key", column)
+	}
+
+	return problems
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/asynkron/protoactor-go/actor"
@@ -13,6 +14,10 @@ import (
 
 	"mssql-postgres-sync/internal/config"
 	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/logging"
+	"mssql-postgres-sync/internal/metrics"
+	syncpkg "mssql-postgres-sync/internal/sync"
 )
 
 // Server represents the API server
@@ -25,8 +30,8 @@ type Server struct {
 }
 
 // NewServer creates a new API server
-func NewServer(cfg *config.Config, logger *zap.Logger, coordinatorPID *actor.PID, actorSystem *actor.ActorSystem, dbManager *database.DatabaseManager) *Server {
-	handler := NewAPIHandler(cfg, logger, coordinatorPID, actorSystem, dbManager)
+func NewServer(cfg *config.Config, logger *zap.Logger, coordinatorPID *actor.PID, actorSystem *actor.ActorSystem, dbManager *database.DatabaseManager, syncEngine *syncpkg.SyncEngine, historyStore *history.Store, logs *logging.Manager) *Server {
+	handler := NewAPIHandler(cfg, logger, coordinatorPID, actorSystem, dbManager, syncEngine, historyStore, logs)
 
 	return &Server{
 		Config:      cfg,
@@ -45,29 +50,79 @@ func (s *Server) Start() error {
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(s.loggerMiddleware())
+	router.Use(maxBodyMiddleware(s.Config.API.GetMaxBodyBytes()))
 
 	// CORS middleware
 	if s.Config.API.EnableCORS {
 		router.Use(cors.New(cors.Config{
 			AllowOrigins:     []string{"*"},
 			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+			AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key"},
 			ExposeHeaders:    []string{"Content-Length"},
 			AllowCredentials: true,
 			MaxAge:           12 * time.Hour,
 		}))
 	}
 
+	// Response compression
+	if s.Config.API.Compression.Enabled {
+		router.Use(gzipMiddleware(s.Config.API.Compression.Level))
+	}
+
 	// Routes
 	api := router.Group("/api")
 	{
 		api.GET("/health", s.Handler.HealthCheck)
 		api.GET("/status", s.Handler.GetStatus)
-		api.GET("/projections", s.Handler.ListProjections)
-		api.GET("/projections/:id/data", s.Handler.GetProjectionData)
+		api.GET("/actors", s.Handler.GetActors)
+		api.GET("/tables/:name/stats", s.Handler.GetTableStats)
+		api.GET("/tables/:name/validation", s.Handler.GetTableValidation)
+		api.POST("/tables/:name/rollback", s.Handler.RollbackTable)
+		api.GET("/diff/:table", s.Handler.GetTableDiff)
+		api.GET("/backfill", s.Handler.ListBackfills)
+		api.GET("/backfill/:table", s.Handler.GetBackfill)
+		api.POST("/backfill/:table", s.Handler.StartBackfill)
+		api.DELETE("/backfill/:table", s.Handler.StopBackfill)
+		api.GET("/ui-config", s.Handler.GetUIConfig)
+		api.GET("/dashboards", s.Handler.ListDashboards)
+		api.GET("/dashboards/:id", s.Handler.GetDashboard)
+		api.GET("/usage", s.Handler.GetUsage)
+		api.GET("/usage/:key", s.Handler.GetKeyUsage)
 		api.POST("/sync", s.Handler.TriggerSync)
+		api.POST("/hooks/:name", s.Handler.TriggerHook)
+		api.GET("/jobs/:id", s.Handler.GetJob)
+		api.GET("/batches/:id", s.Handler.GetJob)
+		api.GET("/logging", s.Handler.GetLogLevels)
+		api.PUT("/logging", s.Handler.SetLogLevel)
+		api.GET("/config/validate", s.Handler.ValidateLoadedConfig)
+		api.POST("/config/validate", s.Handler.ValidateCandidateConfig)
+	}
+
+	// Projection reads are identified by API key and accounted
+	projections := api.Group("/projections", s.Handler.usageMiddleware())
+	{
+		projections.GET("", s.Handler.ListProjections)
+		projections.GET("/:id/data", s.Handler.GetProjectionData)
+		projections.GET("/:id/sample", s.Handler.GetProjectionSample)
+		projections.GET("/:id/filters/:filterId/options", s.Handler.GetFilterOptions)
+		projections.GET("/:id/rows/:key", s.Handler.GetProjectionRow)
+		projections.GET("/:id/timeseries", s.Handler.GetProjectionTimeseries)
+		projections.GET("/:id/preferences", s.Handler.GetPreferences)
+		projections.PUT("/:id/preferences", s.Handler.SavePreferences)
+		projections.DELETE("/:id/preferences", s.Handler.DeletePreferences)
 	}
 
+	// OData endpoint over the projections
+	if s.Config.API.OData.Enabled {
+		odata := router.Group("/odata", s.Handler.usageMiddleware())
+		odata.GET("", s.Handler.ODataServiceDocument)
+		odata.GET("/$metadata", s.Handler.ODataMetadata)
+		odata.GET("/:id", s.Handler.GetODataEntitySet)
+	}
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// Serve static frontend files
 	router.Static("/static", "./frontend/build/static")
 	router.StaticFile("/", "./frontend/build/index.html")
@@ -80,9 +135,9 @@ func (s *Server) Start() error {
 	addr := fmt.Sprintf("%s:%d", s.Config.API.Host, s.Config.API.Port)
 	s.HTTPServer = &http.Server{
 		Addr:           addr,
-		Handler:        router,
-		ReadTimeout:    30 * time.Second,
-		WriteTimeout:   30 * time.Second,
+		Handler:        exportDeadlineHandler(router, s.Config.API.GetExportTimeout()),
+		ReadTimeout:    s.Config.API.GetReadTimeout(),
+		WriteTimeout:   s.Config.API.GetWriteTimeout(),
 		MaxHeaderBytes: 1 << 20,
 	}
 
@@ -131,3 +186,34 @@ func (s *Server) loggerMiddleware() gin.HandlerFunc {
 		)
 	}
 }
+
+// maxBodyMiddleware limits the size of request bodies
+func maxBodyMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Request body exceeds %d bytes", limit),
+			})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// exportDeadlineHandler extends the write deadline for projection data and table diff requests,
+// which can run far longer than the server-wide write timeout allows
+func exportDeadlineHandler(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isExportPath(r.URL.Path) {
+			// Best effort: the server-wide write timeout still applies if the writer cannot be extended
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isExportPath(path string) bool {
+	return (strings.HasPrefix(path, "/api/projections/") && strings.HasSuffix(path, "/data")) || strings.HasPrefix(path, "/api/diff/") ||
+		strings.HasPrefix(path, "/odata/")
+}
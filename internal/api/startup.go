@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// WaitForDatabases blocks until both databases respond, for startups where they were unreachable. Meanwhile the API
+// address answers /api/health and /api/status with 503 and the connection attempts, so orchestrators see the
+// service as running but not ready. It returns ctx's error when ctx is done first
+func WaitForDatabases(ctx context.Context, cfg *config.Config, logger *zap.Logger, dbManager *database.DatabaseManager) error {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	starting := func(c *gin.Context) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":      "starting",
+			"service":     "mssql-postgres-sync",
+			"time":        time.Now().Format(time.RFC3339),
+			"connections": dbManager.ConnectionStates(),
+		})
+	}
+	router.GET("/api/health", starting)
+	router.GET("/api/status", starting)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	addr := fmt.Sprintf("%s:%d", cfg.API.Host, cfg.API.Port)
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  cfg.API.GetReadTimeout(),
+		WriteTimeout: cfg.API.GetWriteTimeout(),
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("Startup health endpoint failed", zap.String("address", addr), zap.Error(err))
+		}
+	}()
+	logger.Info("Waiting for databases, serving startup health", zap.String("address", addr))
+
+	err := dbManager.WaitReady(ctx)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+		logger.Warn("Failed to stop startup health endpoint", zap.Error(shutdownErr))
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// defaultUIFeatures are the feature flags the frontend knows about, enabled unless ui.features turns them off
+var defaultUIFeatures = map[string]bool{
+	"table_status": true, // table cards with their sync state
+	"table_sync":   true, // per-table "Sync Now" buttons
+	"sync_all":     true, // the "Sync All Tables" button
+	"projections":  true, // projection views
+}
+
+// UIConfigResponse is the frontend configuration returned by GET /api/ui-config
+type UIConfigResponse struct {
+	Branding  config.UIBranding `json:"branding"`
+	Menu      []UIMenuGroup     `json:"menu"`
+	Refresh   config.UIRefresh  `json:"refresh"`
+	Features  map[string]bool   `json:"features"`
+	Language  string            `json:"language"`  // language of the titles and labels returned
+	Languages []string          `json:"languages"` // languages titles and labels can be requested in, with ?lang= or Accept-Language
+}
+
+// UIMenuGroup is a navigation entry with its projections resolved to their titles
+type UIMenuGroup struct {
+	ID          string             `json:"id"`
+	Label       string             `json:"label"`
+	Icon        string             `json:"icon,omitempty"`
+	Projections []UIMenuProjection `json:"projections"`
+}
+
+// UIMenuProjection is a projection listed in a navigation entry
+type UIMenuProjection struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetUIConfig returns the branding, projection menu, refresh hints and feature flags of the frontend
+func (h *APIHandler) GetUIConfig(c *gin.Context) {
+	ui := h.Config.UI
+
+	branding := ui.Branding
+	branding.Title = branding.GetTitle()
+
+	refresh := ui.Refresh
+	refresh.Status = refresh.GetStatus()
+
+	features := make(map[string]bool, len(defaultUIFeatures)+len(ui.Features))
+	for name, enabled := range defaultUIFeatures {
+		features[name] = enabled
+	}
+	for name, enabled := range ui.Features {
+		features[name] = enabled
+	}
+
+	lang := h.requestLanguage(c)
+	responseLanguage := lang
+	if responseLanguage == "" {
+		responseLanguage = ui.GetLanguage()
+	}
+
+	c.JSON(http.StatusOK, UIConfigResponse{
+		Branding:  branding,
+		Menu:      buildUIMenu(ui.Menu, h.Config.Projections, lang),
+		Refresh:   refresh,
+		Features:  features,
+		Language:  responseLanguage,
+		Languages: h.Config.Languages(),
+	})
+}
+
+// buildUIMenu resolves the configured menu groups, with labels and titles in the given language. Projections not
+// listed in any group are appended under "Other", or form a single "Projections" group when no menu is configured,
+// so every view is reachable
+func buildUIMenu(groups []config.UIMenuGroup, projections []config.ProjectionConfig, lang string) []UIMenuGroup {
+	byID := make(map[string]UIMenuProjection, len(projections))
+	for _, projection := range projections {
+		byID[projection.ID] = UIMenuProjection{
+			ID:          projection.ID,
+			Title:       projection.Titles.Get(lang, projection.Title),
+			Description: projection.Descriptions.Get(lang, projection.Description),
+		}
+	}
+
+	menu := make([]UIMenuGroup, 0, len(groups)+1)
+	listed := make(map[string]bool)
+	for _, group := range groups {
+		entry := UIMenuGroup{ID: group.ID, Label: group.Labels.Get(lang, group.Label), Icon: group.Icon, Projections: []UIMenuProjection{}}
+		for _, id := range group.Projections {
+			entry.Projections = append(entry.Projections, byID[id])
+			listed[id] = true
+		}
+		menu = append(menu, entry)
+	}
+
+	rest := UIMenuGroup{ID: "other", Label: "Other", Projections: []UIMenuProjection{}}
+	if len(groups) == 0 {
+		rest = UIMenuGroup{ID: "projections", Label: "Projections", Projections: []UIMenuProjection{}}
+	}
+	for _, projection := range projections {
+		if !listed[projection.ID] {
+			rest.Projections = append(rest.Projections, byID[projection.ID])
+		}
+	}
+	if len(rest.Projections) > 0 {
+		menu = append(menu, rest)
+	}
+	return menu
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+const (
+	// anonymousKey accounts requests made without an API key
+	anonymousKey = "anonymous"
+
+	// usageRowsKey is the gin context key under which handlers add the rows they returned
+	usageRowsKey = "usage_rows"
+
+	// apiKeyContextKey is the gin context key holding the *config.APIKeyConfig of a request sent with a known key
+	apiKeyContextKey = "api_key"
+)
+
+// KeyUsage is the usage of an API key on the current day
+type KeyUsage struct {
+	Key         string                      `json:"key"`
+	Requests    int64                       `json:"requests"`
+	Rows        int64                       `json:"rows"`
+	Bytes       int64                       `json:"bytes"`
+	Rejected    int64                       `json:"rejected"` // requests refused over a quota
+	Projections map[string]*ProjectionUsage `json:"projections"`
+	Quotas      *UsageQuotas                `json:"quotas,omitempty"`
+}
+
+// ProjectionUsage is the usage of one projection by an API key
+type ProjectionUsage struct {
+	Requests int64 `json:"requests"`
+	Rows     int64 `json:"rows"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// UsageQuotas are the daily quotas of an API key, 0 meaning no limit
+type UsageQuotas struct {
+	Requests int64 `json:"requests"`
+	Rows     int64 `json:"rows"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// usageTracker accounts projection requests per API key. Usage is kept in memory and starts over at midnight UTC
+type usageTracker struct {
+	mu   sync.Mutex
+	day  string
+	keys map[string]*KeyUsage
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{keys: make(map[string]*KeyUsage)}
+}
+
+// usage returns the usage of a key on the day of now, starting a new day when it changed. Callers hold mu
+func (t *usageTracker) usage(name string, now time.Time) *KeyUsage {
+	if day := now.UTC().Format("2006-01-02"); day != t.day {
+		t.day = day
+		t.keys = make(map[string]*KeyUsage)
+	}
+	usage, ok := t.keys[name]
+	if !ok {
+		usage = &KeyUsage{Key: name, Projections: make(map[string]*ProjectionUsage)}
+		t.keys[name] = usage
+	}
+	return usage
+}
+
+// exceeded returns the daily quota of key already used up, or "" when the request may run. Refused requests are counted
+func (t *usageTracker) exceeded(key *config.APIKeyConfig, now time.Time) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.usage(key.Name, now)
+	quota := ""
+	switch {
+	case key.DailyRequests > 0 && usage.Requests >= key.DailyRequests:
+		quota = "request"
+	case key.DailyRows > 0 && usage.Rows >= key.DailyRows:
+		quota = "row"
+	case key.DailyBytes > 0 && usage.Bytes >= key.DailyBytes:
+		quota = "byte"
+	}
+	if quota != "" {
+		usage.Rejected++
+	}
+	return quota
+}
+
+// record adds a served request to the usage of a key and, when the request targeted one, of its projection
+func (t *usageTracker) record(name, projectionID string, rows, bytes int64, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.usage(name, now)
+	usage.Requests++
+	usage.Rows += rows
+	usage.Bytes += bytes
+	if projectionID == "" {
+		return
+	}
+	projection, ok := usage.Projections[projectionID]
+	if !ok {
+		projection = &ProjectionUsage{}
+		usage.Projections[projectionID] = projection
+	}
+	projection.Requests++
+	projection.Rows += rows
+	projection.Bytes += bytes
+}
+
+// snapshot copies the usage of the day of now, by key name
+func (t *usageTracker) snapshot(now time.Time) (string, map[string]KeyUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.usage(anonymousKey, now)
+	usages := make(map[string]KeyUsage, len(t.keys))
+	for name, usage := range t.keys {
+		copied := *usage
+		copied.Projections = make(map[string]*ProjectionUsage, len(usage.Projections))
+		for id, projection := range usage.Projections {
+			p := *projection
+			copied.Projections[id] = &p
+		}
+		usages[name] = copied
+	}
+	return t.day, usages
+}
+
+// apiKey resolves the API key of a request from X-API-Key or the Authorization bearer. It returns nil for requests
+// without a key, and false when a key was sent that is not configured
+func (h *APIHandler) apiKey(c *gin.Context) (*config.APIKeyConfig, bool) {
+	keys := h.Config.API.Usage.Keys
+	if len(keys) == 0 {
+		return nil, true
+	}
+
+	token := c.GetHeader("X-API-Key")
+	if auth := c.GetHeader("Authorization"); token == "" && strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return nil, true
+	}
+	for i := range keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(keys[i].Key)) == 1 {
+			return &keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// usageMiddleware identifies the API key of projection and OData requests, refuses keys over their daily quota and
+// accounts the requests, rows and bytes served. Quotas are checked before a request, so the request crossing one completes
+func (h *APIHandler) usageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := h.apiKey(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unknown API key",
+			})
+			return
+		}
+		if key == nil && h.Config.API.Usage.RequireKey {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "API key required in X-API-Key or Authorization: Bearer",
+			})
+			return
+		}
+
+		name := anonymousKey
+		if key != nil {
+			name = key.Name
+			c.Set(apiKeyContextKey, key)
+			now := time.Now()
+			if quota := h.Usage.exceeded(key, now); quota != "" {
+				c.Header("Retry-After", strconv.Itoa(int(untilNextDay(now).Seconds())+1))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"error": fmt.Sprintf("Daily %s quota of API key %s exceeded", quota, name),
+				})
+				return
+			}
+		}
+
+		c.Next()
+
+		projectionID := c.Param("id")
+		rows := c.GetInt64(usageRowsKey)
+		bytes := int64(c.Writer.Size())
+		if bytes < 0 {
+			bytes = 0
+		}
+		h.Usage.record(name, projectionID, rows, bytes, time.Now())
+		metrics.APIKeyRequestsTotal.WithLabelValues(name, projectionID).Inc()
+		metrics.APIKeyRowsTotal.WithLabelValues(name, projectionID).Add(float64(rows))
+		metrics.APIKeyBytesTotal.WithLabelValues(name, projectionID).Add(float64(bytes))
+	}
+}
+
+// requestAPIKey returns the API key of a request, nil when it was sent without one
+func requestAPIKey(c *gin.Context) *config.APIKeyConfig {
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		return value.(*config.APIKeyConfig)
+	}
+	return nil
+}
+
+// addUsageRows adds rows returned by a handler to the usage of the request's API key
+func addUsageRows(c *gin.Context, rows int) {
+	c.Set(usageRowsKey, c.GetInt64(usageRowsKey)+int64(rows))
+}
+
+// untilNextDay returns the time left until usage starts over at midnight UTC
+func untilNextDay(now time.Time) time.Duration {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC).Sub(now)
+}
+
+// GetUsage returns today's usage of every API key, including requests made without a key
+func (h *APIHandler) GetUsage(c *gin.Context) {
+	now := time.Now()
+	day, usages := h.Usage.snapshot(now)
+
+	result := make([]KeyUsage, 0, len(h.Config.API.Usage.Keys)+1)
+	for i := range h.Config.API.Usage.Keys {
+		result = append(result, h.keyUsage(&h.Config.API.Usage.Keys[i], usages))
+	}
+	result = append(result, h.keyUsage(nil, usages))
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Requests > result[j].Requests
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"day":       day,
+		"resets_at": now.Add(untilNextDay(now)).UTC(),
+		"keys":      result,
+	})
+}
+
+// GetKeyUsage returns today's usage of one API key
+func (h *APIHandler) GetKeyUsage(c *gin.Context) {
+	name := c.Param("key")
+	var key *config.APIKeyConfig
+	if name != anonymousKey {
+		var ok bool
+		if key, ok = h.Config.API.Usage.GetKeyByName(name); !ok {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("API key not found: %s", name),
+			})
+			return
+		}
+	}
+
+	now := time.Now()
+	day, usages := h.Usage.snapshot(now)
+	c.JSON(http.StatusOK, gin.H{
+		"day":       day,
+		"resets_at": now.Add(untilNextDay(now)).UTC(),
+		"usage":     h.keyUsage(key, usages),
+	})
+}
+
+// keyUsage returns the usage of a key with its quotas, or of requests without a key when key is nil
+func (h *APIHandler) keyUsage(key *config.APIKeyConfig, usages map[string]KeyUsage) KeyUsage {
+	name := anonymousKey
+	if key != nil {
+		name = key.Name
+	}
+	usage, ok := usages[name]
+	if !ok {
+		usage = KeyUsage{Key: name, Projections: map[string]*ProjectionUsage{}}
+	}
+	if key != nil {
+		usage.Quotas = &UsageQuotas{Requests: key.DailyRequests, Rows: key.DailyRows, Bytes: key.DailyBytes}
+	}
+	return usage
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetTableValidation returns the latest validation report of a table and its recently quarantined rows
+func (h *APIHandler) GetTableValidation(c *gin.Context) {
+	if h.History == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Sync history is not enabled",
+		})
+		return
+	}
+
+	tableName := c.Param("name")
+	found := false
+	for _, tc := range h.Config.Tables {
+		if tc.TargetTable == tableName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Table not found: " + tableName,
+		})
+		return
+	}
+
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid limit",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	last, err := h.History.LastValidation(tableName)
+	if err != nil {
+		h.Logger.Error("Failed to load validation report",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load validation report",
+		})
+		return
+	}
+
+	quarantined, err := h.History.Quarantined(tableName, limit)
+	if err != nil {
+		h.Logger.Warn("Failed to load quarantined rows",
+			zap.String("table", tableName),
+			zap.Error(err),
+		)
+	}
+
+	response := gin.H{
+		"table":       tableName,
+		"quarantined": quarantined,
+	}
+	if last != nil {
+		response["batch_id"] = last.BatchID
+		response["checked_at"] = last.FinishedAt
+		response["success"] = last.Success
+		response["report"] = json.RawMessage(last.Validation)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
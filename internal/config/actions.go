@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ProjectionAction is a row-level link of a projection, such as opening an order in the ERP. Its URL is rendered by
+// the API for every row of a data response
+type ProjectionAction struct {
+	ID     string       `yaml:"id" json:"id"`
+	Label  string       `yaml:"label" json:"label"`
+	Labels Translations `yaml:"labels,omitempty" json:"-"`                  // label by language
+	URL    string       `yaml:"url" json:"-"`                               // template with {{column}} placeholders replaced by the row's values
+	NewTab bool         `yaml:"new_tab,omitempty" json:"new_tab,omitempty"` // open the link in a new browser tab
+}
+
+// actionPlaceholderPattern matches {{column}} placeholders of action URLs
+var actionPlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Render returns the action's URL for a row, with each placeholder replaced by the escaped value of its column.
+// value returns a column's value as text and false when it is NULL, which leaves the row without the action
+func (a *ProjectionAction) Render(value func(column string) (string, bool)) (string, bool) {
+	query := strings.Index(a.URL, "?")
+	rendered := true
+	var b strings.Builder
+	last := 0
+	for _, match := range actionPlaceholderPattern.FindAllStringSubmatchIndex(a.URL, -1) {
+		b.WriteString(a.URL[last:match[0]])
+		last = match[1]
+
+		text, ok := value(a.URL[match[2]:match[3]])
+		if !ok {
+			rendered = false
+			continue
+		}
+		if query >= 0 && match[0] > query {
+			b.WriteString(url.QueryEscape(text))
+		} else {
+			b.WriteString(url.PathEscape(text))
+		}
+	}
+	b.WriteString(a.URL[last:])
+	return b.String(), rendered
+}
+
+// Columns returns the columns referenced by the action's URL placeholders
+func (a *ProjectionAction) Columns() []string {
+	var columns []string
+	for _, match := range actionPlaceholderPattern.FindAllStringSubmatch(a.URL, -1) {
+		columns = append(columns, match[1])
+	}
+	return columns
+}
+
+// validateActions checks that a projection's actions have unique ids and a label, link to http(s) or mailto URLs or
+// paths of the service, and only take values from fields of the projection. Placeholders may not set the scheme or
+// host, so row values can never send users to another site
+func validateActions(projection *ProjectionConfig) error {
+	ids := make(map[string]bool, len(projection.Actions))
+	for _, action := range projection.Actions {
+		if action.ID == "" || action.Label == "" || action.URL == "" {
+			return fmt.Errorf("projection %s: actions require an id, a label and a url", projection.ID)
+		}
+		if ids[action.ID] {
+			return fmt.Errorf("projection %s: duplicate action %s", projection.ID, action.ID)
+		}
+		ids[action.ID] = true
+
+		prefix := action.URL
+		if loc := actionPlaceholderPattern.FindStringIndex(action.URL); loc != nil {
+			prefix = action.URL[:loc[0]]
+		}
+		if err := validateActionPrefix(prefix, action.URL == prefix); err != nil {
+			return fmt.Errorf("projection %s: action %s: %w", projection.ID, action.ID, err)
+		}
+
+		if len(projection.Fields) == 0 {
+			continue
+		}
+		for _, column := range action.Columns() {
+			found := false
+			for _, field := range projection.Fields {
+				if strings.EqualFold(field.Column, column) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("projection %s: action %s: {{%s}} is not a field", projection.ID, action.ID, column)
+			}
+		}
+	}
+	return nil
+}
+
+// validateActionPrefix checks the part of an action URL before its first placeholder, which must fix the scheme and
+// host, or the URL when it has no placeholders
+func validateActionPrefix(prefix string, whole bool) error {
+	lower := strings.ToLower(prefix)
+	switch {
+	case strings.HasPrefix(lower, "mailto:"):
+		return nil
+	case strings.HasPrefix(prefix, "/") && !strings.HasPrefix(prefix, "//"):
+		return nil
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		rest := prefix[strings.Index(prefix, "://")+3:]
+		if whole {
+			if _, err := url.Parse(prefix); err != nil || rest == "" {
+				return fmt.Errorf("invalid url %q", prefix)
+			}
+			return nil
+		}
+		if !strings.Contains(rest, "/") || strings.HasPrefix(rest, "/") {
+			return fmt.Errorf("url placeholders must come after the host")
+		}
+		return nil
+	default:
+		return fmt.Errorf("url must be an http, https or mailto URL or start with /")
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import "fmt"
+
+// UsageConfig represents the API keys identifying projection consumers and their usage accounting
+type UsageConfig struct {
+	Keys       []APIKeyConfig `yaml:"keys,omitempty"`
+	RequireKey bool           `yaml:"require_key,omitempty"` // reject projection and OData requests without a known key
+}
+
+// APIKeyConfig represents an API key with optional daily quotas. Usage days start at midnight UTC
+type APIKeyConfig struct {
+	Name          string `yaml:"name"`
+	Key           string `yaml:"key"`                      // sent in X-API-Key or "Authorization: Bearer"
+	DailyRequests int64  `yaml:"daily_requests,omitempty"` // 0 for no limit
+	DailyRows     int64  `yaml:"daily_rows,omitempty"`     // rows returned, 0 for no limit
+	DailyBytes    int64  `yaml:"daily_bytes,omitempty"`    // response bytes, 0 for no limit
+	Admin         bool   `yaml:"admin,omitempty"`          // may request the SQL and plan of projection queries with explain and validate configurations
+}
+
+// GetKeyByName returns an API key configuration by name
+func (u *UsageConfig) GetKeyByName(name string) (*APIKeyConfig, bool) {
+	for i := range u.Keys {
+		if u.Keys[i].Name == name {
+			return &u.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// validate checks that API keys are named, unique and have non-negative quotas
+func (u *UsageConfig) validate() error {
+	names := make(map[string]bool, len(u.Keys))
+	keys := make(map[string]bool, len(u.Keys))
+	for _, key := range u.Keys {
+		if key.Name == "" {
+			return fmt.Errorf("api.usage: keys require a name")
+		}
+		if key.Name == "anonymous" {
+			return fmt.Errorf("api.usage: key name anonymous is reserved for requests without a key")
+		}
+		if names[key.Name] {
+			return fmt.Errorf("api.usage: duplicate key name %s", key.Name)
+		}
+		names[key.Name] = true
+		if key.Key == "" {
+			return fmt.Errorf("api.usage: key %s requires a key", key.Name)
+		}
+		if keys[key.Key] {
+			return fmt.Errorf("api.usage: key %s reuses the key of another consumer", key.Name)
+		}
+		keys[key.Key] = true
+		if key.DailyRequests < 0 || key.DailyRows < 0 || key.DailyBytes < 0 {
+			return fmt.Errorf("api.usage: key %s: quotas must not be negative", key.Name)
+		}
+	}
+	if u.RequireKey && len(u.Keys) == 0 {
+		return fmt.Errorf("api.usage: require_key needs at least one key")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// backfillDateLayout is the format of backfill from and to dates
+const backfillDateLayout = "2006-01-02"
+
+// Backfill lets a range-partitioned table be loaded one partition range at a time from a start date, apart from
+// its regular refresh. Started with POST /api/backfill/:table or the -backfill flag; progress is kept in the history database
+type Backfill struct {
+	From   string `yaml:"from"`             // first date loaded, YYYY-MM-DD
+	To     string `yaml:"to,omitempty"`     // date the backfill stops before, YYYY-MM-DD (default: start of the current range)
+	Delay  int    `yaml:"delay,omitempty"`  // seconds paused between ranges (default 10)
+	Filter string `yaml:"filter,omitempty"` // source filter used instead of the table filter, which usually limits the refresh to recent rows
+}
+
+// GetFrom returns the first date loaded by the backfill
+func (b *Backfill) GetFrom() (time.Time, error) {
+	return time.Parse(backfillDateLayout, strings.TrimSpace(b.From))
+}
+
+// GetTo returns the date the backfill stops before, zero when it runs up to the current range
+func (b *Backfill) GetTo() (time.Time, error) {
+	if strings.TrimSpace(b.To) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(backfillDateLayout, strings.TrimSpace(b.To))
+}
+
+// GetDelay returns the pause between backfilled ranges
+func (b *Backfill) GetDelay() time.Duration {
+	if b.Delay > 0 {
+		return time.Duration(b.Delay) * time.Second
+	}
+	return 10 * time.Second
+}
+
+// validateBackfill checks that a backfilled table is range partitioned and that its dates parse
+func validateBackfill(tc TableConfig, historyEnabled bool) error {
+	b := tc.Backfill
+	if tc.Partitioning == nil || !strings.EqualFold(tc.Partitioning.Type, "range") {
+		return fmt.Errorf("backfill requires range partitioning")
+	}
+	if !historyEnabled {
+		return fmt.Errorf("backfill requires history to be enabled to keep its progress")
+	}
+	from, err := b.GetFrom()
+	if err != nil {
+		return fmt.Errorf("backfill from must be a YYYY-MM-DD date")
+	}
+	to, err := b.GetTo()
+	if err != nil {
+		return fmt.Errorf("backfill to must be a YYYY-MM-DD date")
+	}
+	if !to.IsZero() && !to.After(from) {
+		return fmt.Errorf("backfill to must be after from")
+	}
+	if b.Delay < 0 {
+		return fmt.Errorf("backfill delay must not be negative")
+	}
+	return nil
+}
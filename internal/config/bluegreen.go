@@ -0,0 +1,17 @@
+package config
+
+import "fmt"
+
+// validateBlueGreen checks that a blue/green table only uses loads that replace a whole generation in one transaction
+func validateBlueGreen(tc TableConfig, defaults DefaultConfig) error {
+	if tc.GetCommitEvery(defaults) > 0 {
+		return fmt.Errorf("blue_green requires loads in a single transaction (commit_every 0)")
+	}
+	if tc.Chunking != nil || tc.Backfill != nil {
+		return fmt.Errorf("blue_green cannot be combined with chunking or backfill")
+	}
+	if len(tc.Targets) > 0 {
+		return fmt.Errorf("blue_green cannot be combined with targets")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Chunking splits a table's full load into date windows on a column, each read from the source and committed on its
+// own, so a long history is not loaded by a single query and transaction. An interrupted load resumes at its next
+// window on the table's next sync; progress is kept in the history database
+type Chunking struct {
+	Column   string `yaml:"column"`             // date or datetime column the windows are cut on
+	Interval string `yaml:"interval,omitempty"` // window size: day, month (default) or year
+	From     string `yaml:"from"`               // start of the first full window, YYYY-MM-DD; earlier rows and NULLs load with it
+}
+
+// GetInterval returns the window size
+func (c *Chunking) GetInterval() string {
+	if c.Interval != "" {
+		return strings.ToLower(c.Interval)
+	}
+	return "month"
+}
+
+// GetFrom returns the start of the first full window
+func (c *Chunking) GetFrom() (time.Time, error) {
+	return time.Parse(backfillDateLayout, strings.TrimSpace(c.From))
+}
+
+// validateChunking checks a chunked table's window settings and the features chunked loads cannot be combined with
+func validateChunking(tc TableConfig, historyEnabled bool) error {
+	c := tc.Chunking
+	if c.Column == "" {
+		return fmt.Errorf("chunking requires a column")
+	}
+	switch c.GetInterval() {
+	case "day", "month", "year":
+	default:
+		return fmt.Errorf("chunking interval must be day, month or year")
+	}
+	if _, err := c.GetFrom(); err != nil {
+		return fmt.Errorf("chunking from must be a YYYY-MM-DD date")
+	}
+	if !historyEnabled {
+		return fmt.Errorf("chunking requires history to be enabled to keep its progress")
+	}
+	if tc.Backfill != nil {
+		return fmt.Errorf("chunking and backfill are mutually exclusive")
+	}
+	if tc.Validation != nil && len(tc.Validation.Rules) > 0 {
+		return fmt.Errorf("chunking does not support validation rules")
+	}
+	if tc.GetConstraints() == ConstraintsDisable {
+		return fmt.Errorf("chunking does not support constraints: disable")
+	}
+	return nil
+}
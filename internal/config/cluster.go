@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
+)
+
+// ClusterConfig distributes sync actors across service instances connected through actor remoting
+type ClusterConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	NodeID   string        `yaml:"node_id,omitempty"`   // id of this instance, one of nodes
+	BindHost string        `yaml:"bind_host,omitempty"` // interface the remoting server listens on (default 0.0.0.0)
+	Nodes    []ClusterNode `yaml:"nodes,omitempty"`     // static membership, identical on every instance
+}
+
+// ClusterNode is a service instance reachable at a host:port remoting address
+type ClusterNode struct {
+	ID      string `yaml:"id"`
+	Address string `yaml:"address"` // host:port other instances use to reach this node
+}
+
+// GetBindHost returns the interface the remoting server listens on
+func (c *ClusterConfig) GetBindHost() string {
+	if c.BindHost == "" {
+		return "0.0.0.0"
+	}
+	return c.BindHost
+}
+
+// Node returns a cluster node by id
+func (c *ClusterConfig) Node(id string) (ClusterNode, bool) {
+	for _, node := range c.Nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return ClusterNode{}, false
+}
+
+// Port returns the port of the node's remoting address
+func (n ClusterNode) Port() (int, error) {
+	_, port, err := net.SplitHostPort(n.Address)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
+
+// Owner returns the node that runs work identified by key. Keys are spread across nodes by
+// rendezvous hashing, so adding or removing a node only moves the keys it gains or loses
+func (c *ClusterConfig) Owner(key string) string {
+	keyHash := hashString(key)
+	var owner string
+	var best uint64
+	for _, node := range c.Nodes {
+		if score := mixHash(hashString(node.ID) ^ keyHash); owner == "" || score > best {
+			owner, best = node.ID, score
+		}
+	}
+	return owner
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mixHash spreads the bits of a combined hash (the splitmix64 finalizer); FNV alone
+// scores nodes too similarly for a fair rendezvous
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// TableOwner returns the node that runs a table's sync actor: its pinned node, otherwise the hashed owner
+func (c *ClusterConfig) TableOwner(tc TableConfig) string {
+	if tc.Node != "" {
+		return tc.Node
+	}
+	return c.Owner(tc.TargetTable)
+}
+
+// IsLocal reports whether work identified by owner runs on this instance; always true outside cluster mode
+func (c *ClusterConfig) IsLocal(owner string) bool {
+	return !c.Enabled || owner == c.NodeID
+}
+
+// validate checks the node list and that this instance is one of its nodes
+func (c *ClusterConfig) validate(tables []TableConfig) error {
+	seen := make(map[string]bool)
+	for _, node := range c.Nodes {
+		if node.ID == "" {
+			return fmt.Errorf("cluster nodes require an id")
+		}
+		if seen[node.ID] {
+			return fmt.Errorf("duplicate cluster node %s", node.ID)
+		}
+		seen[node.ID] = true
+		if _, err := node.Port(); err != nil {
+			return fmt.Errorf("cluster node %s: address must be host:port: %w", node.ID, err)
+		}
+	}
+	if !seen[c.NodeID] {
+		return fmt.Errorf("cluster node_id %q is not one of the configured nodes", c.NodeID)
+	}
+	for _, tc := range tables {
+		if tc.Node != "" && !seen[tc.Node] {
+			return fmt.Errorf("table %s: node %s is not a configured cluster node", tc.TargetTable, tc.Node)
+		}
+	}
+	return nil
+}
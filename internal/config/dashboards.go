@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DashboardConfig is a screen composed of projection widgets laid out on a grid, served by GET /api/dashboards
+type DashboardConfig struct {
+	ID          string            `yaml:"id" json:"id"`
+	Title       string            `yaml:"title" json:"title"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Order       int               `yaml:"order,omitempty" json:"order"`     // position among dashboards, lowest first; ties keep config order
+	Columns     int               `yaml:"columns,omitempty" json:"columns"` // grid columns (default 2)
+	Widgets     []DashboardWidget `yaml:"widgets" json:"widgets"`           // laid out left to right, top to bottom
+}
+
+// DashboardWidget places a projection on a dashboard with its own default filters and sort
+type DashboardWidget struct {
+	Projection string                `yaml:"projection" json:"projection"`               // projection id
+	Title      string                `yaml:"title,omitempty" json:"title,omitempty"`     // defaults to the projection title
+	Width      int                   `yaml:"width,omitempty" json:"width"`               // grid columns spanned (default 1)
+	Filters    map[string]string     `yaml:"filters,omitempty" json:"filters,omitempty"` // default values by projection filter id, select values comma separated
+	Sort       *ProjectionSortConfig `yaml:"sort,omitempty" json:"sort,omitempty"`       // overrides the projection's default_sort
+}
+
+// GetColumns returns the number of grid columns of the dashboard
+func (d *DashboardConfig) GetColumns() int {
+	if d.Columns <= 0 {
+		return 2
+	}
+	return d.Columns
+}
+
+// GetWidth returns the grid columns a widget spans, at most the dashboard's columns
+func (w *DashboardWidget) GetWidth(columns int) int {
+	if w.Width <= 0 {
+		return 1
+	}
+	if w.Width > columns {
+		return columns
+	}
+	return w.Width
+}
+
+// GetDashboardByID returns a dashboard configuration by its identifier
+func (c *Config) GetDashboardByID(id string) (*DashboardConfig, bool) {
+	for i := range c.Dashboards {
+		if c.Dashboards[i].ID == id {
+			return &c.Dashboards[i], true
+		}
+	}
+	return nil, false
+}
+
+// validateDashboards checks that dashboards have unique ids and that widgets reference configured projections and their filters
+func validateDashboards(dashboards []DashboardConfig, projections []ProjectionConfig) error {
+	byID := make(map[string]*ProjectionConfig, len(projections))
+	for i := range projections {
+		byID[projections[i].ID] = &projections[i]
+	}
+
+	seen := make(map[string]bool)
+	for _, dashboard := range dashboards {
+		if dashboard.ID == "" {
+			return fmt.Errorf("dashboards require an id")
+		}
+		if seen[dashboard.ID] {
+			return fmt.Errorf("duplicate dashboard %s", dashboard.ID)
+		}
+		seen[dashboard.ID] = true
+
+		for i, widget := range dashboard.Widgets {
+			projection, ok := byID[widget.Projection]
+			if !ok {
+				return fmt.Errorf("dashboard %s: widget %d: unknown projection %q", dashboard.ID, i+1, widget.Projection)
+			}
+			for filterID := range widget.Filters {
+				if !hasProjectionFilter(projection, filterID) {
+					return fmt.Errorf("dashboard %s: widget %d: projection %s has no filter %s", dashboard.ID, i+1, projection.ID, filterID)
+				}
+			}
+			if widget.Sort != nil {
+				switch strings.ToUpper(widget.Sort.Direction) {
+				case "", "ASC", "DESC":
+				default:
+					return fmt.Errorf("dashboard %s: widget %d: sort direction must be ASC or DESC", dashboard.ID, i+1)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasProjectionFilter(projection *ProjectionConfig, id string) bool {
+	for _, filter := range projection.Filters {
+		if filter.ID == id {
+			return true
+		}
+	}
+	return false
+}
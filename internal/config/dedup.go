@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Duplicate key policies
+const (
+	DedupKeepFirst  = "keep_first"  // the first row read with a key is loaded
+	DedupKeepLatest = "keep_latest" // the row with the greatest value of the by column is loaded
+	DedupFail       = "fail"        // duplicate keys fail the sync before the load
+)
+
+// Dedup drops rows of a source read whose key repeats, so unique constraints on the target don't abort the load
+type Dedup struct {
+	Keys   []string `yaml:"keys,omitempty"`   // key columns, defaults to the table's keys
+	Policy string   `yaml:"policy,omitempty"` // keep_first (default), keep_latest or fail
+	By     string   `yaml:"by,omitempty"`     // column compared by keep_latest
+}
+
+// GetKeys returns the columns identifying duplicate rows of the table
+func (d *Dedup) GetKeys(tc *TableConfig) []string {
+	if len(d.Keys) > 0 {
+		return d.Keys
+	}
+	return tc.Keys
+}
+
+// GetPolicy returns how duplicate keys are handled
+func (d *Dedup) GetPolicy() string {
+	switch strings.ToLower(d.Policy) {
+	case DedupKeepLatest:
+		return DedupKeepLatest
+	case DedupFail:
+		return DedupFail
+	default:
+		return DedupKeepFirst
+	}
+}
+
+func validateDedup(tc TableConfig) error {
+	d := tc.Dedup
+	if len(d.GetKeys(&tc)) == 0 {
+		return fmt.Errorf("dedup requires keys, on the table or the dedup block")
+	}
+	switch strings.ToLower(d.Policy) {
+	case "", DedupKeepFirst, DedupFail:
+	case DedupKeepLatest:
+		if d.By == "" {
+			return fmt.Errorf("dedup keep_latest requires a by column")
+		}
+	default:
+		return fmt.Errorf("dedup policy must be %s, %s or %s", DedupKeepFirst, DedupKeepLatest, DedupFail)
+	}
+	return nil
+}
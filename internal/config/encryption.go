@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Handling of SQL Server Always Encrypted columns
+const (
+	EncryptedSkip       = "skip"       // leave encrypted columns out of the target
+	EncryptedCiphertext = "ciphertext" // copy the encrypted values as BYTEA
+	EncryptedDecrypt    = "decrypt"    // decrypt the values with the configured column master keys
+)
+
+// ColumnEncryption holds the column master keys used to decrypt Always Encrypted columns of the source
+type ColumnEncryption struct {
+	MasterKeys []ColumnMasterKey `yaml:"master_keys"`
+}
+
+// ColumnMasterKey maps a column master key of the source to the file holding its RSA private key
+type ColumnMasterKey struct {
+	KeyPath        string `yaml:"key_path"`         // key_path of sys.column_master_keys, e.g. CurrentUser/My/<thumbprint>
+	PrivateKeyFile string `yaml:"private_key_file"` // PEM encoded PKCS #1 or PKCS #8 private key
+}
+
+// MasterKey returns the configured master key with the given key path, compared case-insensitively
+func (ce *ColumnEncryption) MasterKey(keyPath string) (*ColumnMasterKey, bool) {
+	if ce == nil {
+		return nil, false
+	}
+	for i := range ce.MasterKeys {
+		if strings.EqualFold(ce.MasterKeys[i].KeyPath, keyPath) {
+			return &ce.MasterKeys[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetEncryptedColumns returns how the table's Always Encrypted columns are synced
+func (tc *TableConfig) GetEncryptedColumns(defaults DefaultConfig) string {
+	mode := tc.EncryptedColumns
+	if mode == "" {
+		mode = defaults.EncryptedColumns
+	}
+	switch strings.ToLower(mode) {
+	case EncryptedCiphertext:
+		return EncryptedCiphertext
+	case EncryptedDecrypt:
+		return EncryptedDecrypt
+	default:
+		return EncryptedSkip
+	}
+}
+
+// validateEncryptedColumns checks an encrypted_columns setting, which can only decrypt with master keys configured
+func validateEncryptedColumns(mode string, encryption *ColumnEncryption) error {
+	switch strings.ToLower(mode) {
+	case "", EncryptedSkip, EncryptedCiphertext:
+		return nil
+	case EncryptedDecrypt:
+		if encryption == nil || len(encryption.MasterKeys) == 0 {
+			return fmt.Errorf("encrypted_columns: decrypt requires source.column_encryption.master_keys")
+		}
+		for _, key := range encryption.MasterKeys {
+			if key.KeyPath == "" || key.PrivateKeyFile == "" {
+				return fmt.Errorf("column_encryption master keys require key_path and private_key_file")
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("encrypted_columns must be %s, %s or %s", EncryptedSkip, EncryptedCiphertext, EncryptedDecrypt)
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables configuring the service, merged over the config file and its overlays
+const (
+	EnvConfigJSON   = "SYNC_CONFIG_JSON" // the whole configuration as one JSON (or YAML) document
+	EnvConfigPrefix = "SYNC__"           // single settings by path, e.g. SYNC__SOURCE__HOST or SYNC__TABLES__0__TARGET_TABLE
+)
+
+// envScalar is a setting taken from an environment variable. It is emitted as written, so it decodes exactly like
+// the same text in the config file: SYNC__API__PORT=8080 sets a number and a password of 0123 keeps its zero
+type envScalar string
+
+// MarshalYAML emits the setting as an untagged scalar
+func (s envScalar) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: string(s)}, nil
+}
+
+// envList is a list built from indexed settings such as SYNC__TABLES__0__REFRESH_RATE. It only holds the entries
+// that were set, nil elsewhere, and merges into the list it overlays by index instead of replacing it
+type envList []interface{}
+
+// envOverlay returns the configuration given by environment variables in KEY=value form, nil when there is none.
+// The SYNC_CONFIG_JSON document comes first and SYNC__ settings are merged over it
+func envOverlay(environ []string) (map[string]interface{}, error) {
+	var overlay map[string]interface{}
+	var settings []string
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		switch {
+		case key == EnvConfigJSON && strings.TrimSpace(value) != "":
+			if err := yaml.Unmarshal([]byte(value), &overlay); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", EnvConfigJSON, err)
+			}
+			if overlay == nil {
+				overlay = make(map[string]interface{})
+			}
+		case strings.HasPrefix(key, EnvConfigPrefix) && len(key) > len(EnvConfigPrefix):
+			settings = append(settings, entry)
+		}
+	}
+
+	// Sorted, so settings of the same list apply in a stable order
+	sort.Strings(settings)
+	for _, entry := range settings {
+		key, raw, _ := strings.Cut(entry, "=")
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(key, EnvConfigPrefix)), "__")
+
+		// Lists and maps, e.g. SYNC__TABLES='[{"source_table": "dbo.Users", ...}]', are given as JSON
+		var value interface{} = envScalar(raw)
+		if trimmed := strings.TrimSpace(raw); strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+			if err := yaml.Unmarshal([]byte(trimmed), &value); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+			}
+		}
+
+		merged, err := setEnvValue(overlay, path, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		overlay = merged.(map[string]interface{})
+	}
+	return overlay, nil
+}
+
+// setEnvValue sets the value at a path of map keys and list indexes below node, creating what is missing, and
+// returns the updated node. Indexes into a list given as JSON set its entries; others build an envList
+func setEnvValue(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return mergeValue(node, value), nil
+	}
+
+	segment := path[0]
+	if segment == "" {
+		return nil, fmt.Errorf("empty path segment")
+	}
+	if index, err := strconv.Atoi(segment); err == nil {
+		if index < 0 {
+			return nil, fmt.Errorf("negative index %d", index)
+		}
+		if list, ok := node.([]interface{}); ok {
+			for len(list) <= index {
+				list = append(list, map[string]interface{}{})
+			}
+			item, err := setEnvValue(list[index], path[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			list[index] = item
+			return list, nil
+		}
+
+		list, ok := node.(envList)
+		if node != nil && !ok {
+			return nil, fmt.Errorf("index %d given for a setting that is not a list", index)
+		}
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+		item, err := setEnvValue(list[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		list[index] = item
+		return list, nil
+	}
+
+	entries, ok := node.(map[string]interface{})
+	if node != nil && !ok {
+		return nil, fmt.Errorf("%s given for a setting that is not a section", segment)
+	}
+	if entries == nil {
+		entries = make(map[string]interface{})
+	}
+	child, err := setEnvValue(entries[segment], path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	entries[segment] = child
+	return entries, nil
+}
+
+// mergeEnvList merges the entries of an envList into base by index. Lists grow to reach an index with empty entries
+func mergeEnvList(base interface{}, overlay envList) []interface{} {
+	b, _ := base.([]interface{})
+	merged := make([]interface{}, len(b), max(len(b), len(overlay)))
+	copy(merged, b)
+	for i, item := range overlay {
+		if i >= len(merged) {
+			merged = append(merged, map[string]interface{}{})
+		}
+		if item != nil {
+			merged[i] = mergeValue(merged[i], item)
+		}
+	}
+	return merged
+}
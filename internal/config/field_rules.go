@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FieldRule is a conditional formatting rule of a projection field. Rules are evaluated on the server for every row
+// of a data response, in order, and the first matching one annotates the field's cell
+type FieldRule struct {
+	Column   string   `yaml:"column,omitempty"` // column tested, defaults to the field's own column
+	Operator string   `yaml:"operator"`         // eq, ne, lt, le, gt, ge, contains, in, null or not_null
+	Value    string   `yaml:"value,omitempty"`  // compared as a number when both sides are numeric, as text otherwise
+	Values   []string `yaml:"values,omitempty"` // values of the in operator
+
+	Color      string `yaml:"color,omitempty"`      // text color, a CSS color name or #hex
+	Background string `yaml:"background,omitempty"` // background color, a CSS color name or #hex
+	Bold       bool   `yaml:"bold,omitempty"`
+	Badge      bool   `yaml:"badge,omitempty"` // show the value as a badge
+}
+
+// Field rule operators
+const (
+	RuleEq       = "eq"
+	RuleNe       = "ne"
+	RuleLt       = "lt"
+	RuleLe       = "le"
+	RuleGt       = "gt"
+	RuleGe       = "ge"
+	RuleContains = "contains"
+	RuleIn       = "in"
+	RuleNull     = "null"
+	RuleNotNull  = "not_null"
+)
+
+var ruleColorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{3,8}|[A-Za-z]+)$`)
+
+// GetColumn returns the column the rule tests for a field
+func (r *FieldRule) GetColumn(field *ProjectionFieldConfig) string {
+	if r.Column == "" {
+		return field.Column
+	}
+	return r.Column
+}
+
+// HasRules reports whether any field of the projection has conditional formatting rules
+func (p *ProjectionConfig) HasRules() bool {
+	for _, field := range p.Fields {
+		if len(field.Rules) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldRules checks the operators, operands and styles of a projection's field rules, and that the columns
+// they test are fields of the projection
+func validateFieldRules(projection *ProjectionConfig) error {
+	for i := range projection.Fields {
+		field := &projection.Fields[i]
+		for j := range field.Rules {
+			rule := &field.Rules[j]
+			rule.Operator = strings.ToLower(rule.Operator)
+			if err := validateFieldRule(projection, field, rule); err != nil {
+				return fmt.Errorf("projection %s: field %s: rule %d: %w", projection.ID, field.Column, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateFieldRule(projection *ProjectionConfig, field *ProjectionFieldConfig, rule *FieldRule) error {
+	switch rule.Operator {
+	case RuleEq, RuleNe, RuleLt, RuleLe, RuleGt, RuleGe, RuleContains:
+		if rule.Value == "" && rule.Operator != RuleEq && rule.Operator != RuleNe {
+			return fmt.Errorf("operator %s requires a value", rule.Operator)
+		}
+	case RuleIn:
+		if len(rule.Values) == 0 {
+			return fmt.Errorf("operator in requires values")
+		}
+	case RuleNull, RuleNotNull:
+	default:
+		return fmt.Errorf("operator must be eq, ne, lt, le, gt, ge, contains, in, null or not_null")
+	}
+
+	for _, color := range []string{rule.Color, rule.Background} {
+		if color != "" && !ruleColorPattern.MatchString(color) {
+			return fmt.Errorf("invalid color %q", color)
+		}
+	}
+	if rule.Color == "" && rule.Background == "" && !rule.Bold && !rule.Badge {
+		return fmt.Errorf("requires color, background, bold or badge")
+	}
+
+	column := rule.GetColumn(field)
+	for _, other := range projection.Fields {
+		if strings.EqualFold(other.Column, column) {
+			return nil
+		}
+	}
+	return fmt.Errorf("column %s is not a field", column)
+}
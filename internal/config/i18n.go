@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Translations maps BCP 47 language tags (e.g. th, th-TH) to the text of a title or label in that language
+type Translations map[string]string
+
+// Get returns the text for a language, falling back to its base language (th for th-TH) and then to text, the
+// label in ui.language
+func (t Translations) Get(lang, text string) string {
+	if lang == "" || len(t) == 0 {
+		return text
+	}
+	if translated, ok := t[lang]; ok && translated != "" {
+		return translated
+	}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		if translated, ok := t[base]; ok && translated != "" {
+			return translated
+		}
+	}
+	return text
+}
+
+// GetLanguage returns the language of the titles and labels written without translations
+func (u *UIConfig) GetLanguage() string {
+	if u.Language == "" {
+		return "en"
+	}
+	return u.Language
+}
+
+// translations calls fn with every translation map of the configuration
+func (c *Config) translations(fn func(owner string, t *Translations) error) error {
+	for i := range c.Projections {
+		p := &c.Projections[i]
+		owner := "projection " + p.ID
+		for _, t := range []*Translations{&p.Titles, &p.Descriptions} {
+			if err := fn(owner, t); err != nil {
+				return err
+			}
+		}
+		for j := range p.Fields {
+			if err := fn(owner+": field "+p.Fields[j].Column, &p.Fields[j].Labels); err != nil {
+				return err
+			}
+		}
+		for j := range p.Filters {
+			filter := &p.Filters[j]
+			if err := fn(owner+": filter "+filter.ID, &filter.Labels); err != nil {
+				return err
+			}
+			for k := range filter.Options {
+				if err := fn(owner+": filter "+filter.ID+": option "+filter.Options[k].Value, &filter.Options[k].Labels); err != nil {
+					return err
+				}
+			}
+		}
+		for j := range p.Totals {
+			if err := fn(owner+": total "+p.Totals[j].Column, &p.Totals[j].Labels); err != nil {
+				return err
+			}
+		}
+		for j := range p.Actions {
+			if err := fn(owner+": action "+p.Actions[j].ID, &p.Actions[j].Labels); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range c.UI.Menu {
+		if err := fn("ui: menu group "+c.UI.Menu[i].ID, &c.UI.Menu[i].Labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTranslations checks that ui.language and the keys of every translation map are BCP 47 tags, and
+// rewrites them in canonical form so requests match them regardless of case
+func validateTranslations(c *Config) error {
+	tag, err := language.Parse(c.UI.GetLanguage())
+	if err != nil {
+		return fmt.Errorf("ui: invalid language %q", c.UI.Language)
+	}
+	c.UI.Language = tag.String()
+
+	return c.translations(func(owner string, t *Translations) error {
+		if len(*t) == 0 {
+			return nil
+		}
+		canonical := make(Translations, len(*t))
+		for key, text := range *t {
+			tag, err := language.Parse(key)
+			if err != nil {
+				return fmt.Errorf("%s: invalid language %q", owner, key)
+			}
+			canonical[tag.String()] = text
+		}
+		*t = canonical
+		return nil
+	})
+}
+
+// Languages returns ui.language followed by the other languages titles and labels are translated to, sorted
+func (c *Config) Languages() []string {
+	seen := map[string]bool{c.UI.GetLanguage(): true}
+	var others []string
+	c.translations(func(_ string, t *Translations) error {
+		for lang := range *t {
+			if !seen[lang] {
+				seen[lang] = true
+				others = append(others, lang)
+			}
+		}
+		return nil
+	})
+	sort.Strings(others)
+	return append([]string{c.UI.GetLanguage()}, others...)
+}
+
+// Localize returns a copy of the projection with its title, description and labels in the given language, where
+// translated. The projection is returned as is for ui.language
+func (p *ProjectionConfig) Localize(lang string) ProjectionConfig {
+	localized := *p
+	if lang == "" {
+		return localized
+	}
+
+	localized.Title = p.Titles.Get(lang, p.Title)
+	localized.Description = p.Descriptions.Get(lang, p.Description)
+
+	localized.Fields = make([]ProjectionFieldConfig, len(p.Fields))
+	for i, field := range p.Fields {
+		field.Label = field.Labels.Get(lang, field.Label)
+		localized.Fields[i] = field
+	}
+
+	localized.Filters = make([]ProjectionFilterConfig, len(p.Filters))
+	for i, filter := range p.Filters {
+		filter.Label = filter.Labels.Get(lang, filter.Label)
+		options := make([]ProjectionFilterOptionConfig, len(filter.Options))
+		for j, option := range filter.Options {
+			option.Label = option.Labels.Get(lang, option.Label)
+			options[j] = option
+		}
+		filter.Options = options
+		localized.Filters[i] = filter
+	}
+
+	localized.Totals = make([]ProjectionTotalConfig, len(p.Totals))
+	for i, total := range p.Totals {
+		total.Label = total.Labels.Get(lang, total.Label)
+		localized.Totals[i] = total
+	}
+
+	localized.Actions = make([]ProjectionAction, len(p.Actions))
+	for i, action := range p.Actions {
+		action.Label = action.Labels.Get(lang, action.Label)
+		localized.Actions[i] = action
+	}
+	return localized
+}
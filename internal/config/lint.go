@@ -0,0 +1,39 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownFieldPattern matches the errors of settings without a configuration field reported by strict decoding
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type`)
+
+// Raw returns the document the configuration was parsed from, nil when it was not parsed by LoadConfig or ParseConfig
+func (c *Config) Raw() []byte {
+	return c.raw
+}
+
+// UnknownFields returns the settings of a configuration document that match no configuration field, such as
+// misspelled keys, which parsing silently ignores
+func UnknownFields(data []byte) []string {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var config Config
+	var typeErr *yaml.TypeError
+	if err := decoder.Decode(&config); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var unknown []string
+	for _, message := range typeErr.Errors {
+		if match := unknownFieldPattern.FindStringSubmatch(message); match != nil {
+			unknown = append(unknown, fmt.Sprintf("line %s: unknown setting %s", match[1], match[2]))
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Oversize policies for binary values above a column's max_bytes
+const (
+	OversizeFail     = "fail"     // the sync fails on the first larger value
+	OversizeTruncate = "truncate" // values are cut to max_bytes on the source
+	OversizeNull     = "null"     // larger values are loaded as NULL
+)
+
+// GetOversize returns how values above the column's max_bytes are handled
+func (cc *ColumnConfig) GetOversize() string {
+	switch strings.ToLower(cc.Oversize) {
+	case OversizeTruncate:
+		return OversizeTruncate
+	case OversizeNull:
+		return OversizeNull
+	default:
+		return OversizeFail
+	}
+}
+
+// IsLargeObject reports whether the column's binary values are capped or stored outside the target
+func (cc *ColumnConfig) IsLargeObject() bool {
+	return cc.MaxBytes > 0 || cc.Storage != nil
+}
+
+func validateLargeObjects(tc TableConfig) error {
+	for _, cc := range tc.Columns {
+		if cc.MaxBytes < 0 {
+			return fmt.Errorf("column %s: max_bytes must not be negative", cc.Column)
+		}
+		switch strings.ToLower(cc.Oversize) {
+		case "", OversizeFail, OversizeTruncate, OversizeNull:
+		default:
+			return fmt.Errorf("column %s: oversize must be %s, %s or %s", cc.Column, OversizeFail, OversizeTruncate, OversizeNull)
+		}
+		if cc.Oversize != "" && cc.MaxBytes == 0 {
+			return fmt.Errorf("column %s: oversize requires max_bytes", cc.Column)
+		}
+		if cc.Storage != nil {
+			switch strings.ToLower(cc.Storage.Type) {
+			case "", "local", "s3", "azure":
+			default:
+				return fmt.Errorf("column %s: storage type must be local, s3 or azure", cc.Column)
+			}
+		}
+		if cc.IsLargeObject() && (cc.IsJSON() || cc.Collation != "") {
+			return fmt.Errorf("column %s: max_bytes and storage apply to binary columns and cannot be combined with type or collation", cc.Column)
+		}
+	}
+	return nil
+}
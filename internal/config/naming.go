@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Target column naming
+const (
+	NamingSource    = "source"     // target columns keep the source column names
+	NamingSnakeCase = "snake_case" // PascalCase source names become snake_case target names, e.g. OrderID -> order_id
+)
+
+// GetNaming returns how the table's target columns are named
+func (tc *TableConfig) GetNaming(defaults DefaultConfig) string {
+	naming := tc.Naming
+	if naming == "" {
+		naming = defaults.Naming
+	}
+	if strings.EqualFold(naming, NamingSnakeCase) {
+		return NamingSnakeCase
+	}
+	return NamingSource
+}
+
+// TargetColumn returns the target column name of a source column: its configured target, else the name given
+// by the table's naming
+func (tc *TableConfig) TargetColumn(column string, defaults DefaultConfig) string {
+	if columnCfg, ok := tc.GetColumnConfig(column); ok && columnCfg.Target != "" {
+		return columnCfg.Target
+	}
+	if tc.GetNaming(defaults) == NamingSnakeCase {
+		return SnakeCase(column)
+	}
+	return column
+}
+
+// renamesColumns reports whether any target column name can differ from its source column name
+func (tc *TableConfig) renamesColumns(defaults DefaultConfig) bool {
+	if tc.GetNaming(defaults) != NamingSource {
+		return true
+	}
+	for _, columnCfg := range tc.Columns {
+		if columnCfg.Target != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SnakeCase converts a PascalCase or camelCase name to snake_case. Acronyms stay together (HTTPStatus -> http_status),
+// digits stay with the preceding word (AddressLine1 -> address_line1) and other characters become underscores
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// TargetColumn returns the view column of a configured projection column. When the projection's sync table renames
+// its columns, projections can name them by their source names and the API returns them under those names
+func (p *ProjectionConfig) TargetColumn(column string) string {
+	if p.targetColumn == nil {
+		return column
+	}
+	return p.targetColumn(column)
+}
+
+// resolveNaming maps the projection's column names through the naming of its sync table
+func (p *ProjectionConfig) resolveNaming(tables []TableConfig, defaults DefaultConfig) {
+	for i := range tables {
+		tc := &tables[i]
+		if tc.TargetTable != p.SyncTable || !tc.renamesColumns(defaults) {
+			continue
+		}
+		p.targetColumn = func(column string) string {
+			return tc.TargetColumn(column, defaults)
+		}
+		return
+	}
+}
+
+// validateNaming checks a naming setting
+func validateNaming(naming string) error {
+	switch strings.ToLower(naming) {
+	case "", NamingSource, NamingSnakeCase:
+		return nil
+	default:
+		return fmt.Errorf("naming must be %s or %s", NamingSource, NamingSnakeCase)
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlayKeys identify list entries that overlays merge into instead of replacing the whole list
+var overlayKeys = []string{"target_table", "id", "name"}
+
+// ProfilePath returns the overlay file for a profile next to the base config, e.g. sync-config.prod.yaml
+func ProfilePath(basePath, profile string) string {
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + profile + ext
+}
+
+// loadMerged reads the base config and deep merges each overlay, then the environment's configuration, on top of
+// it. The base config may be missing when the environment configures the service
+func loadMerged(path string, overlays []string) ([]byte, error) {
+	env, err := envOverlay(os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && env != nil) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if len(overlays) == 0 && env == nil {
+		return data, nil
+	}
+
+	var merged map[string]interface{}
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	for _, overlayPath := range overlays {
+		overlayData, err := os.ReadFile(overlayPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config overlay: %w", err)
+		}
+		var overlay map[string]interface{}
+		if err := yaml.Unmarshal(overlayData, &overlay); err != nil {
+			return nil, fmt.Errorf("failed to parse config overlay %s: %w", overlayPath, err)
+		}
+		merged = mergeMaps(merged, overlay)
+	}
+	if env != nil {
+		merged = mergeMaps(merged, env)
+	}
+
+	return yaml.Marshal(merged)
+}
+
+// mergeMaps deep merges overlay into base. Nested maps merge key by key, lists of keyed entries merge by key,
+// and any other value in the overlay replaces the base value
+func mergeMaps(base, overlay map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{}, len(overlay))
+	}
+	for key, value := range overlay {
+		base[key] = mergeValue(base[key], value)
+	}
+	return base
+}
+
+func mergeValue(base, overlay interface{}) interface{} {
+	switch o := overlay.(type) {
+	case map[string]interface{}:
+		// Merged into an empty map when new, so lists of indexed settings below it become plain lists
+		if b, ok := base.(map[string]interface{}); ok || base == nil {
+			return mergeMaps(b, o)
+		}
+	case envList:
+		return mergeEnvList(base, o)
+	case []interface{}:
+		if b, ok := base.([]interface{}); ok {
+			if key := listKey(b, o); key != "" {
+				return mergeList(b, o, key)
+			}
+		}
+	}
+	return overlay
+}
+
+// listKey returns the identifying key shared by every entry of both lists, if any
+func listKey(base, overlay []interface{}) string {
+	for _, key := range overlayKeys {
+		if allHaveKey(base, key) && allHaveKey(overlay, key) {
+			return key
+		}
+	}
+	return ""
+}
+
+func allHaveKey(list []interface{}, key string) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := entry[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeList merges overlay entries into base entries with the same key and appends new ones
+func mergeList(base, overlay []interface{}, key string) []interface{} {
+	index := make(map[interface{}]int, len(base))
+	for i, item := range base {
+		index[item.(map[string]interface{})[key]] = i
+	}
+
+	for _, item := range overlay {
+		entry := item.(map[string]interface{})
+		if i, ok := index[entry[key]]; ok {
+			base[i] = mergeMaps(base[i].(map[string]interface{}), entry)
+			continue
+		}
+		base = append(base, entry)
+	}
+	return base
+}
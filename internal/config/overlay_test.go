@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadMergedIndexedEnv(t *testing.T) {
+	const file = `
+tables:
+  - source_table: dbo.Orders
+    target_table: public.orders
+    refresh_rate: 300
+    columns:
+      - column: Status
+        null_policy: pass
+  - source_table: dbo.Customers
+    target_table: public.customers
+    refresh_rate: 600
+`
+	orders := map[string]interface{}{
+		"source_table": "dbo.Orders", "target_table": "public.orders", "refresh_rate": 300,
+		"columns": []interface{}{map[string]interface{}{"column": "Status", "null_policy": "pass"}},
+	}
+	customers := map[string]interface{}{
+		"source_table": "dbo.Customers", "target_table": "public.customers", "refresh_rate": 600,
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []interface{}
+	}{
+		{
+			name: "setting of the first table",
+			env:  map[string]string{"SYNC__TABLES__0__REFRESH_RATE": "60"},
+			want: []interface{}{with(orders, "refresh_rate", 60), customers},
+		},
+		{
+			name: "setting of the second table",
+			env:  map[string]string{"SYNC__TABLES__1__REFRESH_RATE": "60"},
+			want: []interface{}{orders, with(customers, "refresh_rate", 60)},
+		},
+		{
+			name: "settings of both tables",
+			env: map[string]string{
+				"SYNC__TABLES__0__FILTER":       "IsActive = 1",
+				"SYNC__TABLES__1__REFRESH_RATE": "60",
+			},
+			want: []interface{}{with(orders, "filter", "IsActive = 1"), with(customers, "refresh_rate", 60)},
+		},
+		{
+			name: "nested list",
+			env:  map[string]string{"SYNC__TABLES__0__COLUMNS__0__NULL_POLICY": "fail"},
+			want: []interface{}{
+				with(orders, "columns", []interface{}{map[string]interface{}{"column": "Status", "null_policy": "fail"}}),
+				customers,
+			},
+		},
+		{
+			name: "index past the end appends",
+			env: map[string]string{
+				"SYNC__TABLES__2__SOURCE_TABLE": "dbo.Products",
+				"SYNC__TABLES__2__TARGET_TABLE": "public.products",
+			},
+			want: []interface{}{orders, customers,
+				map[string]interface{}{"source_table": "dbo.Products", "target_table": "public.products"}},
+		},
+		{
+			name: "JSON entry merges into the indexed table",
+			env:  map[string]string{"SYNC__TABLES__1": `{"refresh_rate": 60, "filter": "IsActive = 1"}`},
+			want: []interface{}{orders, with(with(customers, "refresh_rate", 60), "filter", "IsActive = 1")},
+		},
+		{
+			name: "JSON list merges by target table",
+			env: map[string]string{"SYNC__TABLES": `[{"target_table": "public.customers", "refresh_rate": 60},
+				{"source_table": "dbo.Products", "target_table": "public.products"}]`},
+			want: []interface{}{orders, with(customers, "refresh_rate", 60),
+				map[string]interface{}{"source_table": "dbo.Products", "target_table": "public.products"}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "sync-config.yaml")
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			data, err := loadMerged(path, nil)
+			if err != nil {
+				t.Fatalf("loadMerged: %v", err)
+			}
+			var merged map[string]interface{}
+			if err := yaml.Unmarshal(data, &merged); err != nil {
+				t.Fatalf("merged config does not parse: %v", err)
+			}
+			if got := merged["tables"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tables = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMergedIndexedEnvWithoutFile(t *testing.T) {
+	t.Setenv("SYNC__TABLES__1__TARGET_TABLE", "public.orders")
+
+	data, err := loadMerged(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	if err != nil {
+		t.Fatalf("loadMerged: %v", err)
+	}
+	var merged map[string]interface{}
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		t.Fatalf("merged config does not parse: %v", err)
+	}
+	want := []interface{}{map[string]interface{}{}, map[string]interface{}{"target_table": "public.orders"}}
+	if got := merged["tables"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("tables = %v, want %v", got, want)
+	}
+}
+
+// with returns a copy of entry with key set to value
+func with(entry map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(entry)+1)
+	for k, v := range entry {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Projection types
+const (
+	ProjectionTypeView              = "view"               // reads a synced target view (default)
+	ProjectionTypeSourcePassthrough = "source_passthrough" // runs a query on the source for every request, without syncing
+)
+
+// Passthrough parameter types
+const (
+	ParameterString = "string"
+	ParameterInt    = "int"
+	ParameterNumber = "number"
+	ParameterBool   = "bool"
+	ParameterDate   = "date"
+)
+
+// ProjectionPassthrough is the source query of a source_passthrough projection, meant for small lookup views
+// that are not worth syncing
+type ProjectionPassthrough struct {
+	Query      string                 `yaml:"query"` // read-only SELECT on the source, referencing parameters as @name
+	Parameters []PassthroughParameter `yaml:"parameters,omitempty"`
+	CacheTTL   *int                   `yaml:"cache_ttl,omitempty"` // seconds responses are cached per parameter values (default 60, 0 disables)
+}
+
+// PassthroughParameter is a query string parameter passed to a passthrough query as a typed argument
+type PassthroughParameter struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type,omitempty"` // string (default), int, number, bool or date (YYYY-MM-DD)
+	Required bool     `yaml:"required,omitempty"`
+	Default  *string  `yaml:"default,omitempty"` // used when the request leaves the parameter out; NULL when unset
+	Allowed  []string `yaml:"allowed,omitempty"` // accepted values, any when empty
+	Pattern  string   `yaml:"pattern,omitempty"` // regular expression string values must match
+
+	pattern *regexp.Regexp
+}
+
+var (
+	parameterNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	parameterReferencePattern = regexp.MustCompile(`@@?[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// IsSourcePassthrough reports whether the projection queries the source directly
+func (p *ProjectionConfig) IsSourcePassthrough() bool {
+	return strings.EqualFold(p.Type, ProjectionTypeSourcePassthrough)
+}
+
+// GetCacheTTL returns how long passthrough responses are cached, 0 when they are not
+func (p *ProjectionPassthrough) GetCacheTTL() time.Duration {
+	if p.CacheTTL == nil {
+		return time.Minute
+	}
+	return time.Duration(*p.CacheTTL) * time.Second
+}
+
+// Statement returns the query without surrounding whitespace or a trailing semicolon
+func (p *ProjectionPassthrough) Statement() string {
+	return strings.TrimSuffix(strings.TrimSpace(p.Query), ";")
+}
+
+// GetType returns the type of the parameter
+func (p *PassthroughParameter) GetType() string {
+	if p.Type == "" {
+		return ParameterString
+	}
+	return strings.ToLower(p.Type)
+}
+
+// Parse validates a request value of the parameter and converts it to its query argument
+func (p *PassthroughParameter) Parse(raw string) (interface{}, error) {
+	if len(p.Allowed) > 0 {
+		allowed := false
+		for _, value := range p.Allowed {
+			if value == raw {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(p.Allowed, ", "))
+		}
+	}
+
+	switch p.GetType() {
+	case ParameterInt:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return value, nil
+	case ParameterNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return value, nil
+	case ParameterBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return value, nil
+	case ParameterDate:
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a date (YYYY-MM-DD)")
+		}
+		return value, nil
+	default:
+		if p.pattern != nil && !p.pattern.MatchString(raw) {
+			return nil, fmt.Errorf("must match %s", p.Pattern)
+		}
+		return raw, nil
+	}
+}
+
+// validatePassthrough checks a projection's type and, for source_passthrough projections, that the query is
+// read-only and references exactly the declared parameters. Features built on a target view are rejected
+func validatePassthrough(projection *ProjectionConfig) error {
+	switch strings.ToLower(projection.Type) {
+	case "", ProjectionTypeView:
+		if projection.Passthrough != nil {
+			return fmt.Errorf("projection %s: passthrough requires type %s", projection.ID, ProjectionTypeSourcePassthrough)
+		}
+		return nil
+	case ProjectionTypeSourcePassthrough:
+	default:
+		return fmt.Errorf("projection %s: type must be %s or %s", projection.ID, ProjectionTypeView, ProjectionTypeSourcePassthrough)
+	}
+
+	passthrough := projection.Passthrough
+	if passthrough == nil {
+		return fmt.Errorf("projection %s: type %s requires passthrough", projection.ID, ProjectionTypeSourcePassthrough)
+	}
+	if err := ValidateSourceQuery(passthrough.Query); err != nil {
+		return fmt.Errorf("projection %s: invalid passthrough query: %w", projection.ID, err)
+	}
+	if passthrough.CacheTTL != nil && *passthrough.CacheTTL < 0 {
+		return fmt.Errorf("projection %s: cache_ttl must not be negative", projection.ID)
+	}
+	switch {
+	case projection.TargetView != "" || projection.SyncTable != "":
+		return fmt.Errorf("projection %s: source_passthrough projections have no target_view or sync_table", projection.ID)
+	case len(projection.Filters) > 0 || len(projection.DefaultSort) > 0:
+		return fmt.Errorf("projection %s: source_passthrough projections take parameters instead of filters and default_sort", projection.ID)
+	case len(projection.Key) > 0 || len(projection.Relations) > 0 || projection.Timeseries != nil:
+		return fmt.Errorf("projection %s: key, relations and timeseries require a target view", projection.ID)
+	}
+
+	declared := make(map[string]bool, len(passthrough.Parameters))
+	for i := range passthrough.Parameters {
+		param := &passthrough.Parameters[i]
+		if !parameterNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("projection %s: invalid parameter name %q", projection.ID, param.Name)
+		}
+		if declared[strings.ToLower(param.Name)] {
+			return fmt.Errorf("projection %s: parameter %s is declared twice", projection.ID, param.Name)
+		}
+		declared[strings.ToLower(param.Name)] = true
+
+		switch param.GetType() {
+		case ParameterString, ParameterInt, ParameterNumber, ParameterBool, ParameterDate:
+		default:
+			return fmt.Errorf("projection %s: parameter %s: type must be string, int, number, bool or date", projection.ID, param.Name)
+		}
+		if param.Pattern != "" {
+			if param.GetType() != ParameterString {
+				return fmt.Errorf("projection %s: parameter %s: pattern only applies to strings", projection.ID, param.Name)
+			}
+			compiled, err := regexp.Compile(param.Pattern)
+			if err != nil {
+				return fmt.Errorf("projection %s: parameter %s: invalid pattern: %w", projection.ID, param.Name, err)
+			}
+			param.pattern = compiled
+		}
+		if param.Default != nil {
+			if _, err := param.Parse(*param.Default); err != nil {
+				return fmt.Errorf("projection %s: parameter %s: default %w", projection.ID, param.Name, err)
+			}
+		}
+	}
+
+	// SQL Server names are case-insensitive; @@ names are system functions such as @@ROWCOUNT
+	referenced := make(map[string]bool)
+	for _, reference := range parameterReferencePattern.FindAllString(passthrough.Query, -1) {
+		if strings.HasPrefix(reference, "@@") {
+			continue
+		}
+		name := strings.ToLower(reference[1:])
+		if !declared[name] {
+			return fmt.Errorf("projection %s: passthrough query references undeclared parameter %s", projection.ID, reference)
+		}
+		referenced[name] = true
+	}
+	for _, param := range passthrough.Parameters {
+		if !referenced[strings.ToLower(param.Name)] {
+			return fmt.Errorf("projection %s: parameter %s is not used by the passthrough query", projection.ID, param.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pipeline is a sequence of transformation steps, such as SQL scripts or a dbt run, started when the tables it is
+// built on have finished syncing in a job
+type Pipeline struct {
+	Name    string         `yaml:"name"`
+	After   []string       `yaml:"after"`             // target tables whose syncs in a job must all succeed before the pipeline runs
+	Steps   []PipelineStep `yaml:"steps"`             // run in order, stopping at the first failure
+	Timeout int            `yaml:"timeout,omitempty"` // seconds the whole pipeline may run (default 3600)
+}
+
+// PipelineStep is one step of a pipeline: a SQL script run on the target, or an external command
+type PipelineStep struct {
+	Name    string   `yaml:"name,omitempty"`
+	SQLFile string   `yaml:"sql_file,omitempty"` // file of SQL statements run on the target in one transaction
+	Command []string `yaml:"command,omitempty"`  // program and arguments, e.g. [dbt, run, --select, orders_summary]
+	Dir     string   `yaml:"dir,omitempty"`      // working directory of the command
+}
+
+// GetTimeout returns how long the pipeline may run
+func (p *Pipeline) GetTimeout() time.Duration {
+	if p.Timeout > 0 {
+		return time.Duration(p.Timeout) * time.Second
+	}
+	return time.Hour
+}
+
+// RunsAfter reports whether the pipeline is built on a table
+func (p *Pipeline) RunsAfter(tableName string) bool {
+	for _, after := range p.After {
+		if after == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetName returns the name of the step shown in logs and errors: its name, else its script or program
+func (s *PipelineStep) GetName() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.SQLFile != "":
+		return s.SQLFile
+	case len(s.Command) > 0:
+		return s.Command[0]
+	default:
+		return "step"
+	}
+}
+
+// validatePipelines checks that pipelines are named once, run after configured tables and have runnable steps
+func validatePipelines(config *Config) error {
+	seen := make(map[string]bool, len(config.Pipelines))
+	for _, p := range config.Pipelines {
+		if p.Name == "" {
+			return fmt.Errorf("pipelines: every pipeline requires a name")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("pipelines: %s is defined twice", p.Name)
+		}
+		seen[p.Name] = true
+
+		if len(p.After) == 0 {
+			return fmt.Errorf("pipeline %s: after requires at least one table", p.Name)
+		}
+		for _, tableName := range p.After {
+			if !config.hasTable(tableName) {
+				return fmt.Errorf("pipeline %s: after table %s is not configured", p.Name, tableName)
+			}
+		}
+		if p.Timeout < 0 {
+			return fmt.Errorf("pipeline %s: timeout must not be negative", p.Name)
+		}
+
+		if len(p.Steps) == 0 {
+			return fmt.Errorf("pipeline %s: steps must not be empty", p.Name)
+		}
+		for i, step := range p.Steps {
+			if (step.SQLFile == "") == (len(step.Command) == 0) {
+				return fmt.Errorf("pipeline %s: step %d requires either sql_file or command", p.Name, i+1)
+			}
+			if step.Dir != "" && len(step.Command) == 0 {
+				return fmt.Errorf("pipeline %s: step %d: dir only applies to commands", p.Name, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// hasTable reports whether a target table is configured
+func (c *Config) hasTable(tableName string) bool {
+	for _, tc := range c.Tables {
+		if tc.TargetTable == tableName {
+			return true
+		}
+	}
+	return false
+}
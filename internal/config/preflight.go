@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// Preflight is the source row count check run before each sync of a table, guarding against a configuration
+// pointed at the wrong table
+type Preflight struct {
+	MaxRows int64 `yaml:"max_rows,omitempty"` // abort the sync when the source holds more rows, 0 for no limit
+	Count   bool  `yaml:"count,omitempty"`    // count the rows the sync reads with COUNT_BIG(*) instead of reading table statistics
+}
+
+// GetPreflight returns the preflight check for this table (or default), nil when rows are only estimated
+func (tc *TableConfig) GetPreflight(defaults DefaultConfig) *Preflight {
+	if tc.Preflight != nil {
+		return tc.Preflight
+	}
+	return defaults.Preflight
+}
+
+func (p *Preflight) validate() error {
+	if p.MaxRows < 0 {
+		return fmt.Errorf("preflight max_rows must not be negative")
+	}
+	return nil
+}
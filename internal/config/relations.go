@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProjectionRelationConfig declares a child projection whose rows belong to a row of the projection, returned
+// with the row by GET /api/projections/:id/rows/:key
+type ProjectionRelationConfig struct {
+	Name       string            `yaml:"name" json:"name"`
+	Projection string            `yaml:"projection" json:"projection"`         // child projection id
+	On         map[string]string `yaml:"on" json:"on"`                         // child column: parent column
+	Limit      int               `yaml:"limit,omitempty" json:"limit"`         // most child rows returned (default 100)
+	Sort       string            `yaml:"sort,omitempty" json:"sort,omitempty"` // sort parameter syntax, defaults to the child's default_sort
+}
+
+// GetLimit returns the most child rows returned for a row
+func (r *ProjectionRelationConfig) GetLimit() int {
+	if r.Limit <= 0 {
+		return 100
+	}
+	return r.Limit
+}
+
+// ProjectionKey returns the columns identifying a projection row: its key, or else the keys of its sync table
+func (c *Config) ProjectionKey(projection *ProjectionConfig) []string {
+	if len(projection.Key) > 0 {
+		return projection.Key
+	}
+	for _, table := range c.Tables {
+		if table.TargetTable == projection.SyncTable {
+			return table.Keys
+		}
+	}
+	return nil
+}
+
+// validateRelations checks that relations reference configured projections, and that the parent columns they join
+// on are returned by the parent projection
+func validateRelations(projections []ProjectionConfig) error {
+	byID := make(map[string]*ProjectionConfig, len(projections))
+	for i := range projections {
+		byID[projections[i].ID] = &projections[i]
+	}
+
+	for _, projection := range projections {
+		names := make(map[string]bool, len(projection.Relations))
+		for _, relation := range projection.Relations {
+			if relation.Name == "" {
+				return fmt.Errorf("projection %s: relations require a name", projection.ID)
+			}
+			if names[relation.Name] {
+				return fmt.Errorf("projection %s: duplicate relation %s", projection.ID, relation.Name)
+			}
+			names[relation.Name] = true
+			child, ok := byID[relation.Projection]
+			if !ok {
+				return fmt.Errorf("projection %s: relation %s references unknown projection %q", projection.ID, relation.Name, relation.Projection)
+			}
+			if child.IsSourcePassthrough() {
+				return fmt.Errorf("projection %s: relation %s references source_passthrough projection %s", projection.ID, relation.Name, child.ID)
+			}
+			if len(relation.On) == 0 {
+				return fmt.Errorf("projection %s: relation %s requires on columns", projection.ID, relation.Name)
+			}
+			if len(projection.Fields) == 0 {
+				continue
+			}
+			for _, parent := range relation.On {
+				found := false
+				for _, field := range projection.Fields {
+					if strings.EqualFold(field.Column, parent) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					return fmt.Errorf("projection %s: relation %s joins on %s, which is not a field", projection.ID, relation.Name, parent)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyncActionSCD2 keeps a slowly changing dimension (type 2) history of the table instead of replacing its rows
+const SyncActionSCD2 = "scd2"
+
+// SCD2 configures the row history kept by an scd2 table. Rows are identified by the table's keys
+type SCD2 struct {
+	Compare   []string `yaml:"compare,omitempty"`    // columns whose change starts a new version, defaults to every non-key column
+	ValidFrom string   `yaml:"valid_from,omitempty"` // column holding when a version became current (default valid_from)
+	ValidTo   string   `yaml:"valid_to,omitempty"`   // column holding when a version was replaced, NULL while current (default valid_to)
+}
+
+// IsSCD2 reports whether the table keeps a row history instead of being replaced on every sync
+func (tc *TableConfig) IsSCD2() bool {
+	return strings.EqualFold(tc.SyncAction, SyncActionSCD2)
+}
+
+// GetValidFrom returns the column holding when a version became current
+func (s *SCD2) GetValidFrom() string {
+	if s != nil && s.ValidFrom != "" {
+		return s.ValidFrom
+	}
+	return "valid_from"
+}
+
+// GetValidTo returns the column holding when a version was replaced
+func (s *SCD2) GetValidTo() string {
+	if s != nil && s.ValidTo != "" {
+		return s.ValidTo
+	}
+	return "valid_to"
+}
+
+// GetCompare returns the columns compared to detect a changed row, empty for every non-key column
+func (s *SCD2) GetCompare() []string {
+	if s == nil {
+		return nil
+	}
+	return s.Compare
+}
+
+// validateSCD2 checks that an scd2 table declares its keys and is loaded in one transaction of the whole table
+func validateSCD2(tc TableConfig, defaults DefaultConfig) error {
+	if len(tc.Keys) == 0 {
+		return fmt.Errorf("sync_action %s requires keys", SyncActionSCD2)
+	}
+	if strings.EqualFold(tc.SCD2.GetValidFrom(), tc.SCD2.GetValidTo()) {
+		return fmt.Errorf("scd2 valid_from and valid_to must be different columns")
+	}
+	for _, column := range tc.SCD2.GetCompare() {
+		for _, key := range tc.Keys {
+			if strings.EqualFold(column, key) {
+				return fmt.Errorf("scd2 compare column %s is a key", column)
+			}
+		}
+	}
+	if tc.GetCommitEvery(defaults) > 0 {
+		return fmt.Errorf("sync_action %s requires loads in a single transaction (commit_every 0)", SyncActionSCD2)
+	}
+	if tc.Chunking != nil || tc.Backfill != nil || tc.BlueGreen {
+		return fmt.Errorf("sync_action %s cannot be combined with chunking, backfill or blue_green", SyncActionSCD2)
+	}
+	return nil
+}
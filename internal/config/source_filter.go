@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"mssql-postgres-sync/internal/sqlcheck"
+)
+
+// FilterCondition is a structured source filter condition. Its value is sent as a query parameter, never as SQL text
+type FilterCondition struct {
+	Column string        `yaml:"column"`
+	Op     string        `yaml:"op,omitempty"`     // =, <>, <, <=, >, >=, like, in, not_in, is_null or not_null (default: =)
+	Value  interface{}   `yaml:"value,omitempty"`  // compared value
+	Values []interface{} `yaml:"values,omitempty"` // values of in and not_in
+	Env    string        `yaml:"env,omitempty"`    // environment variable holding the value, comma separated for in and not_in
+}
+
+// Filter condition operators without a value or taking a list of values
+const (
+	FilterIn      = "in"
+	FilterNotIn   = "not_in"
+	FilterIsNull  = "is_null"
+	FilterNotNull = "not_null"
+)
+
+// filterComparisons maps the comparison operators to their SQL form
+var filterComparisons = map[string]string{
+	"=": "=", "==": "=", "eq": "=",
+	"<>": "<>", "!=": "<>", "ne": "<>",
+	"<": "<", "lt": "<",
+	"<=": "<=", "le": "<=",
+	">": ">", "gt": ">",
+	">=": ">=", "ge": ">=",
+	"like": "LIKE",
+}
+
+// GetOp returns the normalized operator of the condition: a SQL comparison or one of the Filter* operators
+func (fc *FilterCondition) GetOp() string {
+	op := strings.ToLower(strings.TrimSpace(fc.Op))
+	if op == "" {
+		return "="
+	}
+	if comparison, ok := filterComparisons[op]; ok {
+		return comparison
+	}
+	return op
+}
+
+// GetValues returns the values compared by the condition, read from the environment when env is set
+func (fc *FilterCondition) GetValues() []interface{} {
+	op := fc.GetOp()
+	if op == FilterIsNull || op == FilterNotNull {
+		return nil
+	}
+	list := op == FilterIn || op == FilterNotIn
+	if fc.Env != "" {
+		value := os.Getenv(fc.Env)
+		if !list {
+			return []interface{}{value}
+		}
+		var values []interface{}
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		return values
+	}
+	if list {
+		return fc.Values
+	}
+	return []interface{}{fc.Value}
+}
+
+// validate checks the condition's operator and that it has the values it compares
+func (fc *FilterCondition) validate() error {
+	if strings.TrimSpace(fc.Column) == "" {
+		return fmt.Errorf("where conditions require a column")
+	}
+	op := fc.GetOp()
+	list := false
+	switch op {
+	case FilterIsNull, FilterNotNull:
+		if fc.Value != nil || len(fc.Values) > 0 || fc.Env != "" {
+			return fmt.Errorf("where %s: %s takes no value", fc.Column, op)
+		}
+		return nil
+	case FilterIn, FilterNotIn:
+		if fc.Value != nil {
+			return fmt.Errorf("where %s: %s takes values, not value", fc.Column, op)
+		}
+		list = true
+	case "=", "<>", "<", "<=", ">", ">=", "LIKE":
+		if len(fc.Values) > 0 {
+			return fmt.Errorf("where %s: %s takes value, not values", fc.Column, op)
+		}
+	default:
+		return fmt.Errorf("where %s: unknown op %q", fc.Column, fc.Op)
+	}
+
+	if fc.Env != "" {
+		if fc.Value != nil || len(fc.Values) > 0 {
+			return fmt.Errorf("where %s: env and value are mutually exclusive", fc.Column)
+		}
+		if _, ok := os.LookupEnv(fc.Env); !ok {
+			return fmt.Errorf("where %s: environment variable %s is not set", fc.Column, fc.Env)
+		}
+	} else if !list && fc.Value == nil {
+		return fmt.Errorf("where %s: %s requires a value", fc.Column, op)
+	}
+	if list && len(fc.GetValues()) == 0 {
+		return fmt.Errorf("where %s: %s requires at least one value", fc.Column, op)
+	}
+	return nil
+}
+
+// ValidateFilter checks that a raw source filter is a single WHERE condition: no statement separators, comments or
+// write keywords, and balanced parentheses outside string literals, so it cannot close the WHERE clause it is put in
+func ValidateFilter(filter string) error {
+	if strings.Contains(filter, ";") {
+		return fmt.Errorf("filter must not contain ;")
+	}
+	if strings.Contains(filter, "--") || strings.Contains(filter, "/*") {
+		return fmt.Errorf("filter must not contain comments")
+	}
+	keyword, err := sqlcheck.WriteKeyword(filter)
+	if err != nil {
+		return fmt.Errorf("filter: %w", err)
+	}
+	if keyword != "" {
+		return fmt.Errorf("filter must be read-only, found %s", keyword)
+	}
+
+	depth := 0
+	quoted := false
+	for _, ch := range filter {
+		switch {
+		case ch == '\'':
+			// Doubled quotes inside a literal toggle twice and leave it open
+			quoted = !quoted
+		case quoted:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("filter has unbalanced parentheses")
+			}
+		}
+	}
+	if quoted {
+		return fmt.Errorf("filter has an unterminated string literal")
+	}
+	if depth != 0 {
+		return fmt.Errorf("filter has unbalanced parentheses")
+	}
+	return nil
+}
+
+// validateSourceFilter checks a table's raw filter and structured where conditions
+func validateSourceFilter(tc TableConfig) error {
+	if tc.Filter != "" {
+		if err := ValidateFilter(tc.Filter); err != nil {
+			return err
+		}
+	}
+	for i := range tc.Where {
+		if err := tc.Where[i].validate(); err != nil {
+			return err
+		}
+	}
+	if tc.Tenants != nil && tc.Tenants.Filter != "" {
+		if err := ValidateFilter(tc.Tenants.Filter); err != nil {
+			return fmt.Errorf("tenants: %w", err)
+		}
+	}
+	if tc.Backfill != nil && tc.Backfill.Filter != "" {
+		if err := ValidateFilter(tc.Backfill.Filter); err != nil {
+			return fmt.Errorf("backfill: %w", err)
+		}
+	}
+	return nil
+}
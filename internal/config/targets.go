@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateTargets checks the additional PostgreSQL targets and the tables loaded into them
+func validateTargets(config *Config) error {
+	for name, target := range config.Targets {
+		if name == "" || strings.ContainsAny(name, " :/") {
+			return fmt.Errorf("targets: invalid target name %q", name)
+		}
+		if target.Type != "postgresql" {
+			return fmt.Errorf("targets: %s: type must be postgresql", name)
+		}
+		if target.ColumnEncryption != nil || target.Introspection != nil || target.ReadOnlyIntent {
+			return fmt.Errorf("targets: %s: column_encryption, introspection and read_only_intent are source only", name)
+		}
+	}
+
+	for _, tc := range config.Tables {
+		seen := make(map[string]bool, len(tc.Targets))
+		for _, name := range tc.Targets {
+			if _, ok := config.Targets[name]; !ok {
+				return fmt.Errorf("table %s: target %s is not configured under targets", tc.TargetTable, name)
+			}
+			if seen[name] {
+				return fmt.Errorf("table %s: target %s is listed twice", tc.TargetTable, name)
+			}
+			seen[name] = true
+		}
+		if len(tc.Targets) > 0 && (tc.Chunking != nil || tc.Backfill != nil) {
+			return fmt.Errorf("table %s: targets cannot be combined with chunking or backfill", tc.TargetTable)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TenantPlaceholder is replaced by the tenant id in tenant schema and filter templates
+const TenantPlaceholder = "{tenant}"
+
+// TenantConfig turns a table config into a template projected once per tenant
+type TenantConfig struct {
+	List   []string `yaml:"list,omitempty"`   // static tenant ids
+	Query  string   `yaml:"query,omitempty"`  // read-only SELECT on the source returning tenant ids in the first column
+	Schema string   `yaml:"schema,omitempty"` // target schema template (default: tenant_{tenant})
+	Filter string   `yaml:"filter,omitempty"` // source filter template, e.g. TenantID = '{tenant}', ANDed with filter
+}
+
+// GetSchema returns the target schema of a tenant
+func (t *TenantConfig) GetSchema(tenant string) string {
+	template := t.Schema
+	if template == "" {
+		template = "tenant_" + TenantPlaceholder
+	}
+	return strings.ReplaceAll(template, TenantPlaceholder, tenantIdentifier(tenant))
+}
+
+// GetFilter returns the source filter of a tenant. Quotes in the tenant id are doubled so it can be used inside a string literal
+func (t *TenantConfig) GetFilter(tenant string) string {
+	return strings.ReplaceAll(t.Filter, TenantPlaceholder, strings.ReplaceAll(tenant, "'", "''"))
+}
+
+// tenantIdentifier lower-cases a tenant id and replaces characters that are not valid in an unquoted identifier
+func tenantIdentifier(tenant string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(tenant) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// ExpandTenants replaces every tenant template in Tables with one table config per tenant. Tenant ids
+// come from the template's list, or from its query run through resolve. Each tenant table targets
+// <tenant schema>.<template table> and belongs to the family named after the template's target table
+func (c *Config) ExpandTenants(resolve func(query string) ([]string, error)) error {
+	families := make(map[string]bool)
+	for _, tc := range c.Tables {
+		if tc.Tenants != nil {
+			families[tc.TargetTable] = true
+		}
+	}
+	if len(families) == 0 {
+		return nil
+	}
+
+	expanded := make([]TableConfig, 0, len(c.Tables))
+	for _, tc := range c.Tables {
+		if tc.Tenants == nil {
+			expanded = append(expanded, tc)
+			continue
+		}
+
+		tenants := tc.Tenants.List
+		if tc.Tenants.Query != "" {
+			resolved, err := resolve(tc.Tenants.Query)
+			if err != nil {
+				return fmt.Errorf("table %s: failed to resolve tenants: %w", tc.TargetTable, err)
+			}
+			tenants = resolved
+		}
+
+		for _, tenant := range tenants {
+			if strings.TrimSpace(tenant) == "" {
+				continue
+			}
+			expanded = append(expanded, tenantTable(tc, tenant, families))
+		}
+	}
+
+	c.Tables = expanded
+	return nil
+}
+
+// tenantTable builds the table config of a single tenant from a template
+func tenantTable(template TableConfig, tenant string, families map[string]bool) TableConfig {
+	tc := template
+	tc.Tenants = nil
+	tc.Family = template.TargetTable
+	tc.Tenant = tenant
+	tc.TargetTable = tenantTarget(template.Tenants.GetSchema(tenant), template.TargetTable)
+
+	if filter := template.Tenants.GetFilter(tenant); filter != "" {
+		if tc.Filter != "" {
+			tc.Filter = fmt.Sprintf("(%s) AND (%s)", tc.Filter, filter)
+		} else {
+			tc.Filter = filter
+		}
+	}
+
+	// Dependencies on other tenant families resolve to the same tenant's table
+	if len(template.DependsOn) > 0 {
+		tc.DependsOn = make([]string, len(template.DependsOn))
+		for i, dependency := range template.DependsOn {
+			if families[dependency] {
+				dependency = tenantTarget(template.Tenants.GetSchema(tenant), dependency)
+			}
+			tc.DependsOn[i] = dependency
+		}
+	}
+	return tc
+}
+
+// tenantTarget places the unqualified template table name in the tenant schema
+func tenantTarget(schema, templateTable string) string {
+	table := templateTable
+	if i := strings.LastIndex(templateTable, "."); i >= 0 {
+		table = templateTable[i+1:]
+	}
+	return schema + "." + table
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReadThrottle limits how fast a table is read from the source, so large syncs don't degrade the OLTP workload
+type ReadThrottle struct {
+	RowsPerSecond int           `yaml:"rows_per_second,omitempty"` // 0 for no row limit
+	MBPerSecond   float64       `yaml:"mb_per_second,omitempty"`   // approximate value size, 0 for no bandwidth limit
+	Peak          *PeakThrottle `yaml:"peak,omitempty"`            // different limits during business hours
+}
+
+// PeakThrottle replaces the read limits while one of its windows is active
+type PeakThrottle struct {
+	Windows       []BlackoutWindow `yaml:"windows"` // same start, end, days and timezone fields as blackouts
+	RowsPerSecond int              `yaml:"rows_per_second,omitempty"`
+	MBPerSecond   float64          `yaml:"mb_per_second,omitempty"`
+}
+
+// GetReadThrottle returns the read throttle for this table (or default), nil when reads are not throttled
+func (tc *TableConfig) GetReadThrottle(defaults DefaultConfig) *ReadThrottle {
+	if tc.ReadThrottle != nil {
+		return tc.ReadThrottle
+	}
+	return defaults.ReadThrottle
+}
+
+// Limits returns the rows per second and bytes per second allowed at the given time, 0 meaning unlimited
+func (t *ReadThrottle) Limits(now time.Time) (float64, float64) {
+	if p := t.Peak; p != nil {
+		for i := range p.Windows {
+			if p.Windows[i].Contains(now) {
+				return float64(p.RowsPerSecond), p.MBPerSecond * 1024 * 1024
+			}
+		}
+	}
+	return float64(t.RowsPerSecond), t.MBPerSecond * 1024 * 1024
+}
+
+// validate checks that limits are not negative and peak windows are valid
+func (t *ReadThrottle) validate() error {
+	if t.RowsPerSecond < 0 || t.MBPerSecond < 0 {
+		return fmt.Errorf("read_throttle limits must not be negative")
+	}
+	if p := t.Peak; p != nil {
+		if p.RowsPerSecond < 0 || p.MBPerSecond < 0 {
+			return fmt.Errorf("read_throttle peak limits must not be negative")
+		}
+		if len(p.Windows) == 0 {
+			return fmt.Errorf("read_throttle peak requires windows")
+		}
+		for _, w := range p.Windows {
+			if err := w.validate(); err != nil {
+				return fmt.Errorf("read_throttle peak window %s: %w", w.String(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// SnapshotAtColumn holds the time of the load a snapshot row was taken after
+const SnapshotAtColumn = "_snapshot_at"
+
+// TimeTravel keeps the output of the projections on a table as of its last loads, so projection requests can ask for
+// the data as of an earlier load with as_of
+type TimeTravel struct {
+	Keep int `yaml:"keep,omitempty"` // loads kept per projection (default 7)
+}
+
+// GetKeep returns the number of load snapshots kept per projection
+func (t *TimeTravel) GetKeep() int {
+	if t.Keep > 0 {
+		return t.Keep
+	}
+	return 7
+}
+
+// SnapshotTable returns the table holding the load snapshots of a projection's target view
+func (p *ProjectionConfig) SnapshotTable() string {
+	parts := sqlident.Split(p.TargetView)
+	parts[len(parts)-1] += "__snapshots"
+	return strings.Join(parts, ".")
+}
+
+// GetTimeTravel returns the time travel settings of the projection's sync table, nil when its loads are not kept
+func (c *Config) GetTimeTravel(projection *ProjectionConfig) *TimeTravel {
+	for i := range c.Tables {
+		if c.Tables[i].TargetTable == projection.SyncTable {
+			return c.Tables[i].TimeTravel
+		}
+	}
+	return nil
+}
+
+func validateTimeTravel(tc TableConfig) error {
+	if tc.TimeTravel.Keep < 0 {
+		return fmt.Errorf("time_travel keep must not be negative")
+	}
+	if tc.Chunking != nil {
+		return fmt.Errorf("time_travel cannot be combined with chunking")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProjectionTimeseriesConfig enables GET /api/projections/:id/timeseries, aggregating projection rows into time buckets
+type ProjectionTimeseriesConfig struct {
+	Column     string `yaml:"column" json:"column"`                               // date or timestamp column rows are bucketed by
+	MaxBuckets int    `yaml:"max_buckets,omitempty" json:"max_buckets,omitempty"` // most buckets in one series (default 1000)
+}
+
+// GetMaxBuckets returns the most buckets returned in one series
+func (t *ProjectionTimeseriesConfig) GetMaxBuckets() int {
+	if t.MaxBuckets <= 0 {
+		return 1000
+	}
+	return t.MaxBuckets
+}
+
+// validateTimeseries checks that a projection's time series column is one of its fields
+func validateTimeseries(projection *ProjectionConfig) error {
+	if projection.Timeseries == nil {
+		return nil
+	}
+	if projection.Timeseries.Column == "" {
+		return fmt.Errorf("projection %s: timeseries requires a column", projection.ID)
+	}
+	if len(projection.Fields) == 0 {
+		return nil
+	}
+	for _, field := range projection.Fields {
+		if strings.EqualFold(field.Column, projection.Timeseries.Column) {
+			return nil
+		}
+	}
+	return fmt.Errorf("projection %s: timeseries column %s is not a field", projection.ID, projection.Timeseries.Column)
+}
@@ -0,0 +1,81 @@
+package config
+
+import "fmt"
+
+// UIConfig describes the frontend's branding, navigation, refresh behaviour and feature flags, served by GET /api/ui-config
+type UIConfig struct {
+	Branding UIBranding      `yaml:"branding" json:"branding"`
+	Menu     []UIMenuGroup   `yaml:"menu,omitempty" json:"menu"` // projection groups in navigation order
+	Refresh  UIRefresh       `yaml:"refresh" json:"refresh"`
+	Features map[string]bool `yaml:"features,omitempty" json:"features"` // flags the frontend checks, e.g. sync_all, exports
+	Language string          `yaml:"language,omitempty" json:"language"` // language of untranslated titles and labels (default en)
+}
+
+// UIBranding holds the titles, logo and colors of the frontend
+type UIBranding struct {
+	Title        string `yaml:"title,omitempty" json:"title"`
+	Subtitle     string `yaml:"subtitle,omitempty" json:"subtitle,omitempty"`
+	LogoURL      string `yaml:"logo_url,omitempty" json:"logo_url,omitempty"`
+	PrimaryColor string `yaml:"primary_color,omitempty" json:"primary_color,omitempty"` // CSS color, e.g. #1f6feb
+	AccentColor  string `yaml:"accent_color,omitempty" json:"accent_color,omitempty"`
+	Footer       string `yaml:"footer,omitempty" json:"footer,omitempty"`
+}
+
+// UIMenuGroup is a navigation entry grouping projections
+type UIMenuGroup struct {
+	ID          string       `yaml:"id" json:"id"`
+	Label       string       `yaml:"label" json:"label"`
+	Labels      Translations `yaml:"labels,omitempty" json:"-"` // label by language
+	Icon        string       `yaml:"icon,omitempty" json:"icon,omitempty"`
+	Projections []string     `yaml:"projections" json:"projections"` // projection ids in display order
+}
+
+// UIRefresh holds the polling intervals the frontend uses, in seconds
+type UIRefresh struct {
+	Status      int `yaml:"status,omitempty" json:"status"`           // table status polling (default 30, 0 keeps the default)
+	Projections int `yaml:"projections,omitempty" json:"projections"` // projection data reloads (default 0, manual only)
+}
+
+// GetTitle returns the application title shown in the header and browser tab
+func (b *UIBranding) GetTitle() string {
+	if b.Title == "" {
+		return "MSSQL → PostgreSQL Sync Service"
+	}
+	return b.Title
+}
+
+// GetStatus returns the table status polling interval in seconds
+func (r *UIRefresh) GetStatus() int {
+	if r.Status <= 0 {
+		return 30
+	}
+	return r.Status
+}
+
+// validate checks that menu groups have ids and reference configured projections
+func (u *UIConfig) validate(projections []ProjectionConfig) error {
+	known := make(map[string]bool, len(projections))
+	for _, projection := range projections {
+		known[projection.ID] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range u.Menu {
+		if group.ID == "" {
+			return fmt.Errorf("ui: menu groups require an id")
+		}
+		if seen[group.ID] {
+			return fmt.Errorf("ui: duplicate menu group %s", group.ID)
+		}
+		seen[group.ID] = true
+		for _, id := range group.Projections {
+			if !known[id] {
+				return fmt.Errorf("ui: menu group %s: unknown projection %s", group.ID, id)
+			}
+		}
+	}
+	if u.Refresh.Projections < 0 {
+		return fmt.Errorf("ui: refresh.projections must not be negative")
+	}
+	return nil
+}
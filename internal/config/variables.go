@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Variables of source filters and source queries, written as {{name}} and resolved each time a table syncs
+const (
+	VarLastSync      = "last_sync"      // start of the table's last successful sync
+	VarLastWatermark = "last_watermark" // latest change_column value loaded by the last successful sync
+	VarToday         = "today"          // current date
+	VarNow           = "now"            // current time
+	VarBatchID       = "batch_id"       // batch ID of the running sync
+)
+
+// variablePattern matches {{name}} variable references
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_]+)\s*\}\}`)
+
+// ExpandVariables replaces the {{name}} references of a filter or query with their values
+func ExpandVariables(text string, values map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(reference string) string {
+		name := strings.ToLower(variablePattern.FindStringSubmatch(reference)[1])
+		return values[name]
+	})
+}
+
+// Variables returns the names of the variables referenced by a filter or query
+func Variables(text string) []string {
+	var names []string
+	for _, match := range variablePattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, strings.ToLower(match[1]))
+	}
+	return names
+}
+
+// UsesVariables reports whether a table's filter or source query references variables
+func (tc *TableConfig) UsesVariables() bool {
+	return variablePattern.MatchString(tc.Filter) || variablePattern.MatchString(tc.SourceQuery)
+}
+
+// validateVariables checks the variables of a table's filter and source query: they must be known, and those
+// read from the sync history need it enabled
+func validateVariables(tc TableConfig, historyEnabled bool) error {
+	for _, text := range []string{tc.Filter, tc.SourceQuery} {
+		for _, name := range Variables(text) {
+			switch name {
+			case VarToday, VarNow, VarBatchID:
+			case VarLastSync, VarLastWatermark:
+				if !historyEnabled {
+					return fmt.Errorf("{{%s}} requires history.enabled", name)
+				}
+				if name == VarLastWatermark && tc.ChangeColumn == "" {
+					return fmt.Errorf("{{%s}} requires change_column", name)
+				}
+			default:
+				return fmt.Errorf("unknown variable {{%s}}", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// breakerProbeTimeout bounds a probe, so a database dropping packets fails it instead of hanging until the TCP
+// connect timeout
+const breakerProbeTimeout = 5 * time.Second
+
+// BreakerStatus is a snapshot of a circuit breaker
+type BreakerStatus struct {
+	Name         string     `json:"name"`
+	State        string     `json:"state"`
+	Failures     int        `json:"consecutive_failures"`
+	LastError    string     `json:"last_error,omitempty"`
+	OpenedAt     *time.Time `json:"opened_at,omitempty"`
+	NextProbeAt  *time.Time `json:"next_probe_at,omitempty"`
+	LastProbedAt *time.Time `json:"last_probed_at,omitempty"`
+}
+
+// CircuitBreaker pauses work against a database connection after repeated failures
+// and periodically probes it until it recovers
+type CircuitBreaker struct {
+	Name      string
+	Threshold int
+	Cooldown  time.Duration
+	Probe     func(ctx context.Context) error
+	Logger    *zap.Logger
+
+	mu           sync.Mutex
+	state        string
+	failures     int
+	lastError    string
+	openedAt     time.Time
+	lastProbedAt time.Time
+}
+
+// NewCircuitBreaker creates a new circuit breaker in the closed state
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration, probe func(ctx context.Context) error, logger *zap.Logger) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 3
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	metrics.CircuitOpen.WithLabelValues(name).Set(0)
+	return &CircuitBreaker{
+		Name:      name,
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		Probe:     probe,
+		Logger:    logger,
+		state:     BreakerClosed,
+	}
+}
+
+// Allow reports whether work may run against the connection. When the breaker is open
+// and the cooldown has elapsed, the connection is probed and the breaker closes on success.
+// The probe runs without the lock and other callers are refused while it is half open, so none of them wait on it.
+func (cb *CircuitBreaker) Allow() bool {
+	if cb == nil {
+		return true
+	}
+
+	cb.mu.Lock()
+	switch {
+	case cb.state == BreakerClosed:
+		cb.mu.Unlock()
+		return true
+	case cb.state == BreakerHalfOpen, time.Since(cb.openedAt) < cb.Cooldown, cb.Probe == nil:
+		cb.mu.Unlock()
+		return false
+	}
+	cb.state = BreakerHalfOpen
+	cb.lastProbedAt = time.Now()
+	cb.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), breakerProbeTimeout)
+	err := cb.Probe(ctx)
+	cancel()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err != nil {
+		cb.state = BreakerOpen
+		cb.openedAt = time.Now()
+		cb.lastError = err.Error()
+		cb.Logger.Warn("Circuit breaker probe failed",
+			zap.String("connection", cb.Name),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	cb.close()
+	return true
+}
+
+// RecordSuccess resets the failure count
+func (cb *CircuitBreaker) RecordSuccess() {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state != BreakerClosed {
+		cb.close()
+	}
+	cb.failures = 0
+}
+
+// RecordFailure counts a connection failure and opens the breaker at the threshold.
+// Errors that don't indicate a connection problem are ignored.
+func (cb *CircuitBreaker) RecordFailure(err error) {
+	if cb == nil || !IsConnectionError(err) {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.lastError = err.Error()
+	if cb.state == BreakerClosed && cb.failures >= cb.Threshold {
+		cb.state = BreakerOpen
+		cb.openedAt = time.Now()
+		metrics.CircuitOpen.WithLabelValues(cb.Name).Set(1)
+		cb.Logger.Error("Circuit breaker opened, pausing syncs",
+			zap.String("connection", cb.Name),
+			zap.Int("failures", cb.failures),
+			zap.Duration("cooldown", cb.Cooldown),
+			zap.Error(err),
+		)
+	}
+}
+
+// Status returns a snapshot of the breaker
+func (cb *CircuitBreaker) Status() BreakerStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	status := BreakerStatus{
+		Name:      cb.Name,
+		State:     cb.state,
+		Failures:  cb.failures,
+		LastError: cb.lastError,
+	}
+	if cb.state != BreakerClosed {
+		openedAt := cb.openedAt
+		nextProbe := cb.openedAt.Add(cb.Cooldown)
+		status.OpenedAt = &openedAt
+		status.NextProbeAt = &nextProbe
+	}
+	if !cb.lastProbedAt.IsZero() {
+		lastProbed := cb.lastProbedAt
+		status.LastProbedAt = &lastProbed
+	}
+	return status
+}
+
+// close must be called with the lock held
+func (cb *CircuitBreaker) close() {
+	cb.state = BreakerClosed
+	cb.failures = 0
+	cb.lastError = ""
+	cb.openedAt = time.Time{}
+	metrics.CircuitOpen.WithLabelValues(cb.Name).Set(0)
+	cb.Logger.Info("Circuit breaker closed, resuming syncs", zap.String("connection", cb.Name))
+}
+
+// IsConnectionError reports whether an error indicates the database is unreachable
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range []string{"connection refused", "connection reset", "broken pipe", "no such host", "i/o timeout", "bad connection"} {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// connectAttemptTimeout bounds a single connection attempt, so a database that drops packets does not stall retries
+const connectAttemptTimeout = 10 * time.Second
+
+// ConnectionState is the reachability of a database at startup
+type ConnectionState struct {
+	Name        string     `json:"name"`
+	Connected   bool       `json:"connected"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+}
+
+// Ready reports whether all database connections have responded since startup
+func (dm *DatabaseManager) Ready() bool {
+	select {
+	case <-dm.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitReady blocks until all database connections have responded, or until ctx is done
+func (dm *DatabaseManager) WaitReady(ctx context.Context) error {
+	select {
+	case <-dm.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ConnectionStates returns the startup reachability of the database connections
+func (dm *DatabaseManager) ConnectionStates() []ConnectionState {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	states := make([]ConnectionState, 0, len(dm.states))
+	for _, db := range dm.connections() {
+		states = append(states, *dm.states[db.Name])
+	}
+	return states
+}
+
+// connections returns the distinct database connections of the manager
+func (dm *DatabaseManager) connections() []*DB {
+	if dm.SourceSchema != dm.Source {
+		return []*DB{dm.Source, dm.SourceSchema, dm.Target}
+	}
+	return []*DB{dm.Source, dm.Target}
+}
+
+// connect pings the connections not reached yet and reports whether all have now responded
+func (dm *DatabaseManager) connect() bool {
+	all := true
+	for _, db := range dm.connections() {
+		dm.mu.Lock()
+		state := dm.states[db.Name]
+		connected := state.Connected
+		dm.mu.Unlock()
+		if connected {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connectAttemptTimeout)
+		err := db.PingContext(ctx)
+		cancel()
+
+		dm.mu.Lock()
+		state.Attempts++
+		if err != nil {
+			state.LastError = err.Error()
+			all = false
+		} else {
+			now := time.Now()
+			state.Connected = true
+			state.LastError = ""
+			state.ConnectedAt = &now
+		}
+		attempts := state.Attempts
+		dm.mu.Unlock()
+
+		if err != nil {
+			dm.Logger.Warn("Database unreachable",
+				zap.String("connection", db.Name),
+				zap.Int("attempt", attempts),
+				zap.Error(err),
+			)
+		} else {
+			dm.Logger.Info("Connected to database", zap.String("connection", db.Name))
+		}
+	}
+
+	if all {
+		close(dm.ready)
+	}
+	return all
+}
+
+// retryConnect keeps trying to reach the databases with exponential backoff up to maxInterval, until all respond
+// or the manager is closed
+func (dm *DatabaseManager) retryConnect(maxInterval time.Duration) {
+	interval := time.Second
+	for {
+		select {
+		case <-time.After(interval):
+		case <-dm.closed:
+			return
+		}
+		if dm.connect() {
+			return
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
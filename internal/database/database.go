@@ -2,6 +2,8 @@ package database
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	"github.com/jmoiron/sqlx"
@@ -13,68 +15,145 @@ import (
 
 // DatabaseManager manages database connections
 type DatabaseManager struct {
-	Source *sqlx.DB
-	Target *sqlx.DB
-	Logger *zap.Logger
+	Source        *DB
+	SourceSchema  *DB // schema introspection of the source; Source unless source.introspection is configured
+	Target        *DB
+	Logger        *zap.Logger
+	SourceBreaker *CircuitBreaker
+	TargetBreaker *CircuitBreaker
+	Targets       map[string]*FanoutTarget // fan-out targets by name
+
+	ready  chan struct{} // closed once both databases responded
+	closed chan struct{} // closed by Close to stop connection retries
+	mu     sync.Mutex
+	states map[string]*ConnectionState
 }
 
-// NewDatabaseManager creates a new database manager
+// NewDatabaseManager creates a new database manager. Connection pools are opened without waiting for the
+// databases; when either is unreachable the manager keeps retrying in the background until both respond
+// (see Ready and WaitReady)
 func NewDatabaseManager(cfg *config.Config, logger *zap.Logger) (*DatabaseManager, error) {
-	// Connect to source database (MSSQL)
-	sourceConn := cfg.Source.GetConnectionString()
-	logger.Info("Connecting to source database", zap.String("type", cfg.Source.Type))
-	
-	sourceDB, err := sqlx.Connect("sqlserver", sourceConn)
+	// Source database (MSSQL)
+	logger.Info("Opening source database", zap.String("type", cfg.Source.Type))
+	sourceDB, err := sqlx.Open("sqlserver", cfg.Source.GetConnectionString())
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to source database: %w", err)
+		return nil, fmt.Errorf("failed to open source database: %w", err)
 	}
 
-	// Test source connection
-	if err := sourceDB.Ping(); err != nil {
-		sourceDB.Close()
-		return nil, fmt.Errorf("failed to ping source database: %w", err)
+	// Separate login for source schema introspection
+	var sourceSchemaDB *sqlx.DB
+	if conn, ok := cfg.Source.GetIntrospectionConnectionString(); ok {
+		logger.Info("Opening source introspection connection")
+		if sourceSchemaDB, err = sqlx.Open("sqlserver", conn); err != nil {
+			sourceDB.Close()
+			return nil, fmt.Errorf("failed to open source introspection connection: %w", err)
+		}
 	}
 
-	logger.Info("Connected to source database successfully")
-
-	// Connect to target database (PostgreSQL)
-	targetConn := cfg.Target.GetConnectionString()
-	logger.Info("Connecting to target database", zap.String("type", cfg.Target.Type))
-	
-	targetDB, err := sqlx.Connect("postgres", targetConn)
+	// Target database (PostgreSQL)
+	logger.Info("Opening target database", zap.String("type", cfg.Target.Type))
+	targetDB, err := sqlx.Open("postgres", cfg.Target.GetConnectionString())
 	if err != nil {
 		sourceDB.Close()
-		return nil, fmt.Errorf("failed to connect to target database: %w", err)
+		if sourceSchemaDB != nil {
+			sourceSchemaDB.Close()
+		}
+		return nil, fmt.Errorf("failed to open target database: %w", err)
 	}
 
-	// Test target connection
-	if err := targetDB.Ping(); err != nil {
+	targets, err := openTargets(cfg, logger)
+	if err != nil {
 		sourceDB.Close()
+		if sourceSchemaDB != nil {
+			sourceSchemaDB.Close()
+		}
 		targetDB.Close()
-		return nil, fmt.Errorf("failed to ping target database: %w", err)
+		return nil, err
+	}
+
+	dm := &DatabaseManager{
+		Source:  NewDB(sourceDB, "source", cfg.Queries.GetSlowThreshold(), logger),
+		Target:  NewDB(targetDB, "target", cfg.Queries.GetSlowThreshold(), logger),
+		Targets: targets,
+		Logger:  logger,
+		ready:   make(chan struct{}),
+		closed:  make(chan struct{}),
+		states: map[string]*ConnectionState{
+			"source": {Name: "source"},
+			"target": {Name: "target"},
+		},
+	}
+
+	// Filters, source queries and trigger queries are user-supplied; nothing but SELECTs may reach the source
+	dm.Source.EnforceReadOnly()
+	dm.SourceSchema = dm.Source
+	if sourceSchemaDB != nil {
+		dm.SourceSchema = NewDB(sourceSchemaDB, "source_schema", cfg.Queries.GetSlowThreshold(), logger)
+		dm.SourceSchema.EnforceReadOnly()
+		dm.states["source_schema"] = &ConnectionState{Name: "source_schema"}
+	}
+
+	if cfg.API.PreparedStatements.Enabled {
+		dm.Target.EnableStatementCache(cfg.API.PreparedStatements.GetCacheSize())
+	}
+
+	if cfg.Breaker.Enabled {
+		cooldown := time.Duration(cfg.Breaker.Cooldown) * time.Second
+		dm.SourceBreaker = NewCircuitBreaker("source", cfg.Breaker.FailureThreshold, cooldown, sourceDB.PingContext, logger)
+		dm.TargetBreaker = NewCircuitBreaker("target", cfg.Breaker.FailureThreshold, cooldown, targetDB.PingContext, logger)
+	}
+
+	if !dm.connect() {
+		logger.Warn("Databases unreachable at startup, retrying in the background")
+		go dm.retryConnect(cfg.Startup.GetRetryMaxInterval())
 	}
 
-	logger.Info("Connected to target database successfully")
+	return dm, nil
+}
 
-	return &DatabaseManager{
-		Source: sourceDB,
-		Target: targetDB,
-		Logger: logger,
-	}, nil
+// Available reports whether both circuit breakers allow work to run
+func (dm *DatabaseManager) Available() bool {
+	return dm.SourceBreaker.Allow() && dm.TargetBreaker.Allow()
+}
+
+// BreakerStatuses returns the state of the configured circuit breakers
+func (dm *DatabaseManager) BreakerStatuses() map[string]BreakerStatus {
+	statuses := make(map[string]BreakerStatus)
+	if dm.SourceBreaker != nil {
+		statuses["source"] = dm.SourceBreaker.Status()
+	}
+	if dm.TargetBreaker != nil {
+		statuses["target"] = dm.TargetBreaker.Status()
+	}
+	for name, target := range dm.Targets {
+		if target.Breaker != nil {
+			statuses["target:"+name] = target.Breaker.Status()
+		}
+	}
+	return statuses
 }
 
 // Close closes all database connections
 func (dm *DatabaseManager) Close() error {
+	close(dm.closed)
 	var err error
 	if dm.Source != nil {
 		if e := dm.Source.Close(); e != nil {
 			err = e
 		}
 	}
+	if dm.SourceSchema != nil && dm.SourceSchema != dm.Source {
+		if e := dm.SourceSchema.Close(); e != nil {
+			err = e
+		}
+	}
 	if dm.Target != nil {
 		if e := dm.Target.Close(); e != nil {
 			err = e
 		}
 	}
+	if e := closeTargets(dm.Targets); e != nil {
+		err = e
+	}
 	return err
 }
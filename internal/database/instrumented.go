@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/metrics"
+)
+
+type queryLabelKey struct{}
+
+// maxLoggedQueryLength truncates long generated statements in slow query logs
+const maxLoggedQueryLength = 2000
+
+// WithQueryLabel tags queries run with ctx with the table or projection they belong to
+func WithQueryLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, queryLabelKey{}, label)
+}
+
+// QueryLabel returns the table or projection label of ctx
+func QueryLabel(ctx context.Context) string {
+	if label, ok := ctx.Value(queryLabelKey{}).(string); ok && label != "" {
+		return label
+	}
+	return "other"
+}
+
+// DB wraps a sqlx connection, timing queries and logging those slower than the threshold.
+// Query timings cover execution until rows are returned, not reading them
+type DB struct {
+	*sqlx.DB
+	Name          string
+	SlowThreshold time.Duration
+	Logger        *zap.Logger
+
+	stmts    *stmtCache // nil unless EnableStatementCache was called
+	readOnly bool       // set by EnforceReadOnly
+}
+
+// NewDB wraps a sqlx connection with query instrumentation
+func NewDB(db *sqlx.DB, name string, slowThreshold time.Duration, logger *zap.Logger) *DB {
+	return &DB{
+		DB:            db,
+		Name:          name,
+		SlowThreshold: slowThreshold,
+		Logger:        logger,
+	}
+}
+
+// Observe records the duration of a query started at start and logs it when slow
+func (db *DB) Observe(ctx context.Context, query string, start time.Time, err error) {
+	duration := time.Since(start)
+	label := QueryLabel(ctx)
+	metrics.QueryDurationSeconds.WithLabelValues(db.Name, label).Observe(duration.Seconds())
+
+	if db.SlowThreshold <= 0 || duration < db.SlowThreshold {
+		return
+	}
+	if len(query) > maxLoggedQueryLength {
+		query = query[:maxLoggedQueryLength] + "..."
+	}
+	db.Logger.Warn("Slow query",
+		zap.String("connection", db.Name),
+		zap.String("context", label),
+		zap.Duration("duration", duration),
+		zap.String("query", query),
+		zap.Error(err),
+	)
+}
+
+// Queryx runs a query and times it
+func (db *DB) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	return db.QueryxContext(context.Background(), query, args...)
+}
+
+// QueryxContext runs a query and times it under the label of ctx
+func (db *DB) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := db.DB.QueryxContext(ctx, query, args...)
+	db.Observe(ctx, query, start, err)
+	return rows, err
+}
+
+// QueryRow runs a single row query and times it
+func (db *DB) QueryRow(query string, args ...interface{}) *Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext runs a single row query and times it under the label of ctx
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return &Row{err: err}
+	}
+	start := time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	db.Observe(ctx, query, start, row.Err())
+	return &Row{row: row}
+}
+
+// Exec runs a statement and times it
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext runs a statement and times it under the label of ctx
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := db.refuse(ctx, "exec"); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	db.Observe(ctx, query, start, err)
+	return result, err
+}
+
+// Select runs a query into dest and times it
+func (db *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	return db.SelectContext(context.Background(), dest, query, args...)
+}
+
+// SelectContext runs a query into dest and times it under the label of ctx
+func (db *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := db.DB.SelectContext(ctx, dest, query, args...)
+	db.Observe(ctx, query, start, err)
+	return err
+}
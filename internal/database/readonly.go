@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/sqlcheck"
+)
+
+// ErrNotReadOnly is returned for statements refused on a read-only connection
+var ErrNotReadOnly = sqlcheck.ErrNotReadOnly
+
+// EnforceReadOnly makes the connection refuse anything but single SELECT statements, so user-supplied filters and
+// queries can never write to the database. Exec, transactions and dedicated connections are refused altogether
+func (db *DB) EnforceReadOnly() {
+	db.readOnly = true
+}
+
+// checkStatement refuses statements that are not read-only on read-only connections, logging the attempt
+func (db *DB) checkStatement(ctx context.Context, query string) error {
+	if !db.readOnly {
+		return nil
+	}
+	err := sqlcheck.ReadOnly(query)
+	if err != nil {
+		if len(query) > maxLoggedQueryLength {
+			query = query[:maxLoggedQueryLength] + "..."
+		}
+		db.Logger.Error("Refused statement on read-only connection",
+			zap.String("connection", db.Name),
+			zap.String("context", QueryLabel(ctx)),
+			zap.String("query", query),
+			zap.Error(err),
+		)
+	}
+	return err
+}
+
+// refuse returns ErrNotReadOnly for an operation whose statements cannot be checked on read-only connections,
+// logging the attempt, and nil on other connections
+func (db *DB) refuse(ctx context.Context, operation string) error {
+	if !db.readOnly {
+		return nil
+	}
+	db.Logger.Error("Refused "+operation+" on read-only connection",
+		zap.String("connection", db.Name),
+		zap.String("context", QueryLabel(ctx)),
+	)
+	return fmt.Errorf("%w: %s is not allowed", ErrNotReadOnly, operation)
+}
+
+// Row is the result of a single row query. Statements refused by checkStatement never reach the database and fail
+// on Scan with their ErrNotReadOnly refusal
+type Row struct {
+	row interface {
+		Scan(dest ...interface{}) error
+		Err() error
+	}
+	err error
+}
+
+// Scan copies the columns of the row into dest, returning the refusal or query error
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.row.Scan(dest...)
+}
+
+// Err returns the refusal or query error of the row without scanning it
+func (r *Row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.row.Err()
+}
+
+// The methods below shadow those of the embedded sqlx connection that would otherwise reach the database without
+// the read-only checks. Queries and prepared statements are checked like Queryx; execs, transactions and dedicated
+// connections are refused on read-only connections
+
+// Get runs a single row query into dest and times it
+func (db *DB) Get(dest interface{}, query string, args ...interface{}) error {
+	return db.GetContext(context.Background(), dest, query, args...)
+}
+
+// GetContext runs a single row query into dest and times it under the label of ctx
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := db.DB.GetContext(ctx, dest, query, args...)
+	db.Observe(ctx, query, start, err)
+	return err
+}
+
+// QueryRowx runs a single row query like QueryRow
+func (db *DB) QueryRowx(query string, args ...interface{}) *Row {
+	return db.QueryRowxContext(context.Background(), query, args...)
+}
+
+// QueryRowxContext runs a single row query like QueryRowContext
+func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *Row {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return &Row{err: err}
+	}
+	start := time.Now()
+	row := db.DB.QueryRowxContext(ctx, query, args...)
+	db.Observe(ctx, query, start, row.Err())
+	return &Row{row: row}
+}
+
+// Query runs a query like Queryx, returning database/sql rows
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext runs a query like QueryxContext, returning database/sql rows
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	db.Observe(ctx, query, start, err)
+	return rows, err
+}
+
+// NamedQuery runs a query with named parameters
+func (db *DB) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return db.NamedQueryContext(context.Background(), query, arg)
+}
+
+// NamedQueryContext runs a query with named parameters under the label of ctx
+func (db *DB) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := db.DB.NamedQueryContext(ctx, query, arg)
+	db.Observe(ctx, query, start, err)
+	return rows, err
+}
+
+// NamedExec runs a statement with named parameters
+func (db *DB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return db.NamedExecContext(context.Background(), query, arg)
+}
+
+// NamedExecContext runs a statement with named parameters under the label of ctx
+func (db *DB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	if err := db.refuse(ctx, "exec"); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := db.DB.NamedExecContext(ctx, query, arg)
+	db.Observe(ctx, query, start, err)
+	return result, err
+}
+
+// MustExec runs a statement, panicking on errors
+func (db *DB) MustExec(query string, args ...interface{}) sql.Result {
+	return db.MustExecContext(context.Background(), query, args...)
+}
+
+// MustExecContext runs a statement under the label of ctx, panicking on errors
+func (db *DB) MustExecContext(ctx context.Context, query string, args ...interface{}) sql.Result {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Begin starts a transaction
+func (db *DB) Begin() (*sql.Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// BeginTx starts a transaction
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if err := db.refuse(ctx, "transaction"); err != nil {
+		return nil, err
+	}
+	return db.DB.BeginTx(ctx, opts)
+}
+
+// Beginx starts a transaction
+func (db *DB) Beginx() (*sqlx.Tx, error) {
+	return db.BeginTxx(context.Background(), nil)
+}
+
+// BeginTxx starts a transaction
+func (db *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	if err := db.refuse(ctx, "transaction"); err != nil {
+		return nil, err
+	}
+	return db.DB.BeginTxx(ctx, opts)
+}
+
+// MustBegin starts a transaction, panicking on errors
+func (db *DB) MustBegin() *sqlx.Tx {
+	return db.MustBeginTx(context.Background(), nil)
+}
+
+// MustBeginTx starts a transaction, panicking on errors
+func (db *DB) MustBeginTx(ctx context.Context, opts *sql.TxOptions) *sqlx.Tx {
+	tx, err := db.BeginTxx(ctx, opts)
+	if err != nil {
+		panic(err)
+	}
+	return tx
+}
+
+// Prepare creates a prepared statement
+func (db *DB) Prepare(query string) (*sql.Stmt, error) {
+	return db.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext creates a prepared statement
+func (db *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	return db.DB.PrepareContext(ctx, query)
+}
+
+// Preparex creates a prepared statement
+func (db *DB) Preparex(query string) (*sqlx.Stmt, error) {
+	return db.PreparexContext(context.Background(), query)
+}
+
+// PreparexContext creates a prepared statement
+func (db *DB) PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	return db.DB.PreparexContext(ctx, query)
+}
+
+// PrepareNamed creates a prepared statement with named parameters
+func (db *DB) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
+	return db.PrepareNamedContext(context.Background(), query)
+}
+
+// PrepareNamedContext creates a prepared statement with named parameters
+func (db *DB) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	if err := db.checkStatement(ctx, query); err != nil {
+		return nil, err
+	}
+	return db.DB.PrepareNamedContext(ctx, query)
+}
+
+// Conn returns a dedicated connection
+func (db *DB) Conn(ctx context.Context) (*sql.Conn, error) {
+	if err := db.refuse(ctx, "dedicated connection"); err != nil {
+		return nil, err
+	}
+	return db.DB.Conn(ctx)
+}
+
+// Connx returns a dedicated connection
+func (db *DB) Connx(ctx context.Context) (*sqlx.Conn, error) {
+	if err := db.refuse(ctx, "dedicated connection"); err != nil {
+		return nil, err
+	}
+	return db.DB.Connx(ctx)
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// stmtCache keeps prepared statements by query text. database/sql prepares a statement on each pooled connection
+// the first time it runs there, so repeated queries skip parsing and, once PostgreSQL settles on a generic plan,
+// planning. The least recently used statement is closed beyond the capacity
+type stmtCache struct {
+	capacity int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+}
+
+// cachedStmt is a cache entry. A statement evicted while queries are starting on it is closed by the last of them
+type cachedStmt struct {
+	query   string
+	stmt    *sqlx.Stmt
+	users   int
+	evicted bool
+}
+
+// EnableStatementCache makes QueryxPreparedContext and QueryRowxPreparedContext reuse prepared statements, keeping
+// at most capacity of them
+func (db *DB) EnableStatementCache(capacity int) {
+	db.stmts = &stmtCache{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// QueryxPreparedContext runs a query through a cached prepared statement when the statement cache is enabled, and
+// as a plain query otherwise. It is meant for queries whose text repeats with different arguments
+func (db *DB) QueryxPreparedContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if db.stmts == nil || db.readOnly {
+		return db.QueryxContext(ctx, query, args...)
+	}
+
+	start := time.Now()
+	entry, err := db.acquireStmt(ctx, query)
+	if err != nil {
+		db.Observe(ctx, query, start, err)
+		return nil, err
+	}
+	// Rows keep the statement open on their connection until closed, so the entry can be released right away
+	rows, err := entry.stmt.QueryxContext(ctx, args...)
+	db.releaseStmt(entry)
+	db.Observe(ctx, query, start, err)
+	return rows, err
+}
+
+// QueryRowxPreparedContext runs a single row query like QueryxPreparedContext
+func (db *DB) QueryRowxPreparedContext(ctx context.Context, query string, args ...interface{}) *Row {
+	if db.stmts == nil || db.readOnly {
+		if err := db.checkStatement(ctx, query); err != nil {
+			return &Row{err: err}
+		}
+		start := time.Now()
+		row := db.DB.QueryRowxContext(ctx, query, args...)
+		db.Observe(ctx, query, start, row.Err())
+		return &Row{row: row}
+	}
+
+	start := time.Now()
+	entry, err := db.acquireStmt(ctx, query)
+	if err != nil {
+		db.Observe(ctx, query, start, err)
+		// Surfaces the error on Scan, like a failed QueryRowx
+		return &Row{row: db.DB.QueryRowxContext(ctx, query, args...)}
+	}
+	row := entry.stmt.QueryRowxContext(ctx, args...)
+	db.releaseStmt(entry)
+	db.Observe(ctx, query, start, row.Err())
+	return &Row{row: row}
+}
+
+// acquireStmt returns the cached statement of a query, preparing it on a miss
+func (db *DB) acquireStmt(ctx context.Context, query string) (*cachedStmt, error) {
+	sc := db.stmts
+	sc.mu.Lock()
+	if element, ok := sc.entries[query]; ok {
+		sc.order.MoveToFront(element)
+		entry := element.Value.(*cachedStmt)
+		entry.users++
+		sc.mu.Unlock()
+		metrics.StatementCacheTotal.WithLabelValues(db.Name, "hit").Inc()
+		return entry, nil
+	}
+	sc.mu.Unlock()
+
+	metrics.StatementCacheTotal.WithLabelValues(db.Name, "miss").Inc()
+	stmt, err := db.DB.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if element, ok := sc.entries[query]; ok {
+		// Prepared concurrently by another request
+		stmt.Close()
+		entry := element.Value.(*cachedStmt)
+		entry.users++
+		return entry, nil
+	}
+	entry := &cachedStmt{query: query, stmt: stmt, users: 1}
+	sc.entries[query] = sc.order.PushFront(entry)
+	for sc.order.Len() > sc.capacity {
+		oldest := sc.order.Back()
+		evicted := oldest.Value.(*cachedStmt)
+		sc.order.Remove(oldest)
+		delete(sc.entries, evicted.query)
+		evicted.evicted = true
+		if evicted.users == 0 {
+			evicted.stmt.Close()
+		}
+		metrics.StatementCacheTotal.WithLabelValues(db.Name, "evicted").Inc()
+	}
+	return entry, nil
+}
+
+// releaseStmt ends a query's use of a cached statement, closing it if it was evicted meanwhile
+func (db *DB) releaseStmt(entry *cachedStmt) {
+	sc := db.stmts
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry.users--
+	if entry.evicted && entry.users == 0 {
+		entry.stmt.Close()
+	}
+}
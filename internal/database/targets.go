@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// FanoutTarget is an additional target database tables are loaded into from the same source read as the primary
+// target. Fan-out targets are not waited for at startup, so one being unreachable only fails its own loads
+type FanoutTarget struct {
+	Name    string
+	DB      *DB
+	Breaker *CircuitBreaker
+}
+
+// openTargets opens the connection pools of the configured fan-out targets
+func openTargets(cfg *config.Config, logger *zap.Logger) (map[string]*FanoutTarget, error) {
+	targets := make(map[string]*FanoutTarget, len(cfg.Targets))
+	for name, targetCfg := range cfg.Targets {
+		logger.Info("Opening fan-out target database", zap.String("target", name))
+		targetDB, err := sqlx.Open("postgres", targetCfg.GetConnectionString())
+		if err != nil {
+			closeTargets(targets)
+			return nil, fmt.Errorf("failed to open target %s: %w", name, err)
+		}
+
+		target := &FanoutTarget{Name: name, DB: NewDB(targetDB, "target:"+name, cfg.Queries.GetSlowThreshold(), logger)}
+		if cfg.Breaker.Enabled {
+			cooldown := time.Duration(cfg.Breaker.Cooldown) * time.Second
+			target.Breaker = NewCircuitBreaker("target:"+name, cfg.Breaker.FailureThreshold, cooldown, targetDB.PingContext, logger)
+		}
+		targets[name] = target
+	}
+	return targets, nil
+}
+
+// closeTargets closes the connection pools of fan-out targets, returning the last error
+func closeTargets(targets map[string]*FanoutTarget) error {
+	var err error
+	for _, target := range targets {
+		if e := target.DB.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
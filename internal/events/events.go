@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Event types
+const (
+	TypeSyncCompleted = "sync.completed"
+	TypeRowUpserted   = "row.upserted"
+)
+
+// Publication modes
+const (
+	ModeBatch = "batch" // one summary event per sync
+	ModeRows  = "rows"  // one event per synced row followed by the summary
+)
+
+// BatchEvent summarizes a completed table sync
+type BatchEvent struct {
+	Type            string     `json:"type"`
+	Table           string     `json:"table"`
+	BatchID         string     `json:"batch_id"`
+	RowsSynced      int        `json:"rows_synced"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      time.Time  `json:"finished_at"`
+	SourceChangedAt *time.Time `json:"source_changed_at,omitempty"`
+}
+
+// RowEvent carries a single synced row
+type RowEvent struct {
+	Type    string                 `json:"type"`
+	Table   string                 `json:"table"`
+	BatchID string                 `json:"batch_id"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+// Publisher publishes sync events to a Kafka topic
+type Publisher struct {
+	Writer *kafka.Writer
+	Mode   string
+	Logger *zap.Logger
+}
+
+// NewPublisher creates a Kafka publisher, or returns nil when events are disabled
+func NewPublisher(cfg config.EventsConfig, logger *zap.Logger) (*Publisher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+		return nil, fmt.Errorf("events require brokers and topic")
+	}
+
+	mode := strings.ToLower(cfg.Mode)
+	if mode == "" {
+		mode = ModeBatch
+	}
+	if mode != ModeBatch && mode != ModeRows {
+		return nil, fmt.Errorf("unsupported events mode: %s", cfg.Mode)
+	}
+
+	transport := &kafka.Transport{}
+	if cfg.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.Username != "" {
+		transport.SASL = plain.Mechanism{Username: cfg.Username, Password: cfg.Password}
+	}
+
+	return &Publisher{
+		Writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 50 * time.Millisecond,
+			Transport:    transport,
+		},
+		Mode:   mode,
+		Logger: logger,
+	}, nil
+}
+
+// Publish sends the events for a completed sync. Row events are keyed by table so they stay ordered.
+func (p *Publisher) Publish(ctx context.Context, batch BatchEvent, rows []map[string]interface{}) error {
+	if p == nil {
+		return nil
+	}
+
+	key := []byte(batch.Table)
+	var messages []kafka.Message
+
+	if p.Mode == ModeRows {
+		messages = make([]kafka.Message, 0, len(rows)+1)
+		for _, row := range rows {
+			value, err := json.Marshal(RowEvent{
+				Type:    TypeRowUpserted,
+				Table:   batch.Table,
+				BatchID: batch.BatchID,
+				Data:    normalizeRow(row),
+			})
+			if err != nil {
+				return err
+			}
+			messages = append(messages, kafka.Message{Key: key, Value: value})
+		}
+	}
+
+	batch.Type = TypeSyncCompleted
+	value, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	messages = append(messages, kafka.Message{Key: key, Value: value})
+
+	return p.Writer.WriteMessages(ctx, messages...)
+}
+
+// Close flushes pending messages and closes the writer
+func (p *Publisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	return p.Writer.Close()
+}
+
+func normalizeRow(row map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		if b, ok := value.([]byte); ok {
+			normalized[column] = string(b)
+			continue
+		}
+		normalized[column] = value
+	}
+	return normalized
+}
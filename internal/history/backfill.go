@@ -0,0 +1,107 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// Backfill statuses
+const (
+	BackfillRunning   = "running"   // loading ranges, or interrupted by a shutdown and resumed on start
+	BackfillStopped   = "stopped"   // stopped on request, resumed by starting it again
+	BackfillFailed    = "failed"    // a range failed to load, retried by starting it again
+	BackfillCompleted = "completed" // every range up to the end date is loaded
+)
+
+// Backfill is the persisted progress of a table's backfill
+type Backfill struct {
+	TableName   string    `db:"table_name" json:"table_name"`
+	Status      string    `db:"status" json:"status"`
+	From        time.Time `db:"range_from" json:"from"`
+	To          time.Time `db:"range_to" json:"to"`
+	Next        time.Time `db:"next_start" json:"next"` // start of the next range to load
+	RangesDone  int       `db:"ranges_done" json:"ranges_done"`
+	RangesTotal int       `db:"ranges_total" json:"ranges_total"`
+	RowsSynced  int64     `db:"rows_synced" json:"rows_synced"`
+	Error       string    `db:"error" json:"error,omitempty"`
+	StartedAt   time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// BackfillTable returns the table holding backfill progress, next to the history table
+func (s *Store) BackfillTable() string {
+	schema, table := sqlident.SplitQualified(s.Table, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_backfill")
+}
+
+// backfillSchema returns the statements creating the backfill progress table
+func (s *Store) backfillSchema() []string {
+	return []string{fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			table_name TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			range_from TIMESTAMPTZ NOT NULL,
+			range_to TIMESTAMPTZ NOT NULL,
+			next_start TIMESTAMPTZ NOT NULL,
+			ranges_done INTEGER NOT NULL DEFAULT 0,
+			ranges_total INTEGER NOT NULL DEFAULT 0,
+			rows_synced BIGINT NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`, s.BackfillTable())}
+}
+
+// SaveBackfill stores the progress of a table's backfill, replacing the previous one
+func (s *Store) SaveBackfill(b *Backfill) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (table_name, status, range_from, range_to, next_start, ranges_done, ranges_total, rows_synced, error, started_at, updated_at)
+		VALUES (:table_name, :status, :range_from, :range_to, :next_start, :ranges_done, :ranges_total, :rows_synced, :error, :started_at, :updated_at)
+		ON CONFLICT (table_name) DO UPDATE SET
+			status = EXCLUDED.status,
+			range_from = EXCLUDED.range_from,
+			range_to = EXCLUDED.range_to,
+			next_start = EXCLUDED.next_start,
+			ranges_done = EXCLUDED.ranges_done,
+			ranges_total = EXCLUDED.ranges_total,
+			rows_synced = EXCLUDED.rows_synced,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			updated_at = EXCLUDED.updated_at`, s.BackfillTable())
+	_, err := s.DB.NamedExec(query, b)
+	return err
+}
+
+// GetBackfill returns the backfill progress of a table, nil when it was never backfilled
+func (s *Store) GetBackfill(tableName string) (*Backfill, error) {
+	query := fmt.Sprintf(`
+		SELECT table_name, status, range_from, range_to, next_start, ranges_done, ranges_total, rows_synced, error, started_at, updated_at
+		FROM %s
+		WHERE table_name = $1`, s.BackfillTable())
+
+	var b Backfill
+	if err := s.DB.Get(&b, query, tableName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Backfills returns the backfill progress of every table, by table name
+func (s *Store) Backfills() ([]Backfill, error) {
+	query := fmt.Sprintf(`
+		SELECT table_name, status, range_from, range_to, next_start, ranges_done, ranges_total, rows_synced, error, started_at, updated_at
+		FROM %s
+		ORDER BY table_name`, s.BackfillTable())
+
+	var backfills []Backfill
+	if err := s.DB.Select(&backfills, query); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return backfills, nil
+}
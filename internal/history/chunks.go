@@ -0,0 +1,88 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// Chunked load statuses
+const (
+	ChunksRunning   = "running"   // loading windows, or interrupted and resumed by the table's next sync
+	ChunksCompleted = "completed" // every window is loaded; the next sync starts a new load
+)
+
+// ChunkedLoad is the persisted progress of a table's full load split into date windows
+type ChunkedLoad struct {
+	TableName    string    `db:"table_name" json:"table_name"`
+	Status       string    `db:"status" json:"status"`
+	From         time.Time `db:"window_from" json:"from"`
+	Next         time.Time `db:"next_start" json:"next"` // start of the next window to load
+	WindowsDone  int       `db:"windows_done" json:"windows_done"`
+	WindowsTotal int       `db:"windows_total" json:"windows_total"`
+	RowsSynced   int64     `db:"rows_synced" json:"rows_synced"`
+	Error        string    `db:"error" json:"error,omitempty"`
+	StartedAt    time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ChunksTable returns the table holding chunked load progress, next to the history table
+func (s *Store) ChunksTable() string {
+	schema, table := sqlident.SplitQualified(s.Table, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_chunks")
+}
+
+// chunksSchema returns the statements creating the chunked load progress table
+func (s *Store) chunksSchema() []string {
+	return []string{fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			table_name TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			window_from TIMESTAMPTZ NOT NULL,
+			next_start TIMESTAMPTZ NOT NULL,
+			windows_done INTEGER NOT NULL DEFAULT 0,
+			windows_total INTEGER NOT NULL DEFAULT 0,
+			rows_synced BIGINT NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`, s.ChunksTable())}
+}
+
+// SaveChunkedLoad stores the progress of a table's chunked load, replacing the previous one
+func (s *Store) SaveChunkedLoad(c *ChunkedLoad) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (table_name, status, window_from, next_start, windows_done, windows_total, rows_synced, error, started_at, updated_at)
+		VALUES (:table_name, :status, :window_from, :next_start, :windows_done, :windows_total, :rows_synced, :error, :started_at, :updated_at)
+		ON CONFLICT (table_name) DO UPDATE SET
+			status = EXCLUDED.status,
+			window_from = EXCLUDED.window_from,
+			next_start = EXCLUDED.next_start,
+			windows_done = EXCLUDED.windows_done,
+			windows_total = EXCLUDED.windows_total,
+			rows_synced = EXCLUDED.rows_synced,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			updated_at = EXCLUDED.updated_at`, s.ChunksTable())
+	_, err := s.DB.NamedExec(query, c)
+	return err
+}
+
+// GetChunkedLoad returns the chunked load progress of a table, nil when it was never loaded in chunks
+func (s *Store) GetChunkedLoad(tableName string) (*ChunkedLoad, error) {
+	query := fmt.Sprintf(`
+		SELECT table_name, status, window_from, next_start, windows_done, windows_total, rows_synced, error, started_at, updated_at
+		FROM %s
+		WHERE table_name = $1`, s.ChunksTable())
+
+	var c ChunkedLoad
+	if err := s.DB.Get(&c, query, tableName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,197 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// DefaultTable is the target table used to persist sync history
+const DefaultTable = "public.sync_history"
+
+// History record kinds: table syncs, loads into fan-out targets and target maintenance operations
+const (
+	KindSync       = "sync"
+	KindFanout     = "fanout"
+	KindAnalyze    = "analyze"
+	KindVacuum     = "vacuum"
+	KindVacuumFull = "vacuum_full"
+)
+
+// Record represents a single sync run or maintenance operation
+type Record struct {
+	ID              int64      `db:"id" json:"id"`
+	Kind            string     `db:"kind" json:"kind"`
+	BatchID         string     `db:"batch_id" json:"batch_id"`
+	TableName       string     `db:"table_name" json:"table_name"`
+	StartedAt       time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt      time.Time  `db:"finished_at" json:"finished_at"`
+	DurationMs      int64      `db:"duration_ms" json:"duration_ms"`
+	Success         bool       `db:"success" json:"success"`
+	Error           string     `db:"error" json:"error,omitempty"`
+	RowsSynced      int64      `db:"rows_synced" json:"rows_synced"`
+	RowsRead        int64      `db:"rows_read" json:"rows_read"`
+	RowsSkipped     int64      `db:"rows_skipped" json:"rows_skipped"`
+	BytesRead       int64      `db:"bytes_read" json:"bytes_read"`
+	SourceChangedAt *time.Time `db:"source_changed_at" json:"source_changed_at,omitempty"`
+	Validation      string     `db:"validation" json:"-"`            // JSON validation report, empty when the table has no rules
+	Target          string     `db:"target" json:"target,omitempty"` // fan-out target of a fanout record
+	Stack           string     `db:"stack" json:"stack,omitempty"`   // stack trace of a sync that panicked
+}
+
+// Store persists sync history to the target database
+type Store struct {
+	DB     *sqlx.DB
+	Table  string
+	Logger *zap.Logger
+}
+
+// NewStore creates a new history store
+func NewStore(db *sqlx.DB, table string, logger *zap.Logger) *Store {
+	if strings.TrimSpace(table) == "" {
+		table = DefaultTable
+	}
+	return &Store{
+		DB:     db,
+		Table:  table,
+		Logger: logger,
+	}
+}
+
+// EnsureSchema creates and upgrades the service's tables by applying the pending migrations
+func (s *Store) EnsureSchema() error {
+	return s.Migrate()
+}
+
+// historySchema returns the statements creating the history table as it was before migrations were versioned
+func (s *Store) historySchema() []string {
+	statements := []string{fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			batch_id TEXT NOT NULL,
+			table_name TEXT NOT NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			finished_at TIMESTAMPTZ NOT NULL,
+			duration_ms BIGINT NOT NULL,
+			success BOOLEAN NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			rows_synced BIGINT NOT NULL DEFAULT 0,
+			source_changed_at TIMESTAMPTZ
+		)`, sqlident.Postgres(s.Table))}
+
+	// Columns added after the table was first released
+	for _, column := range []string{
+		"rows_read BIGINT NOT NULL DEFAULT 0",
+		"rows_skipped BIGINT NOT NULL DEFAULT 0",
+		"bytes_read BIGINT NOT NULL DEFAULT 0",
+		"kind TEXT NOT NULL DEFAULT 'sync'",
+		"validation JSONB",
+		"target TEXT NOT NULL DEFAULT ''",
+	} {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", sqlident.Postgres(s.Table), column))
+	}
+
+	return append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (table_name, started_at DESC)",
+		sqlident.PostgresColumn(indexPrefix(s.Table)+"_table_started_idx"), sqlident.Postgres(s.Table)))
+}
+
+// Record persists a sync run
+func (s *Store) Record(rec Record) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (kind, batch_id, table_name, started_at, finished_at, duration_ms, success, error, rows_synced, rows_read, rows_skipped, bytes_read, source_changed_at, validation, target, stack)
+		VALUES (:kind, :batch_id, :table_name, :started_at, :finished_at, :duration_ms, :success, :error, :rows_synced, :rows_read, :rows_skipped, :bytes_read, :source_changed_at, CAST(NULLIF(:validation, '') AS JSONB), :target, :stack)`, sqlident.Postgres(s.Table))
+	_, err := s.DB.NamedExec(query, rec)
+	return err
+}
+
+// Recent returns the most recent sync runs for a table, newest first
+func (s *Store) Recent(tableName string, limit int) ([]Record, error) {
+	return s.recent(tableName, "= 'sync'", limit)
+}
+
+// RecentMaintenance returns the most recent maintenance operations for a table, newest first
+func (s *Store) RecentMaintenance(tableName string, limit int) ([]Record, error) {
+	return s.recent(tableName, "NOT IN ('sync', 'fanout')", limit)
+}
+
+// RecentFanout returns the most recent loads of a table into its fan-out targets, newest first
+func (s *Store) RecentFanout(tableName string, limit int) ([]Record, error) {
+	return s.recent(tableName, "= 'fanout'", limit)
+}
+
+func (s *Store) recent(tableName, kindCondition string, limit int) ([]Record, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := fmt.Sprintf(`
+		SELECT id, kind, batch_id, table_name, started_at, finished_at, duration_ms, success, error, rows_synced, rows_read, rows_skipped, bytes_read, source_changed_at,
+			COALESCE(validation::text, '') AS validation, target, stack
+		FROM %s
+		WHERE table_name = $1 AND kind %s
+		ORDER BY started_at DESC
+		LIMIT $2`, sqlident.Postgres(s.Table), kindCondition)
+
+	var records []Record
+	if err := s.DB.Select(&records, query, tableName, limit); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return records, nil
+}
+
+// LastRun summarizes the latest sync runs of a table
+type LastRun struct {
+	TableName   string     `db:"table_name"`
+	LastAttempt time.Time  `db:"last_attempt"`
+	LastSuccess *time.Time `db:"last_success"`
+	LastError   string     `db:"last_error"`
+}
+
+// LastRuns returns the last attempted and last successful run of every table
+func (s *Store) LastRuns() ([]LastRun, error) {
+	table := sqlident.Postgres(s.Table)
+	query := fmt.Sprintf(`
+		SELECT DISTINCT ON (r.table_name)
+			r.table_name,
+			r.finished_at AS last_attempt,
+			(SELECT MAX(s.finished_at) FROM %s s WHERE s.table_name = r.table_name AND s.kind = 'sync' AND s.success) AS last_success,
+			CASE WHEN r.success THEN '' ELSE r.error END AS last_error
+		FROM %s r
+		WHERE r.kind = 'sync'
+		ORDER BY r.table_name, r.finished_at DESC`, table, table)
+
+	var runs []LastRun
+	if err := s.DB.Select(&runs, query); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// LastSuccess returns the latest successful sync run of a table, nil when it never synced successfully
+func (s *Store) LastSuccess(tableName string) (*Record, error) {
+	query := fmt.Sprintf(`
+		SELECT id, kind, batch_id, table_name, started_at, finished_at, duration_ms, success, error, rows_synced, rows_read, rows_skipped, bytes_read, source_changed_at,
+			COALESCE(validation::text, '') AS validation
+		FROM %s
+		WHERE table_name = $1 AND kind = 'sync' AND success
+		ORDER BY started_at DESC
+		LIMIT 1`, sqlident.Postgres(s.Table))
+
+	var rec Record
+	if err := s.DB.Get(&rec, query, tableName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func indexPrefix(table string) string {
+	return strings.Join(sqlident.Split(table), "_")
+}
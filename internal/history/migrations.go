@@ -0,0 +1,108 @@
+package history
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// migration is a versioned change to the tables the service owns next to the history table. Statements stay
+// idempotent, so databases whose tables were created before migrations were versioned adopt them without errors
+type migration struct {
+	version    int
+	name       string
+	statements func(s *Store) []string
+}
+
+// migrations are applied in order, each once. New schema changes are appended with the next version; applied
+// migrations are never edited, reordered or removed
+var migrations = []migration{
+	{1, "create_sync_history", (*Store).historySchema},
+	{2, "create_quarantine", (*Store).quarantineSchema},
+	{3, "create_backfill", (*Store).backfillSchema},
+	{4, "create_chunks", (*Store).chunksSchema},
+	{5, "add_sync_history_stack", func(s *Store) []string {
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS stack TEXT NOT NULL DEFAULT ''", sqlident.Postgres(s.Table))}
+	}},
+	{6, "create_preferences", (*Store).preferencesSchema},
+}
+
+// MigrationsTable returns the table recording the applied migrations, next to the history table
+func (s *Store) MigrationsTable() string {
+	schema, table := sqlident.SplitQualified(s.Table, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_migrations")
+}
+
+// Migrate applies the migrations not yet recorded in the migrations table, each in its own transaction
+func (s *Store) Migrate() error {
+	for _, m := range migrations {
+		applied, err := s.applyMigration(m)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		if applied {
+			s.Logger.Info("Applied schema migration",
+				zap.Int("version", m.version),
+				zap.String("name", m.name),
+			)
+		}
+	}
+
+	var current int
+	query := fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s", s.MigrationsTable())
+	if err := s.DB.Get(&current, query); err != nil {
+		return err
+	}
+	if latest := migrations[len(migrations)-1].version; current > latest {
+		s.Logger.Warn("Database schema is newer than this service version",
+			zap.Int("schema_version", current),
+			zap.Int("service_version", latest),
+		)
+	}
+	return nil
+}
+
+// applyMigration runs a migration and records it unless it was applied before, reporting whether it ran. An
+// advisory lock held until commit keeps instances starting together from applying it twice
+func (s *Store) applyMigration(m migration) (bool, error) {
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", s.MigrationsTable()); err != nil {
+		return false, err
+	}
+	createQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`, s.MigrationsTable())
+	if _, err := tx.Exec(createQuery); err != nil {
+		return false, err
+	}
+
+	var applied bool
+	existsQuery := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE version = $1)", s.MigrationsTable())
+	if err := tx.Get(&applied, existsQuery, m.version); err != nil {
+		return false, err
+	}
+	if applied {
+		return false, nil
+	}
+
+	for _, statement := range m.statements(s) {
+		if _, err := tx.Exec(statement); err != nil {
+			return false, err
+		}
+	}
+	insertQuery := fmt.Sprintf("INSERT INTO %s (version, name) VALUES ($1, $2)", s.MigrationsTable())
+	if _, err := tx.Exec(insertQuery, m.version, m.name); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
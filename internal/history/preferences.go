@@ -0,0 +1,101 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ColumnPreferences are the columns a user hides and the order they want them in for a projection
+type ColumnPreferences struct {
+	Identity     string         `db:"identity" json:"-"`
+	ProjectionID string         `db:"projection_id" json:"projection_id"`
+	Hidden       pq.StringArray `db:"hidden_columns" json:"hidden"`
+	Order        pq.StringArray `db:"column_order" json:"order"` // columns not listed follow in projection order
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// PreferencesTable returns the table holding column preferences, next to the history table
+func (s *Store) PreferencesTable() string {
+	schema, table := sqlident.SplitQualified(s.Table, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_preferences")
+}
+
+// preferencesSchema returns the statements creating the column preferences table
+func (s *Store) preferencesSchema() []string {
+	return []string{fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			identity TEXT NOT NULL,
+			projection_id TEXT NOT NULL,
+			hidden_columns TEXT[] NOT NULL DEFAULT '{}',
+			column_order TEXT[] NOT NULL DEFAULT '{}',
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (identity, projection_id)
+		)`, s.PreferencesTable())}
+}
+
+// SavePreferences stores a user's column preferences for a projection, replacing the previous ones
+func (s *Store) SavePreferences(p *ColumnPreferences) error {
+	if p.Hidden == nil {
+		p.Hidden = pq.StringArray{}
+	}
+	if p.Order == nil {
+		p.Order = pq.StringArray{}
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (identity, projection_id, hidden_columns, column_order, updated_at)
+		VALUES (:identity, :projection_id, :hidden_columns, :column_order, :updated_at)
+		ON CONFLICT (identity, projection_id) DO UPDATE SET
+			hidden_columns = EXCLUDED.hidden_columns,
+			column_order = EXCLUDED.column_order,
+			updated_at = EXCLUDED.updated_at`, s.PreferencesTable())
+	_, err := s.DB.NamedExec(query, p)
+	return err
+}
+
+// GetPreferences returns a user's column preferences for a projection, nil when none were saved
+func (s *Store) GetPreferences(identity, projectionID string) (*ColumnPreferences, error) {
+	query := fmt.Sprintf(`
+		SELECT identity, projection_id, hidden_columns, column_order, updated_at
+		FROM %s
+		WHERE identity = $1 AND projection_id = $2`, s.PreferencesTable())
+
+	var p ColumnPreferences
+	if err := s.DB.Get(&p, query, identity, projectionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Preferences returns a user's column preferences of every projection, by projection id
+func (s *Store) Preferences(identity string) ([]ColumnPreferences, error) {
+	query := fmt.Sprintf(`
+		SELECT identity, projection_id, hidden_columns, column_order, updated_at
+		FROM %s
+		WHERE identity = $1
+		ORDER BY projection_id`, s.PreferencesTable())
+
+	var preferences []ColumnPreferences
+	if err := s.DB.Select(&preferences, query, identity); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// DeletePreferences removes a user's column preferences for a projection, reporting whether any were saved
+func (s *Store) DeletePreferences(identity, projectionID string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE identity = $1 AND projection_id = $2", s.PreferencesTable())
+	result, err := s.DB.Exec(query, identity, projectionID)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
@@ -0,0 +1,103 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// QuarantinedRow represents a source row held back by a validation rule
+type QuarantinedRow struct {
+	ID            int64           `db:"id" json:"id"`
+	BatchID       string          `db:"batch_id" json:"batch_id"`
+	TableName     string          `db:"table_name" json:"table_name"`
+	Rules         string          `db:"rules" json:"rules"` // comma separated names of the violated rules
+	Row           json.RawMessage `db:"row_data" json:"row"`
+	QuarantinedAt time.Time       `db:"quarantined_at" json:"quarantined_at"`
+}
+
+// QuarantineTable returns the table holding quarantined rows, next to the history table
+func (s *Store) QuarantineTable() string {
+	schema, table := sqlident.SplitQualified(s.Table, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_quarantine")
+}
+
+// quarantineSchema returns the statements creating the quarantine table
+func (s *Store) quarantineSchema() []string {
+	return []string{
+		fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			batch_id TEXT NOT NULL,
+			table_name TEXT NOT NULL,
+			rules TEXT NOT NULL,
+			row_data JSONB NOT NULL,
+			quarantined_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`, s.QuarantineTable()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (table_name, quarantined_at DESC)",
+			sqlident.PostgresColumn(indexPrefix(s.Table)+"_quarantine_table_idx"), s.QuarantineTable()),
+	}
+}
+
+// Quarantine stores rows that violated validation rules in a single transaction
+func (s *Store) Quarantine(rows []QuarantinedRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("INSERT INTO %s (batch_id, table_name, rules, row_data) VALUES ($1, $2, $3, CAST($4 AS JSONB))", s.QuarantineTable())
+	for _, row := range rows {
+		if _, err := tx.Exec(query, row.BatchID, row.TableName, row.Rules, string(row.Row)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Quarantined returns the most recently quarantined rows of a table, newest first
+func (s *Store) Quarantined(tableName string, limit int) ([]QuarantinedRow, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := fmt.Sprintf(`
+		SELECT id, batch_id, table_name, rules, row_data, quarantined_at
+		FROM %s
+		WHERE table_name = $1
+		ORDER BY quarantined_at DESC, id DESC
+		LIMIT $2`, s.QuarantineTable())
+
+	var rows []QuarantinedRow
+	if err := s.DB.Select(&rows, query, tableName, limit); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// LastValidation returns the latest sync run of a table that produced a validation report
+func (s *Store) LastValidation(tableName string) (*Record, error) {
+	query := fmt.Sprintf(`
+		SELECT id, kind, batch_id, table_name, started_at, finished_at, duration_ms, success, error, rows_synced, rows_read, rows_skipped, bytes_read, source_changed_at,
+			validation::text AS validation
+		FROM %s
+		WHERE table_name = $1 AND kind = 'sync' AND validation IS NOT NULL
+		ORDER BY started_at DESC
+		LIMIT 1`, sqlident.Postgres(s.Table))
+
+	var rec Record
+	if err := s.DB.Get(&rec, query, tableName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
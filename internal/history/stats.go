@@ -0,0 +1,160 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// TableStats summarises recent sync runs for a table
+type TableStats struct {
+	TableName         string       `json:"table_name"`
+	Runs              int          `json:"runs"`
+	Failures          int          `json:"failures"`
+	FailureRate       float64      `json:"failure_rate"`
+	AvgDurationMs     float64      `json:"avg_duration_ms"`
+	MaxDurationMs     int64        `json:"max_duration_ms"`
+	AvgRowsPerSecond  float64      `json:"avg_rows_per_second"`
+	AvgBytesPerSecond float64      `json:"avg_bytes_per_second"`
+	TotalRowsRead     int64        `json:"total_rows_read"`
+	TotalRowsSkipped  int64        `json:"total_rows_skipped"`
+	TotalBytesRead    int64        `json:"total_bytes_read"`
+	AvgLagSeconds     *float64     `json:"avg_lag_seconds,omitempty"`
+	From              *time.Time   `json:"from,omitempty"`
+	To                *time.Time   `json:"to,omitempty"`
+	Trend             []TrendPoint `json:"trend"`
+}
+
+// TrendPoint aggregates sync runs for a single day
+type TrendPoint struct {
+	Date             string  `json:"date"`
+	Runs             int     `json:"runs"`
+	Failures         int     `json:"failures"`
+	AvgDurationMs    float64 `json:"avg_duration_ms"`
+	AvgRowsPerSecond float64 `json:"avg_rows_per_second"`
+}
+
+// ComputeStats aggregates a set of sync runs into table statistics
+func ComputeStats(tableName string, records []Record) TableStats {
+	stats := TableStats{TableName: tableName, Trend: []TrendPoint{}}
+	if len(records) == 0 {
+		return stats
+	}
+
+	var (
+		totalDuration int64
+		byteRateSum   float64
+		throughputSum float64
+		throughputN   int
+		days          = make(map[string]*trendAccumulator)
+	)
+
+	for _, rec := range records {
+		stats.Runs++
+		totalDuration += rec.DurationMs
+		if rec.DurationMs > stats.MaxDurationMs {
+			stats.MaxDurationMs = rec.DurationMs
+		}
+
+		startedAt := rec.StartedAt
+		if stats.From == nil || startedAt.Before(*stats.From) {
+			stats.From = &startedAt
+		}
+		if stats.To == nil || startedAt.After(*stats.To) {
+			stats.To = &startedAt
+		}
+
+		day := rec.StartedAt.UTC().Format("2006-01-02")
+		acc, ok := days[day]
+		if !ok {
+			acc = &trendAccumulator{}
+			days[day] = acc
+		}
+		acc.runs++
+		acc.duration += rec.DurationMs
+		stats.TotalRowsRead += rec.RowsRead
+		stats.TotalRowsSkipped += rec.RowsSkipped
+		stats.TotalBytesRead += rec.BytesRead
+
+		if !rec.Success {
+			stats.Failures++
+			acc.failures++
+			continue
+		}
+
+		if rec.DurationMs > 0 {
+			rate := float64(rec.RowsSynced) / (float64(rec.DurationMs) / 1000)
+			throughputSum += rate
+			byteRateSum += float64(rec.BytesRead) / (float64(rec.DurationMs) / 1000)
+			throughputN++
+			acc.throughput += rate
+			acc.throughputN++
+		}
+
+	}
+
+	stats.FailureRate = float64(stats.Failures) / float64(stats.Runs)
+	stats.AvgDurationMs = float64(totalDuration) / float64(stats.Runs)
+	if throughputN > 0 {
+		stats.AvgRowsPerSecond = throughputSum / float64(throughputN)
+		stats.AvgBytesPerSecond = byteRateSum / float64(throughputN)
+	}
+	stats.AvgLagSeconds = averageLag(records)
+
+	for day, acc := range days {
+		point := TrendPoint{
+			Date:          day,
+			Runs:          acc.runs,
+			Failures:      acc.failures,
+			AvgDurationMs: float64(acc.duration) / float64(acc.runs),
+		}
+		if acc.throughputN > 0 {
+			point.AvgRowsPerSecond = acc.throughput / float64(acc.throughputN)
+		}
+		stats.Trend = append(stats.Trend, point)
+	}
+	sort.Slice(stats.Trend, func(i, j int) bool {
+		return stats.Trend[i].Date < stats.Trend[j].Date
+	})
+
+	return stats
+}
+
+type trendAccumulator struct {
+	runs        int
+	failures    int
+	duration    int64
+	throughput  float64
+	throughputN int
+}
+
+// averageLag returns the average seconds from the newest change_column value a successful run picked up to the end
+// of that run, over runs whose newest change is more recent than the start of the previous successful run. Runs
+// that found nothing new are left out, as on a quiet table their newest change only tells the age of the newest
+// row. The first successful run of the records has no previous run to compare with. Nil when no run qualifies
+func averageLag(records []Record) *float64 {
+	runs := make([]Record, 0, len(records))
+	for _, rec := range records {
+		if rec.Success && (rec.Kind == "" || rec.Kind == KindSync) {
+			runs = append(runs, rec)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+
+	var sum float64
+	var n int
+	for i := 1; i < len(runs); i++ {
+		changed := runs[i].SourceChangedAt
+		if changed == nil || !changed.After(runs[i-1].StartedAt) {
+			continue
+		}
+		sum += runs[i].FinishedAt.Sub(*changed).Seconds()
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	avg := sum / float64(n)
+	return &avg
+}
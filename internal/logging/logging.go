@@ -0,0 +1,167 @@
+// Package logging builds the service loggers from configuration and allows changing levels at runtime.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Logging modules with individually configurable levels
+const (
+	ModuleAPI      = "api"
+	ModuleSync     = "sync"
+	ModuleActor    = "actor"
+	ModuleDatabase = "database"
+)
+
+// Manager owns the shared log output and the level of every module
+type Manager struct {
+	Logger *zap.Logger
+
+	encoder  zapcore.Encoder
+	output   zapcore.WriteSyncer
+	sampling *config.LogSampling
+
+	mu     sync.Mutex
+	levels map[string]zap.AtomicLevel
+}
+
+// NewManager creates the root logger and module levels from the logging configuration
+func NewManager(cfg config.LoggingConfig) (*Manager, error) {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s", cfg.Format)
+	}
+
+	outputPaths := cfg.OutputPaths
+	if len(outputPaths) == 0 {
+		outputPaths = []string{"stdout"}
+	}
+	output, _, err := zap.Open(outputPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output: %w", err)
+	}
+
+	sampling := cfg.Sampling
+	if sampling == nil {
+		sampling = &config.LogSampling{Initial: 100, Thereafter: 100}
+	}
+
+	m := &Manager{
+		encoder:  encoder,
+		output:   output,
+		sampling: sampling,
+		levels:   map[string]zap.AtomicLevel{"": zap.NewAtomicLevelAt(level)},
+	}
+
+	for _, module := range []string{ModuleAPI, ModuleSync, ModuleActor, ModuleDatabase} {
+		m.levels[module] = zap.NewAtomicLevelAt(level)
+	}
+	for module, name := range cfg.Modules {
+		moduleLevel, err := parseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		m.levels[module] = zap.NewAtomicLevelAt(moduleLevel)
+	}
+
+	m.Logger = m.newLogger(m.levels[""])
+	return m, nil
+}
+
+// For returns the logger of a module, named after it and filtered by its level
+func (m *Manager) For(module string) *zap.Logger {
+	m.mu.Lock()
+	level, ok := m.levels[module]
+	if !ok {
+		level = zap.NewAtomicLevelAt(m.levels[""].Level())
+		m.levels[module] = level
+	}
+	m.mu.Unlock()
+
+	return m.newLogger(level).Named(module)
+}
+
+// Levels returns the current level of every module, with the default level under "default"
+func (m *Manager) Levels() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	levels := make(map[string]string, len(m.levels))
+	for module, level := range m.levels {
+		if module == "" {
+			module = "default"
+		}
+		levels[module] = level.Level().String()
+	}
+	return levels
+}
+
+// SetLevel changes the level of a module at runtime. An empty module changes the default and every module
+func (m *Manager) SetLevel(module, name string) error {
+	level, err := parseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if module == "" || module == "default" {
+		for _, atomic := range m.levels {
+			atomic.SetLevel(level)
+		}
+		return nil
+	}
+
+	atomic, ok := m.levels[module]
+	if !ok {
+		return fmt.Errorf("unknown logging module: %s", module)
+	}
+	atomic.SetLevel(level)
+	return nil
+}
+
+// Sync flushes buffered log entries
+func (m *Manager) Sync() error {
+	return m.output.Sync()
+}
+
+func (m *Manager) newLogger(level zap.AtomicLevel) *zap.Logger {
+	core := zapcore.NewCore(m.encoder, m.output, level)
+	if m.sampling.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, m.sampling.Initial, m.sampling.Thereafter)
+	}
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+}
+
+func parseLevel(name string) (zapcore.Level, error) {
+	if name == "" {
+		return zapcore.InfoLevel, nil
+	}
+	level, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return level, fmt.Errorf("invalid log level: %s", name)
+	}
+	return level, nil
+}
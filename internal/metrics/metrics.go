@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// SyncRunsTotal counts completed sync runs by table and status
+	SyncRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_runs_total",
+		Help: "Number of completed table syncs by status.",
+	}, []string{"table", "status"})
+
+	// SyncPanicsTotal counts table syncs that panicked and were failed instead of crashing their actor
+	SyncPanicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_panics_total",
+		Help: "Number of table syncs that panicked.",
+	}, []string{"table"})
+
+	// SyncTargetRunsTotal counts loads into fan-out targets by table, target and status
+	SyncTargetRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_target_runs_total",
+		Help: "Number of table loads into fan-out targets by status.",
+	}, []string{"table", "target", "status"})
+
+	// PipelineRunsTotal counts finished post-sync pipeline runs by pipeline and status
+	PipelineRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pipeline_runs_total",
+		Help: "Number of post-sync pipeline runs by status.",
+	}, []string{"pipeline", "status"})
+
+	// SyncDurationSeconds observes how long table syncs take
+	SyncDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sync_duration_seconds",
+		Help:    "Duration of table syncs in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+	}, []string{"table"})
+
+	// SyncRowsTotal counts rows processed by table syncs by stage
+	SyncRowsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_rows_total",
+		Help: "Number of rows processed by table syncs by stage (read, written, skipped).",
+	}, []string{"table", "stage"})
+
+	// SyncBytesTotal counts the approximate bytes read from the source
+	SyncBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_bytes_read_total",
+		Help: "Approximate number of bytes read from the source by table syncs.",
+	}, []string{"table"})
+
+	// SyncReadThrottledSeconds counts the time source reads were paused by a read throttle
+	SyncReadThrottledSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_read_throttled_seconds_total",
+		Help: "Seconds source reads were paused to stay within the table's read throttle.",
+	}, []string{"table"})
+
+	// SyncSourceRowsEstimated reports the source row count estimated before the last sync started
+	SyncSourceRowsEstimated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sync_source_rows_estimated",
+		Help: "Source rows of a table estimated by the preflight check before its last sync.",
+	}, []string{"table"})
+
+	// TableSecondsSinceSuccess reports the time since the last successful sync
+	TableSecondsSinceSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sync_table_seconds_since_success",
+		Help: "Seconds since the last successful sync of a table.",
+	}, []string{"table"})
+
+	// TableStale is 1 while a table breaches its staleness SLO
+	TableStale = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sync_table_stale",
+		Help: "Whether a table is breaching its max_staleness SLO (1) or not (0).",
+	}, []string{"table"})
+	// ActorRestartsTotal counts supervised restarts of sync actors
+	ActorRestartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_actor_restarts_total",
+		Help: "Number of times a sync actor crashed and was handled by the supervisor.",
+	}, []string{"table"})
+	// ActorWatchdogRestartsTotal counts sync actors replaced by the watchdog
+	ActorWatchdogRestartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_actor_watchdog_restarts_total",
+		Help: "Number of times the watchdog replaced a sync actor stuck in a sync (stuck_sync) or not answering pings (no_pong).",
+	}, []string{"table", "reason"})
+	// CircuitOpen is 1 while a database circuit breaker is open
+	CircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_circuit_open",
+		Help: "Whether the circuit breaker of a database connection is open (1) or closed (0).",
+	}, []string{"connection"})
+	// QueryDurationSeconds observes database query execution time by connection and table or projection
+	QueryDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database queries in seconds by connection and table or projection.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"connection", "context"})
+	// StatementCacheTotal counts prepared statement cache lookups and evictions by connection
+	StatementCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_statement_cache_total",
+		Help: "Prepared statement cache events by connection and result (hit, miss, evicted).",
+	}, []string{"connection", "result"})
+	// APIKeyRequestsTotal counts projection and OData requests by API key and projection
+	APIKeyRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "api_key_requests_total",
+		Help: "Projection and OData requests served by API key and projection.",
+	}, []string{"key", "projection"})
+	// APIKeyRowsTotal counts projection rows returned by API key and projection
+	APIKeyRowsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "api_key_rows_total",
+		Help: "Projection rows returned by API key and projection.",
+	}, []string{"key", "projection"})
+	// APIKeyBytesTotal counts response bytes served by API key and projection
+	APIKeyBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "api_key_bytes_total",
+		Help: "Response bytes served by API key and projection.",
+	}, []string{"key", "projection"})
+	// ActorMailboxDepth reports the number of messages queued for an actor
+	ActorMailboxDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "actor_mailbox_depth",
+		Help: "Messages queued in an actor mailbox, including the one being processed.",
+	}, []string{"actor"})
+	// ActorMessageDurationSeconds observes how long actors take to process a message
+	ActorMessageDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "actor_message_duration_seconds",
+		Help:    "Time spent processing an actor message in seconds by actor and message type.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 4, 12),
+	}, []string{"actor", "message"})
+	// ActorDeadLettersTotal counts messages that could not be delivered to an actor
+	ActorDeadLettersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "actor_dead_letters_total",
+		Help: "Number of messages sent to stopped or unknown actors by message type.",
+	}, []string{"message"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		SyncRunsTotal,
+		SyncPanicsTotal,
+		SyncTargetRunsTotal,
+		PipelineRunsTotal,
+		SyncDurationSeconds,
+		SyncRowsTotal,
+		SyncBytesTotal,
+		SyncReadThrottledSeconds,
+		SyncSourceRowsEstimated,
+		TableSecondsSinceSuccess,
+		TableStale,
+		ActorRestartsTotal,
+		ActorWatchdogRestartsTotal,
+		CircuitOpen,
+		QueryDurationSeconds,
+		StatementCacheTotal,
+		APIKeyRequestsTotal,
+		APIKeyRowsTotal,
+		APIKeyBytesTotal,
+		ActorMailboxDepth,
+		ActorMessageDurationSeconds,
+		ActorDeadLettersTotal,
+	)
+}
+
+// Handler returns the HTTP handler exposing the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// azureAPIVersion is the Blob service version sent with every request
+const azureAPIVersion = "2021-08-06"
+
+// AzureSink stores snapshots as block blobs in an Azure Storage container authorized by a SAS token
+type AzureSink struct {
+	ContainerURL string
+	SASToken     string
+	Client       *http.Client
+}
+
+func newAzureSink(dest config.SnapshotDestination) (*AzureSink, error) {
+	if dest.ContainerURL == "" || dest.SASToken == "" {
+		return nil, fmt.Errorf("azure destination requires container_url and sas_token")
+	}
+	return &AzureSink{
+		ContainerURL: strings.TrimRight(dest.ContainerURL, "/"),
+		SASToken:     strings.TrimPrefix(dest.SASToken, "?"),
+		Client:       &http.Client{Timeout: 30 * time.Minute},
+	}, nil
+}
+
+// Put uploads a snapshot file as a block blob
+func (s *AzureSink) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.blobURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	_, err = s.do(req)
+	return err
+}
+
+// List returns the blobs below prefix
+func (s *AzureSink) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.ContainerURL+"?"+query.Encode()+"&"+s.SASToken, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Blobs []struct {
+				Name         string `xml:"Name"`
+				LastModified string `xml:"Properties>Last-Modified"`
+			} `xml:"Blobs>Blob"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse container listing: %w", err)
+		}
+		for _, blob := range result.Blobs {
+			modified, _ := time.Parse(time.RFC1123, blob.LastModified)
+			objects = append(objects, Object{Key: blob.Name, Modified: modified})
+		}
+		if result.NextMarker == "" {
+			return objects, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// Delete removes a blob
+func (s *AzureSink) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.blobURL(key), nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(req)
+	return err
+}
+
+func (s *AzureSink) blobURL(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.ContainerURL + "/" + strings.Join(segments, "/") + "?" + s.SASToken
+}
+
+func (s *AzureSink) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("azure %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
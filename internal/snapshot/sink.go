@@ -0,0 +1,116 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Object is a stored snapshot file
+type Object struct {
+	Key      string
+	Modified time.Time
+}
+
+// Sink stores snapshot files
+type Sink interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewSink creates the sink for a snapshot destination
+func NewSink(dest config.SnapshotDestination) (Sink, error) {
+	switch strings.ToLower(dest.Type) {
+	case "local", "":
+		if dest.Path == "" {
+			return nil, fmt.Errorf("local destination requires path")
+		}
+		return &LocalSink{Root: dest.Path}, nil
+	case "s3":
+		return newS3Sink(dest)
+	case "azure":
+		return newAzureSink(dest)
+	default:
+		return nil, fmt.Errorf("unsupported snapshot destination type: %s", dest.Type)
+	}
+}
+
+// LocalSink stores snapshots on the local filesystem
+type LocalSink struct {
+	Root string
+}
+
+// Put writes the snapshot file and marks it read-only
+func (s *LocalSink) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path := filepath.Join(s.Root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Chmod(path, 0o444)
+}
+
+// List returns the snapshot files below prefix
+func (s *LocalSink) List(ctx context.Context, prefix string) ([]Object, error) {
+	root := filepath.Join(s.Root, filepath.FromSlash(prefix))
+	var objects []Object
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(path, ".partial") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.Root, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: filepath.ToSlash(rel), Modified: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+// Delete removes a snapshot file
+func (s *LocalSink) Delete(ctx context.Context, key string) error {
+	path := filepath.Join(s.Root, filepath.FromSlash(key))
+	if err := os.Chmod(path, 0o644); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
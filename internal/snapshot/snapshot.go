@@ -0,0 +1,261 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Exporter writes a projection's data in the given format
+type Exporter interface {
+	ExportProjection(ctx context.Context, w io.Writer, projectionID, format string) (int, error)
+}
+
+// job is a configured snapshot with its resolved sink
+type job struct {
+	config   config.SnapshotConfig
+	format   string
+	location *time.Location
+	sink     Sink
+}
+
+// Scheduler runs projection snapshots on their schedules
+type Scheduler struct {
+	Exporter Exporter
+	Logger   *zap.Logger
+
+	jobs   []*job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler validates the snapshot configuration and creates a scheduler
+func NewScheduler(cfg *config.Config, exporter Exporter, logger *zap.Logger) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		Exporter: exporter,
+		Logger:   logger,
+	}
+
+	for _, snapCfg := range cfg.Snapshots {
+		if snapCfg.Name == "" {
+			return nil, fmt.Errorf("snapshot name is required")
+		}
+		projection, ok := cfg.GetProjectionByID(snapCfg.Projection)
+		if !ok {
+			return nil, fmt.Errorf("snapshot %s: projection not found: %s", snapCfg.Name, snapCfg.Projection)
+		}
+		if projection.IsSourcePassthrough() {
+			return nil, fmt.Errorf("snapshot %s: projection %s queries the source and cannot be exported", snapCfg.Name, snapCfg.Projection)
+		}
+
+		format := strings.ToLower(snapCfg.Format)
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "parquet" {
+			return nil, fmt.Errorf("snapshot %s: unsupported format %s", snapCfg.Name, snapCfg.Format)
+		}
+
+		if snapCfg.At == "" && snapCfg.Interval <= 0 {
+			return nil, fmt.Errorf("snapshot %s: interval or at is required", snapCfg.Name)
+		}
+		if snapCfg.At != "" {
+			if _, err := time.Parse("15:04", snapCfg.At); err != nil {
+				return nil, fmt.Errorf("snapshot %s: invalid at %q, expected HH:MM", snapCfg.Name, snapCfg.At)
+			}
+		}
+
+		location := time.UTC
+		if snapCfg.Timezone != "" {
+			loc, err := time.LoadLocation(snapCfg.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s: invalid timezone: %w", snapCfg.Name, err)
+			}
+			location = loc
+		}
+
+		sink, err := NewSink(snapCfg.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", snapCfg.Name, err)
+		}
+
+		scheduler.jobs = append(scheduler.jobs, &job{
+			config:   snapCfg,
+			format:   format,
+			location: location,
+			sink:     sink,
+		})
+	}
+
+	return scheduler, nil
+}
+
+// Start schedules every configured snapshot
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+
+	if len(s.jobs) > 0 {
+		s.Logger.Info("Snapshot scheduler started", zap.Int("snapshots", len(s.jobs)))
+	}
+}
+
+// Stop cancels scheduled snapshots and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.next(time.Now())
+		s.Logger.Debug("Next snapshot scheduled",
+			zap.String("snapshot", j.config.Name),
+			zap.Time("at", next),
+		)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.run(ctx, j); err != nil {
+			s.Logger.Error("Snapshot failed",
+				zap.String("snapshot", j.config.Name),
+				zap.String("projection", j.config.Projection),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// next returns the time of the next snapshot after now
+func (j *job) next(now time.Time) time.Time {
+	if j.config.At == "" {
+		return now.Add(time.Duration(j.config.Interval) * time.Second)
+	}
+
+	clock, _ := time.Parse("15:04", j.config.At)
+	local := now.In(j.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, j.location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// run exports the projection to a temporary file, uploads it and applies retention
+func (s *Scheduler) run(ctx context.Context, j *job) error {
+	started := time.Now().UTC()
+
+	file, err := os.CreateTemp("", "snapshot-*."+j.format)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	rows, err := s.Exporter.ExportProjection(ctx, file, j.config.Projection, j.format)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := path.Join(j.prefix(), fmt.Sprintf("%s-%s.%s", j.config.Name, started.Format("20060102T150405Z"), j.format))
+	if err := j.sink.Put(ctx, key, file, size, contentType(j.format)); err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	s.Logger.Info("Snapshot written",
+		zap.String("snapshot", j.config.Name),
+		zap.String("projection", j.config.Projection),
+		zap.String("key", key),
+		zap.Int("rows", rows),
+		zap.Int64("bytes", size),
+		zap.Duration("duration", time.Since(started)),
+	)
+
+	if err := s.applyRetention(ctx, j, started); err != nil {
+		s.Logger.Warn("Snapshot retention failed",
+			zap.String("snapshot", j.config.Name),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// applyRetention deletes snapshots beyond the configured count or age
+func (s *Scheduler) applyRetention(ctx context.Context, j *job, now time.Time) error {
+	retention := j.config.Retention
+	if retention.Days <= 0 && retention.Count <= 0 {
+		return nil
+	}
+
+	objects, err := j.sink.List(ctx, j.prefix()+"/")
+	if err != nil {
+		return err
+	}
+
+	// Keys embed the snapshot timestamp, so newest first is reverse key order
+	sort.Slice(objects, func(a, b int) bool { return objects[a].Key > objects[b].Key })
+
+	cutoff := now.AddDate(0, 0, -retention.Days)
+	for i, object := range objects {
+		expired := retention.Count > 0 && i >= retention.Count
+		if retention.Days > 0 && !object.Modified.IsZero() && object.Modified.Before(cutoff) {
+			expired = true
+		}
+		if !expired {
+			continue
+		}
+
+		if err := j.sink.Delete(ctx, object.Key); err != nil {
+			return err
+		}
+		s.Logger.Info("Snapshot expired",
+			zap.String("snapshot", j.config.Name),
+			zap.String("key", object.Key),
+		)
+	}
+	return nil
+}
+
+func (j *job) prefix() string {
+	return path.Join(strings.Trim(j.config.Destination.Prefix, "/"), j.config.Name)
+}
+
+func contentType(format string) string {
+	if format == "parquet" {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv"
+}
@@ -0,0 +1,123 @@
+// Package sqlcheck checks that source (MSSQL) statements and filters are read-only, for both configuration
+// validation and the read-only source connection.
+package sqlcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotReadOnly is returned for statements that are not a single read-only query
+var ErrNotReadOnly = errors.New("statement is not read-only")
+
+// writeKeywords are the keywords refused outside string literals and quoted identifiers.
+// INTO covers SELECT ... INTO, and the OPEN* functions reach linked servers, where writes could not be checked
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "DROP": true, "ALTER": true, "CREATE": true,
+	"TRUNCATE": true, "EXEC": true, "EXECUTE": true, "GRANT": true, "REVOKE": true, "DENY": true, "INTO": true,
+	"BACKUP": true, "RESTORE": true, "SHUTDOWN": true, "DBCC": true, "KILL": true, "RECONFIGURE": true,
+	"OPENQUERY": true, "OPENROWSET": true, "OPENDATASOURCE": true,
+}
+
+// ReadOnly reports whether a statement is a single SELECT (optionally preceded by WITH) without write keywords.
+// String literals, quoted identifiers and comments are skipped, so names and values containing keywords pass
+func ReadOnly(query string) error {
+	words, err := Words(query)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("%w: empty statement", ErrNotReadOnly)
+	}
+	if words[0] != "SELECT" && words[0] != "WITH" {
+		return fmt.Errorf("%w: %s is not a query", ErrNotReadOnly, words[0])
+	}
+	if keyword := writeKeyword(words); keyword != "" {
+		return fmt.Errorf("%w: found %s", ErrNotReadOnly, keyword)
+	}
+	return nil
+}
+
+// WriteKeyword returns the first write keyword of a statement fragment such as a WHERE condition, empty when it
+// has none
+func WriteKeyword(fragment string) (string, error) {
+	words, err := Words(fragment)
+	if err != nil {
+		return "", err
+	}
+	return writeKeyword(words), nil
+}
+
+// writeKeyword returns the first write keyword among words, empty when there is none
+func writeKeyword(words []string) string {
+	for _, word := range words {
+		if writeKeywords[word] {
+			return word
+		}
+	}
+	return ""
+}
+
+// Words returns the upper-cased bare words of a T-SQL statement, skipping string literals, quoted identifiers and
+// comments. A semicolon followed by anything but whitespace is refused as a second statement
+func Words(query string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '[':
+			flush()
+			closing := ch
+			if ch == '[' {
+				closing = ']'
+			}
+			// Doubled closing characters escape themselves
+			for i++; i < len(query); i++ {
+				if query[i] == closing {
+					if i+1 < len(query) && query[i+1] == closing {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			if i >= len(query) {
+				return nil, fmt.Errorf("%w: unterminated %c", ErrNotReadOnly, ch)
+			}
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			flush()
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case ch == '/' && i+1 < len(query) && query[i+1] == '*':
+			flush()
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment", ErrNotReadOnly)
+			}
+			i += end + 3
+		case ch == ';':
+			flush()
+			if strings.TrimSpace(query[i+1:]) != "" {
+				return nil, fmt.Errorf("%w: multiple statements", ErrNotReadOnly)
+			}
+			return words, nil
+		case ch == '_' || ch == '@' || ch == '#' || ch == '$' ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch >= 0x80:
+			word.WriteByte(ch)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words, nil
+}
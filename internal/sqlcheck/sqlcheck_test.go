@@ -0,0 +1,67 @@
+package sqlcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		refused bool
+	}{
+		{"select", "SELECT id FROM dbo.Orders", false},
+		{"trailing semicolon", "SELECT 1;  ", false},
+		{"cte", "WITH recent AS (SELECT id FROM dbo.Orders) SELECT id FROM recent", false},
+		{"lower case", "select id from dbo.Orders", false},
+		{"keyword in string literal", "SELECT id FROM dbo.Orders WHERE Note = 'DELETE INTO'", false},
+		{"keyword in quoted identifier", `SELECT "update", [insert] FROM dbo.Orders`, false},
+		{"escaped quotes", "SELECT 'it''s ; DROP' AS [a]]b]", false},
+		{"keyword in comment", "SELECT id -- DELETE\nFROM dbo.Orders /* DROP */", false},
+		{"keyword inside a name", "SELECT UpdatedAt FROM dbo.Orders", false},
+		{"empty", "  ", true},
+		{"insert", "INSERT INTO dbo.Orders VALUES (1)", true},
+		{"exec", "EXEC dbo.Purge", true},
+		{"select into", "SELECT * INTO dbo.Copy FROM dbo.Orders", true},
+		{"delete in cte", "WITH d AS (SELECT 1 AS x) DELETE FROM dbo.Orders", true},
+		{"openquery", "SELECT * FROM OPENQUERY(linked, 'SELECT 1')", true},
+		{"second statement", "SELECT 1; SELECT 2", true},
+		{"unterminated literal", "SELECT 'open", true},
+		{"unterminated comment", "SELECT 1 /* open", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadOnly(tt.in)
+			if tt.refused && !errors.Is(err, ErrNotReadOnly) {
+				t.Errorf("ReadOnly(%q) = %v, want ErrNotReadOnly", tt.in, err)
+			}
+			if !tt.refused && err != nil {
+				t.Errorf("ReadOnly(%q) = %v, want nil", tt.in, err)
+			}
+		})
+	}
+}
+
+func TestWriteKeyword(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"condition", "IsActive = 1 AND Region IN ('EU', 'US')", ""},
+		{"keyword in string literal", "Status = 'DELETED' OR Note = 'drop'", ""},
+		{"write keyword", "1 = 1 OR EXISTS (SELECT 1 FROM dbo.Orders) UNION ALL delete FROM x", "DELETE"},
+		{"into", "id IN (SELECT id INTO #t FROM dbo.Orders)", "INTO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WriteKeyword(tt.in)
+			if err != nil || got != tt.want {
+				t.Errorf("WriteKeyword(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
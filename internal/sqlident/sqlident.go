@@ -0,0 +1,80 @@
+// Package sqlident quotes table and column identifiers for the source (MSSQL) and target (PostgreSQL) dialects.
+package sqlident
+
+import "strings"
+
+// Split splits a possibly schema-qualified name into its parts. Dots inside
+// "double quoted" or [bracketed] parts do not split, and the quotes are removed.
+func Split(name string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+		closing rune
+	)
+
+	runes := []rune(strings.TrimSpace(name))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case closing != 0 && r == closing:
+			// A doubled closing character is an escaped literal
+			if i+1 < len(runes) && runes[i+1] == closing {
+				current.WriteRune(r)
+				i++
+				continue
+			}
+			closing = 0
+		case closing != 0:
+			current.WriteRune(r)
+		case r == '"':
+			closing = '"'
+		case r == '[':
+			closing = ']'
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	parts = append(parts, strings.TrimSpace(current.String()))
+
+	return parts
+}
+
+// SplitQualified returns the schema and object name, using defaultSchema for unqualified names
+func SplitQualified(name, defaultSchema string) (string, string) {
+	parts := Split(name)
+	if len(parts) == 1 {
+		return defaultSchema, parts[0]
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// Postgres quotes a possibly qualified identifier for PostgreSQL, preserving case
+func Postgres(name string) string {
+	parts := Split(name)
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+// MSSQL quotes a possibly qualified identifier for SQL Server
+func MSSQL(name string) string {
+	parts := Split(name)
+	for i, part := range parts {
+		parts[i] = "[" + strings.ReplaceAll(part, "]", "]]") + "]"
+	}
+	return strings.Join(parts, ".")
+}
+
+// PostgresColumn quotes a single column name for PostgreSQL without splitting on dots
+func PostgresColumn(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// MSSQLColumn quotes a single column name for SQL Server without splitting on dots
+func MSSQLColumn(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
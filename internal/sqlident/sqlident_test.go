@@ -0,0 +1,129 @@
+package sqlident
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"unqualified", "Orders", []string{"Orders"}},
+		{"schema qualified", "dbo.Orders", []string{"dbo", "Orders"}},
+		{"database qualified", "Sales.dbo.Orders", []string{"Sales", "dbo", "Orders"}},
+		{"double quoted dot", `"order.lines"`, []string{"order.lines"}},
+		{"bracketed dot", "[order.lines]", []string{"order.lines"}},
+		{"quoted schema and table", `"my.schema"."my.table"`, []string{"my.schema", "my.table"}},
+		{"bracketed schema and table", "[my.schema].[my.table]", []string{"my.schema", "my.table"}},
+		{"mixed quoting", `[dbo]."Order Lines"`, []string{"dbo", "Order Lines"}},
+		{"doubled double quote", `"say ""hi"""`, []string{`say "hi"`}},
+		{"doubled closing bracket", "[a]]b]", []string{"a]b"}},
+		{"doubled closing bracket with dot", "[x]].y].[z]", []string{"x].y", "z"}},
+		{"bracket inside double quotes", `"a[b]"`, []string{"a[b]"}},
+		{"double quote inside brackets", `[a"b]`, []string{`a"b`}},
+		{"reserved word", "select", []string{"select"}},
+		{"quoted reserved words", `"user"."order"`, []string{"user", "order"}},
+		{"mixed case", "Reporting.OrderLines", []string{"Reporting", "OrderLines"}},
+		{"unicode", "ventas.Año_Fiscal", []string{"ventas", "Año_Fiscal"}},
+		{"quoted unicode", `"販売"."注文.明細"`, []string{"販売", "注文.明細"}},
+		{"surrounding whitespace", "  dbo.Orders\t", []string{"dbo", "Orders"}},
+		{"whitespace around dot", "dbo . Orders", []string{"dbo", "Orders"}},
+		{"empty", "", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQualified(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		wantSchema string
+		wantTable  string
+	}{
+		{"unqualified uses default", "orders", "public", "orders"},
+		{"schema qualified", "reporting.orders", "reporting", "orders"},
+		{"database qualified keeps last two", "Sales.dbo.Orders", "dbo", "Orders"},
+		{"quoted dots", `"my.schema"."my.table"`, "my.schema", "my.table"},
+		{"bracketed dots", "[my.schema].[my.table]", "my.schema", "my.table"},
+		{"quoted unqualified with dot", `"order.lines"`, "public", "order.lines"},
+		{"escapes", `"a""b".[c]]d]`, `a"b`, "c]d"},
+		{"reserved words", `"select"."from"`, "select", "from"},
+		{"mixed case", "Reporting.OrderLines", "Reporting", "OrderLines"},
+		{"unicode", "ventas.Año_Fiscal", "ventas", "Año_Fiscal"},
+		{"surrounding whitespace", "  reporting.orders  ", "reporting", "orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, table := SplitQualified(tt.in, "public")
+			if schema != tt.wantSchema || table != tt.wantTable {
+				t.Errorf("SplitQualified(%q) = %q, %q, want %q, %q", tt.in, schema, table, tt.wantSchema, tt.wantTable)
+			}
+		})
+	}
+}
+
+func TestPostgres(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unqualified", "orders", `"orders"`},
+		{"schema qualified", "reporting.orders", `"reporting"."orders"`},
+		{"preserves case", "Reporting.OrderLines", `"Reporting"."OrderLines"`},
+		{"reserved words", "user.order", `"user"."order"`},
+		{"quoted dot stays one part", `"order.lines"`, `"order.lines"`},
+		{"bracketed dot from MSSQL", "[dbo].[order.lines]", `"dbo"."order.lines"`},
+		{"doubled quote kept escaped", `"say ""hi"""`, `"say ""hi"""`},
+		{"quote inside brackets escaped", `[a"b]`, `"a""b"`},
+		{"unicode", "ventas.Año_Fiscal", `"ventas"."Año_Fiscal"`},
+		{"surrounding whitespace", "  reporting.orders ", `"reporting"."orders"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Postgres(tt.in); got != tt.want {
+				t.Errorf("Postgres(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMSSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unqualified", "Orders", "[Orders]"},
+		{"schema qualified", "dbo.Orders", "[dbo].[Orders]"},
+		{"database qualified", "Sales.dbo.Orders", "[Sales].[dbo].[Orders]"},
+		{"reserved words", "dbo.select", "[dbo].[select]"},
+		{"bracketed dot stays one part", "[order.lines]", "[order.lines]"},
+		{"double quoted dot from PostgreSQL", `"my.schema"."my.table"`, "[my.schema].[my.table]"},
+		{"doubled bracket kept escaped", "[a]]b]", "[a]]b]"},
+		{"bracket inside double quotes escaped", `"a]b"`, "[a]]b]"},
+		{"mixed case", "Reporting.OrderLines", "[Reporting].[OrderLines]"},
+		{"unicode", "ventas.Año_Fiscal", "[ventas].[Año_Fiscal]"},
+		{"surrounding whitespace", "\tdbo.Orders  ", "[dbo].[Orders]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MSSQL(tt.in); got != tt.want {
+				t.Errorf("MSSQL(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
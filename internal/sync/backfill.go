@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ErrBackfillStopped is the cancellation cause of a backfill stopped on request
+var ErrBackfillStopped = errors.New("backfill stopped")
+
+// StartBackfill returns the progress a table's backfill starts from: the saved progress when it did not complete,
+// otherwise a new backfill over the configured dates. Restart discards saved progress
+func (se *SyncEngine) StartBackfill(tableConfig config.TableConfig, restart bool) (*history.Backfill, error) {
+	if tableConfig.Backfill == nil {
+		return nil, fmt.Errorf("table %s has no backfill configured", tableConfig.TargetTable)
+	}
+	if se.History == nil {
+		return nil, fmt.Errorf("backfill requires history to be enabled")
+	}
+
+	progress, err := se.History.GetBackfill(tableConfig.TargetTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backfill progress: %w", err)
+	}
+	now := time.Now()
+	if progress != nil && !restart && progress.Status != history.BackfillCompleted {
+		progress.Status = history.BackfillRunning
+		progress.Error = ""
+		progress.UpdatedAt = now
+		return progress, nil
+	}
+
+	interval := tableConfig.Partitioning.GetInterval()
+	from, err := tableConfig.Backfill.GetFrom()
+	if err != nil {
+		return nil, err
+	}
+	to, err := tableConfig.Backfill.GetTo()
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		// The current range is left to the regular refresh
+		to, _ = rangeBounds(interval, now)
+	}
+	from, _ = rangeBounds(interval, from)
+
+	total := 0
+	for start := from; start.Before(to); _, start = rangeBounds(interval, start) {
+		total++
+	}
+
+	return &history.Backfill{
+		TableName:   tableConfig.TargetTable,
+		Status:      history.BackfillRunning,
+		From:        from,
+		To:          to,
+		Next:        from,
+		RangesTotal: total,
+		StartedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Backfill loads a table one partition range at a time from progress.Next up to progress.To, saving the progress after
+// each range and pausing the configured delay between ranges. It returns once every range is loaded, when a range fails
+// or when ctx is cancelled; a backfill cancelled for another cause than ErrBackfillStopped stays running so it resumes
+func (se *SyncEngine) Backfill(ctx context.Context, tableConfig config.TableConfig, progress *history.Backfill) error {
+	logger := se.Logger.With(zap.String("target_table", tableConfig.TargetTable))
+	interval := tableConfig.Partitioning.GetInterval()
+	delay := tableConfig.Backfill.GetDelay()
+
+	if err := se.saveBackfill(progress); err != nil {
+		return err
+	}
+	logger.Info("Backfill started",
+		zap.Time("next", progress.Next),
+		zap.Time("to", progress.To),
+		zap.Int("ranges_done", progress.RangesDone),
+		zap.Int("ranges_total", progress.RangesTotal),
+	)
+
+	for progress.Next.Before(progress.To) {
+		start, end := rangeBounds(interval, progress.Next)
+		result, err := se.SyncRange(ctx, tableConfig, start, end)
+		switch {
+		case errors.Is(err, ErrCircuitOpen):
+			// Wait for the databases to recover rather than failing the backfill
+		case err != nil && cancelled(ctx) != nil:
+			return se.interruptBackfill(ctx, progress)
+		case err != nil:
+			progress.Status = history.BackfillFailed
+			progress.Error = err.Error()
+			progress.UpdatedAt = time.Now()
+			logger.Error("Backfill range failed", zap.Time("range_start", start), zap.Error(err))
+			if saveErr := se.saveBackfill(progress); saveErr != nil {
+				logger.Warn("Failed to save backfill progress", zap.Error(saveErr))
+			}
+			return err
+		default:
+			progress.Next = end
+			progress.RangesDone++
+			progress.RowsSynced += int64(result.RowsSynced)
+			progress.UpdatedAt = time.Now()
+			if err := se.saveBackfill(progress); err != nil {
+				return err
+			}
+			logger.Info("Backfilled range",
+				zap.Time("range_start", start),
+				zap.Int("rows", result.RowsSynced),
+				zap.Int("ranges_done", progress.RangesDone),
+				zap.Int("ranges_total", progress.RangesTotal),
+			)
+		}
+
+		if !progress.Next.Before(progress.To) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return se.interruptBackfill(ctx, progress)
+		case <-time.After(delay):
+		}
+	}
+
+	progress.Status = history.BackfillCompleted
+	progress.UpdatedAt = time.Now()
+	if err := se.saveBackfill(progress); err != nil {
+		return err
+	}
+	logger.Info("Backfill completed",
+		zap.Int("ranges", progress.RangesDone),
+		zap.Int64("rows", progress.RowsSynced),
+	)
+	return nil
+}
+
+// SyncRange loads the source rows of one partition range into a backfilled table, replacing only that range's
+// partition. Ranges are not recorded in the sync history and publish no events
+func (se *SyncEngine) SyncRange(ctx context.Context, tableConfig config.TableConfig, start, end time.Time) (*SyncResult, error) {
+	result := &SyncResult{
+		BatchID:   NewBatchID(),
+		TableName: tableConfig.TargetTable,
+		StartedAt: time.Now(),
+	}
+
+	if !se.DB.Available() {
+		return result, ErrCircuitOpen
+	}
+
+	tableConfig.Filter = rangeFilter(tableConfig.Partitioning.Column, tableConfig.Backfill.Filter, start, end)
+	publish := false
+	tableConfig.PublishEvents = &publish
+
+	err := se.runSync(ctx, tableConfig, result)
+	result.Duration = time.Since(result.StartedAt)
+	return result, err
+}
+
+// interruptBackfill saves the progress of a cancelled backfill, marking it stopped when that was requested
+func (se *SyncEngine) interruptBackfill(ctx context.Context, progress *history.Backfill) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrBackfillStopped) {
+		progress.Status = history.BackfillStopped
+	}
+	progress.UpdatedAt = time.Now()
+	if err := se.saveBackfill(progress); err != nil {
+		se.Logger.Warn("Failed to save backfill progress", zap.String("table", progress.TableName), zap.Error(err))
+	}
+	se.Logger.Info("Backfill interrupted",
+		zap.String("table", progress.TableName),
+		zap.String("status", progress.Status),
+		zap.Time("next", progress.Next),
+	)
+	return cause
+}
+
+func (se *SyncEngine) saveBackfill(progress *history.Backfill) error {
+	if err := se.History.SaveBackfill(progress); err != nil {
+		return fmt.Errorf("failed to save backfill progress: %w", err)
+	}
+	return nil
+}
+
+// rangeFilter limits the source to the rows of a partition range, on top of the backfill's own filter
+func rangeFilter(column, filter string, start, end time.Time) string {
+	const layout = "2006-01-02T15:04:05"
+	condition := fmt.Sprintf("%s >= '%s' AND %s < '%s'",
+		sqlident.MSSQLColumn(column), start.Format(layout), sqlident.MSSQLColumn(column), end.Format(layout))
+	if filter == "" {
+		return condition
+	}
+	return fmt.Sprintf("(%s) AND %s", filter, condition)
+}
@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// Suffixes of the two generations of a blue/green table
+const (
+	generationA = "__a"
+	generationB = "__b"
+)
+
+// ErrNoPreviousGeneration is returned by a rollback when the table has no loaded generation to switch back to
+var ErrNoPreviousGeneration = errors.New("no previous generation to roll back to")
+
+// generationTable returns the table of one generation of a blue/green target table
+func generationTable(tableName, suffix string) string {
+	parts := sqlident.Split(tableName)
+	parts[len(parts)-1] += suffix
+	return strings.Join(parts, ".")
+}
+
+// otherGeneration returns the generation of a blue/green table that is not the given one, the first one when none
+// is given
+func otherGeneration(tableName, generation string) string {
+	if generation == generationTable(tableName, generationA) {
+		return generationTable(tableName, generationB)
+	}
+	return generationTable(tableName, generationA)
+}
+
+// liveGeneration returns the generation the view of a blue/green table reads, empty before its first load. It fails
+// when the target table exists as something other than a view
+func (se *SyncEngine) liveGeneration(ctx context.Context, tableName string) (string, error) {
+	var relkind sql.NullString
+	query := "SELECT relkind::text FROM pg_class WHERE oid = to_regclass($1)"
+	if err := se.DB.Target.QueryRowContext(ctx, query, sqlident.Postgres(tableName)).Scan(&relkind); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	if relkind.String != "v" {
+		return "", fmt.Errorf("target table %s exists and is not a view; rename or drop it to use blue_green", tableName)
+	}
+
+	query = `
+		SELECT DISTINCT c.relname
+		FROM pg_rewrite r
+		JOIN pg_depend d ON d.objid = r.oid AND d.classid = 'pg_rewrite'::regclass AND d.refclassid = 'pg_class'::regclass
+		JOIN pg_class c ON c.oid = d.refobjid
+		WHERE r.ev_class = to_regclass($1) AND c.oid <> r.ev_class
+	`
+	var tables []string
+	if err := se.DB.Target.SelectContext(ctx, &tables, query, sqlident.Postgres(tableName)); err != nil {
+		return "", err
+	}
+	_, table := sqlident.SplitQualified(tableName, "public")
+	for _, name := range tables {
+		switch name {
+		case table + generationA:
+			return generationTable(tableName, generationA), nil
+		case table + generationB:
+			return generationTable(tableName, generationB), nil
+		}
+	}
+	return "", fmt.Errorf("view %s does not read a generation of the table", tableName)
+}
+
+// switchGeneration points the view of a blue/green table at a generation. Readers of the view move from one
+// generation to the other at once, since the view is replaced in a single statement
+func (se *SyncEngine) switchGeneration(ctx context.Context, tableName, generation string) error {
+	query := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS SELECT * FROM %s", sqlident.Postgres(tableName), sqlident.Postgres(generation))
+	if _, err := se.DB.Target.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	se.Logger.Info("Switched blue/green table generation",
+		zap.String("table", tableName),
+		zap.String("generation", generation),
+	)
+	return nil
+}
+
+// RollbackTable points the view of a blue/green table back at its other generation, which holds the load before the
+// live one, and returns that generation. Rolling back twice returns to the latest load; the next sync overwrites the
+// generation rolled back from
+func (se *SyncEngine) RollbackTable(ctx context.Context, tableConfig config.TableConfig) (string, error) {
+	tableName := tableConfig.TargetTable
+	if !tableConfig.BlueGreen {
+		return "", fmt.Errorf("table %s is not a blue_green table", tableName)
+	}
+
+	live, err := se.liveGeneration(ctx, tableName)
+	if err != nil {
+		return "", err
+	}
+	if live == "" {
+		return "", fmt.Errorf("%w: table %s has not been loaded yet", ErrNoPreviousGeneration, tableName)
+	}
+
+	previous := otherGeneration(tableName, live)
+	var exists bool
+	if err := se.DB.Target.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", sqlident.Postgres(previous)).Scan(&exists); err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%w: table %s has been loaded once", ErrNoPreviousGeneration, tableName)
+	}
+
+	if err := se.switchGeneration(ctx, tableName, previous); err != nil {
+		return "", fmt.Errorf("failed to switch generation: %w", err)
+	}
+	return previous, nil
+}
+
+// maintainedTable returns the table maintenance runs on: the live generation of a blue/green table, else the table
+func (se *SyncEngine) maintainedTable(ctx context.Context, tableName string) (string, error) {
+	for _, tc := range se.Config.Tables {
+		if tc.TargetTable != tableName || !tc.BlueGreen {
+			continue
+		}
+		live, err := se.liveGeneration(ctx, tableName)
+		if err != nil {
+			return "", err
+		}
+		if live == "" {
+			return "", fmt.Errorf("blue_green table %s has not been loaded yet", tableName)
+		}
+		return live, nil
+	}
+	return tableName, nil
+}
@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// changeDetectionQuery builds the query whose result identifies the current state of the source, with its parameters
+func changeDetectionQuery(tableConfig config.TableConfig) (string, []interface{}) {
+	detection := tableConfig.ChangeDetection
+	if detection.Query != "" {
+		return strings.TrimSuffix(strings.TrimSpace(detection.Query), ";"), nil
+	}
+
+	source := sourceRelation(tableConfig.SourceTable, tableConfig.SourceQueryStatement())
+	query := fmt.Sprintf("SELECT COUNT_BIG(*) FROM %s", source)
+	if detection.Column != "" {
+		query = fmt.Sprintf("SELECT MAX(%s), COUNT_BIG(*) FROM %s", sqlident.MSSQLColumn(detection.Column), source)
+	}
+	where, args := sourceWhere(tableConfig)
+	return query + where, args
+}
+
+// ChangeToken runs the table's change detection query and returns its result as a comparable token
+func (se *SyncEngine) ChangeToken(ctx context.Context, tableConfig config.TableConfig) (string, error) {
+	if tableConfig.ChangeDetection == nil {
+		return "", fmt.Errorf("change detection is not configured for %s", tableConfig.TargetTable)
+	}
+
+	ctx = database.WithQueryLabel(ctx, "table:"+tableConfig.TargetTable)
+	tableConfig, err := se.expandVariables(tableConfig, "")
+	if err != nil {
+		return "", err
+	}
+	query, args := changeDetectionQuery(tableConfig)
+	rows, err := se.DB.Source.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var token strings.Builder
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return "", err
+		}
+		for _, value := range values {
+			if b, ok := value.([]byte); ok {
+				// rowversion values are binary, hex keeps the token printable
+				value = fmt.Sprintf("%x", b)
+			}
+			fmt.Fprintf(&token, "%v|", value)
+		}
+		token.WriteByte('\n')
+	}
+	return token.String(), rows.Err()
+}
@@ -0,0 +1,230 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// loadWindow is the date window of a chunked load. The first window is open at its start and also holds NULLs, the
+// last one is open at its end, so together they cover every row
+type loadWindow struct {
+	column     ColumnInfo
+	start, end time.Time // zero when the window is open on that side
+}
+
+// condition selects the rows of the window on a quoted column, empty when the window is open on both sides
+func (w *loadWindow) condition(column string) string {
+	const layout = "2006-01-02T15:04:05"
+	switch {
+	case w.start.IsZero() && w.end.IsZero():
+		return ""
+	case w.start.IsZero():
+		return fmt.Sprintf("(%s < '%s' OR %s IS NULL)", column, w.end.Format(layout), column)
+	case w.end.IsZero():
+		return fmt.Sprintf("%s >= '%s'", column, w.start.Format(layout))
+	default:
+		return fmt.Sprintf("%s >= '%s' AND %s < '%s'", column, w.start.Format(layout), column, w.end.Format(layout))
+	}
+}
+
+// sourceFilter limits the source to the rows of the window, on top of the table's own filter
+func (w *loadWindow) sourceFilter(filter string) string {
+	condition := w.condition(sqlident.MSSQLColumn(w.column.Name))
+	switch {
+	case condition == "":
+		return filter
+	case filter == "":
+		return condition
+	default:
+		return fmt.Sprintf("(%s) AND %s", filter, condition)
+	}
+}
+
+// targetCondition selects the rows of the window on the target table
+func (w *loadWindow) targetCondition() string {
+	if condition := w.condition(sqlident.PostgresColumn(w.column.targetName())); condition != "" {
+		return condition
+	}
+	return "TRUE"
+}
+
+// loadChunks runs steps 3 and 4 of a sync for a chunked table: the target is truncated once, then every window is
+// read and committed on its own, saving the progress after each. A load interrupted by a failure, cancellation or
+// shutdown resumes at its next window on the table's next sync instead of starting over
+func (se *SyncEngine) loadChunks(ctx context.Context, tableConfig config.TableConfig, columns, targetColumns []ColumnInfo, lineage bool, result *SyncResult) error {
+	tableName := tableConfig.TargetTable
+	logger := se.Logger.With(zap.String("target_table", tableName), zap.String("batch_id", result.BatchID))
+	chunking := tableConfig.Chunking
+	interval := chunking.GetInterval()
+
+	if se.History == nil {
+		return fmt.Errorf("chunking requires history to be enabled")
+	}
+	column, ok := findColumn(columns, chunking.Column)
+	if !ok {
+		return fmt.Errorf("chunking column %s is not synced from the source", chunking.Column)
+	}
+	from, err := chunking.GetFrom()
+	if err != nil {
+		return err
+	}
+	from, _ = rangeBounds(interval, from)
+	// The window holding the current date is the last one and stays open, so rows dated later are loaded too
+	current, _ := rangeBounds(interval, time.Now())
+
+	progress, err := se.History.GetChunkedLoad(tableName)
+	if err != nil {
+		return fmt.Errorf("failed to load chunked load progress: %w", err)
+	}
+	if progress == nil || progress.Status == history.ChunksCompleted || !progress.From.Equal(from) {
+		total := 1
+		for start := from; start.Before(current); _, start = rangeBounds(interval, start) {
+			total++
+		}
+
+		logger.Info("Truncating target table for chunked load", zap.Int("windows", total))
+		if _, err := se.DB.Target.ExecContext(ctx, fmt.Sprintf("TRUNCATE TABLE %s", sqlident.Postgres(tableName))); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to truncate target table: %w", err)
+		}
+
+		now := time.Now()
+		progress = &history.ChunkedLoad{
+			TableName:    tableName,
+			Status:       history.ChunksRunning,
+			From:         from,
+			Next:         from,
+			WindowsTotal: total,
+			StartedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := se.History.SaveChunkedLoad(progress); err != nil {
+			return fmt.Errorf("failed to save chunked load progress: %w", err)
+		}
+	} else {
+		logger.Info("Resuming chunked load",
+			zap.Time("next", progress.Next),
+			zap.Int("windows_done", progress.WindowsDone),
+			zap.Int("windows_total", progress.WindowsTotal),
+		)
+	}
+
+	for {
+		start, end := rangeBounds(interval, progress.Next)
+		window := &loadWindow{column: column, start: start, end: end}
+		if !start.After(from) {
+			window.start = time.Time{}
+		}
+		last := !start.Before(current)
+		if last {
+			window.end = time.Time{}
+		}
+
+		synced := result.RowsSynced
+		if err := se.syncWindow(ctx, tableConfig, columns, targetColumns, lineage, window, result); err != nil {
+			if errors.Is(err, ErrPreempted) || cancelled(ctx) != nil {
+				logger.Info("Chunked load interrupted", zap.Time("next", progress.Next), zap.Error(err))
+				return err
+			}
+			progress.Error = err.Error()
+			progress.UpdatedAt = time.Now()
+			if saveErr := se.History.SaveChunkedLoad(progress); saveErr != nil {
+				logger.Warn("Failed to save chunked load progress", zap.Error(saveErr))
+			}
+			return err
+		}
+
+		progress.Next = end
+		progress.WindowsDone++
+		progress.RowsSynced += int64(result.RowsSynced - synced)
+		progress.Error = ""
+		progress.UpdatedAt = time.Now()
+		if last {
+			progress.Status = history.ChunksCompleted
+		}
+		if err := se.History.SaveChunkedLoad(progress); err != nil {
+			return fmt.Errorf("failed to save chunked load progress: %w", err)
+		}
+		logger.Info("Loaded chunk",
+			zap.Time("window_start", start),
+			zap.Int("windows_done", progress.WindowsDone),
+			zap.Int("windows_total", progress.WindowsTotal),
+			zap.Int("rows_synced", result.RowsSynced),
+		)
+		if last {
+			break
+		}
+	}
+
+	result.RowsSkipped = result.RowsRead - result.RowsWritten
+
+	// Row events would hold the whole history in memory, so chunked loads only publish the batch event
+	se.publishEvents(ctx, tableConfig, result, nil)
+
+	logger.Info("Table sync completed",
+		zap.Duration("duration", time.Since(result.StartedAt)),
+		zap.Int("windows", progress.WindowsDone),
+		zap.Int("rows_read", result.RowsRead),
+		zap.Int("rows_written", result.RowsWritten),
+		zap.Int64("bytes_read", result.BytesRead),
+	)
+	return nil
+}
+
+// syncWindow reads the source rows of one window and replaces them on the target in their own transaction, adding
+// the counts to the result
+func (se *SyncEngine) syncWindow(ctx context.Context, tableConfig config.TableConfig, columns, targetColumns []ColumnInfo, lineage bool, window *loadWindow, result *SyncResult) error {
+	windowConfig := tableConfig
+	windowConfig.Filter = window.sourceFilter(tableConfig.Filter)
+
+	data, err := se.fetchSourceData(ctx, windowConfig, columns)
+	if err != nil {
+		if cause := cancelled(ctx); cause != nil {
+			return cause
+		}
+		se.DB.SourceBreaker.RecordFailure(err)
+		return fmt.Errorf("failed to fetch source data: %w", err)
+	}
+	se.DB.SourceBreaker.RecordSuccess()
+	result.RowsRead += len(data)
+	result.BytesRead += estimateBytes(data)
+
+	if err := applyColumnPolicies(tableConfig, columns, data); err != nil {
+		return fmt.Errorf("failed to apply column policies: %w", err)
+	}
+
+	if data, err = se.dedupRows(tableConfig, columns, data); err != nil {
+		return err
+	}
+
+	if tableConfig.ChangeColumn != "" {
+		if changed := latestChange(data, tableConfig.ChangeColumn); changed != nil && (result.SourceChangedAt == nil || changed.After(*result.SourceChangedAt)) {
+			result.SourceChangedAt = changed
+		}
+	}
+
+	if lineage {
+		stampLineage(data, result.BatchID, se.Config.Source.Database, time.Now())
+	}
+
+	if _, err := se.syncToTarget(ctx, tableConfig, targetColumns, data, window); err != nil {
+		if errors.Is(err, ErrPreempted) || cancelled(ctx) != nil {
+			return err
+		}
+		se.DB.TargetBreaker.RecordFailure(err)
+		return fmt.Errorf("failed to sync to target: %w", err)
+	}
+	se.DB.TargetBreaker.RecordSuccess()
+
+	result.RowsSynced += len(data)
+	result.RowsWritten += len(data)
+	return nil
+}
@@ -0,0 +1,54 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// stringType reports whether an MSSQL column type is mapped to a PostgreSQL string type
+func stringType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "char", "varchar", "nchar", "nvarchar", "text", "ntext":
+		return true
+	default:
+		return false
+	}
+}
+
+// markCollations sets the configured collation on string columns, reporting whether any is mapped to citext
+func markCollations(tableConfig config.TableConfig, columns []ColumnInfo) bool {
+	citext := false
+	for i := range columns {
+		if columns[i].JSON || columns[i].Encrypted == config.EncryptedCiphertext || !stringType(columns[i].DataType) {
+			continue
+		}
+		columns[i].Collation = tableConfig.GetCollation(columns[i].Name)
+		if strings.EqualFold(columns[i].Collation, config.CollationCitext) {
+			citext = true
+		}
+	}
+	return citext
+}
+
+// collateClause returns the COLLATE clause of a created column, empty when it uses the database default or citext
+func collateClause(col ColumnInfo) string {
+	if col.Collation == "" || strings.EqualFold(col.Collation, config.CollationCitext) {
+		return ""
+	}
+	return " COLLATE " + sqlident.PostgresColumn(col.Collation)
+}
+
+// ensureCitext verifies the citext extension is installed in the target database
+func (se *SyncEngine) ensureCitext() error {
+	var installed bool
+	if err := se.DB.Target.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'citext')").Scan(&installed); err != nil {
+		return fmt.Errorf("failed to check citext extension: %w", err)
+	}
+	if !installed {
+		return fmt.Errorf("collation citext is configured but the citext extension is not installed in the target database")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// sourceColumns introspects the columns of a table's source table or source query selected by its fields, leaving
+// out the columns excluded by the defaults or the table and skipped encrypted columns. Table creation, the source
+// read and the load all use them
+func (se *SyncEngine) sourceColumns(tableConfig config.TableConfig) ([]ColumnInfo, error) {
+	var columns []ColumnInfo
+	var err error
+	if tableConfig.SourceQuery != "" {
+		columns, err = se.getQueryColumns(tableConfig.SourceQueryStatement(), tableConfig.Fields)
+	} else {
+		columns, err = se.getSourceColumns(tableConfig.SourceTable, tableConfig.Fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns = excludeColumns(columns, tableConfig.GetExcludeColumns(se.Config.Defaults))
+	if columns, err = se.applyEncryptedColumns(tableConfig, columns); err != nil {
+		return nil, err
+	}
+	if err := nameTargetColumns(tableConfig, se.Config.Defaults, columns); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns left after applying fields and exclude_columns")
+	}
+	return columns, nil
+}
+
+// nameTargetColumns sets the target names of columns renamed by the table's naming or column targets
+func nameTargetColumns(tableConfig config.TableConfig, defaults config.DefaultConfig, columns []ColumnInfo) error {
+	names := make(map[string]string, len(columns))
+	for i := range columns {
+		target := tableConfig.TargetColumn(columns[i].Name, defaults)
+		if target != columns[i].Name {
+			columns[i].Target = target
+		}
+		if other, ok := names[target]; ok {
+			return fmt.Errorf("columns %s and %s both map to target column %s", other, columns[i].Name, target)
+		}
+		names[target] = columns[i].Name
+	}
+	return nil
+}
+
+// excludeColumns removes the columns matching any of the patterns
+func excludeColumns(columns []ColumnInfo, patterns []string) []ColumnInfo {
+	if len(patterns) == 0 {
+		return columns
+	}
+	kept := columns[:0]
+	for _, col := range columns {
+		if !matchAnyColumnPattern(patterns, col.Name) {
+			kept = append(kept, col)
+		}
+	}
+	return kept
+}
+
+// matchAnyColumnPattern reports whether a column name matches one of the patterns
+func matchAnyColumnPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchColumnPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchColumnPattern matches a column name against a case-insensitive pattern in which * and % match any
+// characters and ? a single one; every other character, including _, matches itself
+func matchColumnPattern(pattern, name string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for _, r := range strings.TrimSpace(pattern) {
+		switch r {
+		case '*', '%':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(name)
+}
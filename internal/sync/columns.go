@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// applyColumnPolicies applies the configured NULL and empty string policies to fetched rows
+func applyColumnPolicies(tableConfig config.TableConfig, columns []ColumnInfo, data []map[string]interface{}) error {
+	if len(tableConfig.Columns) == 0 {
+		return nil
+	}
+
+	for _, col := range columns {
+		columnCfg, ok := tableConfig.GetColumnConfig(col.Name)
+		if !ok {
+			continue
+		}
+
+		for i, row := range data {
+			value, err := applyColumnPolicy(columnCfg, row[col.Name])
+			if err == nil && col.JSON {
+				value, err = normalizeJSON(columnCfg, value)
+			}
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+			row[col.Name] = value
+		}
+	}
+
+	return nil
+}
+
+// applyColumnPolicy resolves a single value against a column's NULL and empty string policies
+func applyColumnPolicy(columnCfg *config.ColumnConfig, value interface{}) (interface{}, error) {
+	if isEmptyString(value) {
+		switch strings.ToLower(columnCfg.EmptyString) {
+		case "null":
+			value = nil
+		case "default":
+			if columnCfg.Default == nil {
+				return nil, fmt.Errorf("column %s: empty_string policy is default but no default is configured", columnCfg.Column)
+			}
+			return *columnCfg.Default, nil
+		}
+	}
+
+	if value != nil {
+		return value, nil
+	}
+
+	switch strings.ToLower(columnCfg.NullPolicy) {
+	case "default":
+		if columnCfg.Default == nil {
+			return nil, fmt.Errorf("column %s: null_policy is default but no default is configured", columnCfg.Column)
+		}
+		return *columnCfg.Default, nil
+	case "fail":
+		return nil, fmt.Errorf("column %s: unexpected NULL value", columnCfg.Column)
+	default:
+		return nil, nil
+	}
+}
+
+// markJSONColumns flags columns configured as jsonb
+func markJSONColumns(tableConfig config.TableConfig, columns []ColumnInfo) {
+	for i := range columns {
+		if columnCfg, ok := tableConfig.GetColumnConfig(columns[i].Name); ok && columnCfg.IsJSON() {
+			columns[i].JSON = true
+		}
+	}
+}
+
+// normalizeJSON validates a JSON value and returns it in compact form
+func normalizeJSON(columnCfg *config.ColumnConfig, value interface{}) (interface{}, error) {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return nil, fmt.Errorf("column %s: unsupported JSON source type %T", columnCfg.Column, value)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, bytes.TrimSpace(raw)); err != nil {
+		if strings.EqualFold(columnCfg.InvalidJSON, "null") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("column %s: invalid JSON: %w", columnCfg.Column, err)
+	}
+	return buf.String(), nil
+}
+
+func isEmptyString(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case []byte:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
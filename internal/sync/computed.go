@@ -0,0 +1,21 @@
+package sync
+
+import (
+	"fmt"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ensureComputedColumns adds the configured computed columns to the target table as stored generated columns.
+// Postgres fills them on insert, so they are never part of the synced column list.
+func (se *SyncEngine) ensureComputedColumns(tableName string, computed []config.ComputedColumn) error {
+	for _, col := range computed {
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s GENERATED ALWAYS AS (%s) STORED",
+			sqlident.Postgres(tableName), sqlident.PostgresColumn(col.Name), col.Type, col.Expression)
+		if _, err := se.DB.Target.Exec(query); err != nil {
+			return fmt.Errorf("computed column %s: %w", col.Name, err)
+		}
+	}
+	return nil
+}
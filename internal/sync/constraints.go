@@ -0,0 +1,125 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// foreignKey is a foreign key constraint on or referencing a target table
+type foreignKey struct {
+	Name       string         `db:"conname"`
+	Table      string         `db:"child"`  // referencing table
+	References string         `db:"parent"` // referenced table
+	Definition string         `db:"definition"`
+	Columns    pq.StringArray `db:"child_columns"`
+	RefColumns pq.StringArray `db:"parent_columns"`
+}
+
+// ConstraintReport is the outcome of validating a foreign key restored after a load
+type ConstraintReport struct {
+	Name       string `json:"name"`
+	Table      string `json:"table"`
+	References string `json:"references"`
+	Valid      bool   `json:"valid"`
+	Violations int64  `json:"violations"` // referencing rows without a referenced row, when not valid
+}
+
+// foreignKeys returns the foreign keys declared on a target table and those of other tables referencing it.
+// Constraints inherited by partitions are left out; they follow their parent's
+func (se *SyncEngine) foreignKeys(tx *sqlx.Tx, tableName string) ([]foreignKey, error) {
+	var keys []foreignKey
+	err := tx.Select(&keys, `
+		SELECT c.conname,
+			c.conrelid::regclass::text AS child,
+			c.confrelid::regclass::text AS parent,
+			pg_get_constraintdef(c.oid) AS definition,
+			ARRAY(SELECT a.attname::text FROM unnest(c.conkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum ORDER BY k.ord) AS child_columns,
+			ARRAY(SELECT a.attname::text FROM unnest(c.confkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum ORDER BY k.ord) AS parent_columns
+		FROM pg_constraint c
+		WHERE c.contype = 'f' AND c.conparentid = 0
+			AND (c.conrelid = $1::text::regclass OR c.confrelid = $1::text::regclass)
+		ORDER BY c.conrelid::regclass::text, c.conname`, sqlident.Postgres(tableName))
+	return keys, err
+}
+
+// dropForeignKeys drops foreign keys for the duration of a load transaction
+func (se *SyncEngine) dropForeignKeys(tx *sqlx.Tx, keys []foreignKey) error {
+	for _, fk := range keys {
+		query := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", fk.Table, sqlident.PostgresColumn(fk.Name))
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to drop constraint %s: %w", fk.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreForeignKeys adds dropped foreign keys back as NOT VALID, so they are enforced for new rows without
+// checking the loaded ones inside the load transaction
+func (se *SyncEngine) restoreForeignKeys(tx *sqlx.Tx, keys []foreignKey) error {
+	for _, fk := range keys {
+		definition := strings.TrimSuffix(fk.Definition, " NOT VALID")
+		query := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s NOT VALID", fk.Table, sqlident.PostgresColumn(fk.Name), definition)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to restore constraint %s: %w", fk.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateForeignKeys validates restored foreign keys after the load has committed. A key with violations stays
+// NOT VALID and its violating rows are counted, instead of failing the sync
+func (se *SyncEngine) validateForeignKeys(ctx context.Context, tableName string, keys []foreignKey) []ConstraintReport {
+	reports := make([]ConstraintReport, 0, len(keys))
+	for _, fk := range keys {
+		report := ConstraintReport{Name: fk.Name, Table: fk.Table, References: fk.References, Valid: true}
+		query := fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", fk.Table, sqlident.PostgresColumn(fk.Name))
+		if _, err := se.DB.Target.ExecContext(ctx, query); err != nil {
+			report.Valid = false
+			violations, countErr := se.countViolations(ctx, fk)
+			if countErr != nil {
+				se.Logger.Warn("Failed to count foreign key violations",
+					zap.String("constraint", fk.Name),
+					zap.Error(countErr),
+				)
+			}
+			report.Violations = violations
+			se.Logger.Warn("Foreign key not valid after load",
+				zap.String("table", tableName),
+				zap.String("constraint", fk.Name),
+				zap.String("referencing_table", fk.Table),
+				zap.Int64("violations", violations),
+				zap.Error(err),
+			)
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// countViolations counts referencing rows whose non-NULL key has no referenced row
+func (se *SyncEngine) countViolations(ctx context.Context, fk foreignKey) (int64, error) {
+	if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.RefColumns) {
+		return 0, fmt.Errorf("unexpected columns of constraint %s", fk.Name)
+	}
+
+	var notNull, matches []string
+	for i, column := range fk.Columns {
+		notNull = append(notNull, fmt.Sprintf("c.%s IS NOT NULL", sqlident.PostgresColumn(column)))
+		matches = append(matches, fmt.Sprintf("p.%s = c.%s", sqlident.PostgresColumn(fk.RefColumns[i]), sqlident.PostgresColumn(column)))
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s c WHERE %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s)",
+		fk.Table, strings.Join(notNull, " AND "), fk.References, strings.Join(matches, " AND "))
+
+	var count int64
+	err := se.DB.Target.QueryRowContext(ctx, query).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// ErrDuplicateKeys is returned when a source read holds repeated keys and the table's dedup policy is fail
+var ErrDuplicateKeys = errors.New("duplicate keys in source rows")
+
+// dedupRows applies the table's dedup policy to the rows of a source read, returning the rows to load. Rows keep
+// their read order; with keep_latest a duplicate takes the place of the row it replaces
+func (se *SyncEngine) dedupRows(tableConfig config.TableConfig, columns []ColumnInfo, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	dedup := tableConfig.Dedup
+	if dedup == nil {
+		return data, nil
+	}
+
+	names := dedup.GetKeys(&tableConfig)
+	keyColumns := make([]ColumnInfo, 0, len(names))
+	for _, name := range names {
+		col, ok := findColumn(columns, name)
+		if !ok {
+			return nil, fmt.Errorf("dedup key column %s is not synced from the source", name)
+		}
+		keyColumns = append(keyColumns, col)
+	}
+	policy := dedup.GetPolicy()
+	var by ColumnInfo
+	if policy == config.DedupKeepLatest {
+		col, ok := findColumn(columns, dedup.By)
+		if !ok {
+			return nil, fmt.Errorf("dedup by column %s is not synced from the source", dedup.By)
+		}
+		by = col
+	}
+
+	seen := make(map[string]int, len(data))
+	kept := data[:0:0]
+	duplicates := 0
+	var firstDuplicate string
+	for _, row := range data {
+		key := diffKey(keyColumns, row)
+		i, ok := seen[key]
+		if !ok {
+			seen[key] = len(kept)
+			kept = append(kept, row)
+			continue
+		}
+
+		duplicates++
+		if firstDuplicate == "" {
+			firstDuplicate = strings.ReplaceAll(key, "\x1f", ", ")
+		}
+		if policy == config.DedupKeepLatest && laterValue(row[by.Name], kept[i][by.Name]) {
+			kept[i] = row
+		}
+	}
+
+	if duplicates == 0 {
+		return data, nil
+	}
+	if policy == config.DedupFail {
+		return nil, fmt.Errorf("%w: %d rows repeat a key, first %s", ErrDuplicateKeys, duplicates, firstDuplicate)
+	}
+	se.Logger.Warn("Dropped source rows with duplicate keys",
+		zap.String("table", tableConfig.TargetTable),
+		zap.String("policy", policy),
+		zap.Int("rows", duplicates),
+		zap.String("first_key", firstDuplicate),
+	)
+	return kept, nil
+}
+
+// laterValue reports whether a is greater than b for keep_latest; NULLs are older than any value
+func laterValue(a, b interface{}) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.After(tb)
+		}
+	}
+	if na, ok := numericValue(a); ok {
+		if nb, ok := numericValue(b); ok {
+			return na > nb
+		}
+	}
+	return textValue(a) > textValue(b)
+}
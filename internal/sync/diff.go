@@ -0,0 +1,313 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// DiffReport compares the rows of a table's source and target by its declared keys. Counts cover
+// every row; the key and mismatch lists are capped at the requested limit
+type DiffReport struct {
+	Table           string                   `json:"table"`
+	Keys            []string                 `json:"keys"`
+	Columns         []string                 `json:"columns"` // non-key columns compared
+	SourceRows      int                      `json:"source_rows"`
+	TargetRows      int                      `json:"target_rows"`
+	MissingInTarget int                      `json:"missing_in_target"`
+	MissingInSource int                      `json:"missing_in_source"`
+	Mismatched      int                      `json:"mismatched"`
+	DuplicateKeys   int                      `json:"duplicate_keys"` // rows sharing a key with an earlier row on the same side, left out of the comparison
+	TargetOnlyKeys  []map[string]interface{} `json:"target_only_keys"`
+	SourceOnlyKeys  []map[string]interface{} `json:"source_only_keys"`
+	Mismatches      []RowDiff                `json:"mismatches"`
+	Truncated       bool                     `json:"truncated"` // more differences exist than were listed
+	ComparedAt      time.Time                `json:"compared_at"`
+	DurationMs      int64                    `json:"duration_ms"`
+}
+
+// RowDiff lists the columns whose values disagree for a key present on both sides
+type RowDiff struct {
+	Key     map[string]interface{} `json:"key"`
+	Columns []ColumnDiff           `json:"columns"`
+}
+
+// ColumnDiff is a column value as read from the source and from the target
+type ColumnDiff struct {
+	Column string      `json:"column"`
+	Source interface{} `json:"source"`
+	Target interface{} `json:"target"`
+}
+
+// DiffTable reads a table from the source, with column policies applied as during a sync, and from the
+// target, and reports keys present on one side only and rows whose column values disagree
+func (se *SyncEngine) DiffTable(ctx context.Context, tableConfig config.TableConfig, limit int) (*DiffReport, error) {
+	if len(tableConfig.Keys) == 0 {
+		return nil, fmt.Errorf("table %s has no keys declared", tableConfig.TargetTable)
+	}
+	start := time.Now()
+
+	tableConfig, err := se.expandVariables(tableConfig, "")
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := se.sourceColumns(tableConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source columns: %w", err)
+	}
+	markJSONColumns(tableConfig, columns)
+
+	// Spatial values are converted on the way in and large objects may be cut or stored elsewhere, so neither can be
+	// compared as read
+	compared := make([]ColumnInfo, 0, len(columns))
+	for _, col := range columns {
+		if columnCfg, ok := tableConfig.GetColumnConfig(col.Name); ok && columnCfg.IsLargeObject() {
+			continue
+		}
+		switch strings.ToLower(col.DataType) {
+		case "geography", "geometry":
+		default:
+			compared = append(compared, col)
+		}
+	}
+
+	keyColumns := make([]ColumnInfo, 0, len(tableConfig.Keys))
+	for _, key := range tableConfig.Keys {
+		col, ok := findColumn(compared, key)
+		if !ok {
+			return nil, fmt.Errorf("key column %s is not synced from the source", key)
+		}
+		keyColumns = append(keyColumns, col)
+	}
+
+	source, err := se.fetchSourceData(ctx, tableConfig, compared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source data: %w", err)
+	}
+	if err := applyColumnPolicies(tableConfig, compared, source); err != nil {
+		return nil, fmt.Errorf("failed to apply column policies: %w", err)
+	}
+
+	target, err := se.fetchTargetData(ctx, tableConfig.TargetTable, compared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target data: %w", err)
+	}
+
+	report := &DiffReport{
+		Table:          tableConfig.TargetTable,
+		Keys:           tableConfig.Keys,
+		Columns:        []string{},
+		SourceRows:     len(source),
+		TargetRows:     len(target),
+		TargetOnlyKeys: []map[string]interface{}{},
+		SourceOnlyKeys: []map[string]interface{}{},
+		Mismatches:     []RowDiff{},
+		ComparedAt:     start,
+	}
+	valueColumns := make([]ColumnInfo, 0, len(compared))
+	for _, col := range compared {
+		if _, isKey := findColumn(keyColumns, col.Name); !isKey {
+			valueColumns = append(valueColumns, col)
+			report.Columns = append(report.Columns, col.Name)
+		}
+	}
+
+	targetRows := make(map[string]map[string]interface{}, len(target))
+	targetOrder := make([]string, 0, len(target))
+	for _, row := range target {
+		key := diffKey(keyColumns, row)
+		if _, exists := targetRows[key]; exists {
+			report.DuplicateKeys++
+			continue
+		}
+		targetRows[key] = row
+		targetOrder = append(targetOrder, key)
+	}
+
+	seen := make(map[string]bool, len(source))
+	for _, row := range source {
+		key := diffKey(keyColumns, row)
+		if seen[key] {
+			report.DuplicateKeys++
+			continue
+		}
+		seen[key] = true
+
+		targetRow, ok := targetRows[key]
+		if !ok {
+			report.MissingInTarget++
+			if len(report.SourceOnlyKeys) < limit {
+				report.SourceOnlyKeys = append(report.SourceOnlyKeys, diffKeyValues(keyColumns, row))
+			}
+			continue
+		}
+
+		var diffs []ColumnDiff
+		for _, col := range valueColumns {
+			if !valuesEqual(col, row[col.Name], targetRow[col.Name]) {
+				diffs = append(diffs, ColumnDiff{
+					Column: col.Name,
+					Source: diffValue(col, row[col.Name]),
+					Target: diffValue(col, targetRow[col.Name]),
+				})
+			}
+		}
+		if len(diffs) > 0 {
+			report.Mismatched++
+			if len(report.Mismatches) < limit {
+				report.Mismatches = append(report.Mismatches, RowDiff{Key: diffKeyValues(keyColumns, row), Columns: diffs})
+			}
+		}
+	}
+
+	for _, key := range targetOrder {
+		if seen[key] {
+			continue
+		}
+		report.MissingInSource++
+		if len(report.TargetOnlyKeys) < limit {
+			report.TargetOnlyKeys = append(report.TargetOnlyKeys, diffKeyValues(keyColumns, targetRows[key]))
+		}
+	}
+
+	report.Truncated = report.MissingInTarget > len(report.SourceOnlyKeys) ||
+		report.MissingInSource > len(report.TargetOnlyKeys) ||
+		report.Mismatched > len(report.Mismatches)
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// fetchTargetData reads the given columns of every target row
+func (se *SyncEngine) fetchTargetData(ctx context.Context, tableName string, columns []ColumnInfo) ([]map[string]interface{}, error) {
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = sqlident.PostgresColumn(col.targetName())
+		if col.Target != "" {
+			// Read under the source name, so rows are keyed like the source rows
+			columnNames[i] += " AS " + sqlident.PostgresColumn(col.Name)
+		}
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columnNames, ", "), sqlident.Postgres(tableName))
+
+	rows, err := se.DB.Target.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+func findColumn(columns []ColumnInfo, name string) (ColumnInfo, bool) {
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+	return ColumnInfo{}, false
+}
+
+// diffKey joins the normalized key values of a row into a map key
+func diffKey(keyColumns []ColumnInfo, row map[string]interface{}) string {
+	parts := make([]string, len(keyColumns))
+	for i, col := range keyColumns {
+		parts[i] = comparableText(col, row[col.Name])
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+func diffKeyValues(keyColumns []ColumnInfo, row map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(keyColumns))
+	for _, col := range keyColumns {
+		values[col.Name] = diffValue(col, row[col.Name])
+	}
+	return values
+}
+
+// diffValue converts a value read from either database into a form that renders readably in JSON
+func diffValue(col ColumnInfo, value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if id, ok := mssqlUUID(col, v); ok {
+			return id
+		}
+		return string(v)
+	default:
+		return v
+	}
+}
+
+// comparableText renders a value as text that is equal for equal source and target values
+func comparableText(col ColumnInfo, value interface{}) string {
+	switch v := diffValue(col, value).(type) {
+	case nil:
+		return "\x00"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case string:
+		if strings.EqualFold(col.DataType, "uniqueidentifier") {
+			return strings.ToLower(v)
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// valuesEqual compares a source and a target value, tolerating the representation differences of the two drivers
+func valuesEqual(col ColumnInfo, source, target interface{}) bool {
+	if source == nil || target == nil {
+		return source == nil && target == nil
+	}
+	if col.JSON {
+		var a, b interface{}
+		if json.Unmarshal([]byte(fmt.Sprint(diffValue(col, source))), &a) == nil &&
+			json.Unmarshal([]byte(fmt.Sprint(diffValue(col, target))), &b) == nil {
+			return reflect.DeepEqual(a, b)
+		}
+	}
+	if a, ok := source.(time.Time); ok {
+		if b, ok := target.(time.Time); ok {
+			return a.Equal(b)
+		}
+	}
+
+	a, b := comparableText(col, source), comparableText(col, target)
+	if a == b {
+		return true
+	}
+	// Numerics may differ in trailing zeros or arrive as text on one side only
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && x == y
+}
+
+// mssqlUUID formats a uniqueidentifier read from MSSQL, whose first three groups are little-endian
+func mssqlUUID(col ColumnInfo, value []byte) (string, bool) {
+	if !strings.EqualFold(col.DataType, "uniqueidentifier") || len(value) != 16 {
+		return "", false
+	}
+	b := []byte{
+		value[3], value[2], value[1], value[0],
+		value[5], value[4],
+		value[7], value[6],
+	}
+	b = append(b, value[8:]...)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+}
@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ensureDurability switches an existing target table between LOGGED and UNLOGGED to match its durability setting.
+// Tables without an explicit setting are left as they are
+func (se *SyncEngine) ensureDurability(tableName, durability string) error {
+	if durability == "" {
+		return nil
+	}
+
+	schema, table := sqlident.SplitQualified(tableName, "public")
+	var persistence string
+	err := se.DB.Target.QueryRow(`
+		SELECT c.relpersistence
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`, schema, table).Scan(&persistence)
+	if err != nil {
+		return err
+	}
+
+	unlogged := persistence == "u"
+	wantUnlogged := durability == config.DurabilityUnlogged
+	if unlogged == wantUnlogged {
+		return nil
+	}
+
+	mode := "LOGGED"
+	if wantUnlogged {
+		mode = "UNLOGGED"
+	}
+	se.Logger.Info("Changing target table durability", zap.String("table", tableName), zap.String("mode", mode))
+
+	// SET LOGGED / UNLOGGED rewrites the table
+	_, err = se.DB.Target.Exec(fmt.Sprintf("ALTER TABLE %s SET %s", sqlident.Postgres(tableName), mode))
+	return err
+}
+
+// WarnBatchedCommits logs the tables whose loads commit in batches, since readers can see them partially loaded
+// and a failed load leaves the batches committed before it
+func (se *SyncEngine) WarnBatchedCommits() {
+	for _, tc := range BatchedCommitTables(se.Config) {
+		se.Logger.Warn("Target loads commit in batches; the table is not consistent while a load runs or after one fails",
+			zap.String("table", tc.TargetTable),
+			zap.Int("commit_every", tc.GetCommitEvery(se.Config.Defaults)),
+		)
+	}
+}
+
+// BatchedCommitTables returns the tables of a configuration whose loads commit in batches
+func BatchedCommitTables(cfg *config.Config) []config.TableConfig {
+	var tables []config.TableConfig
+	for _, tc := range cfg.Tables {
+		if tc.GetCommitEvery(cfg.Defaults) > 0 {
+			tables = append(tables, tc)
+		}
+	}
+	return tables
+}
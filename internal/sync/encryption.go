@@ -0,0 +1,413 @@
+package sync
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/charmap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// cellAlgorithm is the only cell encryption algorithm of Always Encrypted
+const cellAlgorithm = "AEAD_AES_256_CBC_HMAC_SHA256"
+
+// encryptedColumn is an Always Encrypted column of a source table
+type encryptedColumn struct {
+	Name      string `db:"name"`
+	KeyID     int    `db:"column_encryption_key_id"`
+	Type      string `db:"encryption_type_desc"` // DETERMINISTIC or RANDOMIZED
+	Algorithm string `db:"encryption_algorithm_name"`
+}
+
+// encryptedColumns returns the Always Encrypted columns of a source table. Servers older than SQL Server 2016
+// have no encryption metadata, and no encrypted columns
+func (se *SyncEngine) encryptedColumns(tableName string) ([]encryptedColumn, error) {
+	var columns []encryptedColumn
+	err := se.DB.SourceSchema.Select(&columns, `
+		SELECT c.name, c.column_encryption_key_id, c.encryption_type_desc, c.encryption_algorithm_name
+		FROM sys.columns c
+		WHERE c.object_id = OBJECT_ID(@p1) AND c.encryption_type IS NOT NULL`, sqlident.MSSQL(tableName))
+	var sqlErr mssql.Error
+	if errors.As(err, &sqlErr) && sqlErr.Number == 207 { // invalid column name
+		return nil, nil
+	}
+	return columns, err
+}
+
+// applyEncryptedColumns applies the table's encrypted_columns setting to the Always Encrypted columns among the
+// selected ones. Without it, their ciphertext would be written to columns of the plaintext type and fail the load.
+// Columns of a source query cannot be detected
+func (se *SyncEngine) applyEncryptedColumns(tableConfig config.TableConfig, columns []ColumnInfo) ([]ColumnInfo, error) {
+	if tableConfig.SourceQuery != "" {
+		return columns, nil
+	}
+	encrypted, err := se.encryptedColumns(tableConfig.SourceTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted columns: %w", err)
+	}
+	if len(encrypted) == 0 {
+		return columns, nil
+	}
+
+	mode := tableConfig.GetEncryptedColumns(se.Config.Defaults)
+	keys := make(map[int]*cellKey)
+	kept := columns[:0]
+	for _, col := range columns {
+		var enc *encryptedColumn
+		for i := range encrypted {
+			if strings.EqualFold(encrypted[i].Name, col.Name) {
+				enc = &encrypted[i]
+				break
+			}
+		}
+		if enc == nil {
+			kept = append(kept, col)
+			continue
+		}
+
+		switch mode {
+		case config.EncryptedSkip:
+			se.Logger.Warn("Skipping Always Encrypted column",
+				zap.String("table", tableConfig.TargetTable),
+				zap.String("column", col.Name),
+			)
+			continue
+		case config.EncryptedCiphertext:
+			col.Encrypted = config.EncryptedCiphertext
+		case config.EncryptedDecrypt:
+			if !strings.EqualFold(strings.ReplaceAll(enc.Algorithm, "_", ""), strings.ReplaceAll(cellAlgorithm, "_", "")) {
+				return nil, fmt.Errorf("column %s: unsupported encryption algorithm %s", col.Name, enc.Algorithm)
+			}
+			if !decryptableType(col.DataType) {
+				return nil, fmt.Errorf("column %s: decrypting %s columns is not supported, use encrypted_columns: ciphertext or skip", col.Name, col.DataType)
+			}
+			key, ok := keys[enc.KeyID]
+			if !ok {
+				if key, err = se.columnEncryptionKey(enc.KeyID); err != nil {
+					return nil, fmt.Errorf("column %s: %w", col.Name, err)
+				}
+				keys[enc.KeyID] = key
+			}
+			col.Encrypted = config.EncryptedDecrypt
+			col.cellKey = key
+		}
+		kept = append(kept, col)
+	}
+	return kept, nil
+}
+
+// columnEncryptionKey decrypts a column encryption key with one of the configured column master keys
+func (se *SyncEngine) columnEncryptionKey(keyID int) (*cellKey, error) {
+	var values []struct {
+		Value     []byte `db:"encrypted_value"`
+		Algorithm string `db:"encryption_algorithm_name"`
+		KeyPath   string `db:"key_path"`
+	}
+	err := se.DB.SourceSchema.Select(&values, `
+		SELECT v.encrypted_value, v.encryption_algorithm_name, m.key_path
+		FROM sys.column_encryption_key_values v
+		JOIN sys.column_master_keys m ON m.column_master_key_id = v.column_master_key_id
+		WHERE v.column_encryption_key_id = @p1`, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column encryption key: %w", err)
+	}
+
+	var paths []string
+	for _, value := range values {
+		masterKey, ok := se.Config.Source.ColumnEncryption.MasterKey(value.KeyPath)
+		if !ok {
+			paths = append(paths, value.KeyPath)
+			continue
+		}
+		if !strings.EqualFold(value.Algorithm, "RSA_OAEP") {
+			return nil, fmt.Errorf("column encryption key algorithm %s is not supported", value.Algorithm)
+		}
+		privateKey, err := loadPrivateKey(masterKey.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cek, err := decryptColumnEncryptionKey(privateKey, value.Value)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s: %w", value.KeyPath, err)
+		}
+		return newCellKey(cek), nil
+	}
+	return nil, fmt.Errorf("no master key configured for column encryption key %d (key paths: %s)", keyID, strings.Join(paths, ", "))
+}
+
+// loadPrivateKey reads a PEM encoded RSA private key
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("master key %s is not PEM encoded", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse master key %s: %w", path, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("master key %s is not an RSA key", path)
+	}
+	return rsaKey, nil
+}
+
+// decryptColumnEncryptionKey decrypts an encrypted column encryption key value: a version byte, the key path and
+// ciphertext lengths, the key path, the RSA-OAEP ciphertext and an RSA signature over everything before it
+func decryptColumnEncryptionKey(privateKey *rsa.PrivateKey, value []byte) ([]byte, error) {
+	if len(value) < 5 || value[0] != 1 {
+		return nil, fmt.Errorf("unexpected encrypted key format")
+	}
+	keyPathLength := int(binary.LittleEndian.Uint16(value[1:3]))
+	ciphertextLength := int(binary.LittleEndian.Uint16(value[3:5]))
+	start := 5 + keyPathLength
+	end := start + ciphertextLength
+	if end > len(value) {
+		return nil, fmt.Errorf("unexpected encrypted key format")
+	}
+
+	hash := sha256.Sum256(value[:end])
+	if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], value[end:]); err != nil {
+		return nil, fmt.Errorf("encrypted key signature does not match the master key")
+	}
+	return rsa.DecryptOAEP(sha1.New(), nil, privateKey, value[start:end], nil)
+}
+
+// cellKey holds the keys derived from a column encryption key that decrypt its cells
+type cellKey struct {
+	encryption []byte
+	mac        []byte
+}
+
+// newCellKey derives the encryption and MAC keys of a column encryption key
+func newCellKey(cek []byte) *cellKey {
+	derive := func(purpose string) []byte {
+		salt := fmt.Sprintf("Microsoft SQL Server cell %s with encryption algorithm:%s and key length:256", purpose, cellAlgorithm)
+		h := hmac.New(sha256.New, cek)
+		h.Write(utf16LE(salt))
+		return h.Sum(nil)
+	}
+	return &cellKey{encryption: derive("encryption key"), mac: derive("MAC key")}
+}
+
+// decrypt authenticates and decrypts a cell: a version byte, an HMAC-SHA256 tag, the IV and the AES-256-CBC ciphertext
+func (k *cellKey) decrypt(cell []byte) ([]byte, error) {
+	const headerLength = 1 + sha256.Size + aes.BlockSize
+	if len(cell) < headerLength+aes.BlockSize || cell[0] != 1 || (len(cell)-headerLength)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("unexpected ciphertext format")
+	}
+	tag := cell[1 : 1+sha256.Size]
+	iv := cell[1+sha256.Size : headerLength]
+	ciphertext := cell[headerLength:]
+
+	mac := hmac.New(sha256.New, k.mac)
+	mac.Write(cell[:1])
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write([]byte{1}) // length of the version
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return nil, fmt.Errorf("ciphertext authentication failed")
+	}
+
+	block, err := aes.NewCipher(k.encryption)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// decryptRow replaces the ciphertext of decrypted columns in a fetched row with their values
+func decryptRow(columns []ColumnInfo, row map[string]interface{}) error {
+	for _, col := range columns {
+		if col.cellKey == nil || row[col.Name] == nil {
+			continue
+		}
+		cell, ok := row[col.Name].([]byte)
+		if !ok {
+			return fmt.Errorf("column %s: expected ciphertext, got %T", col.Name, row[col.Name])
+		}
+		plaintext, err := col.cellKey.decrypt(cell)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		value, err := decodePlaintext(col, plaintext)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		row[col.Name] = value
+	}
+	return nil
+}
+
+// decryptableType reports whether plaintext values of an MSSQL type can be decoded by decodePlaintext
+func decryptableType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "bit", "tinyint", "smallint", "int", "bigint", "float", "real", "decimal", "numeric", "money", "smallmoney",
+		"char", "varchar", "nchar", "nvarchar", "uniqueidentifier", "binary", "varbinary", "date", "datetime", "smalldatetime":
+		return true
+	default:
+		return false
+	}
+}
+
+// decodePlaintext decodes a decrypted value, which Always Encrypted stores in a normalized binary form: integers
+// and bit as 8 byte little-endian integers, strings in the column's encoding and the rest as on the TDS wire
+func decodePlaintext(col ColumnInfo, b []byte) (interface{}, error) {
+	switch strings.ToLower(col.DataType) {
+	case "bit", "tinyint", "smallint", "int", "bigint":
+		if len(b) == 0 || len(b) > 8 {
+			return nil, fmt.Errorf("unexpected integer length %d", len(b))
+		}
+		padded := make([]byte, 8)
+		copy(padded, b)
+		if b[len(b)-1]&0x80 != 0 {
+			for i := len(b); i < 8; i++ {
+				padded[i] = 0xff
+			}
+		}
+		value := int64(binary.LittleEndian.Uint64(padded))
+		if strings.EqualFold(col.DataType, "bit") {
+			return value != 0, nil
+		}
+		return value, nil
+	case "float", "real":
+		switch len(b) {
+		case 4:
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+		case 8:
+			return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+		}
+		return nil, fmt.Errorf("unexpected float length %d", len(b))
+	case "decimal", "numeric":
+		// A sign byte (1 for positive) and the unscaled value as a 16 byte little-endian integer
+		if len(b) < 2 {
+			return nil, fmt.Errorf("unexpected decimal length %d", len(b))
+		}
+		magnitude := make([]byte, len(b)-1)
+		for i := range magnitude {
+			magnitude[i] = b[len(b)-1-i]
+		}
+		unscaled := new(big.Int).SetBytes(magnitude)
+		if b[0] != 1 {
+			unscaled.Neg(unscaled)
+		}
+		return scaledString(unscaled, col.Scale), nil
+	case "money", "smallmoney":
+		var unscaled int64
+		switch len(b) {
+		case 4:
+			unscaled = int64(int32(binary.LittleEndian.Uint32(b)))
+		case 8:
+			unscaled = int64(int32(binary.LittleEndian.Uint32(b[:4])))<<32 | int64(binary.LittleEndian.Uint32(b[4:]))
+		default:
+			return nil, fmt.Errorf("unexpected money length %d", len(b))
+		}
+		return scaledString(big.NewInt(unscaled), 4), nil
+	case "char", "varchar":
+		// UTF-8 collations store UTF-8; the binary collations encrypted columns usually use store Windows-1252
+		if utf8.Valid(b) {
+			return string(b), nil
+		}
+		decoded, err := charmap.Windows1252.NewDecoder().Bytes(b)
+		return string(decoded), err
+	case "nchar", "nvarchar":
+		if len(b)%2 != 0 {
+			return nil, fmt.Errorf("unexpected UTF-16 length %d", len(b))
+		}
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+	case "uniqueidentifier":
+		if len(b) != 16 {
+			return nil, fmt.Errorf("unexpected uniqueidentifier length %d", len(b))
+		}
+		// The first three groups are little-endian
+		return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+			binary.LittleEndian.Uint32(b[0:4]), binary.LittleEndian.Uint16(b[4:6]), binary.LittleEndian.Uint16(b[6:8]), b[8:10], b[10:16]), nil
+	case "binary", "varbinary":
+		return b, nil
+	case "date":
+		if len(b) != 3 {
+			return nil, fmt.Errorf("unexpected date length %d", len(b))
+		}
+		days := int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+		return time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days), nil
+	case "datetime", "smalldatetime":
+		base := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+		switch len(b) {
+		case 4:
+			days := int(binary.LittleEndian.Uint16(b[0:2]))
+			minutes := time.Duration(binary.LittleEndian.Uint16(b[2:4])) * time.Minute
+			return base.AddDate(0, 0, days).Add(minutes), nil
+		case 8:
+			days := int(int32(binary.LittleEndian.Uint32(b[0:4])))
+			ticks := time.Duration(binary.LittleEndian.Uint32(b[4:8])) * time.Second / 300
+			return base.AddDate(0, 0, days).Add(ticks), nil
+		}
+		return nil, fmt.Errorf("unexpected datetime length %d", len(b))
+	default:
+		return nil, fmt.Errorf("decrypting %s values is not supported", col.DataType)
+	}
+}
+
+// scaledString formats an unscaled integer with the given number of decimal places
+func scaledString(unscaled *big.Int, scale int) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if scale <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// utf16LE encodes a string as UTF-16 little-endian, as SQL Server hashes key derivation salts
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(b[2*i:], unit)
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/events"
+)
+
+// publishEvents publishes the change events for a completed sync. The target is already
+// committed at this point, so failures are logged rather than failing the sync.
+func (se *SyncEngine) publishEvents(ctx context.Context, tableConfig config.TableConfig, result *SyncResult, data []map[string]interface{}) {
+	if se.Events == nil || !tableConfig.GetPublishEvents() {
+		return
+	}
+
+	err := se.Events.Publish(ctx, events.BatchEvent{
+		Table:           tableConfig.TargetTable,
+		BatchID:         result.BatchID,
+		RowsSynced:      result.RowsSynced,
+		StartedAt:       result.StartedAt,
+		FinishedAt:      time.Now(),
+		SourceChangedAt: result.SourceChangedAt,
+	}, data)
+	if err != nil {
+		se.Logger.Warn("Failed to publish sync events",
+			zap.String("table", tableConfig.TargetTable),
+			zap.String("batch_id", result.BatchID),
+			zap.Error(err),
+		)
+	}
+}
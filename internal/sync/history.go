@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/history"
+)
+
+// recordHistory persists the outcome of a sync run when history is enabled
+func (se *SyncEngine) recordHistory(result *SyncResult, syncErr error) {
+	if se.History == nil {
+		return
+	}
+
+	rec := history.Record{
+		Kind:            history.KindSync,
+		BatchID:         result.BatchID,
+		TableName:       result.TableName,
+		StartedAt:       result.StartedAt,
+		FinishedAt:      result.StartedAt.Add(result.Duration),
+		DurationMs:      result.Duration.Milliseconds(),
+		Success:         syncErr == nil,
+		RowsSynced:      int64(result.RowsSynced),
+		RowsRead:        int64(result.RowsRead),
+		RowsSkipped:     int64(result.RowsSkipped),
+		BytesRead:       result.BytesRead,
+		SourceChangedAt: result.SourceChangedAt,
+	}
+	if syncErr != nil {
+		rec.Error = syncErr.Error()
+	}
+	var panicErr *PanicError
+	if errors.As(syncErr, &panicErr) {
+		rec.Stack = panicErr.Stack
+	}
+	if result.Validation != nil {
+		if report, err := json.Marshal(result.Validation); err == nil {
+			rec.Validation = string(report)
+		}
+	}
+
+	if err := se.History.Record(rec); err != nil {
+		se.Logger.Warn("Failed to record sync history",
+			zap.String("table", result.TableName),
+			zap.Error(err),
+		)
+	}
+}
+
+// recordTargetHistory persists the load of a sync into a fan-out target when history is enabled
+func (se *SyncEngine) recordTargetHistory(result *SyncResult, target TargetResult) {
+	if se.History == nil {
+		return
+	}
+
+	rec := history.Record{
+		Kind:            history.KindFanout,
+		BatchID:         result.BatchID,
+		TableName:       result.TableName,
+		Target:          target.Target,
+		StartedAt:       target.StartedAt,
+		FinishedAt:      target.StartedAt.Add(target.Duration),
+		DurationMs:      target.Duration.Milliseconds(),
+		Success:         target.Error == "",
+		Error:           target.Error,
+		RowsSynced:      int64(target.RowsWritten),
+		RowsRead:        int64(result.RowsRead),
+		BytesRead:       result.BytesRead,
+		SourceChangedAt: result.SourceChangedAt,
+	}
+	if err := se.History.Record(rec); err != nil {
+		se.Logger.Warn("Failed to record fan-out target history",
+			zap.String("table", result.TableName),
+			zap.String("target", target.Target),
+			zap.Error(err),
+		)
+	}
+}
+
+// latestChange returns the most recent timestamp found in the change column
+func latestChange(data []map[string]interface{}, changeColumn string) *time.Time {
+	var latest *time.Time
+	for _, row := range data {
+		for col, val := range row {
+			if !strings.EqualFold(col, changeColumn) {
+				continue
+			}
+			if ts, ok := val.(time.Time); ok && (latest == nil || ts.After(*latest)) {
+				changed := ts
+				latest = &changed
+			}
+			break
+		}
+	}
+	return latest
+}
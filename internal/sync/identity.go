@@ -0,0 +1,69 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// sequenceColumn is a target column backed by a sequence: an identity column or a serial column
+type sequenceColumn struct {
+	Name     string `db:"attname"`
+	Identity string `db:"attidentity"` // a for GENERATED ALWAYS, d for GENERATED BY DEFAULT, empty for serial
+}
+
+// identityType reports whether a PostgreSQL column type can be an identity column
+func identityType(pgType string) bool {
+	switch pgType {
+	case "SMALLINT", "INTEGER", "BIGINT":
+		return true
+	default:
+		return false
+	}
+}
+
+// sequenceColumns returns the identity and serial columns of a target table
+func (se *SyncEngine) sequenceColumns(tx *sqlx.Tx, tableName string) ([]sequenceColumn, error) {
+	var columns []sequenceColumn
+	err := tx.Select(&columns, `
+		SELECT a.attname, a.attidentity::text AS attidentity
+		FROM pg_attribute a
+		WHERE a.attrelid = $1::text::regclass AND a.attnum > 0 AND NOT a.attisdropped
+			AND pg_get_serial_sequence($1, a.attname) IS NOT NULL
+		ORDER BY a.attnum`, sqlident.Postgres(tableName))
+	return columns, err
+}
+
+// hasIdentityColumn reports whether any of the loaded columns is an identity column of the target
+func hasIdentityColumn(sequences []sequenceColumn, columns []ColumnInfo) bool {
+	for _, seq := range sequences {
+		if seq.Identity == "" {
+			continue
+		}
+		for _, col := range columns {
+			if col.targetName() == seq.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reseed sets the sequence of each identity and serial column to the column's highest value. Sequences of empty
+// columns are left as they are
+func (se *SyncEngine) reseed(tx *sqlx.Tx, tableName string, sequences []sequenceColumn) error {
+	for _, seq := range sequences {
+		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, $2), MAX(%s)) FROM %s HAVING MAX(%s) IS NOT NULL",
+			sqlident.PostgresColumn(seq.Name), sqlident.Postgres(tableName), sqlident.PostgresColumn(seq.Name))
+		if _, err := tx.Exec(query, sqlident.Postgres(tableName), seq.Name); err != nil {
+			return fmt.Errorf("column %s: %w", seq.Name, err)
+		}
+	}
+	if len(sequences) > 0 {
+		se.Logger.Info("Reseeded target sequences", zap.String("table", tableName), zap.Int("columns", len(sequences)))
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// maxBindParameters is PostgreSQL's limit on bind parameters in a single statement
+const maxBindParameters = 65535
+
+// batchInsertRows is the most rows written by one multi-row INSERT
+const batchInsertRows = 500
+
+// loadWriter writes the rows of a load transaction with the table's insert mode. Batch and copy modes buffer
+// rows, so flush must be called before the transaction commits
+type loadWriter struct {
+	tx         *sqlx.Tx
+	mode       string
+	tableName  string
+	columns    []ColumnInfo
+	overriding string // OVERRIDING SYSTEM VALUE when the target has identity columns
+	batchRows  int
+	stmt       *sqlx.Stmt
+	pending    []interface{}
+	rows       int // rows pending in a batch
+}
+
+// newLoadWriter prepares the statement of a load transaction's writes
+func newLoadWriter(tx *sqlx.Tx, mode, tableName string, columns []ColumnInfo, overriding string) (*loadWriter, error) {
+	w := &loadWriter{tx: tx, mode: mode, tableName: tableName, columns: columns, overriding: overriding, batchRows: 1}
+
+	var query string
+	switch mode {
+	case config.InsertCopy:
+		// COPY keeps the given values of identity columns, like OVERRIDING SYSTEM VALUE
+		schema, table := sqlident.SplitQualified(tableName, "public")
+		names := make([]string, len(columns))
+		for i, col := range columns {
+			names[i] = col.targetName()
+		}
+		query = pq.CopyInSchema(schema, table, names...)
+	case config.InsertBatch:
+		w.batchRows = batchRowsFor(len(columns))
+		query = w.insertQuery(w.batchRows)
+	default:
+		query = w.insertQuery(1)
+	}
+
+	stmt, err := tx.Preparex(query)
+	if err != nil {
+		return nil, err
+	}
+	w.stmt = stmt
+	return w, nil
+}
+
+// batchRowsFor returns how many rows of a table with the given column count fit in one multi-row INSERT
+func batchRowsFor(columnCount int) int {
+	if columnCount == 0 {
+		return batchInsertRows
+	}
+	rows := maxBindParameters / columnCount
+	if rows > batchInsertRows {
+		rows = batchInsertRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// insertQuery builds an INSERT of the given number of rows
+func (w *loadWriter) insertQuery(rows int) string {
+	columnNames := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		columnNames[i] = sqlident.PostgresColumn(col.targetName())
+	}
+
+	tuples := make([]string, rows)
+	placeholders := make([]string, len(w.columns))
+	for r := range tuples {
+		for i := range placeholders {
+			placeholders[i] = fmt.Sprintf("$%d", r*len(w.columns)+i+1)
+		}
+		tuples[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	return fmt.Sprintf(
+		"INSERT INTO %s (%s)%s VALUES %s",
+		sqlident.Postgres(w.tableName),
+		strings.Join(columnNames, ", "),
+		w.overriding,
+		strings.Join(tuples, ", "),
+	)
+}
+
+// write writes a row's values, or buffers them until a batch is full
+func (w *loadWriter) write(values []interface{}) error {
+	switch w.mode {
+	case config.InsertBatch:
+		w.pending = append(w.pending, values...)
+		w.rows++
+		if w.rows < w.batchRows {
+			return nil
+		}
+		_, err := w.stmt.Exec(w.pending...)
+		w.pending, w.rows = w.pending[:0], 0
+		return err
+	default:
+		// Single-row INSERT and COPY both take one row per Exec
+		_, err := w.stmt.Exec(values...)
+		return err
+	}
+}
+
+// flush writes buffered rows: the last partial batch, or the end of the COPY data. Nothing else can run in the
+// transaction while a COPY is in progress
+func (w *loadWriter) flush() error {
+	switch w.mode {
+	case config.InsertCopy:
+		// An Exec without values ends the COPY
+		_, err := w.stmt.Exec()
+		return err
+	case config.InsertBatch:
+		if w.rows == 0 {
+			return nil
+		}
+		_, err := w.tx.Exec(w.insertQuery(w.rows), w.pending...)
+		w.pending, w.rows = w.pending[:0], 0
+		return err
+	default:
+		return nil
+	}
+}
+
+// close releases the prepared statement
+func (w *loadWriter) close() error {
+	return w.stmt.Close()
+}
+
+// logInsertFailure logs a failed write. Only single-row inserts fail on the row being written, so the values are
+// logged for them alone
+func (se *SyncEngine) logInsertFailure(tableName, mode string, values []interface{}, err error) {
+	if mode == config.InsertRow && values != nil {
+		se.Logger.Error("Failed to insert row", zap.Error(err), zap.Any("values", values))
+		return
+	}
+	se.Logger.Error("Failed to insert rows", zap.String("table", tableName), zap.String("insert_mode", mode), zap.Error(err))
+}
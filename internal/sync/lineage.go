@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// Lineage metadata columns maintained on target tables
+const (
+	SyncedAtColumn    = "_synced_at"
+	SyncBatchIDColumn = "_sync_batch_id"
+	SourceDBColumn    = "_source_db"
+)
+
+// lineageColumns returns the metadata columns appended to target tables
+func lineageColumns() []ColumnInfo {
+	return []ColumnInfo{
+		{Name: SyncedAtColumn, DataType: "datetimeoffset", TargetType: "TIMESTAMPTZ", Nullable: true},
+		{Name: SyncBatchIDColumn, DataType: "varchar", Length: 64, Nullable: true},
+		{Name: SourceDBColumn, DataType: "varchar", Length: 256, Nullable: true},
+	}
+}
+
+// stampLineage sets the lineage metadata values on every fetched row
+func stampLineage(data []map[string]interface{}, batchID, sourceDB string, syncedAt time.Time) {
+	for _, row := range data {
+		row[SyncedAtColumn] = syncedAt
+		row[SyncBatchIDColumn] = batchID
+		row[SourceDBColumn] = sourceDB
+	}
+}
+
+// ensureLineageColumns adds the lineage metadata columns to an existing target table
+func (se *SyncEngine) ensureLineageColumns(tableName string) error {
+	for _, col := range lineageColumns() {
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s",
+			sqlident.Postgres(tableName), sqlident.PostgresColumn(col.Name), mapMSSQLToPostgreSQL(col))
+		if _, err := se.DB.Target.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBatchID generates a unique identifier for a sync run or batch
+func NewBatchID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405"), hex.EncodeToString(buf))
+}
@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/snapshot"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ErrValueTooLarge is returned when a binary value exceeds its column's max_bytes and the oversize policy is fail
+var ErrValueTooLarge = errors.New("binary value exceeds max_bytes")
+
+// largeObject is the handling of a binary column whose values are capped or stored outside the target
+type largeObject struct {
+	maxBytes int
+	oversize string
+	sink     snapshot.Sink // nil when values are loaded into the target
+	prefix   string        // key prefix of the column's stored values
+}
+
+// markLargeObjects sets the configured cap and storage on binary columns
+func markLargeObjects(tableConfig config.TableConfig, columns []ColumnInfo) error {
+	for i := range columns {
+		col := &columns[i]
+		columnCfg, ok := tableConfig.GetColumnConfig(col.Name)
+		if !ok || !columnCfg.IsLargeObject() {
+			continue
+		}
+		switch strings.ToLower(col.DataType) {
+		case "varbinary", "binary", "image":
+		default:
+			return fmt.Errorf("column %s: max_bytes and storage require a binary column, found %s", col.Name, col.DataType)
+		}
+		if col.Encrypted != "" {
+			return fmt.Errorf("column %s: max_bytes and storage cannot be used on encrypted columns", col.Name)
+		}
+
+		lob := &largeObject{maxBytes: columnCfg.MaxBytes, oversize: columnCfg.GetOversize()}
+		if columnCfg.Storage != nil {
+			sink, err := snapshot.NewSink(*columnCfg.Storage)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			lob.sink = sink
+			lob.prefix = path.Join(strings.Trim(columnCfg.Storage.Prefix, "/"), tableConfig.TargetTable, col.targetName())
+		}
+		col.lob = lob
+	}
+	return nil
+}
+
+// largeObjectSelectExpression reads a capped binary column, cutting or nulling larger values on the source so they
+// are never transferred. Values above the cap of a fail column are read and rejected by storeLargeObjects
+func largeObjectSelectExpression(col ColumnInfo) string {
+	name := sqlident.MSSQLColumn(col.Name)
+	if col.lob.maxBytes == 0 {
+		return name
+	}
+	switch col.lob.oversize {
+	case config.OversizeTruncate:
+		return fmt.Sprintf("SUBSTRING(%[1]s, 1, %[2]d) AS %[1]s", name, col.lob.maxBytes)
+	case config.OversizeNull:
+		return fmt.Sprintf("CASE WHEN DATALENGTH(%[1]s) > %[2]d THEN NULL ELSE %[1]s END AS %[1]s", name, col.lob.maxBytes)
+	default:
+		return name
+	}
+}
+
+// storeLargeObjects checks the binary values of a row against their column's cap and writes those of stored columns
+// to storage as they are read, replacing them with their object key so a read holds no more than one value at a time.
+// Keys are the SHA-256 of the value, so repeated values share an object
+func storeLargeObjects(ctx context.Context, columns []ColumnInfo, row map[string]interface{}) error {
+	for _, col := range columns {
+		if col.lob == nil {
+			continue
+		}
+		value, ok := row[col.Name].([]byte)
+		if !ok {
+			continue
+		}
+		if col.lob.maxBytes > 0 && len(value) > col.lob.maxBytes {
+			return fmt.Errorf("%w: column %s holds %d bytes, max_bytes is %d", ErrValueTooLarge, col.Name, len(value), col.lob.maxBytes)
+		}
+		if col.lob.sink == nil {
+			continue
+		}
+
+		sum := sha256.Sum256(value)
+		key := path.Join(col.lob.prefix, hex.EncodeToString(sum[:]))
+		if err := col.lob.sink.Put(ctx, key, bytes.NewReader(value), int64(len(value)), "application/octet-stream"); err != nil {
+			return fmt.Errorf("column %s: failed to store value: %w", col.Name, err)
+		}
+		row[col.Name] = key
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// maintenanceStatement returns the statement for a maintenance operation on a target table
+func maintenanceStatement(tableName, operation string) (string, error) {
+	table := sqlident.Postgres(tableName)
+	switch operation {
+	case history.KindAnalyze:
+		return "ANALYZE " + table, nil
+	case history.KindVacuum:
+		return "VACUUM (ANALYZE) " + table, nil
+	case history.KindVacuumFull:
+		return "VACUUM (FULL, ANALYZE) " + table, nil
+	default:
+		return "", fmt.Errorf("unsupported maintenance operation: %s", operation)
+	}
+}
+
+// RunMaintenance runs an ANALYZE or VACUUM on a target table and records it in the sync history
+func (se *SyncEngine) RunMaintenance(ctx context.Context, tableName, operation string) error {
+	relation, err := se.maintainedTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	statement, err := maintenanceStatement(relation, operation)
+	if err != nil {
+		return err
+	}
+
+	startedAt := time.Now()
+	ctx = database.WithQueryLabel(ctx, "table:"+tableName)
+	_, err = se.DB.Target.ExecContext(ctx, statement)
+	duration := time.Since(startedAt)
+
+	se.Logger.Info("Target maintenance finished",
+		zap.String("table", tableName),
+		zap.String("operation", operation),
+		zap.Duration("duration", duration),
+		zap.Error(err),
+	)
+
+	if se.History != nil {
+		rec := history.Record{
+			Kind:       operation,
+			BatchID:    NewBatchID(),
+			TableName:  tableName,
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(duration),
+			DurationMs: duration.Milliseconds(),
+			Success:    err == nil,
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		if recordErr := se.History.Record(rec); recordErr != nil {
+			se.Logger.Warn("Failed to record maintenance history",
+				zap.String("table", tableName),
+				zap.Error(recordErr),
+			)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,43 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// PanicError is a panic recovered from a table sync, which fails the sync instead of crashing its actor
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("sync panicked: %v", e.Value)
+}
+
+// runSyncRecovered runs the sync steps of a table, converting a panic into a *PanicError. Open transactions are
+// rolled back by their deferred rollbacks while the panic unwinds, before it is recovered here
+func (se *SyncEngine) runSyncRecovered(ctx context.Context, tableConfig config.TableConfig, result *SyncResult) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		panicErr := &PanicError{Value: recovered, Stack: string(debug.Stack())}
+		metrics.SyncPanicsTotal.WithLabelValues(tableConfig.TargetTable).Inc()
+		se.Logger.Error("Sync panicked",
+			zap.String("table", tableConfig.TargetTable),
+			zap.String("batch_id", result.BatchID),
+			zap.Any("panic", recovered),
+			zap.String("stack", panicErr.Stack),
+		)
+		err = panicErr
+	}()
+	return se.runSync(ctx, tableConfig, result)
+}
@@ -0,0 +1,173 @@
+package sync
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// maxPartitionSuffix keeps partition names within the PostgreSQL identifier limit
+const maxPartitionSuffix = 40
+
+// partitionBound describes the partition a row is routed to
+type partitionBound struct {
+	Suffix string // appended to the parent table name
+	Bound  string // FOR VALUES clause of the partition
+}
+
+// partitionClause returns the PARTITION BY clause for a partitioned target table with the given columns
+func partitionClause(p *config.Partitioning, columns []ColumnInfo) string {
+	method := "LIST"
+	if strings.EqualFold(p.Type, "range") {
+		method = "RANGE"
+	}
+	column := p.Column
+	if col, ok := findColumn(columns, p.Column); ok {
+		column = col.targetName()
+	}
+	return fmt.Sprintf("PARTITION BY %s (%s)", method, sqlident.PostgresColumn(column))
+}
+
+// partitionName returns the qualified name of a partition of the target table
+func partitionName(tableName, suffix string) string {
+	schema, table := sqlident.SplitQualified(tableName, "public")
+	return sqlident.PostgresColumn(schema) + "." + sqlident.PostgresColumn(table+"_"+suffix)
+}
+
+// rowPartition resolves the partition holding a partition key value
+func rowPartition(p *config.Partitioning, value interface{}) (partitionBound, error) {
+	if strings.EqualFold(p.Type, "range") {
+		return rangePartition(p.GetInterval(), value)
+	}
+	return listPartition(value), nil
+}
+
+// rangePartition returns the day, month or year partition containing a date value. Rows without a date go to the DEFAULT partition
+func rangePartition(interval string, value interface{}) (partitionBound, error) {
+	if value == nil {
+		return partitionBound{Suffix: "default", Bound: "DEFAULT"}, nil
+	}
+	t, ok := value.(time.Time)
+	if !ok {
+		return partitionBound{}, fmt.Errorf("range partition key must be a date, got %T", value)
+	}
+
+	start, end := rangeBounds(interval, t)
+	var suffix string
+	switch interval {
+	case "day":
+		suffix = start.Format("p20060102")
+	case "year":
+		suffix = start.Format("p2006")
+	default:
+		suffix = start.Format("p200601")
+	}
+
+	const layout = "2006-01-02 15:04:05-07"
+	return partitionBound{
+		Suffix: suffix,
+		Bound:  fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", pq.QuoteLiteral(start.Format(layout)), pq.QuoteLiteral(end.Format(layout))),
+	}, nil
+}
+
+// rangeBounds returns the start and end of the day, month or year range containing t. Bounds are absolute UTC
+// instants so timestamptz keys route the same way regardless of the session time zone
+func rangeBounds(interval string, t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	switch interval {
+	case "day":
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	case "year":
+		start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	default:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// listPartition returns the partition holding a single list value
+func listPartition(value interface{}) partitionBound {
+	if value == nil {
+		return partitionBound{Suffix: "null", Bound: "FOR VALUES IN (NULL)"}
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	key := fmt.Sprint(value)
+	return partitionBound{
+		Suffix: partitionSuffix(key),
+		Bound:  fmt.Sprintf("FOR VALUES IN (%s)", pq.QuoteLiteral(key)),
+	}
+}
+
+// partitionSuffix turns a list value into an identifier-safe suffix, adding a hash when characters were replaced
+func partitionSuffix(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	suffix := b.String()
+	if suffix == key && len(suffix) > 0 && len(suffix) <= maxPartitionSuffix {
+		return suffix
+	}
+
+	if len(suffix) > maxPartitionSuffix {
+		suffix = suffix[:maxPartitionSuffix]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("%s_%08x", suffix, h.Sum32())
+}
+
+// ensurePartitions creates the partitions needed for the rows about to be inserted into a partitioned target table
+// and returns their names
+func (se *SyncEngine) ensurePartitions(tx *sqlx.Tx, tableName string, p *config.Partitioning, columns []ColumnInfo, data []map[string]interface{}) ([]string, error) {
+	column := ""
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, p.Column) {
+			column = col.Name
+			break
+		}
+	}
+	if column == "" {
+		return nil, fmt.Errorf("partition column %s is not synced", p.Column)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, row := range data {
+		bound, err := rowPartition(p, row[column])
+		if err != nil {
+			return nil, err
+		}
+		if seen[bound.Suffix] {
+			continue
+		}
+		seen[bound.Suffix] = true
+
+		name := partitionName(tableName, bound.Suffix)
+		query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s %s", name, sqlident.Postgres(tableName), bound.Bound)
+		if _, err := tx.Exec(query); err != nil {
+			return nil, fmt.Errorf("failed to create partition %s: %w", name, err)
+		}
+		names = append(names, name)
+	}
+
+	se.Logger.Info("Ensured target partitions", zap.String("table", tableName), zap.Int("partitions", len(seen)))
+	return names, nil
+}
@@ -0,0 +1,98 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+)
+
+// pipelineOutputTail is how much of a failed command's output is kept in its error
+const pipelineOutputTail = 2000
+
+// RunPipeline runs the steps of a pipeline in order within its timeout, stopping at the first failing step. SQL
+// scripts run on the target in one transaction each; commands get the job id and pipeline name in SYNC_JOB_ID and
+// SYNC_PIPELINE
+func (se *SyncEngine) RunPipeline(ctx context.Context, pipeline config.Pipeline, jobID string) error {
+	ctx, cancel := context.WithTimeout(database.WithQueryLabel(ctx, "pipeline:"+pipeline.Name), pipeline.GetTimeout())
+	defer cancel()
+
+	logger := se.Logger.With(zap.String("pipeline", pipeline.Name), zap.String("job_id", jobID))
+	for i, step := range pipeline.Steps {
+		start := time.Now()
+		var err error
+		if step.SQLFile != "" {
+			err = se.runPipelineSQL(ctx, step.SQLFile)
+		} else {
+			err = runPipelineCommand(ctx, step, pipeline.Name, jobID, logger)
+		}
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("timed out after %s: %w", pipeline.GetTimeout(), err)
+			}
+			return fmt.Errorf("step %d (%s): %w", i+1, step.GetName(), err)
+		}
+		logger.Info("Pipeline step completed",
+			zap.Int("step", i+1),
+			zap.String("name", step.GetName()),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+	return nil
+}
+
+// runPipelineSQL runs the statements of a SQL script on the target in one transaction
+func (se *SyncEngine) runPipelineSQL(ctx context.Context, path string) error {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	tx, err := se.DB.Target.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Without arguments the script is sent as a simple query, which may hold several statements
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// runPipelineCommand runs an external command, logging its output and keeping the end of it in the error
+func runPipelineCommand(ctx context.Context, step config.PipelineStep, pipelineName, jobID string, logger *zap.Logger) error {
+	cmd := exec.CommandContext(ctx, step.Command[0], step.Command[1:]...)
+	cmd.Dir = step.Dir
+	cmd.Env = append(os.Environ(), "SYNC_JOB_ID="+jobID, "SYNC_PIPELINE="+pipelineName)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+
+	logger.Debug("Pipeline command output",
+		zap.Strings("command", step.Command),
+		zap.String("output", output.String()),
+	)
+	if err != nil {
+		tail := strings.TrimSpace(output.String())
+		if len(tail) > pipelineOutputTail {
+			tail = "..." + tail[len(tail)-pipelineOutputTail:]
+		}
+		if tail == "" {
+			return err
+		}
+		return fmt.Errorf("%w: %s", err, tail)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrPreempted is returned when a load is abandoned between chunks to make room for a higher priority sync.
+// The load's transaction is rolled back, so the target table keeps its previous contents unless it commits in batches
+var ErrPreempted = errors.New("sync preempted by a higher priority sync")
+
+// insertChunkSize is the number of rows inserted between preemption checks
+const insertChunkSize = 1000
+
+type preemptKey struct{}
+
+// WithPreemption lets the sync run with ctx be preempted by setting flag
+func WithPreemption(ctx context.Context, flag *atomic.Bool) context.Context {
+	return context.WithValue(ctx, preemptKey{}, flag)
+}
+
+// preempted reports whether the sync run with ctx has been asked to yield
+func preempted(ctx context.Context) bool {
+	flag, ok := ctx.Value(preemptKey{}).(*atomic.Bool)
+	return ok && flag.Load()
+}
+
+// cancelled returns why a sync was cancelled before finishing, nil while it may continue. Timeouts are
+// not cancellations: they still count against the circuit breakers
+func cancelled(ctx context.Context) error {
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	return context.Cause(ctx)
+}
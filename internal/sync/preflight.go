@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/metrics"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ErrTooManyRows is returned when the preflight check finds more source rows than the table's max_rows
+var ErrTooManyRows = errors.New("source row count exceeds preflight max_rows")
+
+// preflight estimates the source rows of a sync before it reads them, recording the estimate in the result, and
+// aborts the sync when they exceed the table's max_rows. Source tables are estimated from their partition statistics
+// without scanning them; source queries have none and are only counted when a preflight check is configured
+func (se *SyncEngine) preflight(ctx context.Context, tableConfig config.TableConfig, result *SyncResult) error {
+	check := tableConfig.GetPreflight(se.Config.Defaults)
+	if tableConfig.SourceQuery != "" && check == nil {
+		return nil
+	}
+	count := tableConfig.SourceQuery != "" || check != nil && check.Count
+
+	estimate, err := se.estimateSourceRows(ctx, tableConfig, count)
+	if err != nil {
+		if check != nil && check.MaxRows > 0 {
+			return fmt.Errorf("failed to estimate source rows: %w", err)
+		}
+		se.Logger.Warn("Failed to estimate source rows", zap.String("table", tableConfig.TargetTable), zap.Error(err))
+		return nil
+	}
+
+	result.RowsEstimated = estimate
+	metrics.SyncSourceRowsEstimated.WithLabelValues(tableConfig.TargetTable).Set(float64(estimate))
+	se.Logger.Info("Estimated source rows",
+		zap.String("table", tableConfig.TargetTable),
+		zap.Int64("rows", estimate),
+		zap.Bool("counted", count),
+	)
+
+	if check != nil && check.MaxRows > 0 && estimate > check.MaxRows {
+		return fmt.Errorf("%w: about %d rows, max_rows is %d", ErrTooManyRows, estimate, check.MaxRows)
+	}
+	return nil
+}
+
+// estimateSourceRows returns the rows of the table's source: counted with its filter and where conditions, or the
+// row count of the source table's heap or clustered index from sys.partitions
+func (se *SyncEngine) estimateSourceRows(ctx context.Context, tableConfig config.TableConfig, count bool) (int64, error) {
+	var rows sql.NullInt64
+	if count {
+		where, args := sourceWhere(tableConfig)
+		query := fmt.Sprintf("SELECT COUNT_BIG(*) FROM %s%s",
+			sourceRelation(tableConfig.SourceTable, tableConfig.SourceQueryStatement()), where)
+		if err := se.DB.Source.QueryRowContext(ctx, query, args...).Scan(&rows); err != nil {
+			return 0, err
+		}
+		return rows.Int64, nil
+	}
+
+	query := `
+		SELECT SUM(p.rows)
+		FROM sys.partitions p
+		WHERE p.object_id = OBJECT_ID(@p1) AND p.index_id IN (0, 1)
+	`
+	if err := se.DB.SourceSchema.QueryRowContext(ctx, query, sqlident.MSSQL(tableConfig.SourceTable)).Scan(&rows); err != nil {
+		return 0, err
+	}
+	if !rows.Valid {
+		return 0, fmt.Errorf("source table %s not found", tableConfig.SourceTable)
+	}
+	return rows.Int64, nil
+}
@@ -0,0 +1,227 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// scd2Stage is the temporary table an scd2 load writes the source read into before merging it into the history
+const scd2Stage = "pg_temp._scd2_stage"
+
+// scd2Columns returns the key columns of an scd2 table and the columns compared to detect a changed row: the
+// configured compare columns, or every column but the keys and lineage columns
+func scd2Columns(tableConfig config.TableConfig, columns []ColumnInfo) (keys, compared []ColumnInfo, err error) {
+	for _, key := range tableConfig.Keys {
+		col, ok := findColumn(columns, key)
+		if !ok {
+			return nil, nil, fmt.Errorf("key column %s is not synced from the source", key)
+		}
+		keys = append(keys, col)
+	}
+
+	if names := tableConfig.SCD2.GetCompare(); len(names) > 0 {
+		for _, name := range names {
+			col, ok := findColumn(columns, name)
+			if !ok {
+				return nil, nil, fmt.Errorf("scd2 compare column %s is not synced from the source", name)
+			}
+			compared = append(compared, col)
+		}
+		return keys, compared, nil
+	}
+
+	for _, col := range columns {
+		switch col.Name {
+		case SyncedAtColumn, SyncBatchIDColumn, SourceDBColumn:
+			continue
+		}
+		if _, isKey := findColumn(keys, col.Name); !isKey {
+			compared = append(compared, col)
+		}
+	}
+	return keys, compared, nil
+}
+
+// ensureSCD2Columns adds the validity columns of an scd2 table to the target table, with an index on the keys of
+// current versions that the merge looks rows up by
+func (se *SyncEngine) ensureSCD2Columns(tableConfig config.TableConfig, columns []ColumnInfo) error {
+	keys, _, err := scd2Columns(tableConfig, columns)
+	if err != nil {
+		return err
+	}
+	tableName := tableConfig.TargetTable
+	validTo := sqlident.PostgresColumn(tableConfig.SCD2.GetValidTo())
+
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s TIMESTAMPTZ", sqlident.Postgres(tableName), sqlident.PostgresColumn(tableConfig.SCD2.GetValidFrom())),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s TIMESTAMPTZ", sqlident.Postgres(tableName), validTo),
+	}
+	_, table := sqlident.SplitQualified(tableName, "public")
+	keyNames := make([]string, len(keys))
+	for i, key := range keys {
+		keyNames[i] = sqlident.PostgresColumn(key.targetName())
+	}
+	statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s) WHERE %s IS NULL",
+		sqlident.PostgresColumn(table+"_current_idx"), sqlident.Postgres(tableName), strings.Join(keyNames, ", "), validTo))
+
+	for _, statement := range statements {
+		if _, err := se.DB.Target.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadHistory merges a source read into the history of an scd2 table in one transaction. Current versions whose
+// key is missing from the read or whose compared columns changed are closed by setting valid_to, and rows without
+// a current version are inserted as new versions valid from the load. Unchanged rows are left as they are
+func (se *SyncEngine) loadHistory(ctx context.Context, tableConfig config.TableConfig, columns []ColumnInfo, data []map[string]interface{}) (err error) {
+	tableName := tableConfig.TargetTable
+	keys, compared, err := scd2Columns(tableConfig, columns)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	defer func() {
+		se.DB.Target.Observe(ctx, fmt.Sprintf("MERGE %d rows INTO history %s", len(data), tableName), start, err)
+	}()
+
+	tx, err := se.beginLoad(tableConfig.GetDurability() == config.DurabilityAsyncCommit)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = sqlident.PostgresColumn(col.targetName())
+	}
+	columnList := strings.Join(names, ", ")
+	target := sqlident.Postgres(tableName)
+	stage := sqlident.Postgres(scd2Stage)
+
+	createStage := fmt.Sprintf("CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA", stage, columnList, target)
+	if _, err := tx.Exec(createStage); err != nil {
+		return fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	insertMode := tableConfig.GetInsertMode(se.Config.Defaults)
+	writer, err := newLoadWriter(tx, insertMode, scd2Stage, columns, "")
+	if err != nil {
+		return err
+	}
+	for n, row := range data {
+		if n%insertChunkSize == 0 {
+			if cause := cancelled(ctx); cause != nil {
+				writer.close()
+				return cause
+			}
+			if preempted(ctx) {
+				writer.close()
+				return ErrPreempted
+			}
+		}
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col.Name]
+		}
+		if err := writer.write(values); err != nil {
+			writer.close()
+			se.logInsertFailure(tableName, insertMode, values, err)
+			return err
+		}
+	}
+	err = writer.flush()
+	writer.close()
+	if err != nil {
+		se.logInsertFailure(tableName, insertMode, nil, err)
+		return err
+	}
+
+	keyMatch := make([]string, len(keys))
+	keyNames := make([]string, len(keys))
+	for i, key := range keys {
+		name := sqlident.PostgresColumn(key.targetName())
+		keyMatch[i] = fmt.Sprintf("s.%s = t.%s", name, name)
+		keyNames[i] = name
+	}
+	sameKey := strings.Join(keyMatch, " AND ")
+
+	// Two current versions of one key could not be told apart, so repeated keys fail the load
+	var repeated int
+	repeatedQuery := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s GROUP BY %s HAVING COUNT(*) > 1) r", stage, strings.Join(keyNames, ", "))
+	if err := tx.Get(&repeated, repeatedQuery); err != nil {
+		return err
+	}
+	if repeated > 0 {
+		return fmt.Errorf("%d keys repeat in the source read; add dedup to keep one row per key", repeated)
+	}
+
+	unchanged := sameKey
+	if len(compared) > 0 {
+		sourceValues := make([]string, len(compared))
+		targetValues := make([]string, len(compared))
+		for i, col := range compared {
+			name := sqlident.PostgresColumn(col.targetName())
+			sourceValues[i] = "s." + name
+			targetValues[i] = "t." + name
+		}
+		unchanged += fmt.Sprintf(" AND ROW(%s) IS NOT DISTINCT FROM ROW(%s)", strings.Join(sourceValues, ", "), strings.Join(targetValues, ", "))
+	}
+
+	validFrom := sqlident.PostgresColumn(tableConfig.SCD2.GetValidFrom())
+	validTo := sqlident.PostgresColumn(tableConfig.SCD2.GetValidTo())
+
+	// Versions are closed and opened at the transaction's start time, so a replaced version ends where the next begins
+	closeQuery := fmt.Sprintf("UPDATE %s t SET %s = now() WHERE t.%s IS NULL AND NOT EXISTS (SELECT 1 FROM %s s WHERE %s)",
+		target, validTo, validTo, stage, unchanged)
+	closed, err := tx.Exec(closeQuery)
+	if err != nil {
+		return fmt.Errorf("failed to close changed versions: %w", err)
+	}
+
+	sequences, err := se.sequenceColumns(tx, tableName)
+	if err != nil {
+		return fmt.Errorf("failed to read target sequences: %w", err)
+	}
+	overriding := ""
+	if hasIdentityColumn(sequences, columns) {
+		overriding = " OVERRIDING SYSTEM VALUE"
+	}
+	stageColumns := make([]string, len(names))
+	for i, name := range names {
+		stageColumns[i] = "s." + name
+	}
+	openQuery := fmt.Sprintf(`INSERT INTO %s (%s, %s, %s)%s
+		SELECT %s, now(), NULL FROM %s s
+		WHERE NOT EXISTS (SELECT 1 FROM %s t WHERE t.%s IS NULL AND %s)`,
+		target, columnList, validFrom, validTo, overriding,
+		strings.Join(stageColumns, ", "), stage,
+		target, validTo, sameKey)
+	opened, err := tx.Exec(openQuery)
+	if err != nil {
+		return fmt.Errorf("failed to insert new versions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	closedRows, _ := closed.RowsAffected()
+	openedRows, _ := opened.RowsAffected()
+	se.Logger.Info("History merged successfully",
+		zap.String("table", tableName),
+		zap.Int("rows", len(data)),
+		zap.Int64("versions_closed", closedRows),
+		zap.Int64("versions_inserted", openedRows),
+	)
+	return nil
+}
@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// targetSchema returns the schema of a target table name, defaulting to public
+func targetSchema(tableName string) string {
+	schema, _ := sqlident.SplitQualified(tableName, "public")
+	return schema
+}
+
+// ensureTargetSchema creates the target table's schema if it does not exist
+func (se *SyncEngine) ensureTargetSchema(tableName string) error {
+	schema := targetSchema(tableName)
+	query := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", sqlident.PostgresColumn(schema))
+	if _, err := se.DB.Target.Exec(query); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateTargetPermissions checks that every target schema exists (or can be created) and that
+// the connected user can use it and, when tables are auto-created, create tables in it
+func (se *SyncEngine) ValidateTargetPermissions() error {
+	return errors.Join(se.TargetPermissionProblems(se.Config)...)
+}
+
+// TargetPermissionProblems returns a problem for every target schema of a configuration that is missing and
+// cannot be created, or that the connected user cannot use or, when tables are auto-created, create tables in
+func (se *SyncEngine) TargetPermissionProblems(cfg *config.Config) []error {
+	checked := make(map[string]bool)
+	var problems []error
+
+	for _, tc := range cfg.Tables {
+		schema := targetSchema(tc.TargetTable)
+		if checked[schema] {
+			continue
+		}
+		checked[schema] = true
+
+		if err := se.validateSchemaPermissions(schema, cfg.Defaults); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	return problems
+}
+
+func (se *SyncEngine) validateSchemaPermissions(schema string, defaults config.DefaultConfig) error {
+	var exists bool
+	if err := se.DB.Target.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)", schema,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("schema %s: %w", schema, err)
+	}
+
+	if !exists {
+		if !defaults.CreateTargetTable {
+			return fmt.Errorf("schema %s does not exist and create_target_table is disabled", schema)
+		}
+		if !defaults.CreateSchema {
+			return fmt.Errorf("schema %s does not exist and create_target_schema is disabled", schema)
+		}
+
+		var canCreate bool
+		if err := se.DB.Target.QueryRow(
+			"SELECT has_database_privilege(current_database(), 'CREATE')",
+		).Scan(&canCreate); err != nil {
+			return fmt.Errorf("schema %s: %w", schema, err)
+		}
+		if !canCreate {
+			return fmt.Errorf("schema %s does not exist and the target user cannot create schemas", schema)
+		}
+		return nil
+	}
+
+	privileges := []string{"USAGE"}
+	if defaults.CreateTargetTable {
+		privileges = append(privileges, "CREATE")
+	}
+
+	for _, privilege := range privileges {
+		var allowed bool
+		if err := se.DB.Target.QueryRow(
+			"SELECT has_schema_privilege($1, $2)", schema, privilege,
+		).Scan(&allowed); err != nil {
+			return fmt.Errorf("schema %s: %w", schema, err)
+		}
+		if !allowed {
+			return fmt.Errorf("target user lacks %s privilege on schema %s", privilege, schema)
+		}
+	}
+	return nil
+}
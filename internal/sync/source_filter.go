@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// sourceWhere builds the WHERE clause of a table's source reads from its filter and structured where conditions,
+// empty when it has neither. Condition values are returned as @pN query parameters
+func sourceWhere(tableConfig config.TableConfig) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if tableConfig.Filter != "" {
+		conditions = append(conditions, "("+tableConfig.Filter+")")
+	}
+
+	for i := range tableConfig.Where {
+		condition := &tableConfig.Where[i]
+		column := sqlident.MSSQLColumn(condition.Column)
+		switch op := condition.GetOp(); op {
+		case config.FilterIsNull:
+			conditions = append(conditions, column+" IS NULL")
+		case config.FilterNotNull:
+			conditions = append(conditions, column+" IS NOT NULL")
+		case config.FilterIn, config.FilterNotIn:
+			var params []string
+			for _, value := range condition.GetValues() {
+				args = append(args, value)
+				params = append(params, fmt.Sprintf("@p%d", len(args)))
+			}
+			keyword := "IN"
+			if op == config.FilterNotIn {
+				keyword = "NOT IN"
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s (%s)", column, keyword, strings.Join(params, ", ")))
+		default:
+			args = append(args, condition.GetValues()[0])
+			conditions = append(conditions, fmt.Sprintf("%s %s @p%d", column, op, len(args)))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,90 @@
+package sync
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// sourceQueryAlias is the derived table alias used when selecting from a source query
+const sourceQueryAlias = "src"
+
+// getQueryColumns describes the result set of a source query using SQL Server's metadata DMV
+func (se *SyncEngine) getQueryColumns(sourceQuery string, requestedFields []string) ([]ColumnInfo, error) {
+	query := `
+		SELECT
+			name,
+			system_type_name,
+			max_length,
+			precision,
+			scale,
+			is_nullable
+		FROM sys.dm_exec_describe_first_result_set(@p1, NULL, 0)
+		WHERE is_hidden = 0
+		ORDER BY column_ordinal
+	`
+
+	rows, err := se.DB.SourceSchema.Queryx(query, sourceQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var name sql.NullString
+		var systemType string
+		var maxLength, precision, scale int
+		var nullable sql.NullBool
+
+		if err := rows.Scan(&name, &systemType, &maxLength, &precision, &scale, &nullable); err != nil {
+			return nil, err
+		}
+		if !name.Valid || name.String == "" {
+			return nil, fmt.Errorf("source query returns an unnamed column of type %s; add a column alias", systemType)
+		}
+
+		dataType := strings.ToLower(strings.TrimSpace(strings.SplitN(systemType, "(", 2)[0]))
+		length := maxLength
+		if dataType == "nchar" || dataType == "nvarchar" {
+			if length > 0 {
+				length /= 2
+			}
+		}
+
+		col := ColumnInfo{
+			Name:      name.String,
+			DataType:  dataType,
+			Length:    length,
+			Precision: precision,
+			Scale:     scale,
+			Nullable:  !nullable.Valid || nullable.Bool,
+		}
+
+		if len(requestedFields) > 0 && !matchAnyColumnPattern(requestedFields, col.Name) {
+			continue
+		}
+
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("source query returns no columns")
+	}
+
+	return columns, nil
+}
+
+// sourceRelation returns the FROM clause target for a table: the source table or the source query as a derived table
+func sourceRelation(sourceTable, sourceQuery string) string {
+	if sourceQuery == "" {
+		return sqlident.MSSQL(sourceTable)
+	}
+	// The newline ends a line comment closing the query, which would otherwise swallow the parenthesis
+	return fmt.Sprintf("(%s\n) AS %s", sourceQuery, sqlident.MSSQLColumn(sourceQueryAlias))
+}
@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// markSpatialColumns flags geography and geometry columns for PostGIS mapping, reporting whether any were found
+func markSpatialColumns(columns []ColumnInfo) bool {
+	found := false
+	for i := range columns {
+		switch strings.ToLower(columns[i].DataType) {
+		case "geography", "geometry":
+			columns[i].Spatial = true
+			found = true
+		}
+	}
+	return found
+}
+
+// postgisType returns the PostGIS column type for a spatial column
+func postgisType(col ColumnInfo) string {
+	if strings.EqualFold(col.DataType, "geography") {
+		return "GEOGRAPHY"
+	}
+	return "GEOMETRY"
+}
+
+// spatialSelectExpression reads a spatial column as EWKT (SRID=4326;POINT (...)), which PostGIS accepts as input
+func spatialSelectExpression(col ColumnInfo) string {
+	return fmt.Sprintf(
+		"CASE WHEN %[1]s IS NULL THEN NULL ELSE 'SRID=' + CAST(%[1]s.STSrid AS varchar(10)) + ';' + %[1]s.STAsText() END AS %[1]s",
+		sqlident.MSSQLColumn(col.Name),
+	)
+}
+
+// ensurePostGIS verifies the PostGIS extension is installed in the target database
+func (se *SyncEngine) ensurePostGIS() error {
+	var installed bool
+	if err := se.DB.Target.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')").Scan(&installed); err != nil {
+		return fmt.Errorf("failed to check PostGIS extension: %w", err)
+	}
+	if !installed {
+		return fmt.Errorf("postgis is enabled but the PostGIS extension is not installed in the target database")
+	}
+	return nil
+}
@@ -3,105 +3,324 @@ package sync
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"mssql-postgres-sync/internal/config"
 	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/events"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/sqlident"
 )
 
 // SyncEngine handles the synchronization logic
 type SyncEngine struct {
-	DB     *database.DatabaseManager
-	Config *config.Config
-	Logger *zap.Logger
+	DB      *database.DatabaseManager
+	Config  *config.Config
+	Logger  *zap.Logger
+	History *history.Store
+	Events  *events.Publisher
 }
 
 // NewSyncEngine creates a new sync engine
-func NewSyncEngine(db *database.DatabaseManager, cfg *config.Config, logger *zap.Logger) *SyncEngine {
+func NewSyncEngine(db *database.DatabaseManager, cfg *config.Config, logger *zap.Logger, historyStore *history.Store, publisher *events.Publisher) *SyncEngine {
 	return &SyncEngine{
-		DB:     db,
-		Config: cfg,
-		Logger: logger,
+		DB:      db,
+		Config:  cfg,
+		Logger:  logger,
+		History: historyStore,
+		Events:  publisher,
 	}
 }
 
+// ErrCircuitOpen is returned when a sync is skipped because a database circuit breaker is open
+var ErrCircuitOpen = errors.New("database circuit breaker is open")
+
+// SyncResult describes the outcome of a single table sync
+type SyncResult struct {
+	BatchID         string
+	TableName       string
+	StartedAt       time.Time
+	Duration        time.Duration
+	RowsSynced      int
+	RowsRead        int   // rows fetched from the source
+	RowsWritten     int   // rows written to the target
+	RowsSkipped     int   // rows read but not written
+	BytesRead       int64 // approximate size of the fetched values
+	RowsEstimated   int64 // source rows estimated by the preflight check, 0 when not estimated
+	SourceChangedAt *time.Time
+	Targets         []TargetResult    // loads into the table's fan-out targets, in configured order
+	Validation      *ValidationReport // nil when the table has no validation rules
+}
+
 // SyncTable synchronizes a single table from source to target
-func (se *SyncEngine) SyncTable(ctx context.Context, tableConfig config.TableConfig) error {
-	startTime := time.Now()
+func (se *SyncEngine) SyncTable(ctx context.Context, tableConfig config.TableConfig) (*SyncResult, error) {
+	result := &SyncResult{
+		BatchID:   NewBatchID(),
+		TableName: tableConfig.TargetTable,
+		StartedAt: time.Now(),
+	}
+
+	if !se.DB.Available() {
+		return result, ErrCircuitOpen
+	}
+
+	err := se.runSyncRecovered(ctx, tableConfig, result)
+	result.Duration = time.Since(result.StartedAt)
+
+	se.recordHistory(result, err)
+
+	return result, err
+}
+
+// runSync performs the sync steps for a table, filling in the result as it goes
+func (se *SyncEngine) runSync(ctx context.Context, tableConfig config.TableConfig, result *SyncResult) error {
 	logger := se.Logger.With(
 		zap.String("source_table", tableConfig.SourceTable),
 		zap.String("target_table", tableConfig.TargetTable),
+		zap.String("batch_id", result.BatchID),
 	)
 
 	logger.Info("Starting table sync")
+	ctx = database.WithQueryLabel(ctx, "table:"+tableConfig.TargetTable)
+
+	tableConfig, err := se.expandVariables(tableConfig, result.BatchID)
+	if err != nil {
+		return err
+	}
+
+	if err := se.preflight(ctx, tableConfig, result); err != nil {
+		return err
+	}
 
-	// Step 1: Get source table schema
-	columns, err := se.getSourceColumns(tableConfig.SourceTable, tableConfig.Fields)
+	// Step 1: Get source table (or source query) schema
+	columns, err := se.sourceColumns(tableConfig)
 	if err != nil {
+		se.DB.SourceBreaker.RecordFailure(err)
 		return fmt.Errorf("failed to get source columns: %w", err)
 	}
 
+	markJSONColumns(tableConfig, columns)
+	citext := markCollations(tableConfig, columns)
+	if err := markLargeObjects(tableConfig, columns); err != nil {
+		return err
+	}
+
+	spatial := tableConfig.GetPostGIS(se.Config.Defaults) && markSpatialColumns(columns)
+
 	logger.Info("Retrieved source columns", zap.Int("count", len(columns)))
 
-	// Step 2: Create target table if it doesn't exist
-	if se.Config.Defaults.CreateTargetTable {
-		if err := se.createTargetTable(tableConfig.TargetTable, columns); err != nil {
-			return fmt.Errorf("failed to create target table: %w", err)
+	lineage := tableConfig.GetLineageColumns(se.Config.Defaults)
+	targetColumns := columns
+	if lineage {
+		targetColumns = append(append([]ColumnInfo{}, columns...), lineageColumns()...)
+	}
+
+	// Blue/green tables are loaded into the generation their view does not read, then the view is switched to it
+	loadConfig := tableConfig
+	var liveGeneration string
+	if tableConfig.BlueGreen {
+		if liveGeneration, err = se.liveGeneration(ctx, tableConfig.TargetTable); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to resolve blue/green generation: %w", err)
 		}
+		loadConfig.TargetTable = otherGeneration(tableConfig.TargetTable, liveGeneration)
+		logger.Info("Loading blue/green generation", zap.String("generation", loadConfig.TargetTable))
+	}
+
+	// Step 2: Create target table if it doesn't exist
+	if err := se.prepareTarget(loadConfig, targetColumns, citext, spatial, lineage); err != nil {
+		return err
+	}
+
+	// Chunked tables load their source window by window instead of in one read and transaction
+	if tableConfig.Chunking != nil {
+		return se.loadChunks(ctx, tableConfig, columns, targetColumns, lineage, result)
 	}
 
 	// Step 3: Fetch data from source
-	data, err := se.fetchSourceData(tableConfig, columns)
+	data, err := se.fetchSourceData(ctx, tableConfig, columns)
 	if err != nil {
+		if cause := cancelled(ctx); cause != nil {
+			return cause
+		}
+		se.DB.SourceBreaker.RecordFailure(err)
 		return fmt.Errorf("failed to fetch source data: %w", err)
 	}
+	se.DB.SourceBreaker.RecordSuccess()
+	result.RowsRead = len(data)
+	result.BytesRead = estimateBytes(data)
 
-	logger.Info("Fetched source data", zap.Int("rows", len(data)))
+	logger.Info("Fetched source data",
+		zap.Int("rows", len(data)),
+		zap.Int64("bytes", result.BytesRead),
+	)
+
+	if err := applyColumnPolicies(tableConfig, columns, data); err != nil {
+		return fmt.Errorf("failed to apply column policies: %w", err)
+	}
+
+	data, result.Validation, err = se.validateRows(tableConfig, columns, data, result.BatchID)
+	if err != nil {
+		return err
+	}
+
+	if data, err = se.dedupRows(tableConfig, columns, data); err != nil {
+		return err
+	}
+
+	if tableConfig.ChangeColumn != "" {
+		result.SourceChangedAt = latestChange(data, tableConfig.ChangeColumn)
+	}
+
+	if lineage {
+		stampLineage(data, result.BatchID, se.Config.Source.Database, time.Now())
+	}
 
 	// Step 4: Sync data to target (truncate and insert for full sync)
-	if err := se.syncToTarget(tableConfig.TargetTable, columns, data); err != nil {
+	restored, err := se.syncToTarget(ctx, loadConfig, targetColumns, data, nil)
+
+	// Fan-out targets load the same rows whether or not the primary target took them
+	if len(tableConfig.Targets) > 0 && !errors.Is(err, ErrPreempted) && cancelled(ctx) == nil {
+		se.syncTargets(ctx, tableConfig, targetColumns, citext, spatial, lineage, data, result)
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrPreempted) || cancelled(ctx) != nil {
+			return err
+		}
+		se.DB.TargetBreaker.RecordFailure(err)
 		return fmt.Errorf("failed to sync to target: %w", err)
 	}
+	se.DB.TargetBreaker.RecordSuccess()
+
+	if len(restored) > 0 {
+		if result.Validation == nil {
+			result.Validation = &ValidationReport{Rules: []RuleReport{}}
+		}
+		result.Validation.Constraints = se.validateForeignKeys(ctx, loadConfig.TargetTable, restored)
+	}
+
+	// An empty read leaves the generation untouched, so the view keeps reading the live one unless there is none yet
+	if tableConfig.BlueGreen && (len(data) > 0 || liveGeneration == "") {
+		if err := se.switchGeneration(ctx, tableConfig.TargetTable, loadConfig.TargetTable); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to switch blue/green generation: %w", err)
+		}
+	}
+
+	result.RowsSynced = len(data)
+	result.RowsWritten = len(data)
+	result.RowsSkipped = result.RowsRead - result.RowsWritten
+
+	// An empty read leaves the table as it was, which the latest snapshot already holds
+	if len(data) > 0 {
+		se.snapshotProjections(ctx, tableConfig, time.Now())
+	}
+
+	se.publishEvents(ctx, tableConfig, result, data)
 
-	duration := time.Since(startTime)
 	logger.Info("Table sync completed",
-		zap.Duration("duration", duration),
-		zap.Int("rows_synced", len(data)),
+		zap.Duration("duration", time.Since(result.StartedAt)),
+		zap.Int("rows_read", result.RowsRead),
+		zap.Int("rows_written", result.RowsWritten),
+		zap.Int("rows_skipped", result.RowsSkipped),
+		zap.Int64("bytes_read", result.BytesRead),
 	)
 
 	return nil
 }
 
+// prepareTarget creates the target table, with its schema and required extensions, when the sync creates tables,
+// and brings an existing table's durability, lineage, scd2 and computed columns in line with the table's settings
+func (se *SyncEngine) prepareTarget(tableConfig config.TableConfig, targetColumns []ColumnInfo, citext, spatial, lineage bool) error {
+	if spatial {
+		if err := se.ensurePostGIS(); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return err
+		}
+	}
+
+	durability := tableConfig.GetDurability()
+	if se.Config.Defaults.CreateTargetTable {
+		if citext {
+			if err := se.ensureCitext(); err != nil {
+				se.DB.TargetBreaker.RecordFailure(err)
+				return err
+			}
+		}
+		if se.Config.Defaults.CreateSchema {
+			if err := se.ensureTargetSchema(tableConfig.TargetTable); err != nil {
+				se.DB.TargetBreaker.RecordFailure(err)
+				return fmt.Errorf("failed to create target schema: %w", err)
+			}
+		}
+		if err := se.createTargetTable(tableConfig.TargetTable, targetColumns, durability == config.DurabilityUnlogged, tableConfig.Partitioning, tableConfig.GetPreserveIdentity(se.Config.Defaults)); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to create target table: %w", err)
+		}
+	}
+
+	if err := se.ensureDurability(tableConfig.TargetTable, durability); err != nil {
+		se.DB.TargetBreaker.RecordFailure(err)
+		return fmt.Errorf("failed to set target table durability: %w", err)
+	}
+
+	if lineage {
+		if err := se.ensureLineageColumns(tableConfig.TargetTable); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to add lineage columns: %w", err)
+		}
+	}
+
+	if tableConfig.IsSCD2() {
+		if err := se.ensureSCD2Columns(tableConfig, targetColumns); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to add scd2 columns: %w", err)
+		}
+	}
+
+	if len(tableConfig.Computed) > 0 {
+		if err := se.ensureComputedColumns(tableConfig.TargetTable, tableConfig.Computed); err != nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+			return fmt.Errorf("failed to add computed columns: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // getSourceColumns retrieves column information from source table
 func (se *SyncEngine) getSourceColumns(tableName string, requestedFields []string) ([]ColumnInfo, error) {
-	parts := strings.Split(tableName, ".")
-	var schema, table string
-	if len(parts) == 2 {
-		schema = parts[0]
-		table = parts[1]
-	} else {
-		schema = "dbo"
-		table = tableName
-	}
+	schema, table := sqlident.SplitQualified(tableName, "dbo")
 
 	query := `
 		SELECT 
-			COLUMN_NAME,
-			DATA_TYPE,
-			CHARACTER_MAXIMUM_LENGTH,
-			NUMERIC_PRECISION,
-			NUMERIC_SCALE,
-			IS_NULLABLE
-		FROM INFORMATION_SCHEMA.COLUMNS
-		WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2
-		ORDER BY ORDINAL_POSITION
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.NUMERIC_PRECISION,
+			c.NUMERIC_SCALE,
+			c.IS_NULLABLE,
+			CAST(ep.value AS NVARCHAR(4000)) AS DESCRIPTION,
+			COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'IsIdentity') AS IS_IDENTITY
+		FROM INFORMATION_SCHEMA.COLUMNS c
+		LEFT JOIN sys.extended_properties ep
+			ON ep.class = 1
+			AND ep.name = 'MS_Description'
+			AND ep.major_id = OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME))
+			AND ep.minor_id = COLUMNPROPERTY(ep.major_id, c.COLUMN_NAME, 'ColumnId')
+		WHERE c.TABLE_SCHEMA = @p1 AND c.TABLE_NAME = @p2
+		ORDER BY c.ORDINAL_POSITION
 	`
 
-	rows, err := se.DB.Source.Queryx(query, schema, table)
+	rows, err := se.DB.SourceSchema.Queryx(query, schema, table)
 	if err != nil {
 		return nil, err
 	}
@@ -112,8 +331,10 @@ func (se *SyncEngine) getSourceColumns(tableName string, requestedFields []strin
 		var col ColumnInfo
 		var charLen, numPrec, numScale sql.NullInt64
 		var isNullable string
+		var description sql.NullString
+		var isIdentity sql.NullInt64
 
-		err := rows.Scan(&col.Name, &col.DataType, &charLen, &numPrec, &numScale, &isNullable)
+		err := rows.Scan(&col.Name, &col.DataType, &charLen, &numPrec, &numScale, &isNullable, &description, &isIdentity)
 		if err != nil {
 			return nil, err
 		}
@@ -122,19 +343,12 @@ func (se *SyncEngine) getSourceColumns(tableName string, requestedFields []strin
 		col.Precision = int(numPrec.Int64)
 		col.Scale = int(numScale.Int64)
 		col.Nullable = (isNullable == "YES")
+		col.Description = description.String
+		col.Identity = isIdentity.Int64 == 1
 
-		// Filter by requested fields if specified
-		if len(requestedFields) > 0 {
-			found := false
-			for _, field := range requestedFields {
-				if strings.EqualFold(field, col.Name) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		// Filter by requested fields if specified, which may be patterns
+		if len(requestedFields) > 0 && !matchAnyColumnPattern(requestedFields, col.Name) {
+			continue
 		}
 
 		columns = append(columns, col)
@@ -144,17 +358,9 @@ func (se *SyncEngine) getSourceColumns(tableName string, requestedFields []strin
 }
 
 // createTargetTable creates the target table if it doesn't exist
-func (se *SyncEngine) createTargetTable(tableName string, columns []ColumnInfo) error {
+func (se *SyncEngine) createTargetTable(tableName string, columns []ColumnInfo, unlogged bool, partitioning *config.Partitioning, preserveIdentity bool) error {
 	// Check if table exists
-	parts := strings.Split(tableName, ".")
-	var schema, table string
-	if len(parts) == 2 {
-		schema = parts[0]
-		table = parts[1]
-	} else {
-		schema = "public"
-		table = tableName
-	}
+	schema, table := sqlident.SplitQualified(tableName, "public")
 
 	checkQuery := `
 		SELECT EXISTS (
@@ -182,10 +388,22 @@ func (se *SyncEngine) createTargetTable(tableName string, columns []ColumnInfo)
 		if !col.Nullable {
 			nullable = " NOT NULL"
 		}
-		colDefs = append(colDefs, fmt.Sprintf("\"%s\" %s%s", col.Name, pgType, nullable))
+		// Partitioned tables only support identity columns from PostgreSQL 17, so they keep plain columns
+		if preserveIdentity && col.Identity && partitioning == nil && identityType(pgType) {
+			nullable = " GENERATED BY DEFAULT AS IDENTITY"
+		}
+		colDefs = append(colDefs, fmt.Sprintf("%s %s%s%s", sqlident.PostgresColumn(col.targetName()), pgType, collateClause(col), nullable))
 	}
 
-	createQuery := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", tableName, strings.Join(colDefs, ",\n  "))
+	createStatement := "CREATE TABLE"
+	if unlogged {
+		createStatement = "CREATE UNLOGGED TABLE"
+	}
+	createQuery := fmt.Sprintf("%s %s (\n  %s\n)", createStatement, sqlident.Postgres(tableName), strings.Join(colDefs, ",\n  "))
+	if partitioning != nil {
+		// Partitions are created on demand as rows are synced
+		createQuery += " " + partitionClause(partitioning, columns)
+	}
 
 	se.Logger.Info("Creating target table", zap.String("query", createQuery))
 
@@ -194,109 +412,241 @@ func (se *SyncEngine) createTargetTable(tableName string, columns []ColumnInfo)
 		return err
 	}
 
+	if err := se.commentColumns(tableName, columns); err != nil {
+		return err
+	}
+
 	se.Logger.Info("Target table created successfully", zap.String("table", tableName))
 	return nil
 }
 
 // fetchSourceData retrieves data from source table
-func (se *SyncEngine) fetchSourceData(tableConfig config.TableConfig, columns []ColumnInfo) ([]map[string]interface{}, error) {
+func (se *SyncEngine) fetchSourceData(ctx context.Context, tableConfig config.TableConfig, columns []ColumnInfo) ([]map[string]interface{}, error) {
 	// Build column list
 	var columnNames []string
 	for _, col := range columns {
-		columnNames = append(columnNames, fmt.Sprintf("[%s]", col.Name))
+		if col.Spatial {
+			columnNames = append(columnNames, spatialSelectExpression(col))
+			continue
+		}
+		if col.lob != nil {
+			columnNames = append(columnNames, largeObjectSelectExpression(col))
+			continue
+		}
+		columnNames = append(columnNames, sqlident.MSSQLColumn(col.Name))
 	}
 
 	// Build query
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columnNames, ", "), tableConfig.SourceTable)
+	source := sourceRelation(tableConfig.SourceTable, tableConfig.SourceQueryStatement())
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columnNames, ", "), source)
 	
 	// Add filter if specified
-	if tableConfig.Filter != "" {
-		query += fmt.Sprintf(" WHERE %s", tableConfig.Filter)
-	}
+	where, args := sourceWhere(tableConfig)
+	query += where
 
-	se.Logger.Info("Fetching source data", zap.String("query", query))
+	se.Logger.Info("Fetching source data", zap.String("query", query), zap.Any("args", args))
 
-	rows, err := se.DB.Source.Queryx(query)
+	rows, err := se.DB.Source.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	// Reads are paced as rows arrive, so a throttled table holds the source query open for longer at a lower rate
+	limiter := newReadLimiter(tableConfig, se.Config.Defaults)
+
 	var results []map[string]interface{}
 	for rows.Next() {
 		row := make(map[string]interface{})
 		if err := rows.MapScan(row); err != nil {
 			return nil, err
 		}
+		if err := decryptRow(columns, row); err != nil {
+			return nil, err
+		}
+		if err := storeLargeObjects(ctx, columns, row); err != nil {
+			return nil, err
+		}
 		results = append(results, row)
+		if err := limiter.read(ctx, row); err != nil {
+			return nil, err
+		}
 	}
 
+	if throttled := limiter.throttled(); throttled > 0 {
+		se.Logger.Info("Source read throttled",
+			zap.String("table", tableConfig.TargetTable),
+			zap.Duration("throttled", throttled),
+		)
+	}
 	return results, nil
 }
 
-// syncToTarget synchronizes data to target table
-func (se *SyncEngine) syncToTarget(tableName string, columns []ColumnInfo, data []map[string]interface{}) error {
-	if len(data) == 0 {
+// syncToTarget synchronizes data to target table, timing the whole write as one query. The load runs in a single
+// transaction unless the table commits every N rows. With a window, only the window's rows are replaced instead of
+// truncating the table. It returns the foreign keys restored as NOT VALID after the load
+func (se *SyncEngine) syncToTarget(ctx context.Context, tableConfig config.TableConfig, columns []ColumnInfo, data []map[string]interface{}, window *loadWindow) (restored []foreignKey, err error) {
+	tableName := tableConfig.TargetTable
+	if len(data) == 0 && window == nil {
 		se.Logger.Info("No data to sync", zap.String("table", tableName))
-		return nil
+		return nil, nil
+	}
+
+	// scd2 tables keep their previous versions, so the read is merged into the history instead of replacing rows
+	if tableConfig.IsSCD2() {
+		return nil, se.loadHistory(ctx, tableConfig, columns, data)
 	}
 
+	start := time.Now()
+	defer func() {
+		replaced := "TRUNCATE"
+		if window != nil {
+			replaced = "DELETE window"
+		}
+		se.DB.Target.Observe(ctx, fmt.Sprintf("%s + INSERT %d rows INTO %s", replaced, len(data), tableName), start, err)
+	}()
+
+	asyncCommit := tableConfig.GetDurability() == config.DurabilityAsyncCommit
+	commitEvery := tableConfig.GetCommitEvery(se.Config.Defaults)
+
 	// Start transaction
-	tx, err := se.DB.Target.Beginx()
+	tx, err := se.beginLoad(asyncCommit)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer func() {
+		if tx != nil {
+			tx.Rollback()
+		}
+	}()
 
-	// Truncate target table
-	truncateQuery := fmt.Sprintf("TRUNCATE TABLE %s", tableName)
-	se.Logger.Info("Truncating target table", zap.String("table", tableName))
-	
-	if _, err := tx.Exec(truncateQuery); err != nil {
-		return err
+	// PostgreSQL routes inserted rows to their partition, so only missing partitions need creating
+	var partitions []string
+	if tableConfig.Partitioning != nil {
+		if partitions, err = se.ensurePartitions(tx, tableName, tableConfig.Partitioning, columns, data); err != nil {
+			return nil, err
+		}
 	}
 
-	// Build INSERT statement
-	var columnNames []string
-	for _, col := range columns {
-		columnNames = append(columnNames, fmt.Sprintf("\"%s\"", col.Name))
+	// Foreign keys on and referencing the table are dropped for the load, so it needs no ordering, and restored before commit
+	var keys []foreignKey
+	if tableConfig.GetConstraints() == config.ConstraintsDisable {
+		if keys, err = se.foreignKeys(tx, tableName); err != nil {
+			return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+		}
+		if err := se.dropForeignKeys(tx, keys); err != nil {
+			return nil, err
+		}
 	}
 
-	placeholders := make([]string, len(columnNames))
-	for i := range placeholders {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	// Truncate target table, or only the partitions being loaded when it keeps backfilled ranges
+	partitionsOnly := tableConfig.Backfill != nil
+	truncated := sqlident.Postgres(tableName)
+	if partitionsOnly {
+		truncated = strings.Join(partitions, ", ")
+	}
+	truncateQuery := fmt.Sprintf("TRUNCATE TABLE %s", truncated)
+	if window != nil {
+		// Rows of a window loaded before an interruption are replaced, so resuming it does not duplicate them
+		truncateQuery = fmt.Sprintf("DELETE FROM %s WHERE %s", sqlident.Postgres(tableName), window.targetCondition())
+		se.Logger.Debug("Replacing target window", zap.String("table", tableName), zap.String("condition", window.targetCondition()))
+	} else {
+		se.Logger.Info("Truncating target table", zap.String("table", tableName), zap.Bool("partitions_only", partitionsOnly))
 	}
 
-	insertQuery := fmt.Sprintf(
-		"INSERT INTO %s (%s) VALUES (%s)",
-		tableName,
-		strings.Join(columnNames, ", "),
-		strings.Join(placeholders, ", "),
-	)
+	if _, err := tx.Exec(truncateQuery); err != nil {
+		return nil, err
+	}
 
-	// Prepare statement
-	stmt, err := tx.Preparex(insertQuery)
+	// Source values are kept for identity columns, including GENERATED ALWAYS ones
+	sequences, err := se.sequenceColumns(tx, tableName)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read target sequences: %w", err)
+	}
+	overriding := ""
+	if hasIdentityColumn(sequences, columns) {
+		overriding = " OVERRIDING SYSTEM VALUE"
+	}
+
+	// Prepare the INSERT, or start the COPY
+	insertMode := tableConfig.GetInsertMode(se.Config.Defaults)
+	writer, err := newLoadWriter(tx, insertMode, tableName, columns, overriding)
+	if err != nil {
+		return nil, err
 	}
-	defer stmt.Close()
+	defer func() {
+		if writer != nil {
+			writer.close()
+		}
+	}()
+
+	// Insert data in chunks, stopping between them when the sync is cancelled or yields to a higher priority sync
+	for n, row := range data {
+		if n%insertChunkSize == 0 {
+			if cause := cancelled(ctx); cause != nil {
+				return nil, cause
+			}
+			if preempted(ctx) {
+				se.Logger.Info("Load preempted",
+					zap.String("table", tableName),
+					zap.Int("rows_inserted", n),
+				)
+				return nil, ErrPreempted
+			}
+		}
+
+		// Batched commits keep WAL and locks bounded, but readers see the table partially loaded until the last one
+		if commitEvery > 0 && n > 0 && n%commitEvery == 0 {
+			if err := writer.flush(); err != nil {
+				return nil, err
+			}
+			writer.close()
+			writer = nil
+			if err := tx.Commit(); err != nil {
+				return nil, err
+			}
+			if tx, err = se.beginLoad(asyncCommit); err != nil {
+				return nil, err
+			}
+			if writer, err = newLoadWriter(tx, insertMode, tableName, columns, overriding); err != nil {
+				return nil, err
+			}
+			se.Logger.Debug("Committed load batch",
+				zap.String("table", tableName),
+				zap.Int("rows_committed", n),
+			)
+		}
 
-	// Insert data
-	for _, row := range data {
 		values := make([]interface{}, len(columns))
 		for i, col := range columns {
 			values[i] = row[col.Name]
 		}
 
-		if _, err := stmt.Exec(values...); err != nil {
-			se.Logger.Error("Failed to insert row", zap.Error(err), zap.Any("values", values))
-			return err
+		if err := writer.write(values); err != nil {
+			se.logInsertFailure(tableName, insertMode, values, err)
+			return nil, err
 		}
 	}
 
+	if err := writer.flush(); err != nil {
+		se.logInsertFailure(tableName, insertMode, nil, err)
+		return nil, err
+	}
+	writer.close()
+	writer = nil
+
+	if err := se.restoreForeignKeys(tx, keys); err != nil {
+		return nil, err
+	}
+
+	// Move sequences past the loaded values so inserts made directly on the target don't collide
+	if err := se.reseed(tx, tableName, sequences); err != nil {
+		return nil, fmt.Errorf("failed to reseed target sequences: %w", err)
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		return err
+		return nil, err
 	}
 
 	se.Logger.Info("Data synced successfully", 
@@ -304,21 +654,75 @@ func (se *SyncEngine) syncToTarget(tableName string, columns []ColumnInfo, data
 		zap.Int("rows", len(data)),
 	)
 
-	return nil
+	return keys, nil
+}
+
+// beginLoad starts a target load transaction
+func (se *SyncEngine) beginLoad(asyncCommit bool) (*sqlx.Tx, error) {
+	tx, err := se.DB.Target.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	// Trade durability of this load for throughput: the commit returns before the WAL is flushed
+	if asyncCommit {
+		if _, err := tx.Exec("SET LOCAL synchronous_commit = off"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	return tx, nil
 }
 
 // ColumnInfo represents database column information
 type ColumnInfo struct {
-	Name      string
-	DataType  string
-	Length    int
-	Precision int
-	Scale     int
-	Nullable  bool
+	Name        string
+	DataType    string
+	Length      int
+	Precision   int
+	Scale       int
+	Nullable    bool
+	JSON        bool   // stored as jsonb in the target
+	Spatial     bool   // geography/geometry stored as a PostGIS type
+	Description string // MS_Description extended property, copied to the column comment
+	Identity    bool   // IDENTITY column of the source table
+	Collation   string // citext or the collation of a created string column
+	Encrypted   string // Always Encrypted column synced as ciphertext or decrypted
+	Target      string // target column name, when it differs from the source name
+	TargetType  string // target column type of a metadata column, used instead of mapping DataType
+	cellKey     *cellKey
+	lob         *largeObject
+}
+
+// targetName returns the name of the column on the target table
+func (col ColumnInfo) targetName() string {
+	if col.Target != "" {
+		return col.Target
+	}
+	return col.Name
 }
 
 // mapMSSQLToPostgreSQL maps MSSQL data types to PostgreSQL
 func mapMSSQLToPostgreSQL(col ColumnInfo) string {
+	if col.TargetType != "" {
+		return col.TargetType
+	}
+	if col.JSON {
+		return "JSONB"
+	}
+	if col.Encrypted == config.EncryptedCiphertext {
+		return "BYTEA"
+	}
+	if strings.EqualFold(col.Collation, config.CollationCitext) {
+		return "CITEXT"
+	}
+	if col.Spatial {
+		return postgisType(col)
+	}
+	if col.lob != nil && col.lob.sink != nil {
+		return "TEXT"
+	}
+
 	switch strings.ToLower(col.DataType) {
 	case "int":
 		return "INTEGER"
@@ -379,3 +783,18 @@ func mapMSSQLToPostgreSQL(col ColumnInfo) string {
 		return "TEXT"
 	}
 }
+
+// commentColumns copies source column descriptions onto the target column comments
+func (se *SyncEngine) commentColumns(tableName string, columns []ColumnInfo) error {
+	for _, col := range columns {
+		if col.Description == "" {
+			continue
+		}
+		query := fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s",
+			sqlident.Postgres(tableName), sqlident.PostgresColumn(col.targetName()), pq.QuoteLiteral(col.Description))
+		if _, err := se.DB.Target.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
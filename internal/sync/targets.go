@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/database"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// TargetResult describes the load of a sync's rows into one fan-out target
+type TargetResult struct {
+	Target      string
+	StartedAt   time.Time
+	Duration    time.Duration
+	RowsWritten int
+	Error       string // empty when the load succeeded
+}
+
+// syncTargets loads the rows of a sync into the table's fan-out targets one after another. Each target is prepared
+// and loaded on its own, so a failing target neither stops the others nor fails the sync; every outcome is added to
+// the result, the history and the sync_target_runs_total metric
+func (se *SyncEngine) syncTargets(ctx context.Context, tableConfig config.TableConfig, targetColumns []ColumnInfo, citext, spatial, lineage bool, data []map[string]interface{}, result *SyncResult) {
+	for _, name := range tableConfig.Targets {
+		if cancelled(ctx) != nil {
+			return
+		}
+
+		targetResult := TargetResult{Target: name, StartedAt: time.Now()}
+		var err error
+		target, ok := se.DB.Targets[name]
+		switch {
+		case !ok:
+			err = fmt.Errorf("target %s is not configured", name)
+		case !target.Breaker.Allow():
+			err = ErrCircuitOpen
+		default:
+			err = se.forTarget(target).loadTarget(ctx, tableConfig, targetColumns, citext, spatial, lineage, data)
+		}
+		targetResult.Duration = time.Since(targetResult.StartedAt)
+
+		status := "success"
+		if err != nil {
+			status = "failure"
+			targetResult.Error = err.Error()
+			se.Logger.Warn("Failed to load fan-out target",
+				zap.String("table", tableConfig.TargetTable),
+				zap.String("target", name),
+				zap.String("batch_id", result.BatchID),
+				zap.Error(err),
+			)
+		} else {
+			targetResult.RowsWritten = len(data)
+			se.Logger.Info("Loaded fan-out target",
+				zap.String("table", tableConfig.TargetTable),
+				zap.String("target", name),
+				zap.String("batch_id", result.BatchID),
+				zap.Int("rows_written", len(data)),
+				zap.Duration("duration", targetResult.Duration),
+			)
+		}
+
+		metrics.SyncTargetRunsTotal.WithLabelValues(tableConfig.TargetTable, name, status).Inc()
+		result.Targets = append(result.Targets, targetResult)
+		se.recordTargetHistory(result, targetResult)
+	}
+}
+
+// forTarget returns an engine that reads the same source but writes to a fan-out target instead of the primary one
+func (se *SyncEngine) forTarget(target *database.FanoutTarget) *SyncEngine {
+	db := &database.DatabaseManager{
+		Source:        se.DB.Source,
+		SourceSchema:  se.DB.SourceSchema,
+		Target:        target.DB,
+		Logger:        se.DB.Logger,
+		SourceBreaker: se.DB.SourceBreaker,
+		TargetBreaker: target.Breaker,
+	}
+	return &SyncEngine{
+		DB:      db,
+		Config:  se.Config,
+		Logger:  se.Logger.With(zap.String("target", target.Name)),
+		History: se.History,
+		Events:  se.Events,
+	}
+}
+
+// loadTarget prepares the target table and replaces its rows, as steps 2 and 4 of a sync do on the primary target
+func (se *SyncEngine) loadTarget(ctx context.Context, tableConfig config.TableConfig, targetColumns []ColumnInfo, citext, spatial, lineage bool, data []map[string]interface{}) error {
+	if err := se.prepareTarget(tableConfig, targetColumns, citext, spatial, lineage); err != nil {
+		return err
+	}
+
+	restored, err := se.syncToTarget(ctx, tableConfig, targetColumns, data, nil)
+	if err != nil {
+		if !errors.Is(err, ErrPreempted) && cancelled(ctx) == nil {
+			se.DB.TargetBreaker.RecordFailure(err)
+		}
+		return fmt.Errorf("failed to sync to target: %w", err)
+	}
+	se.DB.TargetBreaker.RecordSuccess()
+
+	for _, report := range se.validateForeignKeys(ctx, tableConfig.TargetTable, restored) {
+		if !report.Valid {
+			se.Logger.Warn("Foreign key left NOT VALID on fan-out target",
+				zap.String("table", tableConfig.TargetTable),
+				zap.String("constraint", report.Name),
+				zap.Int64("violations", report.Violations),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sync
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ExpandTenants expands the tenant table templates in the configuration, resolving tenant queries on the source
+func (se *SyncEngine) ExpandTenants() error {
+	before := len(se.Config.Tables)
+	if err := se.Config.ExpandTenants(se.TenantIDs); err != nil {
+		return err
+	}
+
+	if len(se.Config.Tables) != before {
+		se.Logger.Info("Expanded tenant tables", zap.Int("tables", len(se.Config.Tables)))
+	}
+	return nil
+}
+
+// TenantIDs runs a tenant query on the source and returns the first column of every row
+func (se *SyncEngine) TenantIDs(query string) ([]string, error) {
+	rows, err := se.DB.Source.Queryx(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 || values[0] == nil {
+			continue
+		}
+		if b, ok := values[0].([]byte); ok {
+			tenants = append(tenants, string(b))
+		} else {
+			tenants = append(tenants, fmt.Sprint(values[0]))
+		}
+	}
+	return tenants, rows.Err()
+}
@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/metrics"
+)
+
+// throttleCheckRows is the number of rows read between read throttle checks
+const throttleCheckRows = 100
+
+// readLimiter paces a source read to a table's read throttle, re-evaluating the limits as peak windows start and end
+type readLimiter struct {
+	throttle    *config.ReadThrottle
+	table       string
+	rowsPerSec  float64
+	bytesPerSec float64
+	start       time.Time // start of the current pacing period; reset when the limits change
+	rows        int64
+	bytes       int64
+	waited      time.Duration
+}
+
+// newReadLimiter returns a limiter for the table's read throttle, nil when its reads are not throttled
+func newReadLimiter(tableConfig config.TableConfig, defaults config.DefaultConfig) *readLimiter {
+	throttle := tableConfig.GetReadThrottle(defaults)
+	if throttle == nil {
+		return nil
+	}
+	l := &readLimiter{throttle: throttle, table: tableConfig.TargetTable, start: time.Now()}
+	l.rowsPerSec, l.bytesPerSec = throttle.Limits(l.start)
+	return l
+}
+
+// read accounts for a fetched row and, every few rows, sleeps as long as needed to stay within the limits
+func (l *readLimiter) read(ctx context.Context, row map[string]interface{}) error {
+	if l == nil {
+		return nil
+	}
+	l.rows++
+	for _, value := range row {
+		l.bytes += valueSize(value)
+	}
+	if l.rows%throttleCheckRows != 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rowsPerSec, bytesPerSec := l.throttle.Limits(now)
+	if rowsPerSec != l.rowsPerSec || bytesPerSec != l.bytesPerSec {
+		l.rowsPerSec, l.bytesPerSec = rowsPerSec, bytesPerSec
+		l.start, l.rows, l.bytes = now, 0, 0
+		return nil
+	}
+
+	// The slower of the two limits sets how long reading this far should have taken
+	var expected time.Duration
+	if l.rowsPerSec > 0 {
+		expected = time.Duration(float64(l.rows) / l.rowsPerSec * float64(time.Second))
+	}
+	if l.bytesPerSec > 0 {
+		if d := time.Duration(float64(l.bytes) / l.bytesPerSec * float64(time.Second)); d > expected {
+			expected = d
+		}
+	}
+	pause := expected - now.Sub(l.start)
+	if pause <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	l.waited += pause
+	metrics.SyncReadThrottledSeconds.WithLabelValues(l.table).Add(pause.Seconds())
+	return nil
+}
+
+// throttled returns the total time the read was paused
+func (l *readLimiter) throttled() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return l.waited
+}
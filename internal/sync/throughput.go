@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"time"
+)
+
+// estimateBytes approximates the size of the fetched rows as transferred from the source
+func estimateBytes(data []map[string]interface{}) int64 {
+	var total int64
+	for _, row := range data {
+		for _, value := range row {
+			total += valueSize(value)
+		}
+	}
+	return total
+}
+
+func valueSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case time.Time:
+		return 10
+	default:
+		return 8
+	}
+}
+
+// RowsPerSecond returns the write throughput of the sync
+func (r *SyncResult) RowsPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.RowsWritten) / r.Duration.Seconds()
+}
+
+// BytesPerSecond returns the read throughput of the sync
+func (r *SyncResult) BytesPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.BytesRead) / r.Duration.Seconds()
+}
@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// snapshotProjections appends the output of the projections on a time travel table to their snapshot tables after a
+// load, dropping the snapshots of loads beyond the kept number. A failed snapshot is logged and does not fail the sync
+func (se *SyncEngine) snapshotProjections(ctx context.Context, tableConfig config.TableConfig, snapshotAt time.Time) {
+	if tableConfig.TimeTravel == nil {
+		return
+	}
+	for i := range se.Config.Projections {
+		projection := &se.Config.Projections[i]
+		if projection.SyncTable != tableConfig.TargetTable {
+			continue
+		}
+		if err := se.snapshotProjection(ctx, projection, tableConfig.TimeTravel.GetKeep(), snapshotAt); err != nil {
+			se.Logger.Warn("Failed to snapshot projection",
+				zap.String("table", tableConfig.TargetTable),
+				zap.String("projection", projection.ID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// snapshotProjection copies a projection's target view into its snapshot table in one transaction, creating the
+// table with the view's columns and the snapshot time on first use
+func (se *SyncEngine) snapshotProjection(ctx context.Context, projection *config.ProjectionConfig, keep int, snapshotAt time.Time) error {
+	view := sqlident.Postgres(projection.TargetView)
+	snapshots := sqlident.Postgres(projection.SnapshotTable())
+	at := sqlident.PostgresColumn(config.SnapshotAtColumn)
+	_, table := sqlident.SplitQualified(projection.SnapshotTable(), "public")
+
+	tx, err := se.DB.Target.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s AS SELECT v.*, now() AS %s FROM %s v WITH NO DATA", snapshots, at, view),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", sqlident.PostgresColumn(table+"_at_idx"), snapshots, at),
+	} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s SELECT v.*, $1 FROM %s v", snapshots, view), snapshotAt); err != nil {
+		return fmt.Errorf("failed to copy %s (drop %s if the view's columns changed): %w", projection.TargetView, projection.SnapshotTable(), err)
+	}
+
+	prune := fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE %[2]s < (SELECT MIN(k.at) FROM (SELECT DISTINCT %[2]s AS at FROM %[1]s ORDER BY at DESC LIMIT $1) k)`,
+		snapshots, at)
+	if _, err := tx.ExecContext(ctx, prune, keep); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	se.Logger.Info("Snapshotted projection",
+		zap.String("projection", projection.ID),
+		zap.String("snapshot_table", projection.SnapshotTable()),
+		zap.Time("snapshot_at", snapshotAt),
+	)
+	return nil
+}
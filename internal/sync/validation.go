@@ -0,0 +1,241 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"mssql-postgres-sync/internal/config"
+	"mssql-postgres-sync/internal/history"
+	"mssql-postgres-sync/internal/sqlident"
+)
+
+// ValidationReport summarizes the validation rule violations of a sync
+type ValidationReport struct {
+	RowsChecked     int                `json:"rows_checked"`
+	RowsSkipped     int                `json:"rows_skipped"`
+	RowsQuarantined int                `json:"rows_quarantined"`
+	Rules           []RuleReport       `json:"rules"`
+	Constraints     []ConstraintReport `json:"constraints,omitempty"` // foreign keys validated after a load with constraints: disable
+}
+
+// RuleReport counts the rows violating a single validation rule
+type RuleReport struct {
+	Name        string `json:"name"`
+	Column      string `json:"column"`
+	Rule        string `json:"rule"`
+	OnViolation string `json:"on_violation"`
+	Violations  int    `json:"violations"`
+}
+
+// violationSeverity orders actions so a row violating several rules gets the strictest one
+var violationSeverity = map[string]int{
+	config.ViolationSkip:       1,
+	config.ViolationQuarantine: 2,
+	config.ViolationFail:       3,
+}
+
+// ruleCheck evaluates a validation rule against a row value
+type ruleCheck func(value interface{}) bool
+
+// validateRows evaluates the table's validation rules, returning the rows to sync and the report.
+// Rows violating a fail rule fail the sync; skipped and quarantined rows are dropped
+func (se *SyncEngine) validateRows(tableConfig config.TableConfig, columns []ColumnInfo, data []map[string]interface{}, batchID string) ([]map[string]interface{}, *ValidationReport, error) {
+	v := tableConfig.Validation
+	if v == nil || len(v.Rules) == 0 {
+		return data, nil, nil
+	}
+
+	report := &ValidationReport{RowsChecked: len(data)}
+	checks := make([]ruleCheck, len(v.Rules))
+	columnNames := make([]string, len(v.Rules))
+	for i, rule := range v.Rules {
+		column := ""
+		for _, col := range columns {
+			if strings.EqualFold(col.Name, rule.Column) {
+				column = col.Name
+				break
+			}
+		}
+		if column == "" {
+			return nil, report, fmt.Errorf("validation rule %s: column %s is not synced", rule.GetName(), rule.Column)
+		}
+
+		check, err := se.buildRuleCheck(rule)
+		if err != nil {
+			return nil, report, fmt.Errorf("validation rule %s: %w", rule.GetName(), err)
+		}
+		checks[i] = check
+		columnNames[i] = column
+		report.Rules = append(report.Rules, RuleReport{
+			Name:        rule.GetName(),
+			Column:      rule.Column,
+			Rule:        strings.ToLower(rule.Rule),
+			OnViolation: rule.GetOnViolation(v),
+		})
+	}
+
+	var (
+		valid       = make([]map[string]interface{}, 0, len(data))
+		quarantined []history.QuarantinedRow
+		failed      int
+	)
+	for _, row := range data {
+		action := ""
+		var violated []string
+		for i, check := range checks {
+			if check(row[columnNames[i]]) {
+				continue
+			}
+			report.Rules[i].Violations++
+			violated = append(violated, report.Rules[i].Name)
+			if violationSeverity[report.Rules[i].OnViolation] > violationSeverity[action] {
+				action = report.Rules[i].OnViolation
+			}
+		}
+
+		switch action {
+		case "":
+			valid = append(valid, row)
+		case config.ViolationSkip:
+			report.RowsSkipped++
+		case config.ViolationQuarantine:
+			report.RowsQuarantined++
+			rowJSON, err := json.Marshal(jsonRow(row))
+			if err != nil {
+				return nil, report, err
+			}
+			quarantined = append(quarantined, history.QuarantinedRow{
+				BatchID:   batchID,
+				TableName: tableConfig.TargetTable,
+				Rules:     strings.Join(violated, ","),
+				Row:       rowJSON,
+			})
+		case config.ViolationFail:
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return nil, report, fmt.Errorf("validation failed: %d rows violate rules that fail the sync", failed)
+	}
+
+	if len(quarantined) > 0 {
+		if err := se.History.Quarantine(quarantined); err != nil {
+			return nil, report, fmt.Errorf("failed to quarantine rows: %w", err)
+		}
+	}
+
+	if report.RowsSkipped > 0 || report.RowsQuarantined > 0 {
+		se.Logger.Warn("Rows failed validation",
+			zap.String("table", tableConfig.TargetTable),
+			zap.Int("skipped", report.RowsSkipped),
+			zap.Int("quarantined", report.RowsQuarantined),
+		)
+	}
+
+	return valid, report, nil
+}
+
+// buildRuleCheck compiles a validation rule into a check. Only not_null rejects NULL values
+func (se *SyncEngine) buildRuleCheck(rule config.ValidationRule) (ruleCheck, error) {
+	switch strings.ToLower(rule.Rule) {
+	case "not_null":
+		return func(value interface{}) bool { return value != nil }, nil
+	case "range":
+		return func(value interface{}) bool {
+			if value == nil {
+				return true
+			}
+			f, ok := numericValue(value)
+			if !ok {
+				return false
+			}
+			return (rule.Min == nil || f >= *rule.Min) && (rule.Max == nil || f <= *rule.Max)
+		}, nil
+	case "regex":
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(value interface{}) bool {
+			return value == nil || pattern.MatchString(textValue(value))
+		}, nil
+	case "exists":
+		keys, err := se.referencedKeys(rule.Table, rule.GetRefColumn())
+		if err != nil {
+			return nil, err
+		}
+		return func(value interface{}) bool {
+			return value == nil || keys[textValue(value)]
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rule %s", rule.Rule)
+	}
+}
+
+// referencedKeys loads the distinct values of a column of another synced target table
+func (se *SyncEngine) referencedKeys(tableName, column string) (map[string]bool, error) {
+	query := fmt.Sprintf("SELECT DISTINCT %s::text FROM %s WHERE %s IS NOT NULL",
+		sqlident.PostgresColumn(column), sqlident.Postgres(tableName), sqlident.PostgresColumn(column))
+
+	var values []string
+	if err := se.DB.Target.Select(&values, query); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(values))
+	for _, value := range values {
+		keys[value] = true
+	}
+	return keys, nil
+}
+
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case []byte, string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(textValue(v)), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func textValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonRow converts driver byte slices to strings so quarantined rows serialize readably
+func jsonRow(row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for col, value := range row {
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		out[col] = value
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// variableTimeLayout formats time variables as literals SQL Server reads unambiguously as datetime and datetime2
+const variableTimeLayout = "2006-01-02T15:04:05.000"
+
+// beforeFirstSync is the value of last_sync and last_watermark until the table has synced successfully, so the
+// first sync reads everything
+const beforeFirstSync = "1900-01-01T00:00:00.000"
+
+// expandVariables returns the table config with the {{name}} variables of its filter and source query replaced by
+// SQL literals for a sync with the given batch ID
+func (se *SyncEngine) expandVariables(tableConfig config.TableConfig, batchID string) (config.TableConfig, error) {
+	if !tableConfig.UsesVariables() {
+		return tableConfig, nil
+	}
+
+	now := time.Now()
+	values := map[string]string{
+		config.VarToday:         now.Format("2006-01-02"),
+		config.VarNow:           now.Format(variableTimeLayout),
+		config.VarBatchID:       batchID,
+		config.VarLastSync:      beforeFirstSync,
+		config.VarLastWatermark: beforeFirstSync,
+	}
+	if se.History != nil {
+		last, err := se.History.LastSuccess(tableConfig.TargetTable)
+		if err != nil {
+			return tableConfig, fmt.Errorf("failed to read the last sync for filter variables: %w", err)
+		}
+		if last != nil {
+			values[config.VarLastSync] = last.StartedAt.Local().Format(variableTimeLayout)
+			if last.SourceChangedAt != nil {
+				// Source timestamps carry no time zone and come back from the history as UTC wall clock times
+				values[config.VarLastWatermark] = last.SourceChangedAt.UTC().Format(variableTimeLayout)
+			}
+		}
+	}
+	for name, value := range values {
+		values[name] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+
+	tableConfig.Filter = config.ExpandVariables(tableConfig.Filter, values)
+	tableConfig.SourceQuery = config.ExpandVariables(tableConfig.SourceQuery, values)
+	return tableConfig, nil
+}
@@ -0,0 +1,39 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaListener consumes a Kafka topic as part of a consumer group
+type KafkaListener struct {
+	Brokers []string
+	Topic   string
+	GroupID string
+}
+
+// Listen reads messages, committing each once the trigger has fired
+func (l *KafkaListener) Listen(ctx context.Context, fire func()) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: l.Brokers,
+		Topic:   l.Topic,
+		GroupID: l.GroupID,
+	})
+	defer reader.Close()
+
+	for {
+		message, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		fire()
+		if err := reader.CommitMessages(ctx, message); err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+}
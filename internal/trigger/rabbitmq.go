@@ -0,0 +1,49 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQListener consumes a RabbitMQ queue
+type RabbitMQListener struct {
+	URL   string
+	Queue string
+}
+
+// Listen consumes messages, acknowledging each once the trigger has fired
+func (l *RabbitMQListener) Listen(ctx context.Context, fire func()) error {
+	conn, err := amqp.Dial(l.URL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	deliveries, err := ch.Consume(l.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("rabbitmq consumer for queue %s closed", l.Queue)
+			}
+			fire()
+			if err := delivery.Ack(false); err != nil {
+				return err
+			}
+		}
+	}
+}
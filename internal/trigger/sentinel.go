@@ -0,0 +1,71 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SentinelListener polls a cheap source query and fires whenever its result changes
+type SentinelListener struct {
+	DB       Querier
+	Query    string
+	Interval time.Duration
+}
+
+// Listen polls the sentinel query until ctx is cancelled. The first result only sets the baseline
+func (l *SentinelListener) Listen(ctx context.Context, fire func()) error {
+	last, err := l.poll(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(l.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := l.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if current != last {
+			last = current
+			fire()
+		}
+	}
+}
+
+// poll runs the sentinel query and returns its result rendered as a comparable string
+func (l *SentinelListener) poll(ctx context.Context) (string, error) {
+	rows, err := l.DB.QueryxContext(ctx, l.Query)
+	if err != nil {
+		return "", fmt.Errorf("sentinel query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var result strings.Builder
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return "", err
+		}
+		for _, value := range values {
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			fmt.Fprintf(&result, "%v\x1f", value)
+		}
+		result.WriteByte('\n')
+	}
+	return result.String(), rows.Err()
+}
@@ -0,0 +1,96 @@
+package trigger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// serviceBusPollTimeout is the server-side long-poll timeout for receive requests
+const serviceBusPollTimeout = 55 * time.Second
+
+// ServiceBusListener receives messages from an Azure Service Bus queue or topic subscription over the REST API
+type ServiceBusListener struct {
+	Namespace string
+	Entity    string // queue name or <topic>/subscriptions/<subscription>
+	KeyName   string
+	Key       string
+	Client    *http.Client
+}
+
+func newServiceBusListener(cfg config.TriggerConfig) (*ServiceBusListener, error) {
+	entity := cfg.Queue
+	if entity == "" && cfg.Topic != "" && cfg.Subscription != "" {
+		entity = cfg.Topic + "/subscriptions/" + cfg.Subscription
+	}
+	if cfg.Namespace == "" || entity == "" || cfg.KeyName == "" || cfg.Key == "" {
+		return nil, fmt.Errorf("trigger %s: servicebus requires namespace, queue (or topic and subscription), key_name and key", cfg.Name)
+	}
+
+	namespace := cfg.Namespace
+	if !strings.Contains(namespace, ".") {
+		namespace += ".servicebus.windows.net"
+	}
+
+	return &ServiceBusListener{
+		Namespace: namespace,
+		Entity:    entity,
+		KeyName:   cfg.KeyName,
+		Key:       cfg.Key,
+		Client:    &http.Client{Timeout: serviceBusPollTimeout + 10*time.Second},
+	}, nil
+}
+
+// Listen long-polls the entity with receive-and-delete requests
+func (l *ServiceBusListener) Listen(ctx context.Context, fire func()) error {
+	endpoint := fmt.Sprintf("https://%s/%s/messages/head?timeout=%d", l.Namespace, l.Entity, int(serviceBusPollTimeout.Seconds()))
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", l.sasToken(time.Now()))
+
+		resp, err := l.Client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusCreated:
+			fire()
+		case http.StatusNoContent:
+			// Poll timed out without a message
+		default:
+			return fmt.Errorf("service bus receive failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+	}
+}
+
+// sasToken builds a shared access signature for the entity, valid for one hour
+func (l *ServiceBusListener) sasToken(now time.Time) string {
+	resource := url.QueryEscape(strings.ToLower(fmt.Sprintf("https://%s/%s", l.Namespace, l.Entity)))
+	expiry := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(l.Key))
+	mac.Write([]byte(resource + "\n" + expiry))
+	signature := url.QueryEscape(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	return fmt.Sprintf("SharedAccessSignature sr=%s&sig=%s&se=%s&skn=%s", resource, signature, expiry, l.KeyName)
+}
@@ -0,0 +1,59 @@
+// Package trigger listens to external message sources and change sentinels and reports when a sync should be triggered.
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"mssql-postgres-sync/internal/config"
+)
+
+// Trigger source types
+const (
+	TypeRabbitMQ   = "rabbitmq"
+	TypeKafka      = "kafka"
+	TypeServiceBus = "servicebus"
+	TypeSentinel   = "sentinel"
+)
+
+// Listener blocks receiving messages, calling fire for each one, until ctx is cancelled or the connection fails
+type Listener interface {
+	Listen(ctx context.Context, fire func()) error
+}
+
+// Querier runs queries; sentinel triggers poll the source through it
+type Querier interface {
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+}
+
+// NewListener creates the listener for a trigger configuration. Sentinel triggers poll the source database
+func NewListener(cfg config.TriggerConfig, source Querier) (Listener, error) {
+	switch strings.ToLower(cfg.Type) {
+	case TypeRabbitMQ:
+		if cfg.URL == "" || cfg.Queue == "" {
+			return nil, fmt.Errorf("trigger %s: rabbitmq requires url and queue", cfg.Name)
+		}
+		return &RabbitMQListener{URL: cfg.URL, Queue: cfg.Queue}, nil
+	case TypeKafka:
+		if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+			return nil, fmt.Errorf("trigger %s: kafka requires brokers and topic", cfg.Name)
+		}
+		groupID := cfg.GroupID
+		if groupID == "" {
+			groupID = "projection-sync-" + cfg.Name
+		}
+		return &KafkaListener{Brokers: cfg.Brokers, Topic: cfg.Topic, GroupID: groupID}, nil
+	case TypeServiceBus:
+		return newServiceBusListener(cfg)
+	case TypeSentinel:
+		if cfg.Query == "" {
+			return nil, fmt.Errorf("trigger %s: sentinel requires query", cfg.Name)
+		}
+		return &SentinelListener{DB: source, Query: cfg.Query, Interval: cfg.GetInterval()}, nil
+	default:
+		return nil, fmt.Errorf("trigger %s: unsupported type %s", cfg.Name, cfg.Type)
+	}
+}